@@ -0,0 +1,369 @@
+package plcopen
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// AddData represents the addData element used by tools to store vendor
+// specific data, e.g. CODESYS object ids or Beremiz graphical settings.
+// TC6 XML V1.0B does not define it, but it is read from the documents of
+// such tools; Project.Save writes it back to XML only with WithAddData.
+type AddData struct {
+	Data []AddDataData `xml:"data,omitempty" json:"data,omitempty"`
+}
+
+// AddDataData represents one data element of addData. The content is kept
+// as raw XML tokens so that it survives a round trip unchanged. If a decoder
+// is registered for Name with RegisterAddData, Value holds the decoded
+// content; a non-nil Value is encoded instead of Tokens.
+type AddDataData struct {
+	Name          string            `xml:"name,attr" json:"name"`
+	HandleUnknown HandleUnknownType `xml:"handleUnknown,attr" json:"handleUnknown"`
+	Tokens        []xml.Token       `xml:"-" json:"-"`
+	Value         interface{}       `xml:"-" json:"-"`
+}
+
+// HandleUnknownType tells a tool that does not understand a data element what
+// to do with it when the surrounding element is modified
+type HandleUnknownType string
+
+const (
+	HandleUnknownTypePreserve       HandleUnknownType = "preserve"
+	HandleUnknownTypeDiscard        HandleUnknownType = "discard"
+	HandleUnknownTypeImplementation HandleUnknownType = "implementation"
+)
+
+// addDataTypes maps data name URIs to constructors of their typed values
+var addDataTypes sync.Map
+
+// RegisterAddData registers newValue as the constructor of the typed value of
+// data elements named name. The content of such elements is decoded with
+// xml.Unmarshal into the value returned by newValue and stored in Value.
+// Registering a nil constructor removes the registration.
+func RegisterAddData(name string, newValue func() interface{}) {
+	if newValue == nil {
+		addDataTypes.Delete(name)
+		return
+	}
+	addDataTypes.Store(name, newValue)
+}
+
+// Find returns the first data element named name, or nil
+func (a *AddData) Find(name string) *AddDataData {
+	if a == nil {
+		return nil
+	}
+	for i := range a.Data {
+		if a.Data[i].Name == name {
+			return &a.Data[i]
+		}
+	}
+	return nil
+}
+
+// Content returns the content of the data element as XML text
+func (a *AddDataData) Content() (string, error) {
+	var buf bytes.Buffer
+	e := xml.NewEncoder(&buf)
+	if err := a.encodeContent(e); err != nil {
+		return "", err
+	}
+	if err := e.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// UnmarshalXML decodes the data element, keeping its content as tokens and
+// decoding it into Value if a type is registered for its name
+func (a *AddDataData) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*a = AddDataData{}
+	for _, attr := range start.Attr {
+		switch attr.Name.Local {
+		case "name":
+			a.Name = attr.Value
+		case "handleUnknown":
+			a.HandleUnknown = HandleUnknownType(attr.Value)
+		}
+	}
+	tokens, err := captureContent(d, start.Name.Space)
+	if err != nil {
+		return err
+	}
+	a.Tokens = tokens
+	return a.decodeValue()
+}
+
+// MarshalXML encodes the data element with Value or, if Value is nil, Tokens
+// as its content
+func (a AddDataData) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	handleUnknown := a.HandleUnknown
+	if handleUnknown == "" {
+		// The attribute is required; preserve is the safe choice for unknown data
+		handleUnknown = HandleUnknownTypePreserve
+	}
+	start.Attr = append(start.Attr,
+		xml.Attr{Name: xml.Name{Local: "name"}, Value: a.Name},
+		xml.Attr{Name: xml.Name{Local: "handleUnknown"}, Value: string(handleUnknown)},
+	)
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := a.encodeContent(e); err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// MarshalJSON encodes the data element with its content as XML text
+func (a AddDataData) MarshalJSON() ([]byte, error) {
+	content, err := a.Content()
+	if err != nil {
+		return nil, err
+	}
+	return marshalJSON(struct {
+		Name          string            `json:"name"`
+		HandleUnknown HandleUnknownType `json:"handleUnknown"`
+		Content       string            `json:"content,omitempty"`
+	}{a.Name, a.HandleUnknown, content})
+}
+
+// UnmarshalJSON decodes the form written by MarshalJSON
+func (a *AddDataData) UnmarshalJSON(data []byte) error {
+	var obj struct {
+		Name          string            `json:"name"`
+		HandleUnknown HandleUnknownType `json:"handleUnknown"`
+		Content       string            `json:"content"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	d := xml.NewDecoder(strings.NewReader("<data>" + obj.Content + "</data>"))
+	start, err := nextStart(d)
+	if err != nil {
+		return err
+	}
+	tokens, err := captureContent(d, start.Name.Space)
+	if err != nil {
+		return err
+	}
+	*a = AddDataData{Name: obj.Name, HandleUnknown: obj.HandleUnknown, Tokens: tokens}
+	return a.decodeValue()
+}
+
+// decodeValue decodes Tokens into Value if a type is registered for Name
+func (a *AddDataData) decodeValue() error {
+	newValue, ok := addDataTypes.Load(a.Name)
+	if !ok {
+		return nil
+	}
+	content, err := (&AddDataData{Tokens: a.Tokens}).Content()
+	if err != nil {
+		return err
+	}
+	value := newValue.(func() interface{})()
+	if err := xml.Unmarshal([]byte(content), value); err != nil {
+		return fmt.Errorf("plcopen: decoding addData %q: %w", a.Name, err)
+	}
+	a.Value = value
+	return nil
+}
+
+// encodeContent writes Value or Tokens to e
+func (a *AddDataData) encodeContent(e *xml.Encoder) error {
+	if a.Value != nil {
+		return e.Encode(a.Value)
+	}
+	for _, tok := range a.Tokens {
+		if err := e.EncodeToken(tok); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nsScope holds the namespace prefixes declared inside captured content
+type nsScope struct {
+	prefixes map[string]string // namespace URI -> prefix
+	def      string            // default namespace URI
+}
+
+// captureContent reads the content of the current element up to its end
+// element. The returned tokens use prefixed local names, as written in the
+// document, so they can be encoded again as they are. Namespaces declared
+// outside of the content are declared again where they are used; def is the
+// default namespace in effect for the content.
+func captureContent(d *xml.Decoder, def string) ([]xml.Token, error) {
+	var tokens []xml.Token
+	scopes := []nsScope{{prefixes: map[string]string{xmlNamespace: "xml"}, def: def}}
+	var names []xml.Name
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := scopes[len(scopes)-1]
+			scope := nsScope{prefixes: make(map[string]string, len(parent.prefixes)), def: parent.def}
+			for uri, prefix := range parent.prefixes {
+				scope.prefixes[uri] = prefix
+			}
+			var attrs []xml.Attr
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "xmlns":
+					scope.prefixes[attr.Value] = attr.Name.Local
+					attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + attr.Name.Local}, Value: attr.Value})
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					scope.def = attr.Value
+					attrs = append(attrs, attr)
+				}
+			}
+			name := t.Name.Local
+			if t.Name.Space != scope.def {
+				if prefix, ok := scope.prefixes[t.Name.Space]; ok {
+					name = prefix + ":" + name
+				} else {
+					scope.def = t.Name.Space
+					attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: t.Name.Space})
+				}
+			}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") {
+					continue
+				}
+				local := attr.Name.Local
+				if attr.Name.Space != "" {
+					prefix, ok := scope.prefixes[attr.Name.Space]
+					if !ok {
+						prefix = "ns" + strconv.Itoa(len(scope.prefixes))
+						scope.prefixes[attr.Name.Space] = prefix
+						attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "xmlns:" + prefix}, Value: attr.Name.Space})
+					}
+					local = prefix + ":" + local
+				}
+				attrs = append(attrs, xml.Attr{Name: xml.Name{Local: local}, Value: attr.Value})
+			}
+			scopes = append(scopes, scope)
+			names = append(names, xml.Name{Local: name})
+			tokens = append(tokens, xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+		case xml.EndElement:
+			if len(names) == 0 {
+				return tokens, nil
+			}
+			tokens = append(tokens, xml.EndElement{Name: names[len(names)-1]})
+			names = names[:len(names)-1]
+			scopes = scopes[:len(scopes)-1]
+		default:
+			tokens = append(tokens, xml.CopyToken(tok))
+		}
+	}
+}
+
+// xmlNamespace is the namespace bound to the reserved "xml" prefix
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+var addDataType = reflect.TypeOf(&AddData{})
+
+// withoutAddData returns a copy of v without its addData elements, reporting
+// each with warn at the path of its parent element. path is the element path
+// of v, built from the xml tags of the fields and identifying list elements
+// by their localId or name attribute as in diagnostic paths.
+func withoutAddData(v reflect.Value, path string, warn func(path, message string)) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(withoutAddData(v.Elem(), path, warn))
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		if kind := v.Type().Elem().Kind(); kind != reflect.Struct && kind != reflect.Ptr {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(withoutAddData(v.Index(i), path+elementKey(v.Index(i)), warn))
+		}
+		return out
+	case reflect.Struct:
+		// Copy unexported state such as the object order of bodies as well
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := xmlElementName(field)
+			if !ok {
+				continue
+			}
+			if field.Type == addDataType {
+				if !v.Field(i).IsNil() {
+					warn(path, "addData dropped, V1.0B does not define it")
+					out.Field(i).Set(reflect.Zero(field.Type))
+				}
+				continue
+			}
+			out.Field(i).Set(withoutAddData(v.Field(i), path+"/"+name, warn))
+		}
+		return out
+	}
+	return v
+}
+
+// xmlElementName returns the element path of an exported field encoded as
+// child elements, e.g. "pous/pou" for the tag "pous>pou"
+func xmlElementName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	tag := field.Tag.Get("xml")
+	if tag == "-" {
+		return "", false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "attr", "chardata", "innerxml", "comment", "any":
+			return "", false
+		}
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return strings.ReplaceAll(name, ">", "/"), true
+}
+
+// elementKey returns the [@localId='...'] or [@name='...'] predicate of a
+// list element, or "" if it has neither attribute
+func elementKey(v reflect.Value) string {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	for _, key := range []string{"localId", "name"} {
+		for i := 0; i < v.NumField(); i++ {
+			if strings.HasPrefix(v.Type().Field(i).Tag.Get("xml"), key+",attr") {
+				return fmt.Sprintf("[@%s='%v']", key, v.Field(i).Interface())
+			}
+		}
+	}
+	return ""
+}
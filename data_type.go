@@ -0,0 +1,481 @@
+package plcopen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DataTypeKind is the variant held by a DataType, the name of its XML element.
+// The kind of an empty DataType is "".
+type DataTypeKind string
+
+// Elementary data type kinds
+const (
+	DataTypeKindBOOL  DataTypeKind = "BOOL"
+	DataTypeKindBYTE  DataTypeKind = "BYTE"
+	DataTypeKindWORD  DataTypeKind = "WORD"
+	DataTypeKindDWORD DataTypeKind = "DWORD"
+	DataTypeKindLWORD DataTypeKind = "LWORD"
+	DataTypeKindSINT  DataTypeKind = "SINT"
+	DataTypeKindINT   DataTypeKind = "INT"
+	DataTypeKindDINT  DataTypeKind = "DINT"
+	DataTypeKindLINT  DataTypeKind = "LINT"
+	DataTypeKindUSINT DataTypeKind = "USINT"
+	DataTypeKindUINT  DataTypeKind = "UINT"
+	DataTypeKindUDINT DataTypeKind = "UDINT"
+	DataTypeKindULINT DataTypeKind = "ULINT"
+	DataTypeKindREAL  DataTypeKind = "REAL"
+	DataTypeKindLREAL DataTypeKind = "LREAL"
+	DataTypeKindTIME  DataTypeKind = "TIME"
+	DataTypeKindDATE  DataTypeKind = "DATE"
+	DataTypeKindDT    DataTypeKind = "DT"
+	DataTypeKindTOD   DataTypeKind = "TOD"
+)
+
+// Complex data type kinds
+const (
+	DataTypeKindArray            DataTypeKind = "array"
+	DataTypeKindDerived          DataTypeKind = "derived"
+	DataTypeKindEnum             DataTypeKind = "enum"
+	DataTypeKindPointer          DataTypeKind = "pointer"
+	DataTypeKindString           DataTypeKind = "string"
+	DataTypeKindWString          DataTypeKind = "wstring"
+	DataTypeKindStruct           DataTypeKind = "struct"
+	DataTypeKindSubrangeSigned   DataTypeKind = "subrangeSigned"
+	DataTypeKindSubrangeUnsigned DataTypeKind = "subrangeUnsigned"
+)
+
+// complexKinds are the kinds of the complex data types
+var complexKinds = []DataTypeKind{
+	DataTypeKindArray, DataTypeKindDerived, DataTypeKindEnum, DataTypeKindPointer, DataTypeKindString,
+	DataTypeKindWString, DataTypeKindStruct, DataTypeKindSubrangeSigned, DataTypeKindSubrangeUnsigned,
+}
+
+// elementaryTypes are the elementary types of DataType with the field holding them
+var elementaryTypes = []struct {
+	kind  DataTypeKind
+	field func(t *DataType) **struct{}
+}{
+	{DataTypeKindBOOL, func(t *DataType) **struct{} { return &t.BOOL }},
+	{DataTypeKindBYTE, func(t *DataType) **struct{} { return &t.BYTE }},
+	{DataTypeKindWORD, func(t *DataType) **struct{} { return &t.WORD }},
+	{DataTypeKindDWORD, func(t *DataType) **struct{} { return &t.DWORD }},
+	{DataTypeKindLWORD, func(t *DataType) **struct{} { return &t.LWORD }},
+	{DataTypeKindSINT, func(t *DataType) **struct{} { return &t.SINT }},
+	{DataTypeKindINT, func(t *DataType) **struct{} { return &t.INT }},
+	{DataTypeKindDINT, func(t *DataType) **struct{} { return &t.DINT }},
+	{DataTypeKindLINT, func(t *DataType) **struct{} { return &t.LINT }},
+	{DataTypeKindUSINT, func(t *DataType) **struct{} { return &t.USINT }},
+	{DataTypeKindUINT, func(t *DataType) **struct{} { return &t.UINT }},
+	{DataTypeKindUDINT, func(t *DataType) **struct{} { return &t.UDINT }},
+	{DataTypeKindULINT, func(t *DataType) **struct{} { return &t.ULINT }},
+	{DataTypeKindREAL, func(t *DataType) **struct{} { return &t.REAL }},
+	{DataTypeKindLREAL, func(t *DataType) **struct{} { return &t.LREAL }},
+	{DataTypeKindTIME, func(t *DataType) **struct{} { return &t.TIME }},
+	{DataTypeKindDATE, func(t *DataType) **struct{} { return &t.DATE }},
+	{DataTypeKindDT, func(t *DataType) **struct{} { return &t.DT }},
+	{DataTypeKindTOD, func(t *DataType) **struct{} { return &t.TOD }},
+}
+
+// IsElementary reports whether k is an elementary type such as INT or TIME
+func (k DataTypeKind) IsElementary() bool {
+	for _, e := range elementaryTypes {
+		if e.kind == k {
+			return true
+		}
+	}
+	return false
+}
+
+// Kind returns the variant held by t, "" if t is nil or empty
+func (t *DataType) Kind() DataTypeKind {
+	if t == nil {
+		return ""
+	}
+	for _, e := range elementaryTypes {
+		if *e.field(t) != nil {
+			return e.kind
+		}
+	}
+	kind, _ := t.complexKind()
+	return kind
+}
+
+// complexKind returns the kind of the complex type held by t and the field holding it
+func (t *DataType) complexKind() (DataTypeKind, interface{}) {
+	switch {
+	case t.Array != nil:
+		return DataTypeKindArray, t.Array
+	case t.Derived != nil:
+		return DataTypeKindDerived, t.Derived
+	case t.Enum != nil:
+		return DataTypeKindEnum, t.Enum
+	case t.Pointer != nil:
+		return DataTypeKindPointer, t.Pointer
+	case t.String != nil:
+		return DataTypeKindString, t.String
+	case t.WString != nil:
+		return DataTypeKindWString, t.WString
+	case t.Struct != nil:
+		return DataTypeKindStruct, t.Struct
+	case t.SubrangeSigned != nil:
+		return DataTypeKindSubrangeSigned, t.SubrangeSigned
+	case t.SubrangeUnsigned != nil:
+		return DataTypeKindSubrangeUnsigned, t.SubrangeUnsigned
+	}
+	return "", nil
+}
+
+// setComplexKind allocates the field of kind and returns it, nil for other kinds
+func (t *DataType) setComplexKind(kind DataTypeKind) interface{} {
+	switch kind {
+	case DataTypeKindArray:
+		t.Array = &DataTypeArray{}
+		return t.Array
+	case DataTypeKindDerived:
+		t.Derived = &DataTypeDerived{}
+		return t.Derived
+	case DataTypeKindEnum:
+		t.Enum = &DataTypeEnum{}
+		return t.Enum
+	case DataTypeKindPointer:
+		t.Pointer = &DataTypePointer{}
+		return t.Pointer
+	case DataTypeKindString:
+		t.String = &DataTypeString{}
+		return t.String
+	case DataTypeKindWString:
+		t.WString = &DataTypeWString{}
+		return t.WString
+	case DataTypeKindStruct:
+		t.Struct = &VarListPlain{}
+		return t.Struct
+	case DataTypeKindSubrangeSigned:
+		t.SubrangeSigned = &DataTypeSubrangeSigned{}
+		return t.SubrangeSigned
+	case DataTypeKindSubrangeUnsigned:
+		t.SubrangeUnsigned = &DataTypeSubrangeUnsigned{}
+		return t.SubrangeUnsigned
+	}
+	return nil
+}
+
+// IEC renders t in IEC 61131-3 syntax, e.g. "INT", "STRING(80)",
+// "ARRAY[0..9] OF INT", "POINTER TO MyStruct", "(Red, Green)", "INT(0..100)"
+// or "STRUCT x : BOOL; y : INT := 5; END_STRUCT". It is "" for an empty
+// DataType. DataType cannot have a String method because of its String
+// field, Format prints the same text with the %v and %s verbs.
+func (t *DataType) IEC() string {
+	var sb strings.Builder
+	t.writeIEC(&sb)
+	return sb.String()
+}
+
+// Format implements fmt.Formatter, it prints IEC syntax except for %+v and
+// %#v, which print the fields
+func (t *DataType) Format(f fmt.State, verb rune) {
+	if verb == 'v' && (f.Flag('+') || f.Flag('#')) {
+		type fields DataType
+		format := "%+v"
+		if f.Flag('#') {
+			format = "%#v"
+		}
+		fmt.Fprintf(f, format, (*fields)(t))
+		return
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), t.IEC())
+}
+
+func (t *DataType) writeIEC(sb *strings.Builder) {
+	if kind := t.Kind(); kind.IsElementary() {
+		sb.WriteString(string(kind))
+		return
+	}
+	switch {
+	case t == nil:
+	case t.Array != nil:
+		sb.WriteString("ARRAY[")
+		for i, d := range t.Array.Dimensions {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			fmt.Fprintf(sb, "%d..%d", d.Lower, d.Upper)
+		}
+		sb.WriteString("] OF ")
+		t.Array.BaseType.writeIEC(sb)
+	case t.Derived != nil:
+		sb.WriteString(t.Derived.Name)
+	case t.Enum != nil:
+		if t.Enum.BaseType.Kind() != "" {
+			t.Enum.BaseType.writeIEC(sb)
+			sb.WriteByte(' ')
+		}
+		sb.WriteByte('(')
+		if t.Enum.Values != nil {
+			for i, v := range t.Enum.Values.Values {
+				if i > 0 {
+					sb.WriteString(", ")
+				}
+				sb.WriteString(v.Name)
+				if v.Value != nil {
+					sb.WriteString(" := " + *v.Value)
+				}
+			}
+		}
+		sb.WriteByte(')')
+	case t.Pointer != nil:
+		sb.WriteString("POINTER TO ")
+		t.Pointer.BaseType.writeIEC(sb)
+	case t.String != nil:
+		sb.WriteString("STRING")
+		writeLength(sb, t.String.Length)
+	case t.WString != nil:
+		sb.WriteString("WSTRING")
+		writeLength(sb, t.WString.Length)
+	case t.Struct != nil:
+		sb.WriteString("STRUCT")
+		for _, v := range t.Struct.Variables {
+			sb.WriteByte(' ')
+			sb.WriteString(v.Name)
+			if v.Address != "" {
+				sb.WriteString(" AT ")
+				sb.WriteString(v.Address)
+			}
+			sb.WriteString(" : ")
+			v.Type.writeIEC(sb)
+			if v.InitialValue != nil && !v.InitialValue.isEmpty() {
+				sb.WriteString(" := ")
+				v.InitialValue.writeIEC(sb)
+			}
+			sb.WriteByte(';')
+		}
+		sb.WriteString(" END_STRUCT")
+	case t.SubrangeSigned != nil:
+		t.SubrangeSigned.BaseType.writeIEC(sb)
+		if r := t.SubrangeSigned.Range; r != nil {
+			fmt.Fprintf(sb, "(%d..%d)", r.Lower, r.Upper)
+		}
+	case t.SubrangeUnsigned != nil:
+		t.SubrangeUnsigned.BaseType.writeIEC(sb)
+		if r := t.SubrangeUnsigned.Range; r != nil {
+			fmt.Fprintf(sb, "(%d..%d)", r.Lower, r.Upper)
+		}
+	}
+}
+
+func writeLength(sb *strings.Builder, length *uint64) {
+	if length != nil {
+		fmt.Fprintf(sb, "(%d)", *length)
+	}
+}
+
+// Equal reports whether t and u describe the same type. Names of derived
+// types, enum values and struct members are IEC 61131-3 identifiers and
+// compared case-insensitively; documentation is ignored. A nil DataType
+// equals an empty one.
+func (t *DataType) Equal(u *DataType) bool {
+	kind := t.Kind()
+	if kind != u.Kind() {
+		return false
+	}
+	switch kind {
+	case DataTypeKindArray:
+		a, b := t.Array, u.Array
+		if len(a.Dimensions) != len(b.Dimensions) {
+			return false
+		}
+		for i := range a.Dimensions {
+			if a.Dimensions[i] != b.Dimensions[i] {
+				return false
+			}
+		}
+		return a.BaseType.Equal(b.BaseType)
+	case DataTypeKindDerived:
+		return strings.EqualFold(t.Derived.Name, u.Derived.Name)
+	case DataTypeKindEnum:
+		var a, b []DataTypeEnumValuesValue
+		if t.Enum.Values != nil {
+			a = t.Enum.Values.Values
+		}
+		if u.Enum.Values != nil {
+			b = u.Enum.Values.Values
+		}
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !strings.EqualFold(a[i].Name, b[i].Name) || !equalPtr(a[i].Value, b[i].Value) {
+				return false
+			}
+		}
+		return t.Enum.BaseType.Equal(u.Enum.BaseType)
+	case DataTypeKindPointer:
+		return t.Pointer.BaseType.Equal(u.Pointer.BaseType)
+	case DataTypeKindString:
+		return equalPtr(t.String.Length, u.String.Length)
+	case DataTypeKindWString:
+		return equalPtr(t.WString.Length, u.WString.Length)
+	case DataTypeKindStruct:
+		a, b := t.Struct.Variables, u.Struct.Variables
+		if len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !strings.EqualFold(a[i].Name, b[i].Name) || a[i].Address != b[i].Address ||
+				!a[i].Type.Equal(b[i].Type) || a[i].InitialValue.String() != b[i].InitialValue.String() {
+				return false
+			}
+		}
+	case DataTypeKindSubrangeSigned:
+		return equalPtr(t.SubrangeSigned.Range, u.SubrangeSigned.Range) &&
+			t.SubrangeSigned.BaseType.Equal(u.SubrangeSigned.BaseType)
+	case DataTypeKindSubrangeUnsigned:
+		return equalPtr(t.SubrangeUnsigned.Range, u.SubrangeUnsigned.Range) &&
+			t.SubrangeUnsigned.BaseType.Equal(u.SubrangeUnsigned.BaseType)
+	}
+	return true
+}
+
+// equalPtr reports whether a and b are both nil or point to equal values
+func equalPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// MarshalJSON writes elementary types and strings without length as their
+// name, e.g. "INT" or "STRING", and other types as object tagged with their
+// kind, e.g. {"kind":"array","dimensions":[{"lower":1,"upper":10}],"baseType":"REAL"}.
+// An empty DataType is written as null.
+func (t DataType) MarshalJSON() ([]byte, error) {
+	for _, e := range elementaryTypes {
+		if *e.field(&t) != nil {
+			return json.Marshal(e.kind)
+		}
+	}
+	switch {
+	case t.String != nil && t.String.Length == nil:
+		return json.Marshal("STRING")
+	case t.WString != nil && t.WString.Length == nil:
+		return json.Marshal("WSTRING")
+	}
+	kind, v := t.complexKind()
+	if v == nil {
+		return []byte("null"), nil
+	}
+	members, err := marshalJSON(v)
+	if err != nil {
+		return nil, err
+	}
+	out := []byte(`{"kind":"` + string(kind) + `"`)
+	if !bytes.Equal(members, []byte("{}")) {
+		out = append(out, ',')
+	}
+	return append(out, members[1:]...), nil
+}
+
+// UnmarshalJSON accepts the forms written by MarshalJSON and the object
+// with one member per choice written by earlier versions, e.g. {"iNT":{}}
+func (t *DataType) UnmarshalJSON(data []byte) error {
+	*t = DataType{}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return err
+		}
+		for _, e := range elementaryTypes {
+			if strings.EqualFold(name, string(e.kind)) {
+				*e.field(t) = &struct{}{}
+				return nil
+			}
+		}
+		switch strings.ToUpper(name) {
+		case "STRING":
+			t.String = &DataTypeString{}
+			return nil
+		case "WSTRING":
+			t.WString = &DataTypeWString{}
+			return nil
+		}
+		return fmt.Errorf("plcopen: unknown elementary type %q", name)
+	}
+
+	var tagged struct {
+		Kind *DataTypeKind `json:"kind"`
+	}
+	if err := json.Unmarshal(data, &tagged); err != nil {
+		return err
+	}
+	if tagged.Kind == nil {
+		// DataType without its methods, decoded field by field
+		type dataTypeFields DataType
+		return json.Unmarshal(data, (*dataTypeFields)(t))
+	}
+	v := t.setComplexKind(*tagged.Kind)
+	if v == nil {
+		return fmt.Errorf("plcopen: unknown data type kind %q", *tagged.Kind)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// MarshalJSON writes the values as array of names, values with a value or
+// documentation as objects
+func (v DataTypeEnumValues) MarshalJSON() ([]byte, error) {
+	if v.Values == nil {
+		return []byte("[]"), nil
+	}
+	return marshalJSON(v.Values)
+}
+
+// UnmarshalJSON accepts the array written by MarshalJSON and the object
+// {"values":[...]} written by earlier versions
+func (v *DataTypeEnumValues) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var obj struct {
+			Values []DataTypeEnumValuesValue `json:"values"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		v.Values = obj.Values
+		return nil
+	}
+	return json.Unmarshal(data, &v.Values)
+}
+
+// MarshalJSON writes values without value and documentation as their name
+func (v DataTypeEnumValuesValue) MarshalJSON() ([]byte, error) {
+	if v.Value == nil && v.Documentation == nil {
+		return marshalJSON(v.Name)
+	}
+	type fields DataTypeEnumValuesValue
+	return marshalJSON(fields(v))
+}
+
+// UnmarshalJSON accepts a name or an object
+func (v *DataTypeEnumValuesValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '"' {
+		*v = DataTypeEnumValuesValue{}
+		return json.Unmarshal(data, &v.Name)
+	}
+	type fields DataTypeEnumValuesValue
+	return json.Unmarshal(data, (*fields)(v))
+}
+
+// marshalJSON is json.Marshal without escaping <, > and &, which are
+// common in program code
+func marshalJSON(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,452 @@
+package plcopen
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseDataType parses a data type in IEC 61131-3 syntax, the reverse of
+// DataType.IEC. It accepts elementary types including TIME_OF_DAY and
+// DATE_AND_TIME, STRING(80) and STRING[80], ARRAY[1..3, 0..9] OF REAL,
+// POINTER TO T, enumerations (Red, Green := 5) optionally preceded by their
+// base type, subranges INT(0..100), STRUCT a : INT := 5; END_STRUCT and
+// names of derived types. Keywords and type names are case-insensitive.
+//
+// Types that TC6 XML V1.0B cannot hold are rejected rather than taken as
+// derived types: the long types LTIME, LDATE, LTOD and LDT, CHAR and WCHAR,
+// references REF_TO T and arrays of variable length ARRAY[*] OF T.
+func ParseDataType(s string) (*DataType, error) {
+	p := &typeParser{src: s}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+	t, err := p.dataType()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.text != "" {
+		return nil, p.errorf(tok, "end of type")
+	}
+	return t, nil
+}
+
+// typeAliases are the long names of elementary types
+var typeAliases = map[string]DataTypeKind{
+	"TIME_OF_DAY":   DataTypeKindTOD,
+	"DATE_AND_TIME": DataTypeKindDT,
+}
+
+// unsupportedTypes are the keywords of IEC 61131-3 types without a TC6 XML
+// V1.0B equivalent
+var unsupportedTypes = map[string]bool{
+	"LTIME": true, "LDATE": true, "LTOD": true, "LTIME_OF_DAY": true, "LDT": true, "LDATE_AND_TIME": true,
+	"CHAR": true, "WCHAR": true, "REF_TO": true,
+}
+
+// typeToken is a token of a type declaration, text is "" at the end
+type typeToken struct {
+	text string
+	pos  int
+}
+
+type typeParser struct {
+	src    string
+	tokens []typeToken
+	next   int
+}
+
+// tokenize splits src into punctuation, quoted strings and words. Words are
+// names, keywords and literals; after a # they may contain colons, as in
+// TOD#12:00:00.
+func (p *typeParser) tokenize() error {
+	s := p.src
+	for i := 0; i < len(s); {
+		c := s[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+			continue
+		case strings.HasPrefix(s[i:], ":=") || strings.HasPrefix(s[i:], ".."):
+			i += 2
+		case strings.ContainsRune("[](),;:", rune(c)):
+			i++
+		case c == '\'' || c == '"':
+			end := strings.IndexByte(s[i+1:], c)
+			if end < 0 {
+				return fmt.Errorf("plcopen: data type %q: unterminated string at offset %d", p.src, i)
+			}
+			i += end + 2
+		case isWordChar(c):
+			typed := false
+			for i < len(s) && (isWordChar(s[i]) || (typed && s[i] == ':' && !strings.HasPrefix(s[i:], ":="))) {
+				if strings.HasPrefix(s[i:], "..") {
+					break
+				}
+				typed = typed || s[i] == '#'
+				i++
+			}
+		default:
+			return fmt.Errorf("plcopen: data type %q: unexpected %q at offset %d", p.src, c, i)
+		}
+		p.tokens = append(p.tokens, typeToken{s[start:i], start})
+	}
+	return nil
+}
+
+func isWordChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' ||
+		strings.IndexByte("_#.%+-*", c) >= 0
+}
+
+func isIdentifier(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *typeParser) peek() typeToken {
+	if p.next < len(p.tokens) {
+		return p.tokens[p.next]
+	}
+	return typeToken{pos: len(p.src)}
+}
+
+// peekAt returns the token n positions after the next one
+func (p *typeParser) peekAt(n int) typeToken {
+	if p.next+n < len(p.tokens) {
+		return p.tokens[p.next+n]
+	}
+	return typeToken{pos: len(p.src)}
+}
+
+func (p *typeParser) take() typeToken {
+	tok := p.peek()
+	if tok.text != "" {
+		p.next++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is kw and consumes it if so
+func (p *typeParser) keyword(kw string) bool {
+	if strings.EqualFold(p.peek().text, kw) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *typeParser) expect(text string) error {
+	if !p.keyword(text) {
+		return p.errorf(p.peek(), text)
+	}
+	return nil
+}
+
+// unsupported returns the error for tok, the start of a type that V1.0B cannot hold
+func (p *typeParser) unsupported(tok typeToken, what string) error {
+	return fmt.Errorf("plcopen: data type %q: %s at offset %d is not supported by TC6 XML V1.0B", p.src, what, tok.pos)
+}
+
+func (p *typeParser) errorf(tok typeToken, want string) error {
+	if tok.text == "" {
+		return fmt.Errorf("plcopen: data type %q: expected %s at end", p.src, want)
+	}
+	return fmt.Errorf("plcopen: data type %q: expected %s at offset %d, got %q", p.src, want, tok.pos, tok.text)
+}
+
+func (p *typeParser) dataType() (*DataType, error) {
+	tok := p.take()
+	name := strings.ToUpper(tok.text)
+	switch name {
+	case "ARRAY":
+		return p.array()
+	case "POINTER":
+		if err := p.expect("TO"); err != nil {
+			return nil, err
+		}
+		base, err := p.dataType()
+		if err != nil {
+			return nil, err
+		}
+		return &DataType{Pointer: &DataTypePointer{BaseType: base}}, nil
+	case "STRING", "WSTRING":
+		length, err := p.length()
+		if err != nil {
+			return nil, err
+		}
+		if name == "STRING" {
+			return &DataType{String: &DataTypeString{Length: length}}, nil
+		}
+		return &DataType{WString: &DataTypeWString{Length: length}}, nil
+	case "STRUCT":
+		return p.structure()
+	case "(":
+		p.next--
+		return p.enum(nil)
+	}
+	if unsupportedTypes[name] {
+		return nil, p.unsupported(tok, name)
+	}
+	if !isIdentifier(tok.text) || isTypeKeyword(name) {
+		return nil, p.errorf(tok, "data type")
+	}
+
+	t := &DataType{Derived: &DataTypeDerived{Name: tok.text}}
+	if kind, ok := typeAliases[name]; ok {
+		name = string(kind)
+	}
+	for _, e := range elementaryTypes {
+		if string(e.kind) == name {
+			t = &DataType{}
+			*e.field(t) = &struct{}{}
+		}
+	}
+	if p.peek().text != "(" {
+		return t, nil
+	}
+	if p.peekAt(2).text == ".." {
+		return p.subrange(t)
+	}
+	return p.enum(t)
+}
+
+// isTypeKeyword reports whether name is a keyword that cannot name a type
+func isTypeKeyword(name string) bool {
+	switch name {
+	case "OF", "TO", "AT", "END_STRUCT":
+		return true
+	}
+	return false
+}
+
+// length parses the optional length of a string type, (80) or [80]
+func (p *typeParser) length() (*uint64, error) {
+	closing := map[string]string{"(": ")", "[": "]"}[p.peek().text]
+	if closing == "" {
+		return nil, nil
+	}
+	p.next++
+	tok := p.take()
+	n, err := strconv.ParseUint(tok.text, 10, 64)
+	if err != nil {
+		return nil, p.errorf(tok, "string length")
+	}
+	return &n, p.expect(closing)
+}
+
+func (p *typeParser) array() (*DataType, error) {
+	if err := p.expect("["); err != nil {
+		return nil, err
+	}
+	array := &DataTypeArray{}
+	if tok := p.peek(); tok.text == "*" {
+		return nil, p.unsupported(tok, "ARRAY[*]")
+	}
+	for {
+		lower, upper, err := p.bounds()
+		if err != nil {
+			return nil, err
+		}
+		array.Dimensions = append(array.Dimensions, RangeSigned{Lower: lower, Upper: upper})
+		if !p.keyword(",") {
+			break
+		}
+	}
+	if err := p.expect("]"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("OF"); err != nil {
+		return nil, err
+	}
+	base, err := p.dataType()
+	if err != nil {
+		return nil, err
+	}
+	array.BaseType = base
+	return &DataType{Array: array}, nil
+}
+
+// bounds parses a range lower..upper
+func (p *typeParser) bounds() (int64, int64, error) {
+	tok := p.take()
+	lower, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil {
+		return 0, 0, p.errorf(tok, "lower bound")
+	}
+	if err := p.expect(".."); err != nil {
+		return 0, 0, err
+	}
+	tok = p.take()
+	upper, err := strconv.ParseInt(tok.text, 10, 64)
+	if err != nil {
+		return 0, 0, p.errorf(tok, "upper bound")
+	}
+	return lower, upper, nil
+}
+
+// subrange parses the range of a subrange of the integer type base
+func (p *typeParser) subrange(base *DataType) (*DataType, error) {
+	open := p.take()
+	lower, upper, err := p.bounds()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	switch base.Kind() {
+	case DataTypeKindSINT, DataTypeKindINT, DataTypeKindDINT, DataTypeKindLINT:
+		return &DataType{SubrangeSigned: &DataTypeSubrangeSigned{
+			Range:    &RangeSigned{Lower: lower, Upper: upper},
+			BaseType: base,
+		}}, nil
+	case DataTypeKindUSINT, DataTypeKindUINT, DataTypeKindUDINT, DataTypeKindULINT,
+		DataTypeKindBYTE, DataTypeKindWORD, DataTypeKindDWORD, DataTypeKindLWORD:
+		if lower < 0 || upper < 0 {
+			return nil, fmt.Errorf("plcopen: data type %q: negative bound of unsigned subrange", p.src)
+		}
+		return &DataType{SubrangeUnsigned: &DataTypeSubrangeUnsigned{
+			Range:    &RangeUnsigned{Lower: uint64(lower), Upper: uint64(upper)},
+			BaseType: base,
+		}}, nil
+	}
+	return nil, fmt.Errorf("plcopen: data type %q: subrange at offset %d of non-integer type %s", p.src, open.pos, base.IEC())
+}
+
+// enum parses the values (A, B := 5, C) of an enumeration with optional
+// base type
+func (p *typeParser) enum(base *DataType) (*DataType, error) {
+	p.next++
+	values := &DataTypeEnumValues{}
+	for {
+		tok := p.take()
+		if !isIdentifier(tok.text) {
+			return nil, p.errorf(tok, "enumeration value")
+		}
+		value := DataTypeEnumValuesValue{Name: tok.text}
+		if p.keyword(":=") {
+			lit := p.take()
+			if lit.text == "" || strings.ContainsAny(lit.text[:1], "[](),;:.'\"") {
+				return nil, p.errorf(lit, "value of "+tok.text)
+			}
+			value.Value = &lit.text
+		}
+		values.Values = append(values.Values, value)
+		if !p.keyword(",") {
+			break
+		}
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &DataType{Enum: &DataTypeEnum{Values: values, BaseType: base}}, nil
+}
+
+// structure parses the members of STRUCT ... END_STRUCT
+func (p *typeParser) structure() (*DataType, error) {
+	st := &VarListPlain{}
+	for !p.keyword("END_STRUCT") {
+		tok := p.take()
+		if !isIdentifier(tok.text) || isTypeKeyword(strings.ToUpper(tok.text)) {
+			return nil, p.errorf(tok, "member name or END_STRUCT")
+		}
+		v := VarListPlainVariable{Name: tok.text}
+		if p.keyword("AT") {
+			addr := p.take()
+			if !strings.HasPrefix(addr.text, "%") {
+				return nil, p.errorf(addr, "address")
+			}
+			v.Address = addr.text
+		}
+		if err := p.expect(":"); err != nil {
+			return nil, err
+		}
+		t, err := p.dataType()
+		if err != nil {
+			return nil, err
+		}
+		v.Type = t
+		if p.keyword(":=") {
+			if v.InitialValue, err = p.value(); err != nil {
+				return nil, err
+			}
+		}
+		if err := p.expect(";"); err != nil {
+			return nil, err
+		}
+		st.Variables = append(st.Variables, v)
+	}
+	return &DataType{Struct: st}, nil
+}
+
+// value parses an initial value in the syntax written by Value.String:
+// literals, arrays [1, 2, 3(0)] and structs (a := 1, b := 2)
+func (p *typeParser) value() (*Value, error) {
+	tok := p.take()
+	switch tok.text {
+	case "[":
+		array := &ValueArrayValue{}
+		for p.peek().text != "]" {
+			var elem ValueArrayValueValue
+			if p.peekAt(1).text == "(" {
+				count := p.take()
+				n, err := strconv.ParseUint(count.text, 10, 64)
+				if err != nil {
+					return nil, p.errorf(count, "repetition count")
+				}
+				p.next++
+				elem.RepeatCount = &n
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			elem.Value = v
+			if elem.RepeatCount != nil {
+				if err := p.expect(")"); err != nil {
+					return nil, err
+				}
+			}
+			array.Values = append(array.Values, elem)
+			if !p.keyword(",") {
+				break
+			}
+		}
+		return &Value{ArrayValue: array}, p.expect("]")
+	case "(":
+		st := &ValueStructValue{}
+		for p.peek().text != ")" {
+			name := p.take()
+			if !isIdentifier(name.text) {
+				return nil, p.errorf(name, "member name")
+			}
+			if err := p.expect(":="); err != nil {
+				return nil, err
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			st.Values = append(st.Values, ValueStructValueValue{Member: name.text, Value: v})
+			if !p.keyword(",") {
+				break
+			}
+		}
+		return &Value{StructValue: st}, p.expect(")")
+	}
+	switch tok.text {
+	case "", "]", ")", ",", ";", ":", ":=", "..":
+		return nil, p.errorf(tok, "value")
+	}
+	return &Value{SimpleValue: &ValueSimpleValue{Value: tok.text}}, nil
+}
@@ -0,0 +1,429 @@
+package plcopen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity is the severity of a Diagnostic
+type Severity string
+
+const (
+	// SeverityError marks projects that tools will reject or misinterpret
+	SeverityError Severity = "error"
+	// SeverityWarning marks suspicious but loadable content
+	SeverityWarning Severity = "warning"
+)
+
+// Rule IDs of the diagnostics reported by Project.Validate
+const (
+	RuleDuplicateName       = "duplicate-name"
+	RuleDuplicateLocalID    = "duplicate-local-id"
+	RuleDanglingConnection  = "dangling-connection"
+	RuleUndefinedType       = "undefined-type"
+	RuleUndefinedPOU        = "undefined-pou"
+	RuleInstanceNotProgram  = "instance-not-program"
+	RuleTaskWithoutTrigger  = "task-without-trigger"
+	RuleUndefinedLabel      = "undefined-label"
+	RuleUndefinedStep       = "undefined-step"
+	RuleUndefinedTransition = "undefined-transition"
+)
+
+// Diagnostic is a semantic problem found by Project.Validate
+type Diagnostic struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	// Path locates the object, e.g.
+	// "project/types/pous/pou[@name='Main']/body/FBD/block[@localId='3']"
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// String returns the diagnostic as "severity rule: path: message"
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s %s: %s: %s", d.Severity, d.Rule, d.Path, d.Message)
+}
+
+// standardFunctionBlocks are the IEC 61131-3 standard function blocks, which
+// may be used as variable types without being defined in the project
+var standardFunctionBlocks = []string{
+	"SR", "RS", "R_TRIG", "F_TRIG", "TP", "TON", "TOF",
+	"CTU", "CTU_INT", "CTU_DINT", "CTU_LINT", "CTU_UDINT", "CTU_ULINT",
+	"CTD", "CTD_INT", "CTD_DINT", "CTD_LINT", "CTD_UDINT", "CTD_ULINT",
+	"CTUD", "CTUD_INT", "CTUD_DINT", "CTUD_LINT", "CTUD_UDINT", "CTUD_ULINT",
+}
+
+// elementaryTypeNames are the elementary type names, some tools write them as derived types
+var elementaryTypeNames = []string{
+	"BOOL", "BYTE", "WORD", "DWORD", "LWORD", "SINT", "INT", "DINT", "LINT",
+	"USINT", "UINT", "UDINT", "ULINT", "REAL", "LREAL", "TIME", "DATE", "TOD",
+	"TIME_OF_DAY", "DT", "DATE_AND_TIME", "STRING", "WSTRING",
+}
+
+// Validate checks the project for problems the schema cannot express:
+// duplicate names and localIds, connections to missing objects, undefined
+// types, POUs, labels, steps and transitions, and tasks without trigger.
+// IEC 61131-3 identifiers are compared case-insensitively. Types from
+// libraries outside the project are reported as undefined; filter them by
+// rule if needed.
+func (p *Project) Validate() []Diagnostic {
+	v := &projectValidator{types: map[string]bool{}, pous: map[string]*ProjectTypesPOU{}}
+	for _, name := range standardFunctionBlocks {
+		v.types[name] = true
+	}
+	for _, name := range elementaryTypeNames {
+		v.types[name] = true
+	}
+	if p.Types != nil {
+		v.checkTypes(p.Types)
+	}
+	if p.Instances != nil {
+		v.checkInstances(p.Instances)
+	}
+	return v.diags
+}
+
+type projectValidator struct {
+	// types holds the upper case names usable as derived types
+	types map[string]bool
+	// pous holds the POUs by upper case name
+	pous  map[string]*ProjectTypesPOU
+	diags []Diagnostic
+}
+
+func (v *projectValidator) report(rule string, severity Severity, path, format string, args ...interface{}) {
+	v.diags = append(v.diags, Diagnostic{Rule: rule, Severity: severity, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// namePath returns the path of the child elem of path with the given name attribute
+func namePath(path, elem, name string) string {
+	return fmt.Sprintf("%s/%s[@name='%s']", path, elem, name)
+}
+
+// objectPath returns the path of the graphical object obj within the body at path
+func objectPath(path string, obj GraphicalObject) string {
+	return fmt.Sprintf("%s/%s[@localId='%d']", path, obj.ElementName(), obj.ObjectID())
+}
+
+// unique reports names already seen in the same scope
+type unique map[string]string
+
+func (u unique) check(v *projectValidator, path, name string) {
+	key := strings.ToUpper(name)
+	if first, ok := u[key]; ok {
+		v.report(RuleDuplicateName, SeverityError, path, "name %q is already used by %s", name, first)
+		return
+	}
+	u[key] = path
+}
+
+func (v *projectValidator) checkTypes(types *ProjectTypes) {
+	const base = "project/types"
+	// Data types and POUs share one namespace
+	names := unique{}
+	for _, dt := range types.DataTypes {
+		names.check(v, namePath(base+"/dataTypes", "dataType", dt.Name), dt.Name)
+		v.types[strings.ToUpper(dt.Name)] = true
+	}
+	for i := range types.POUs {
+		pou := &types.POUs[i]
+		names.check(v, namePath(base+"/pous", "pou", pou.Name), pou.Name)
+		v.types[strings.ToUpper(pou.Name)] = true
+		v.pous[strings.ToUpper(pou.Name)] = pou
+	}
+
+	for _, dt := range types.DataTypes {
+		v.checkDataType(namePath(base+"/dataTypes", "dataType", dt.Name)+"/baseType", dt.BaseType)
+	}
+	for i := range types.POUs {
+		v.checkPOU(namePath(base+"/pous", "pou", types.POUs[i].Name), &types.POUs[i])
+	}
+}
+
+// checkDataType reports derived types in t that are not defined
+func (v *projectValidator) checkDataType(path string, t *DataType) {
+	if t == nil {
+		return
+	}
+	switch {
+	case t.Derived != nil:
+		if !v.types[strings.ToUpper(t.Derived.Name)] {
+			v.report(RuleUndefinedType, SeverityError, path+"/derived", "type %q is not defined", t.Derived.Name)
+		}
+	case t.Array != nil:
+		v.checkDataType(path+"/array/baseType", t.Array.BaseType)
+	case t.Enum != nil:
+		v.checkDataType(path+"/enum/baseType", t.Enum.BaseType)
+	case t.Pointer != nil:
+		v.checkDataType(path+"/pointer/baseType", t.Pointer.BaseType)
+	case t.SubrangeSigned != nil:
+		v.checkDataType(path+"/subrangeSigned/baseType", t.SubrangeSigned.BaseType)
+	case t.SubrangeUnsigned != nil:
+		v.checkDataType(path+"/subrangeUnsigned/baseType", t.SubrangeUnsigned.BaseType)
+	case t.Struct != nil:
+		members := unique{}
+		for _, m := range t.Struct.Variables {
+			memberPath := namePath(path+"/struct", "variable", m.Name)
+			members.check(v, memberPath, m.Name)
+			v.checkDataType(memberPath+"/type", m.Type)
+		}
+	}
+}
+
+func (v *projectValidator) checkPOU(path string, pou *ProjectTypesPOU) {
+	if iface := pou.Interface; iface != nil {
+		v.checkDataType(path+"/interface/returnType", iface.ReturnType)
+		// All variables of a POU share one namespace
+		names := unique{}
+		for _, list := range []struct {
+			elem string
+			vars *VarList
+		}{
+			{"localVars", iface.LocalVars},
+			{"tempVars", iface.TempVars},
+			{"inputVars", iface.InputVars},
+			{"outputVars", iface.OutputVars},
+			{"inOutVars", iface.InOutVars},
+			{"externalVars", iface.ExternalVars},
+			{"globalVars", iface.GlobalVars},
+			{"accessVars", iface.AccessVars},
+		} {
+			v.checkVarList(path+"/interface/"+list.elem, list.vars, names)
+		}
+	}
+
+	actions := unique{}
+	for _, a := range pou.Actions {
+		actionPath := namePath(path+"/actions", "action", a.Name)
+		actions.check(v, actionPath, a.Name)
+		v.checkBody(actionPath+"/body", a.Body, pou)
+	}
+	transitions := unique{}
+	for _, t := range pou.Transitions {
+		transitionPath := namePath(path+"/transitions", "transition", t.Name)
+		transitions.check(v, transitionPath, t.Name)
+		v.checkBody(transitionPath+"/body", t.Body, pou)
+	}
+	v.checkBody(path+"/body", pou.Body, pou)
+}
+
+func (v *projectValidator) checkVarList(path string, list *VarList, names unique) {
+	if list == nil {
+		return
+	}
+	for _, variable := range list.Variables {
+		varPath := namePath(path, "variable", variable.Name)
+		names.check(v, varPath, variable.Name)
+		v.checkDataType(varPath+"/type", variable.Type)
+	}
+}
+
+// checkBody checks the graphical objects of a body and the bodies nested in
+// them, such as inline actions and transition conditions
+func (v *projectValidator) checkBody(path string, body *Body, pou *ProjectTypesPOU) {
+	if body == nil {
+		return
+	}
+	switch {
+	case body.FBD != nil:
+		v.checkObjects(path+"/FBD", body.FBD.Objects())
+		v.checkJumps(path+"/FBD", body.FBD.Jumps, body.FBD.Labels)
+		v.checkActionBlocks(path+"/FBD", body.FBD.ActionBlocks, pou)
+	case body.LD != nil:
+		v.checkObjects(path+"/LD", body.LD.Objects())
+		v.checkJumps(path+"/LD", body.LD.Jumps, body.LD.Labels)
+		v.checkActionBlocks(path+"/LD", body.LD.ActionBlocks, pou)
+	case body.SFC != nil:
+		sfc := body.SFC
+		path += "/SFC"
+		v.checkObjects(path, sfc.Objects())
+		v.checkJumps(path, sfc.Jumps, sfc.Labels)
+		v.checkSFC(path, sfc, pou)
+		v.checkActionBlocks(path, sfc.ActionBlocks, pou)
+		for i := range sfc.MacroSteps {
+			v.checkBody(objectPath(path, &sfc.MacroSteps[i])+"/body", sfc.MacroSteps[i].Body, pou)
+		}
+		for i := range sfc.Transitions {
+			t := &sfc.Transitions[i]
+			if t.Condition != nil && t.Condition.Inline != nil {
+				v.checkBody(objectPath(path, t)+"/condition/inline", t.Condition.Inline.Body, pou)
+			}
+		}
+	}
+}
+
+// checkActionBlocks checks the inline actions of action blocks like the
+// bodies of the POU
+func (v *projectValidator) checkActionBlocks(path string, blocks []BodyFBDActionBlock, pou *ProjectTypesPOU) {
+	for i := range blocks {
+		for j, a := range blocks[i].Actions {
+			if a.Inline != nil {
+				v.checkBody(fmt.Sprintf("%s/action[%d]/inline", objectPath(path, &blocks[i]), j+1), a.Inline.Body, pou)
+			}
+		}
+	}
+}
+
+// checkObjects reports duplicate localIds and connections to missing objects
+func (v *projectValidator) checkObjects(path string, objs []GraphicalObject) {
+	ids := map[uint64]GraphicalObject{}
+	for _, obj := range objs {
+		if first, ok := ids[obj.ObjectID()]; ok {
+			v.report(RuleDuplicateLocalID, SeverityError, objectPath(path, obj), "localId %d is already used by %s", obj.ObjectID(), first.ElementName())
+			continue
+		}
+		ids[obj.ObjectID()] = obj
+	}
+	for _, obj := range objs {
+		for _, c := range incomingConnections(obj) {
+			if _, ok := ids[c.RefLocalID]; !ok {
+				v.report(RuleDanglingConnection, SeverityError, objectPath(path, obj), "connection refers to missing localId %d", c.RefLocalID)
+			}
+		}
+	}
+}
+
+func (v *projectValidator) checkJumps(path string, jumps []BodyFBDJump, labels []BodyFBDLabel) {
+	defined := map[string]bool{}
+	for _, l := range labels {
+		defined[strings.ToUpper(l.Label)] = true
+	}
+	for i := range jumps {
+		if !defined[strings.ToUpper(jumps[i].Label)] {
+			v.report(RuleUndefinedLabel, SeverityError, objectPath(path, &jumps[i]), "label %q is not defined", jumps[i].Label)
+		}
+	}
+}
+
+func (v *projectValidator) checkSFC(path string, sfc *BodySFC, pou *ProjectTypesPOU) {
+	steps := map[string]bool{}
+	for _, s := range sfc.Steps {
+		steps[strings.ToUpper(s.Name)] = true
+	}
+	for _, s := range sfc.MacroSteps {
+		if s.Name != nil {
+			steps[strings.ToUpper(*s.Name)] = true
+		}
+	}
+	// A jump may also target a named simultaneous divergence
+	for _, d := range sfc.SimultaneousDivergences {
+		if d.Name != nil {
+			steps[strings.ToUpper(*d.Name)] = true
+		}
+	}
+	for i := range sfc.JumpSteps {
+		if !steps[strings.ToUpper(sfc.JumpSteps[i].TargetName)] {
+			v.report(RuleUndefinedStep, SeverityError, objectPath(path, &sfc.JumpSteps[i]), "step %q is not defined", sfc.JumpSteps[i].TargetName)
+		}
+	}
+
+	transitions := map[string]bool{}
+	for _, t := range pou.Transitions {
+		transitions[strings.ToUpper(t.Name)] = true
+	}
+	for i := range sfc.Transitions {
+		t := &sfc.Transitions[i]
+		if t.Condition != nil && t.Condition.Reference != nil && !transitions[strings.ToUpper(t.Condition.Reference.Name)] {
+			v.report(RuleUndefinedTransition, SeverityError, objectPath(path, t), "transition %q is not defined in the POU", t.Condition.Reference.Name)
+		}
+	}
+}
+
+// incomingConnections returns the connections of all input connection points of obj
+func incomingConnections(obj GraphicalObject) []Connection {
+	var conns []Connection
+	in := func(points ...*ConnectionPointIn) {
+		for _, p := range points {
+			if p != nil {
+				conns = append(conns, p.Connections...)
+			}
+		}
+	}
+	switch o := obj.(type) {
+	case *BodyFBDBlock:
+		for _, variable := range o.InputVariables {
+			in(variable.ConnectionPointIn)
+		}
+		for _, variable := range o.InOutVariables {
+			in(variable.ConnectionPointIn)
+		}
+	case *BodyFBDActionBlock:
+		in(o.ConnectionPointIn)
+	case *BodyFBDConnector:
+		in(o.ConnectionPointIn)
+	case *BodyFBDOutVariable:
+		in(o.ConnectionPointIn)
+	case *BodyFBDInOutVariable:
+		in(o.ConnectionPointIn)
+	case *BodyLDContact:
+		in(o.ConnectionPointIn)
+	case *BodyLDCoil:
+		in(o.ConnectionPointIn)
+	case *BodyLDRightPowerRail:
+		in(o.ConnectionPointIn)
+	case *BodySFCStep:
+		in((*ConnectionPointIn)(o.ConnectionPointIn))
+	case *BodySFCMacroStep:
+		in(o.ConnectionPointIn)
+	case *BodySFCJumpStep:
+		in(o.ConnectionPointIn)
+	case *BodySFCTransition:
+		in(o.ConnectionPointIn)
+	case *BodySFCSelectionDivergence:
+		in(o.ConnectionPointIn)
+	case *BodySFCSimultaneousDivergence:
+		in(o.ConnectionPointIn)
+	case *BodySFCSelectionConvergence:
+		for i := range o.ConnectionPointIn {
+			in(&o.ConnectionPointIn[i])
+		}
+	case *BodySFCSimultaneousConvergence:
+		for i := range o.ConnectionPointIn {
+			in(&o.ConnectionPointIn[i])
+		}
+	}
+	return conns
+}
+
+func (v *projectValidator) checkInstances(instances *ProjectInstances) {
+	configurations := unique{}
+	for _, c := range instances.Configurations {
+		configPath := namePath("project/instances/configurations", "configuration", c.Name)
+		configurations.check(v, configPath, c.Name)
+		v.checkVarList(configPath+"/globalVars", c.GlobalVars, unique{})
+
+		resources := unique{}
+		for _, r := range c.Resources {
+			resourcePath := namePath(configPath, "resource", r.Name)
+			resources.check(v, resourcePath, r.Name)
+			v.checkVarList(resourcePath+"/globalVars", r.GlobalVars, unique{})
+
+			tasks := unique{}
+			for _, t := range r.Tasks {
+				taskPath := namePath(resourcePath, "task", t.Name)
+				tasks.check(v, taskPath, t.Name)
+				if t.Interval == nil && t.Single == nil {
+					v.report(RuleTaskWithoutTrigger, SeverityError, taskPath, "task has neither interval nor single")
+				}
+				v.checkPOUInstances(taskPath, t.POUInstances)
+			}
+			v.checkPOUInstances(resourcePath, r.POUInstances)
+		}
+	}
+}
+
+// checkPOUInstances checks the program instances of a task or resource
+func (v *projectValidator) checkPOUInstances(path string, instances []POUInstance) {
+	names := unique{}
+	for _, inst := range instances {
+		instPath := namePath(path, "pouInstance", inst.Name)
+		names.check(v, instPath, inst.Name)
+		pou := v.pous[strings.ToUpper(inst.TypeName)]
+		switch {
+		case pou == nil:
+			v.report(RuleUndefinedPOU, SeverityError, instPath, "POU %q is not defined", inst.TypeName)
+		case pou.POUType != POUTypeProgram:
+			v.report(RuleInstanceNotProgram, SeverityWarning, instPath, "POU %q is a %s, resources run programs", inst.TypeName, pou.POUType)
+		}
+	}
+}
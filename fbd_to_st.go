@@ -0,0 +1,576 @@
+package plcopen
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// ToST translates the function block diagram to Structured Text, following
+// the data flow of the connections:
+//
+//   - output and in-out variables become assignments, set and reset
+//     variables IF statements
+//   - blocks with an instance name become calls of the function block
+//     instance with formal parameters, and their outputs are read as
+//     instance.output
+//   - other blocks become function calls, or operators for the standard
+//     functions such as ADD, AND or GT, inside the expressions that use them
+//   - rising and falling edges become R_TRIG and F_TRIG instances
+//   - comments are placed at the beginning
+//
+// Statements are ordered by the ExecutionOrderID of the objects, where set,
+// then in document order, and move ahead of the statements that use their
+// results. A function result used by several inputs is assigned to a
+// temporary variable once. The temporary variables and edge instances the
+// body needs are returned for declaration, with a nil Type if it cannot be
+// inferred from the diagram.
+//
+// Jumps, labels, returns and action blocks cannot be translated; they are
+// reported in the returned error along with connections to missing
+// objects, and the rest of the body is translated.
+func (b *BodyFBD) ToST() (*BodyST, []VarListVariable, error) {
+	return newDiagramTranslator("FBD", b.Objects()).translate()
+}
+
+// diagramTranslator translates the objects of a graphical body to ST
+type diagramTranslator struct {
+	path       string
+	objs       []GraphicalObject
+	byID       map[uint64]GraphicalObject
+	index      map[uint64]int // document order
+	connectors map[string]*BodyFBDConnector
+	// uses counts the inputs connected to each output, through
+	// connectors and continuations
+	uses map[outputPin]int
+	// nodes holds the statements of objects by localId
+	nodes map[uint64]*diagramNode
+	trigs map[uint64]*diagramNode
+	// values holds the parsed expressions of variables by localId
+	values map[uint64]st.Expr
+	// shared holds the power flows after contacts used several times
+	shared map[uint64]*diagramNode
+	// coilVars holds the upper case variables written by coils
+	coilVars map[string]bool
+	// cur is the node whose statements are being built
+	cur       *diagramNode
+	expanding map[uint64]bool
+	vars      []VarListVariable
+	comments  []string
+	errs      []error
+}
+
+// outputPin is an output of an object; param is the upper case formal
+// parameter of block outputs, empty for other objects
+type outputPin struct {
+	id    uint64
+	param string
+}
+
+// diagramNode is a group of statements, ordered by execution order and
+// data dependencies
+type diagramNode struct {
+	obj   GraphicalObject
+	order uint64  // ExecutionOrderID, 0 if not set
+	temp  string  // variable holding the result of a function or edge
+	value st.Expr // shared power flow, read from temp if set
+	stmts []st.Stmt
+	deps  []*diagramNode
+	state int // 0 not emitted, 1 emitting, 2 emitted
+}
+
+func newDiagramTranslator(path string, objs []GraphicalObject) *diagramTranslator {
+	t := &diagramTranslator{
+		path:       path,
+		objs:       objs,
+		byID:       map[uint64]GraphicalObject{},
+		index:      map[uint64]int{},
+		connectors: map[string]*BodyFBDConnector{},
+		uses:       map[outputPin]int{},
+		nodes:      map[uint64]*diagramNode{},
+		trigs:      map[uint64]*diagramNode{},
+		values:     map[uint64]st.Expr{},
+		shared:     map[uint64]*diagramNode{},
+		expanding:  map[uint64]bool{},
+	}
+	for i, obj := range objs {
+		t.byID[obj.ObjectID()] = obj
+		t.index[obj.ObjectID()] = i
+		if c, ok := obj.(*BodyFBDConnector); ok {
+			t.connectors[strings.ToUpper(c.Name)] = c
+		}
+	}
+	return t
+}
+
+func (t *diagramTranslator) errorf(obj GraphicalObject, format string, args ...interface{}) {
+	t.errs = append(t.errs, fmt.Errorf("plcopen: %s: %s", objectPath(t.path, obj), fmt.Sprintf(format, args...)))
+}
+
+// translate returns the ST of the objects, the variables it needs and the
+// objects that could not be translated
+func (t *diagramTranslator) translate() (*BodyST, []VarListVariable, error) {
+	t.countUses()
+	var nodes []*diagramNode
+	for _, obj := range t.objs {
+		if n := t.newNode(obj); n != nil {
+			t.nodes[obj.ObjectID()] = n
+			nodes = append(nodes, n)
+		}
+	}
+	for _, n := range nodes {
+		t.cur = n
+		t.build(n)
+	}
+	t.cur = nil
+
+	sort.SliceStable(nodes, func(i, j int) bool { return t.less(nodes[i], nodes[j]) })
+	body := &st.Body{}
+	var emit func(n *diagramNode)
+	emit = func(n *diagramNode) {
+		if n.state != 0 {
+			// Emitted, or a feedback loop that reads the previous values
+			return
+		}
+		n.state = 1
+		for _, d := range n.deps {
+			if d.order == 0 || n.order == 0 {
+				emit(d)
+			}
+		}
+		n.state = 2
+		body.Stmts = append(body.Stmts, n.stmts...)
+	}
+	for _, n := range nodes {
+		emit(n)
+	}
+
+	var sb strings.Builder
+	for _, c := range t.comments {
+		sb.WriteString("(* " + strings.ReplaceAll(c, "*)", "* )") + " *)\n")
+	}
+	st.Fprint(&sb, body)
+	text := NewFormattedText(strings.TrimSuffix(sb.String(), "\n"))
+	return &BodyST{Xhtml: text}, t.vars, errors.Join(t.errs...)
+}
+
+// less orders nodes by execution order, then by document order
+func (t *diagramTranslator) less(a, b *diagramNode) bool {
+	oa, ob := a.order, b.order
+	if oa == 0 {
+		oa = math.MaxUint64
+	}
+	if ob == 0 {
+		ob = math.MaxUint64
+	}
+	if oa != ob {
+		return oa < ob
+	}
+	return t.index[a.obj.ObjectID()] < t.index[b.obj.ObjectID()]
+}
+
+// countUses counts the connections to each output
+func (t *diagramTranslator) countUses() {
+	continued := map[string]int{}
+	for _, obj := range t.objs {
+		for _, c := range incomingConnections(obj) {
+			src := t.byID[c.RefLocalID]
+			if cont, ok := src.(*BodyFBDContinuation); ok {
+				continued[strings.ToUpper(cont.Name)]++
+				continue
+			}
+			t.uses[t.pin(src, c.FormalParameter)]++
+		}
+	}
+	// A connector counts once for each use of its continuations
+	for name, n := range continued {
+		if c, ok := t.connectors[name]; ok && c.ConnectionPointIn != nil {
+			for _, conn := range c.ConnectionPointIn.Connections {
+				t.uses[t.pin(t.byID[conn.RefLocalID], conn.FormalParameter)] += n - 1
+			}
+		}
+	}
+}
+
+// pin returns the output of src a connection refers to
+func (t *diagramTranslator) pin(src GraphicalObject, formal *string) outputPin {
+	b, ok := src.(*BodyFBDBlock)
+	if !ok {
+		if src == nil {
+			return outputPin{}
+		}
+		return outputPin{id: src.ObjectID()}
+	}
+	if formal != nil {
+		return outputPin{id: b.LocalID, param: strings.ToUpper(*formal)}
+	}
+	return outputPin{id: b.LocalID, param: strings.ToUpper(defaultOutput(b))}
+}
+
+// newNode returns the node of the statements of obj, nil if obj only
+// provides values to other objects
+func (t *diagramTranslator) newNode(obj GraphicalObject) *diagramNode {
+	n := &diagramNode{obj: obj}
+	switch o := obj.(type) {
+	case *BodyFBDOutVariable:
+		n.order = orderOf(o.ExecutionOrderID)
+	case *BodyFBDInOutVariable:
+		n.order = orderOf(o.ExecutionOrderID)
+	case *BodyFBDBlock:
+		n.order = orderOf(o.ExecutionOrderID)
+		if o.InstanceName == nil {
+			result := defaultOutput(o)
+			switch uses := t.uses[outputPin{id: o.LocalID, param: strings.ToUpper(result)}]; {
+			case result == "" || uses == 0:
+				// Called for its side effects
+			case uses > 1:
+				n.temp = fmt.Sprintf("%s_%d", o.TypeName, o.LocalID)
+				t.declare(n.temp, resultType(o.TypeName))
+			default:
+				// Inlined in the expression that uses it
+				return nil
+			}
+		}
+	case *BodyLDCoil:
+	case *BodyFBDComment:
+		t.comments = append(t.comments, strings.TrimSpace(o.Content.PlainText()))
+		return nil
+	case *BodyFBDJump, *BodyFBDLabel, *BodyFBDReturn, *BodyFBDActionBlock:
+		t.errorf(obj, "%s cannot be translated to ST", obj.ElementName())
+		return nil
+	default:
+		return nil
+	}
+	return n
+}
+
+func orderOf(id *uint64) uint64 {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+func (t *diagramTranslator) declare(name string, typ *DataType) {
+	t.vars = append(t.vars, VarListVariable{Name: name, Type: typ})
+}
+
+// build sets the statements of n
+func (t *diagramTranslator) build(n *diagramNode) {
+	switch o := n.obj.(type) {
+	case *BodyFBDOutVariable:
+		n.stmts = t.store(o, o.Expression, t.input(o, o.ConnectionPointIn), o.EdgeModifier, o.StorageModifier)
+	case *BodyFBDInOutVariable:
+		n.stmts = t.store(o, o.Expression, t.input(o, o.ConnectionPointIn), o.EdgeModifier, o.StorageModifier)
+	case *BodyLDCoil:
+		n.stmts = t.coil(o)
+	case *BodyFBDBlock:
+		t.expanding[o.LocalID] = true
+		defer delete(t.expanding, o.LocalID)
+		switch {
+		case o.InstanceName != nil:
+			call := &st.CallExpr{Fun: &st.Ident{Name: *o.InstanceName}, Args: t.args(o)}
+			n.stmts = []st.Stmt{&st.CallStmt{Call: call}}
+		case n.temp != "":
+			n.stmts = []st.Stmt{&st.AssignStmt{Target: &st.Ident{Name: n.temp}, Value: t.function(o)}}
+		default:
+			call := &st.CallExpr{Fun: &st.Ident{Name: o.TypeName}, Args: t.args(o)}
+			n.stmts = []st.Stmt{&st.CallStmt{Call: call}}
+		}
+	}
+}
+
+// store returns the statements that store value in the variable expr, nil
+// if value is nil
+func (t *diagramTranslator) store(obj GraphicalObject, expr string, value st.Expr, edge *EdgeModifierType, storage *StorageModifierType) []st.Stmt {
+	if value == nil {
+		return nil
+	}
+	var stmts []st.Stmt
+	if call, q := t.trigger(obj, edge, value); call != nil {
+		stmts = append(stmts, call)
+		value = q
+	}
+	target := t.variable(obj, expr)
+	if storage != nil && *storage != StorageModifierTypeNone {
+		set := &st.Literal{Kind: st.BoolLit, Value: "TRUE"}
+		if *storage == StorageModifierTypeReset {
+			set.Value = "FALSE"
+		}
+		return append(stmts, &st.IfStmt{Cond: value, Then: []st.Stmt{&st.AssignStmt{Target: target, Value: set}}})
+	}
+	return append(stmts, &st.AssignStmt{Target: target, Value: value})
+}
+
+// trigger returns the call of an R_TRIG or F_TRIG instance that detects
+// the edge of x and its output Q, or nil if edge is not set
+func (t *diagramTranslator) trigger(obj GraphicalObject, edge *EdgeModifierType, x st.Expr) (st.Stmt, st.Expr) {
+	if edge == nil || *edge == EdgeModifierTypeNone {
+		return nil, nil
+	}
+	fb := "R_TRIG"
+	if *edge == EdgeModifierTypeFalling {
+		fb = "F_TRIG"
+	}
+	name := fmt.Sprintf("%s_%d", fb, obj.ObjectID())
+	t.declare(name, &DataType{Derived: &DataTypeDerived{Name: fb}})
+	clk := &st.Arg{Name: &st.Ident{Name: "CLK"}, Op: st.ASSIGN, Value: x}
+	call := &st.CallStmt{Call: &st.CallExpr{Fun: &st.Ident{Name: name}, Args: []*st.Arg{clk}}}
+	return call, &st.SelectorExpr{X: &st.Ident{Name: name}, Sel: &st.Ident{Name: "Q"}}
+}
+
+// edge returns x, or the output of an R_TRIG or F_TRIG instance detecting
+// the edge of x that obj reads. The instance is called once, before the
+// statements reading it.
+func (t *diagramTranslator) edge(obj GraphicalObject, edge *EdgeModifierType, x st.Expr) st.Expr {
+	if edge == nil || *edge == EdgeModifierTypeNone {
+		return x
+	}
+	n, ok := t.trigs[obj.ObjectID()]
+	if !ok {
+		n = &diagramNode{obj: obj}
+		call, q := t.trigger(obj, edge, x)
+		n.stmts = []st.Stmt{call}
+		n.temp = q.(*st.SelectorExpr).X.(*st.Ident).Name
+		t.trigs[obj.ObjectID()] = n
+	}
+	t.depend(n)
+	return &st.SelectorExpr{X: &st.Ident{Name: n.temp}, Sel: &st.Ident{Name: "Q"}}
+}
+
+// depend records that the node being built reads the results of n
+func (t *diagramTranslator) depend(n *diagramNode) {
+	if n != nil && n != t.cur {
+		t.cur.deps = append(t.cur.deps, n)
+	}
+}
+
+// input returns the value of an input connection point, the OR of all
+// connected outputs, or nil if nothing is connected
+func (t *diagramTranslator) input(obj GraphicalObject, in *ConnectionPointIn) st.Expr {
+	if in == nil {
+		return nil
+	}
+	if len(in.Connections) == 0 {
+		if in.Expression != nil {
+			return t.parseExpr(obj, *in.Expression)
+		}
+		return nil
+	}
+	var x st.Expr
+	for _, c := range in.Connections {
+		y := t.output(obj, c)
+		switch {
+		case y == nil:
+		case x == nil:
+			x = y
+		default:
+			x = &st.BinaryExpr{X: x, Op: st.OR, Y: y}
+		}
+	}
+	return x
+}
+
+// output returns the value of the output connection c of obj refers to
+func (t *diagramTranslator) output(obj GraphicalObject, c Connection) st.Expr {
+	src, ok := t.byID[c.RefLocalID]
+	if !ok {
+		t.errorf(obj, "connection refers to missing localId %d", c.RefLocalID)
+		return nil
+	}
+	switch src := src.(type) {
+	case *BodyFBDInVariable:
+		return t.edge(src, src.EdgeModifier, t.variable(src, src.Expression))
+	case *BodyFBDInOutVariable:
+		t.depend(t.nodes[src.LocalID])
+		return t.variable(src, src.Expression)
+	case *BodyFBDContinuation:
+		return t.continuation(src)
+	case *BodyFBDBlock:
+		return t.blockOutput(obj, src, c.FormalParameter)
+	case *BodyLDLeftPowerRail:
+		return power()
+	case *BodyLDContact:
+		return t.sharedContact(src)
+	case *BodyLDCoil:
+		return t.coilOutput(src)
+	}
+	t.errorf(obj, "%s has no output", src.ElementName())
+	return nil
+}
+
+// continuation returns the value connected to the connector of the same name
+func (t *diagramTranslator) continuation(cont *BodyFBDContinuation) st.Expr {
+	c, ok := t.connectors[strings.ToUpper(cont.Name)]
+	if !ok {
+		t.errorf(cont, "connector %q is not defined", cont.Name)
+		return nil
+	}
+	if t.expanding[c.LocalID] {
+		t.errorf(cont, "connector %q is connected to itself", cont.Name)
+		return nil
+	}
+	t.expanding[c.LocalID] = true
+	defer delete(t.expanding, c.LocalID)
+	return t.input(c, c.ConnectionPointIn)
+}
+
+// blockOutput returns the value of the output param of b, the default
+// output if nil
+func (t *diagramTranslator) blockOutput(obj GraphicalObject, b *BodyFBDBlock, param *string) st.Expr {
+	name := defaultOutput(b)
+	if param != nil {
+		name = *param
+	}
+	for _, v := range b.InOutVariables {
+		if strings.EqualFold(v.FormalParameter, name) {
+			// The output of an in-out is the variable connected to its input
+			t.depend(t.nodes[b.LocalID])
+			return t.input(b, v.ConnectionPointIn)
+		}
+	}
+	if !hasOutput(b, name) {
+		t.errorf(obj, "%s has no output %q", b.TypeName, name)
+		return nil
+	}
+	if b.InstanceName != nil {
+		t.depend(t.nodes[b.LocalID])
+		return &st.SelectorExpr{X: &st.Ident{Name: *b.InstanceName}, Sel: &st.Ident{Name: name}}
+	}
+	if !strings.EqualFold(name, defaultOutput(b)) {
+		t.errorf(obj, "only the result of function %s can be used in ST, not %s", b.TypeName, name)
+		return nil
+	}
+	if n := t.nodes[b.LocalID]; n != nil && n.temp != "" {
+		t.depend(n)
+		return &st.Ident{Name: n.temp}
+	}
+	if t.expanding[b.LocalID] {
+		t.errorf(b, "feedback loop without a variable or function block instance")
+		return nil
+	}
+	t.expanding[b.LocalID] = true
+	defer delete(t.expanding, b.LocalID)
+	return t.function(b)
+}
+
+// defaultOutput returns the first output of b other than ENO, the result
+// of a function
+func defaultOutput(b *BodyFBDBlock) string {
+	for _, v := range b.OutputVariables {
+		if !strings.EqualFold(v.FormalParameter, "ENO") {
+			return v.FormalParameter
+		}
+	}
+	return ""
+}
+
+func hasOutput(b *BodyFBDBlock, name string) bool {
+	for _, v := range b.OutputVariables {
+		if strings.EqualFold(v.FormalParameter, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// args returns the connected inputs and in-outs of b as formal parameters
+func (t *diagramTranslator) args(b *BodyFBDBlock) []*st.Arg {
+	var args []*st.Arg
+	add := func(name string, in *ConnectionPointIn) {
+		if x := t.input(b, in); x != nil {
+			args = append(args, &st.Arg{Name: &st.Ident{Name: name}, Op: st.ASSIGN, Value: x})
+		}
+	}
+	for _, v := range b.InputVariables {
+		add(v.FormalParameter, v.ConnectionPointIn)
+	}
+	for _, v := range b.InOutVariables {
+		add(v.FormalParameter, v.ConnectionPointIn)
+	}
+	return args
+}
+
+// blockOperators are the standard functions written as ST operators
+var blockOperators = map[string]st.Token{
+	"ADD": st.ADD, "MUL": st.MUL, "AND": st.AND, "OR": st.OR, "XOR": st.XOR,
+	"SUB": st.SUB, "DIV": st.DIV, "MOD": st.MOD, "EXPT": st.POW,
+	"GT": st.GTR, "GE": st.GEQ, "EQ": st.EQL, "NE": st.NEQ, "LE": st.LEQ, "LT": st.LSS,
+}
+
+// extensible are the operators of blocks with any number of inputs
+var extensible = map[string]bool{"ADD": true, "MUL": true, "AND": true, "OR": true, "XOR": true}
+
+// function returns the call of the function b, written as operator if b is
+// a standard function whose inputs are all connected
+func (t *diagramTranslator) function(b *BodyFBDBlock) st.Expr {
+	args := t.args(b)
+	name := strings.ToUpper(b.TypeName)
+	operands := len(b.InputVariables) == len(args) && len(b.InOutVariables) == 0
+	for _, a := range args {
+		if strings.EqualFold(a.Name.Name, "EN") {
+			operands = false
+		}
+	}
+	switch {
+	case !operands:
+	case len(args) == 1 && name == "NOT":
+		return &st.UnaryExpr{Op: st.NOT, X: args[0].Value}
+	case len(args) == 1 && name == "NEG":
+		return &st.UnaryExpr{Op: st.SUB, X: args[0].Value}
+	case len(args) == 1 && name == "MOVE":
+		return args[0].Value
+	case len(args) == 2 || len(args) > 2 && extensible[name]:
+		op, ok := blockOperators[name]
+		if !ok {
+			break
+		}
+		x := args[0].Value
+		for _, a := range args[1:] {
+			x = &st.BinaryExpr{X: x, Op: op, Y: a.Value}
+		}
+		return x
+	}
+	return &st.CallExpr{Fun: &st.Ident{Name: b.TypeName}, Args: args}
+}
+
+// resultType returns the result type of a standard function, nil if unknown
+func resultType(function string) *DataType {
+	name := strings.ToUpper(function)
+	switch name {
+	case "GT", "GE", "EQ", "NE", "LE", "LT":
+		return &DataType{BOOL: &struct{}{}}
+	}
+	if _, to, ok := strings.Cut(name, "_TO_"); ok {
+		if t, err := ParseDataType(to); err == nil {
+			return t
+		}
+	}
+	return nil
+}
+
+// variable returns the parsed expression of a variable object
+func (t *diagramTranslator) variable(obj GraphicalObject, expr string) st.Expr {
+	x, ok := t.values[obj.ObjectID()]
+	if !ok {
+		x = t.parseExpr(obj, expr)
+		t.values[obj.ObjectID()] = x
+	}
+	return x
+}
+
+// parseExpr parses the expression of a variable or connection point. An
+// invalid expression is reported and kept as written.
+func (t *diagramTranslator) parseExpr(obj GraphicalObject, src string) st.Expr {
+	src = strings.TrimSpace(src)
+	x, err := st.ParseExpr(src)
+	if err != nil {
+		t.errorf(obj, "expression %q: %v", src, err)
+		return &st.Ident{Name: src}
+	}
+	return x
+}
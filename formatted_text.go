@@ -0,0 +1,414 @@
+package plcopen
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// XHTMLNamespace is the namespace of the content of formattedText elements
+const XHTMLNamespace = "http://www.w3.org/1999/xhtml"
+
+// FormattedText represents the formattedText type of the schema: XHTML markup
+// in the http://www.w3.org/1999/xhtml namespace. The markup is kept in a
+// canonical form that always uses the "xhtml" prefix, regardless of the
+// prefix or default namespace used by the document it was decoded from.
+// Elements and attributes of other namespaces, such as xml:lang, keep their
+// namespace and prefix.
+type FormattedText struct {
+	markup string
+}
+
+// NewFormattedText returns a formatted text holding text as a single
+// <xhtml:p> paragraph
+func NewFormattedText(text string) *FormattedText {
+	var sb strings.Builder
+	sb.WriteString("<xhtml:p>")
+	xml.EscapeText(&sb, []byte(text))
+	sb.WriteString("</xhtml:p>")
+	return &FormattedText{markup: unescapeNewlines(sb.String())}
+}
+
+// ParseFormattedText parses XHTML markup such as "<xhtml:p>text</xhtml:p>" or
+// "<p>text</p>". Elements without a prefix are taken from the XHTML namespace.
+func ParseFormattedText(markup string) (*FormattedText, error) {
+	wrapped := `<root xmlns="` + XHTMLNamespace + `" xmlns:xhtml="` + XHTMLNamespace + `">` + markup + `</root>`
+	d := xml.NewDecoder(strings.NewReader(wrapped))
+	start, err := nextStart(d)
+	if err != nil {
+		return nil, err
+	}
+	ft := &FormattedText{}
+	if err := ft.UnmarshalXML(d, start); err != nil {
+		return nil, err
+	}
+	return ft, nil
+}
+
+// MustParseFormattedText is like ParseFormattedText but panics on malformed markup
+func MustParseFormattedText(markup string) *FormattedText {
+	ft, err := ParseFormattedText(markup)
+	if err != nil {
+		panic(err)
+	}
+	return ft
+}
+
+// XHTML returns the XHTML markup using the "xhtml" prefix, without namespace
+// declarations except those of elements and attributes of other namespaces
+func (f *FormattedText) XHTML() string {
+	if f == nil {
+		return ""
+	}
+	return f.markup
+}
+
+// PlainText returns the text content of the markup. Line breaks and the ends
+// of block elements such as paragraphs become newlines.
+func (f *FormattedText) PlainText() string {
+	if f == nil || f.markup == "" {
+		return ""
+	}
+	d := xml.NewDecoder(strings.NewReader("<root>" + f.markup + "</root>"))
+	var sb strings.Builder
+	for {
+		tok, err := d.RawToken()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			sb.Write(t)
+		case xml.StartElement:
+			if t.Name.Local == "br" {
+				sb.WriteByte('\n')
+			}
+		case xml.EndElement:
+			if isBlockElement(t.Name.Local) {
+				sb.WriteByte('\n')
+			}
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// String returns the plain text of the formatted text
+func (f *FormattedText) String() string {
+	return f.PlainText()
+}
+
+// isPlainParagraph reports whether the markup is what NewFormattedText
+// produces for its plain text
+func (f *FormattedText) isPlainParagraph() bool {
+	return f.markup == NewFormattedText(f.PlainText()).markup
+}
+
+func isBlockElement(local string) bool {
+	switch local {
+	case "p", "div", "li", "tr", "h1", "h2", "h3", "h4", "h5", "h6", "pre":
+		return true
+	}
+	return false
+}
+
+// UnmarshalXML decodes the XHTML content of start into canonical markup.
+// Text outside of any element, as written by some older tools, is wrapped
+// into a single paragraph. Elements without a namespace or in that of start
+// are taken as XHTML. Elements and attributes of other namespaces keep them:
+// they are written with their original prefix and declare it.
+func (f *FormattedText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var sb strings.Builder
+	var text strings.Builder
+	depth := 0
+	hasElements := false
+	// in holds the prefixes declared in the input, out those declared in
+	// the markup, both by namespace for each open element
+	var in, out []map[string]string
+	outer := map[string]string{}
+	for _, attr := range start.Attr {
+		if attr.Name.Space == "xmlns" {
+			outer[attr.Value] = attr.Name.Local
+		}
+	}
+	in = append(in, outer)
+	lookup := func(scopes []map[string]string, space string) string {
+		for i := len(scopes) - 1; i >= 0; i-- {
+			if prefix, ok := scopes[i][space]; ok {
+				return prefix
+			}
+		}
+		return ""
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			hasElements = true
+			depth++
+			declared := map[string]string{}
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" {
+					declared[attr.Value] = attr.Name.Local
+				}
+			}
+			in = append(in, declared)
+			out = append(out, map[string]string{})
+			// prefix returns the prefix of a foreign namespace, declaring it
+			// on the element if it is not in scope
+			var decls []string
+			prefix := func(space string) string {
+				if p := lookup(out, space); p != "" {
+					return p
+				}
+				p := lookup(in, space)
+				if p == "" || p == "xhtml" || p == "xml" {
+					p = "ns" + strconv.Itoa(len(decls)+1)
+				}
+				out[len(out)-1][space] = p
+				decls = append(decls, p, space)
+				return p
+			}
+			sb.WriteByte('<')
+			if isXHTMLSpace(t.Name.Space, start.Name.Space) {
+				sb.WriteString("xhtml:")
+			} else {
+				sb.WriteString(prefix(t.Name.Space) + ":")
+			}
+			sb.WriteString(t.Name.Local)
+			var attrs strings.Builder
+			for _, attr := range t.Attr {
+				if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" {
+					continue
+				}
+				attrs.WriteByte(' ')
+				switch attr.Name.Space {
+				case "":
+				case xmlNamespace:
+					attrs.WriteString("xml:")
+				case XHTMLNamespace:
+					attrs.WriteString("xhtml:")
+				default:
+					attrs.WriteString(prefix(attr.Name.Space) + ":")
+				}
+				attrs.WriteString(attr.Name.Local)
+				attrs.WriteString(`="`)
+				xml.EscapeText(&attrs, []byte(attr.Value))
+				attrs.WriteByte('"')
+			}
+			for i := 0; i < len(decls); i += 2 {
+				sb.WriteString(" xmlns:" + decls[i] + `="`)
+				xml.EscapeText(&sb, []byte(decls[i+1]))
+				sb.WriteByte('"')
+			}
+			sb.WriteString(attrs.String())
+			sb.WriteByte('>')
+		case xml.EndElement:
+			if depth == 0 {
+				switch {
+				case hasElements:
+					f.markup = collapseEmptyElements(sb.String())
+				case strings.TrimSpace(text.String()) != "":
+					f.markup = NewFormattedText(text.String()).markup
+				default:
+					f.markup = ""
+				}
+				return nil
+			}
+			depth--
+			sb.WriteString("</")
+			if isXHTMLSpace(t.Name.Space, start.Name.Space) {
+				sb.WriteString("xhtml:")
+			} else {
+				sb.WriteString(lookup(out, t.Name.Space) + ":")
+			}
+			sb.WriteString(t.Name.Local)
+			sb.WriteByte('>')
+			in, out = in[:len(in)-1], out[:len(out)-1]
+		case xml.CharData:
+			if depth == 0 {
+				text.Write(t)
+				// Indentation between top-level elements is not content
+				if strings.TrimSpace(string(t)) == "" {
+					continue
+				}
+			}
+			var escaped strings.Builder
+			xml.EscapeText(&escaped, t)
+			sb.WriteString(unescapeNewlines(escaped.String()))
+		}
+	}
+}
+
+// isXHTMLSpace reports whether an element in namespace space is taken as
+// XHTML inside a formatted text element in namespace parent
+func isXHTMLSpace(space, parent string) bool {
+	return space == "" || space == XHTMLNamespace || space == parent
+}
+
+// MarshalXML encodes the formatted text as start, declaring the xhtml prefix
+// and writing the markup as <xhtml:...> children
+func (f FormattedText) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:xhtml"}, Value: XHTMLNamespace})
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	d := xml.NewDecoder(strings.NewReader("<root>" + f.markup + "</root>"))
+	if _, err := d.RawToken(); err != nil {
+		return err
+	}
+	depth := 0
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			out := xml.StartElement{Name: prefixedName(t.Name)}
+			for _, attr := range t.Attr {
+				out.Attr = append(out.Attr, xml.Attr{Name: prefixedName(attr.Name), Value: attr.Value})
+			}
+			err = e.EncodeToken(out)
+		case xml.EndElement:
+			if depth == 0 {
+				continue // closing dummy root
+			}
+			depth--
+			err = e.EncodeToken(xml.EndElement{Name: prefixedName(t.Name)})
+		case xml.CharData:
+			err = e.EncodeToken(t)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// MarshalJSON encodes a single plain paragraph as a JSON string and any other
+// markup as {"xhtml": "..."}. Text that starts like markup is written as
+// markup as well, since strings starting with "<xhtml:" are decoded as markup.
+func (f FormattedText) MarshalJSON() ([]byte, error) {
+	if f.isPlainParagraph() && !looksLikeMarkup(f.PlainText()) {
+		return marshalJSON(f.PlainText())
+	}
+	return marshalJSON(struct {
+		XHTML string `json:"xhtml"`
+	}{f.markup})
+}
+
+// UnmarshalJSON accepts the forms written by MarshalJSON. A string starting
+// with an <xhtml:...> element is taken as markup, as ST and IL bodies were
+// written as raw markup strings by versions before FormattedText.
+func (f *FormattedText) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*f = *formattedTextFromString(text)
+		return nil
+	}
+	var obj struct {
+		XHTML *string `json:"xhtml"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	if obj.XHTML == nil {
+		return errors.New("plcopen: formatted text object without xhtml member")
+	}
+	parsed, err := ParseFormattedText(*obj.XHTML)
+	if err != nil {
+		return err
+	}
+	*f = *parsed
+	return nil
+}
+
+// formattedTextFromString returns the formatted text of a JSON string, which
+// holds markup if it starts with an <xhtml:...> element. Strings that only
+// look like markup but do not parse are taken as plain text.
+func formattedTextFromString(s string) *FormattedText {
+	if looksLikeMarkup(s) {
+		if ft, err := ParseFormattedText(s); err == nil {
+			return ft
+		}
+	}
+	return NewFormattedText(s)
+}
+
+func looksLikeMarkup(s string) bool {
+	return strings.HasPrefix(strings.TrimSpace(s), "<xhtml:")
+}
+
+// prefixedName turns a raw token name into an "xhtml:" prefixed local name
+func prefixedName(name xml.Name) xml.Name {
+	if name.Space != "" {
+		return xml.Name{Local: name.Space + ":" + name.Local}
+	}
+	return name
+}
+
+// nextStart returns the next start element of d
+func nextStart(d *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+// unescapeNewlines undoes the escaping of newlines and tabs done by
+// xml.EscapeText, which is not needed in element content
+func unescapeNewlines(s string) string {
+	return strings.NewReplacer("&#xA;", "\n", "&#x9;", "\t", "&#xD;", "\r").Replace(s)
+}
+
+// collapseEmptyElements rewrites "<xhtml:br></xhtml:br>" as "<xhtml:br/>"
+func collapseEmptyElements(s string) string {
+	return strings.ReplaceAll(s, "<xhtml:br></xhtml:br>", "<xhtml:br/>")
+}
+
+// UnmarshalXML decodes the formatted text content of the ST body
+func (b *BodyST) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	b.XMLNSXhtml = XHTMLNamespace
+	b.Xhtml = &FormattedText{}
+	return b.Xhtml.UnmarshalXML(d, start)
+}
+
+// MarshalXML encodes the ST body as formatted text
+func (b BodyST) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var text FormattedText
+	if b.Xhtml != nil {
+		text = *b.Xhtml
+	}
+	return text.MarshalXML(e, start)
+}
+
+// UnmarshalXML decodes the formatted text content of the IL body
+func (b *BodyIL) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	b.XMLNSXhtml = XHTMLNamespace
+	b.Xhtml = &FormattedText{}
+	return b.Xhtml.UnmarshalXML(d, start)
+}
+
+// MarshalXML encodes the IL body as formatted text
+func (b BodyIL) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	var text FormattedText
+	if b.Xhtml != nil {
+		text = *b.Xhtml
+	}
+	return text.MarshalXML(e, start)
+}
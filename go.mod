@@ -1,3 +1,5 @@
-module github.com/suifei/plcopen-go
+module github.com/suifei/plcopen-go/v2
 
-go 1.22.3
+go 1.23
+
+require golang.org/x/text v0.21.0
@@ -0,0 +1,203 @@
+package il
+
+import (
+	"strings"
+
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// Operator is an IL operator without its modifiers
+type Operator string
+
+const (
+	LD  Operator = "LD"
+	ST  Operator = "ST"
+	S   Operator = "S"
+	R   Operator = "R"
+	NOT Operator = "NOT"
+	AND Operator = "AND"
+	OR  Operator = "OR"
+	XOR Operator = "XOR"
+	ADD Operator = "ADD"
+	SUB Operator = "SUB"
+	MUL Operator = "MUL"
+	DIV Operator = "DIV"
+	MOD Operator = "MOD"
+	GT  Operator = "GT"
+	GE  Operator = "GE"
+	EQ  Operator = "EQ"
+	NE  Operator = "NE"
+	LE  Operator = "LE"
+	LT  Operator = "LT"
+	JMP Operator = "JMP"
+	CAL Operator = "CAL"
+	RET Operator = "RET"
+
+	// Function block input operators set the input of the same name of the
+	// function block instance given as operand to the current result and
+	// call the instance
+	S1  Operator = "S1"
+	R1  Operator = "R1"
+	CLK Operator = "CLK"
+	CU  Operator = "CU"
+	CD  Operator = "CD"
+	PV  Operator = "PV"
+	IN  Operator = "IN"
+	PT  Operator = "PT"
+)
+
+// operatorInfo describes the modifiers an operator accepts
+type operatorInfo struct {
+	negate   bool // N, e.g. LDN, ANDN, JMPCN
+	cond     bool // C, e.g. JMPC, CALCN
+	deferred bool // (, e.g. AND(
+	operand  bool // requires an operand
+}
+
+var operators = map[Operator]operatorInfo{
+	LD:  {negate: true, operand: true},
+	ST:  {negate: true, operand: true},
+	S:   {operand: true},
+	R:   {operand: true},
+	NOT: {},
+	AND: {negate: true, deferred: true, operand: true},
+	OR:  {negate: true, deferred: true, operand: true},
+	XOR: {negate: true, deferred: true, operand: true},
+	ADD: {deferred: true, operand: true},
+	SUB: {deferred: true, operand: true},
+	MUL: {deferred: true, operand: true},
+	DIV: {deferred: true, operand: true},
+	MOD: {deferred: true, operand: true},
+	GT:  {deferred: true, operand: true},
+	GE:  {deferred: true, operand: true},
+	EQ:  {deferred: true, operand: true},
+	NE:  {deferred: true, operand: true},
+	LE:  {deferred: true, operand: true},
+	LT:  {deferred: true, operand: true},
+	JMP: {cond: true, operand: true},
+	CAL: {cond: true, operand: true},
+	RET: {cond: true},
+	S1:  {operand: true},
+	R1:  {operand: true},
+	CLK: {operand: true},
+	CU:  {operand: true},
+	CD:  {operand: true},
+	PV:  {operand: true},
+	IN:  {operand: true},
+	PT:  {operand: true},
+}
+
+// IsFBInput reports whether op is a function block input operator
+func (op Operator) IsFBInput() bool {
+	switch op {
+	case S1, R1, CLK, CU, CD, PV, IN, PT:
+		return true
+	}
+	return false
+}
+
+// Program is the instruction list of an IL POU body, action or transition
+type Program struct {
+	Instrs []*Instr
+	// Comments are the comments and pragmas of the source in source order
+	Comments []*st.Comment
+}
+
+// Instr is an instruction. A line holding only a label is an Instr with
+// an empty Op and no Func.
+type Instr struct {
+	// Label is the label before the instruction, nil if none
+	Label *st.Ident
+	OpPos st.Pos
+	// Op is the operator, empty for function calls
+	Op Operator
+	// Func is the called function of a function call instruction such as
+	// SHL 2 or LIMIT(MN := 0, IN := x, MX := 100)
+	Func *st.Ident
+	// Negated is the N modifier, of LDN, ANDN or JMPCN
+	Negated bool
+	// Cond is the C modifier of JMPC, CALC and RETC
+	Cond bool
+	// Operand is the operand, the label of JMP or the instance of CAL; nil
+	// if none
+	Operand st.Expr
+	// Args are the parameters of CAL and the operands or formal
+	// parameters of function calls, without the current result
+	Args []*st.Arg
+	// Deferred is the ( modifier: Nested is evaluated with Operand as
+	// first current result before the operator is applied
+	Deferred bool
+	Nested   []*Instr
+	// End is the position after the instruction
+	End st.Pos
+}
+
+// Pos returns the position of the label or the operator
+func (in *Instr) Pos() st.Pos {
+	if in.Label != nil {
+		return in.Label.Pos()
+	}
+	return in.OpPos
+}
+
+// Name returns the operator with its modifiers, e.g. ANDN( or JMPCN, or
+// the name of the called function
+func (in *Instr) Name() string {
+	if in.Func != nil {
+		return in.Func.Name
+	}
+	name := string(in.Op)
+	if in.Cond {
+		name += "C"
+	}
+	if in.Negated {
+		name += "N"
+	}
+	if in.Deferred {
+		name += "("
+	}
+	return name
+}
+
+// String returns the instruction in IL syntax on one line
+func (in *Instr) String() string {
+	var sb strings.Builder
+	if in.Label != nil {
+		sb.WriteString(in.Label.Name + ":")
+		if in.Op == "" && in.Func == nil {
+			return sb.String()
+		}
+		sb.WriteByte(' ')
+	}
+	sb.WriteString(in.Name())
+	if in.Operand != nil {
+		if !in.Deferred {
+			sb.WriteByte(' ')
+		}
+		st.Fprint(&sb, in.Operand)
+	}
+	if len(in.Args) > 0 {
+		formal := in.Op == CAL || in.Args[0].Name != nil
+		if formal {
+			sb.WriteByte('(')
+		} else {
+			sb.WriteByte(' ')
+		}
+		for i, a := range in.Args {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			st.Fprint(&sb, a)
+		}
+		if formal {
+			sb.WriteByte(')')
+		}
+	}
+	for _, n := range in.Nested {
+		sb.WriteString(" " + n.String())
+	}
+	if in.Deferred {
+		sb.WriteByte(')')
+	}
+	return sb.String()
+}
@@ -0,0 +1,378 @@
+// Package il parses IEC 61131-3 Instruction List and translates it to
+// Structured Text.
+//
+// IL is deprecated since edition 3 of IEC 61131-3. Parse reads the text of
+// a plcopen.BodyIL into instructions, Translate turns them into an ST
+// syntax tree that the st package prints:
+//
+//	prog, err := il.Parse(pou.Body.IL.Xhtml.PlainText())
+//	body, err := il.Translate(prog)
+//	st.Fprint(os.Stdout, body)
+//
+// Identifiers, literals and comments are those of ST, operators and
+// keywords are case-insensitive. Every instruction ends at the end of its
+// line, except for parameter lists and the deferred form OP( ... ) which
+// may span several lines.
+package il
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// Parse parses the instructions in src. Syntax errors are returned as
+// st.Errors along with the instructions that could be read.
+func Parse(src string) (*Program, error) {
+	p := newParser(src)
+	prog := &Program{Instrs: p.instrList(false)}
+	prog.Comments = p.scanner.Comments()
+	return prog, p.err()
+}
+
+// maxErrors is the number of errors after which parsing stops
+const maxErrors = 10
+
+type parser struct {
+	scanner *st.Scanner
+	items   []st.Item
+	next    int
+	tok     st.Item // current token
+	line    int     // line of the last token consumed
+	errs    st.Errors
+}
+
+func newParser(src string) *parser {
+	p := &parser{scanner: st.NewScanner(src)}
+	for {
+		it := p.scanner.Scan()
+		p.items = append(p.items, it)
+		if it.Tok == st.EOF {
+			break
+		}
+	}
+	p.errs = p.scanner.Errors()
+	p.tok = p.items[0]
+	return p
+}
+
+func (p *parser) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs
+}
+
+func (p *parser) errorf(pos st.Pos, format string, args ...interface{}) {
+	p.errs = append(p.errs, &st.Error{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// describe returns the current token for error messages
+func (p *parser) describe() string {
+	switch {
+	case p.tok.Tok == st.EOF:
+		return "end of input"
+	case p.eol():
+		return "end of line"
+	case p.tok.Tok == st.IDENT:
+		return "identifier " + p.tok.Text
+	case p.tok.Tok == st.LITERAL:
+		return p.tok.Kind.String() + " " + p.tok.Text
+	}
+	return "'" + p.tok.Text + "'"
+}
+
+// advance moves to the next token and returns the current one
+func (p *parser) advance() st.Item {
+	it := p.tok
+	p.line = it.Pos.Line
+	if p.next+1 < len(p.items) {
+		p.next++
+		p.tok = p.items[p.next]
+	}
+	return it
+}
+
+func (p *parser) peek(n int) st.Item {
+	if i := p.next + n; i < len(p.items) {
+		return p.items[i]
+	}
+	return p.items[len(p.items)-1]
+}
+
+// eol reports whether the current token starts a new line after the
+// instruction being parsed
+func (p *parser) eol() bool {
+	return p.tok.Tok == st.EOF || p.line > 0 && p.tok.Pos.Line > p.line
+}
+
+// expect consumes tok or reports an error
+func (p *parser) expect(tok st.Token) st.Pos {
+	pos := p.tok.Pos
+	if p.tok.Tok != tok {
+		p.errorf(pos, "expected '%s', found %s", tok, p.describe())
+		return pos
+	}
+	p.advance()
+	return pos
+}
+
+// skipLine skips the rest of the current line after an error
+func (p *parser) skipLine() {
+	for !p.eol() {
+		p.advance()
+	}
+}
+
+// instrList parses instructions up to the end of the source, or up to the
+// closing parenthesis of a deferred operation if nested
+func (p *parser) instrList(nested bool) []*Instr {
+	var list []*Instr
+	for p.tok.Tok != st.EOF && !(nested && p.tok.Tok == st.RPAREN) && len(p.errs) < maxErrors {
+		p.line = 0 // every instruction starts a line
+		errs := len(p.errs)
+		in := p.instr(nested)
+		if len(p.errs) > errs {
+			p.skipLine()
+		}
+		if in != nil {
+			list = append(list, in)
+		}
+	}
+	return list
+}
+
+// instr parses an instruction and the end of its line
+func (p *parser) instr(nested bool) *Instr {
+	in := &Instr{}
+	if p.tok.Tok == st.IDENT && p.peek(1).Tok == st.COLON && p.peek(1).Pos.Line == p.tok.Pos.Line {
+		if nested {
+			p.errorf(p.tok.Pos, "label in parenthesized instructions")
+		}
+		in.Label = p.ident()
+		p.advance()
+		if p.eol() {
+			in.End = p.endPos()
+			return in
+		}
+	}
+
+	in.OpPos = p.tok.Pos
+	word := p.tok.Text
+	switch p.tok.Tok {
+	case st.AMP:
+		// & and &N for AND and ANDN
+		word = "AND"
+		if n := p.peek(1); n.Tok == st.IDENT && strings.EqualFold(n.Text, "N") && n.Pos.Offset == p.tok.Pos.Offset+1 {
+			p.advance()
+			word = "ANDN"
+		}
+	case st.IDENT, st.AND, st.OR, st.XOR, st.NOT, st.MOD:
+	default:
+		p.errorf(p.tok.Pos, "expected operator, found %s", p.describe())
+		p.advance()
+		return nil
+	}
+	op, negated, cond, ok := lookupOperator(word)
+	if !ok {
+		return p.funcCall(in)
+	}
+	p.advance()
+	in.Op, in.Negated, in.Cond = op, negated, cond
+	info := operators[op]
+
+	if info.deferred && p.tok.Tok == st.LPAREN && !p.eol() {
+		p.advance()
+		in.Deferred = true
+		if !p.eol() {
+			in.Operand = p.operand()
+		}
+		in.Nested = p.instrList(true)
+		p.expect(st.RPAREN)
+		in.End = p.endPos()
+		p.endLine()
+		return in
+	}
+
+	switch {
+	case op == JMP:
+		if p.tok.Tok != st.IDENT || p.eol() {
+			p.errorf(p.tok.Pos, "expected label, found %s", p.describe())
+			return in
+		}
+		in.Operand = p.ident()
+	case op == CAL:
+		if p.tok.Tok != st.IDENT || p.eol() {
+			p.errorf(p.tok.Pos, "expected function block instance, found %s", p.describe())
+			return in
+		}
+		in.Operand = p.variable()
+		if p.tok.Tok == st.LPAREN && !p.eol() {
+			in.Args = p.params()
+		}
+	case info.operand:
+		if p.eol() {
+			p.errorf(p.endPos(), "missing operand of %s", in.Name())
+			return in
+		}
+		in.Operand = p.operand()
+	}
+	in.End = p.endPos()
+	p.endLine()
+	return in
+}
+
+// funcCall parses a function call instruction, with operands as in SHL 2
+// or with formal parameters as in LIMIT(MN := 0, IN := x, MX := 100)
+func (p *parser) funcCall(in *Instr) *Instr {
+	in.Func = p.ident()
+	switch {
+	case p.tok.Tok == st.LPAREN && !p.eol():
+		in.Args = p.params()
+	case !p.eol():
+		for {
+			in.Args = append(in.Args, &st.Arg{Value: p.operand()})
+			if p.tok.Tok != st.COMMA || p.eol() {
+				break
+			}
+			p.advance()
+		}
+	}
+	in.End = p.endPos()
+	p.endLine()
+	return in
+}
+
+// params parses a parenthesized parameter list, which may span lines
+func (p *parser) params() []*st.Arg {
+	p.advance()
+	var args []*st.Arg
+	for p.tok.Tok != st.RPAREN && p.tok.Tok != st.EOF {
+		a := &st.Arg{}
+		if p.tok.Tok == st.NOT && p.peek(1).Tok == st.IDENT && p.peek(2).Tok == st.OUTPUT {
+			a.Negated = true
+			a.NotPos = p.advance().Pos
+		}
+		if p.tok.Tok == st.IDENT && (p.peek(1).Tok == st.ASSIGN || p.peek(1).Tok == st.OUTPUT) {
+			a.Name = p.ident()
+			a.Op = p.advance().Tok
+		}
+		if p.tok.Tok == st.LPAREN {
+			p.errorf(p.tok.Pos, "instruction lists as parameter values are not supported")
+			return args
+		}
+		a.Value = p.operand()
+		args = append(args, a)
+		if p.tok.Tok != st.COMMA {
+			break
+		}
+		p.advance()
+	}
+	p.expect(st.RPAREN)
+	return args
+}
+
+// endLine reports tokens after the end of an instruction
+func (p *parser) endLine() {
+	if !p.eol() && p.tok.Tok != st.RPAREN {
+		p.errorf(p.tok.Pos, "expected end of line, found %s", p.describe())
+	}
+}
+
+// endPos returns the position after the last token consumed
+func (p *parser) endPos() st.Pos {
+	if p.next == 0 {
+		return p.tok.Pos
+	}
+	last := p.items[p.next-1]
+	end := last.Pos
+	end.Offset += len(last.Text)
+	end.Column += len(last.Text)
+	return end
+}
+
+func (p *parser) ident() *st.Ident {
+	it := p.advance()
+	return &st.Ident{NamePos: it.Pos, Name: it.Text}
+}
+
+// operand parses a constant, possibly signed, or a variable
+func (p *parser) operand() st.Expr {
+	switch p.tok.Tok {
+	case st.LITERAL:
+		it := p.advance()
+		return &st.Literal{ValuePos: it.Pos, Kind: it.Kind, Value: it.Text}
+	case st.SUB, st.ADD:
+		if n := p.peek(1); n.Tok == st.LITERAL && n.Pos.Line == p.tok.Pos.Line {
+			op := p.advance()
+			it := p.advance()
+			return &st.UnaryExpr{OpPos: op.Pos, Op: op.Tok, X: &st.Literal{ValuePos: it.Pos, Kind: it.Kind, Value: it.Text}}
+		}
+	case st.IDENT:
+		return p.variable()
+	}
+	pos := p.tok.Pos
+	p.errorf(pos, "expected operand, found %s", p.describe())
+	return &st.BadExpr{From: pos, To: pos}
+}
+
+// variable parses a variable with its member, index and dereference
+// selectors, e.g. motors[2].speed or %IX0.1
+func (p *parser) variable() st.Expr {
+	var x st.Expr = p.ident()
+	for !p.eol() {
+		switch p.tok.Tok {
+		case st.PERIOD:
+			p.advance()
+			if p.tok.Tok == st.IDENT || p.tok.Tok == st.LITERAL && p.tok.Kind == st.IntLit && !strings.Contains(p.tok.Text, "#") {
+				x = &st.SelectorExpr{X: x, Sel: p.ident()}
+				continue
+			}
+			p.errorf(p.tok.Pos, "expected member name, found %s", p.describe())
+			return x
+		case st.LBRACK:
+			ix := &st.IndexExpr{X: x, Lbrack: p.advance().Pos}
+			for {
+				ix.Indices = append(ix.Indices, p.operand())
+				if p.tok.Tok != st.COMMA {
+					break
+				}
+				p.advance()
+			}
+			ix.Rbrack = p.expect(st.RBRACK)
+			x = ix
+		case st.CARET:
+			x = &st.DerefExpr{X: x, Caret: p.advance().Pos}
+		default:
+			return x
+		}
+	}
+	return x
+}
+
+// lookupOperator returns the operator and modifiers of word, e.g. JMP,
+// false, true for JMPC
+func lookupOperator(word string) (op Operator, negated, cond, ok bool) {
+	w := Operator(strings.ToUpper(word))
+	if _, ok := operators[w]; ok {
+		return w, false, false, true
+	}
+	for _, suffix := range []string{"N", "C", "CN"} {
+		base, found := strings.CutSuffix(string(w), suffix)
+		if !found {
+			continue
+		}
+		info, ok := operators[Operator(base)]
+		switch {
+		case !ok:
+		case suffix == "N" && info.negate:
+			return Operator(base), true, false, true
+		case suffix == "C" && info.cond:
+			return Operator(base), false, true, true
+		case suffix == "CN" && info.cond:
+			return Operator(base), true, true, true
+		}
+	}
+	return "", false, false, false
+}
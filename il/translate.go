@@ -0,0 +1,544 @@
+package il
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// binaryOps are the ST operators of the IL operators combining the current
+// result with an operand
+var binaryOps = map[Operator]st.Token{
+	AND: st.AND, OR: st.OR, XOR: st.XOR,
+	ADD: st.ADD, SUB: st.SUB, MUL: st.MUL, DIV: st.DIV, MOD: st.MOD,
+	GT: st.GTR, GE: st.GEQ, EQ: st.EQL, NE: st.NEQ, LE: st.LEQ, LT: st.LSS,
+}
+
+// Translate translates prog to an equivalent ST body. The current result
+// of IL becomes part of the expressions assigned by ST and S, R and the
+// conditional operators become IF statements. Jumps are translated when
+// they form structured statements: a conditional jump forward over
+// instructions becomes IF, followed by an unconditional jump over more
+// instructions IF ... ELSE, and a conditional jump back becomes REPEAT.
+//
+// Each statement keeps the position of its first instruction and the
+// comments of prog are copied, so that they print next to the statements
+// they were next to. Instructions that cannot be translated, such as
+// other jumps, uses of an undefined current result or function calls whose
+// result is never used, are reported as st.Errors and replaced by a comment
+// "(* IL: ... *)"; the body is then incomplete.
+func Translate(prog *Program) (*st.Body, error) {
+	t := &translator{instrs: prog.Instrs, labels: map[string]int{}}
+	for i, in := range t.instrs {
+		if in.Label == nil {
+			continue
+		}
+		name := strings.ToUpper(in.Label.Name)
+		if _, ok := t.labels[name]; ok {
+			t.errorf(in, "label %s is already defined", in.Label.Name)
+			continue
+		}
+		t.labels[name] = i
+	}
+	for i, in := range t.instrs {
+		if in.Op == JMP {
+			t.jumps = append(t.jumps, jump{from: i, to: t.target(in)})
+		}
+	}
+	b := t.block(0, len(t.instrs), -1)
+	b.drop(nil)
+
+	comments := append(append([]*st.Comment(nil), prog.Comments...), t.comments...)
+	sort.SliceStable(comments, func(i, j int) bool { return comments[i].Start.Offset < comments[j].Start.Offset })
+	body := &st.Body{Stmts: b.stmts, Comments: comments}
+	if len(t.errs) > 0 {
+		return body, t.errs
+	}
+	return body, nil
+}
+
+type jump struct {
+	from, to int // instruction indices, to is -1 for undefined labels
+}
+
+type translator struct {
+	instrs []*Instr
+	// labels holds the instruction index by upper case label
+	labels map[string]int
+	jumps  []jump
+	// comments are the comments replacing untranslated instructions
+	comments []*st.Comment
+	errs     st.Errors
+	// unused counts the errors about unused function results, which are
+	// reported with a later instruction but do not untranslate it
+	unused int
+}
+
+func (t *translator) errorf(in *Instr, format string, args ...interface{}) {
+	t.errs = append(t.errs, &st.Error{Pos: in.Pos(), Msg: fmt.Sprintf(format, args...)})
+}
+
+// untranslated reports in and replaces it by a comment
+func (t *translator) untranslated(in *Instr, format string, args ...interface{}) {
+	t.errorf(in, format, args...)
+	t.comments = append(t.comments, &st.Comment{Start: in.Pos(), Text: "(* IL: " + in.String() + " *)"})
+}
+
+// target returns the index of the label a jump refers to, or -1
+func (t *translator) target(in *Instr) int {
+	if id, ok := in.Operand.(*st.Ident); ok {
+		if i, ok := t.labels[strings.ToUpper(id.Name)]; ok {
+			return i
+		}
+	}
+	return -1
+}
+
+// targeted reports whether a jump refers to instruction i
+func (t *translator) targeted(i int) bool {
+	for _, j := range t.jumps {
+		if j.to == i {
+			return true
+		}
+	}
+	return false
+}
+
+// closed reports whether the instructions [from, to) can be translated on
+// their own: their jumps stay within [from, to] and jumps from elsewhere
+// enter them only at from
+func (t *translator) closed(from, to int) bool {
+	for _, j := range t.jumps {
+		inside := j.from >= from && j.from < to
+		if inside && (j.to < from || j.to > to) || !inside && j.to > from && j.to < to {
+			return false
+		}
+	}
+	return true
+}
+
+// seq is the state of the translation of an instruction sequence
+type seq struct {
+	t     *translator
+	stmts []st.Stmt
+	// cr is the current result, nil if undefined
+	cr st.Expr
+	// stale is the instruction that changed a variable cr depends on
+	stale *Instr
+	// start is the position of the first instruction of the next statement
+	start st.Pos
+	// call is the function call whose result is cr, until cr is used
+	call *Instr
+}
+
+// block translates the instructions [from, to), a label at noLoop starts
+// no loop
+func (t *translator) block(from, to, noLoop int) *seq {
+	b := &seq{t: t}
+	for i := from; i < to; {
+		in := t.instrs[i]
+		if !b.start.IsValid() {
+			b.start = in.Pos()
+		}
+		if in.Label != nil && t.targeted(i) {
+			b.drop(in)
+			b.cr, b.stale = nil, nil
+			if end := t.loopEnd(i, to); end >= 0 && i != noLoop {
+				b.loop(i, end)
+				i = end + 1
+				continue
+			}
+		}
+		errs := len(t.errs) - t.unused
+		if in.Op == JMP {
+			i = b.jump(i, to)
+		} else {
+			b.instr(in)
+			i++
+		}
+		if len(t.errs)-t.unused > errs {
+			// The next statement starts after the untranslated instruction
+			b.start = st.Pos{}
+		}
+	}
+	return b
+}
+
+// loopEnd returns the index of the last conditional jump back to from in
+// [from, to) that closes a loop, or -1
+func (t *translator) loopEnd(from, to int) int {
+	for i := to - 1; i >= from; i-- {
+		in := t.instrs[i]
+		if in.Op == JMP && in.Cond && t.target(in) == from && t.closed(from, i+1) {
+			return i
+		}
+	}
+	return -1
+}
+
+// loop translates the instructions [from, end] ending with a conditional
+// jump back to from into a REPEAT statement
+func (b *seq) loop(from, end int) {
+	t := b.t
+	body := t.block(from, end, from)
+	in := t.instrs[end]
+	cond := body.use(in)
+	body.drop(nil)
+	if cond == nil {
+		b.stmts = append(b.stmts, body.stmts...)
+		return
+	}
+	if !in.Negated {
+		cond = not(cond)
+	}
+	b.emit(&st.RepeatStmt{Repeat: t.instrs[from].Pos(), Body: body.stmts, Cond: cond, EndRepeat: in.OpPos})
+	b.cr = nil
+}
+
+// jump translates the jump at i, with the instructions it jumps over if it
+// forms an IF statement, and returns the index of the next instruction
+func (b *seq) jump(i, to int) int {
+	t := b.t
+	in := t.instrs[i]
+	target := t.target(in)
+	switch {
+	case target < 0:
+		t.untranslated(in, "undefined label %s", in.Operand.(*st.Ident).Name)
+		return i + 1
+	case target == i+1:
+		// Jump to the next instruction
+		return i + 1
+	case !in.Cond || target < i || target > to:
+		t.untranslated(in, "jump to %s is not an IF or REPEAT statement", in.Operand.(*st.Ident).Name)
+		return i + 1
+	}
+
+	// IF ... ELSE: the instructions jumped over end with a jump over the
+	// ELSE part, which is entered only by the conditional jump
+	if j := target - 1; j > i+1 {
+		if e := t.instrs[j]; e.Op == JMP && !e.Cond && e.Label == nil {
+			end := t.target(e)
+			if end > target && end <= to && t.closed(i+1, j) && t.closed(target, end) && t.enteredOnlyFrom(target, i) {
+				cond := b.jumpCond(in)
+				if cond == nil {
+					return i + 1
+				}
+				then, els := t.block(i+1, j, -1), t.block(target, end, target)
+				then.drop(nil)
+				els.drop(nil)
+				b.emit(&st.IfStmt{If: b.start, Cond: cond, Then: then.stmts, Else: els.stmts,
+					ElsePos: t.instrs[target].Pos(), EndIf: t.instrs[end].Pos()})
+				b.cr, b.stale = nil, nil
+				return end
+			}
+		}
+	}
+	if !t.closed(i+1, target) {
+		t.untranslated(in, "jump to %s is not an IF or REPEAT statement", in.Operand.(*st.Ident).Name)
+		return i + 1
+	}
+	cond := b.jumpCond(in)
+	if cond == nil {
+		return i + 1
+	}
+	then := t.block(i+1, target, -1)
+	then.drop(nil)
+	b.emit(&st.IfStmt{If: b.start, Cond: cond, Then: then.stmts, EndIf: t.instrs[target].Pos()})
+	b.cr, b.stale = nil, nil
+	return target
+}
+
+// enteredOnlyFrom reports whether the only jump to instruction i is at from
+func (t *translator) enteredOnlyFrom(i, from int) bool {
+	for _, j := range t.jumps {
+		if j.to == i && j.from != from {
+			return false
+		}
+	}
+	return true
+}
+
+// jumpCond returns the condition under which the conditional jump in is
+// not taken
+func (b *seq) jumpCond(in *Instr) st.Expr {
+	cr := b.use(in)
+	if cr == nil || in.Negated {
+		return cr
+	}
+	return not(cr)
+}
+
+// emit appends a statement, which starts the next one
+func (b *seq) emit(s st.Stmt) {
+	b.stmts = append(b.stmts, s)
+	b.start = st.Pos{}
+}
+
+// use returns the current result for in, or reports in if there is none
+func (b *seq) use(in *Instr) st.Expr {
+	b.call = nil
+	switch {
+	case b.stale != nil:
+		b.t.untranslated(in, "current result depends on a variable changed by %s at %s", b.stale.Name(), b.stale.Pos())
+		return nil
+	case b.cr == nil:
+		b.t.untranslated(in, "%s needs a current result, which is undefined here", in.Name())
+		return nil
+	}
+	return b.cr
+}
+
+// drop is called before the current result is discarded by in, nil at the
+// end of a block. A function call whose result was never used is reported,
+// as it would be lost with it, and the next statement starts at in.
+func (b *seq) drop(in *Instr) {
+	if b.call != nil {
+		b.t.untranslated(b.call, "result of %s is never used", b.call.Name())
+		b.t.unused++
+		b.call = nil
+		if in != nil {
+			b.start = in.Pos()
+		}
+	}
+}
+
+// conditional returns s, or IF cr THEN s END_IF for conditional
+// instructions; it returns nil if there is no current result
+func (b *seq) conditional(in *Instr, s st.Stmt) st.Stmt {
+	if !in.Cond {
+		return s
+	}
+	cond := b.use(in)
+	if cond == nil {
+		return nil
+	}
+	if in.Negated {
+		cond = not(cond)
+	}
+	return &st.IfStmt{If: b.start, Cond: cond, Then: []st.Stmt{s}, EndIf: in.OpPos}
+}
+
+// instr translates an instruction other than a jump
+func (b *seq) instr(in *Instr) {
+	switch op := in.Op; {
+	case in.Func != nil:
+		b.funcCall(in)
+	case op == "":
+		// Label only
+	case op == LD:
+		b.drop(in)
+		b.cr, b.stale = in.Operand, nil
+		if in.Negated {
+			b.cr = not(in.Operand)
+		}
+	case op == ST:
+		cr := b.use(in)
+		if cr == nil {
+			return
+		}
+		value := cr
+		if in.Negated {
+			value = not(cr)
+		}
+		b.emit(&st.AssignStmt{Target: moveTo(in.Operand, b.start), TokPos: in.OpPos, Value: value})
+		if !isSimple(cr) || mentions(cr, in.Operand) {
+			// Read the stored value instead of evaluating cr again
+			b.cr = in.Operand
+			if in.Negated {
+				b.cr = not(in.Operand)
+			}
+		}
+	case op == S || op == R:
+		cr := b.use(in)
+		if cr == nil {
+			return
+		}
+		value := "TRUE"
+		if op == R {
+			value = "FALSE"
+		}
+		assign := &st.AssignStmt{Target: in.Operand, TokPos: in.OpPos, Value: &st.Literal{ValuePos: in.OpPos, Kind: st.BoolLit, Value: value}}
+		b.emit(&st.IfStmt{If: b.start, Cond: cr, Then: []st.Stmt{assign}, EndIf: in.OpPos})
+		if mentions(cr, in.Operand) {
+			b.stale = in
+		}
+	case op == NOT:
+		if cr := b.use(in); cr != nil {
+			b.cr = not(cr)
+		}
+	case binaryOps[op] != st.ILLEGAL:
+		cr := b.use(in)
+		if cr == nil {
+			return
+		}
+		y := in.Operand
+		if in.Deferred {
+			if y = b.nested(in); y == nil {
+				b.cr = nil
+				return
+			}
+		}
+		if in.Negated {
+			y = not(y)
+		}
+		b.cr = &st.BinaryExpr{X: cr, OpPos: in.OpPos, Op: binaryOps[op], Y: y}
+	case op == CAL:
+		if !in.Cond {
+			b.drop(in)
+		}
+		call := &st.CallExpr{Fun: in.Operand, Lparen: in.End, Args: in.Args, Rparen: in.End}
+		if s := b.conditional(in, &st.CallStmt{Call: call}); s != nil {
+			if !in.Cond {
+				call.Fun = moveTo(call.Fun, b.start)
+			}
+			b.emit(s)
+		}
+		b.cr, b.stale = nil, nil
+	case op == RET:
+		ret := &st.ReturnStmt{Return: in.OpPos}
+		if !in.Cond {
+			ret.Return = b.start
+		}
+		if s := b.conditional(in, ret); s != nil {
+			b.emit(s)
+		}
+		if !in.Cond {
+			b.drop(in)
+			b.cr, b.stale = nil, nil
+		}
+	case op.IsFBInput():
+		cr := b.use(in)
+		if cr == nil {
+			return
+		}
+		param := &st.Arg{Name: &st.Ident{NamePos: in.OpPos, Name: string(op)}, Op: st.ASSIGN, Value: cr}
+		call := &st.CallExpr{Fun: moveTo(in.Operand, b.start), Lparen: in.End, Args: []*st.Arg{param}, Rparen: in.End}
+		b.emit(&st.CallStmt{Call: call})
+		b.cr, b.stale = nil, nil
+	default:
+		b.t.untranslated(in, "%s cannot be translated", in.Name())
+	}
+}
+
+// funcCall translates a function call, whose result is the new current
+// result. The current result is the first input unless the parameters are
+// formal.
+func (b *seq) funcCall(in *Instr) {
+	args := in.Args
+	if len(args) == 0 || args[0].Name == nil {
+		cr := b.use(in)
+		if cr == nil {
+			return
+		}
+		args = append([]*st.Arg{{Value: cr}}, args...)
+	} else {
+		b.drop(in)
+	}
+	b.cr = &st.CallExpr{Fun: in.Func, Lparen: in.End, Args: args, Rparen: in.End}
+	b.stale = nil
+	b.call = in
+}
+
+// nested translates the instructions of a deferred operation and returns
+// their result
+func (b *seq) nested(in *Instr) st.Expr {
+	n := &seq{t: b.t, cr: in.Operand}
+	for _, x := range in.Nested {
+		switch {
+		case x.Func != nil, x.Op == LD, x.Op == NOT, binaryOps[x.Op] != st.ILLEGAL:
+			n.instr(x)
+		default:
+			b.t.untranslated(x, "%s is not allowed in parentheses", x.Name())
+			return nil
+		}
+	}
+	if n.cr == nil {
+		b.t.untranslated(in, "%s has no operand", in.Name())
+	}
+	return n.cr
+}
+
+// inverse holds the comparison operators with their negation
+var inverse = map[st.Token]st.Token{
+	st.EQL: st.NEQ, st.NEQ: st.EQL,
+	st.LSS: st.GEQ, st.GEQ: st.LSS,
+	st.GTR: st.LEQ, st.LEQ: st.GTR,
+}
+
+// not returns the negation of x, removing a NOT or inverting a comparison
+// instead of adding a NOT where possible
+func not(x st.Expr) st.Expr {
+	switch x := x.(type) {
+	case *st.UnaryExpr:
+		if x.Op == st.NOT {
+			return x.X
+		}
+	case *st.BinaryExpr:
+		if op, ok := inverse[x.Op]; ok {
+			c := *x
+			c.Op = op
+			return &c
+		}
+	}
+	return &st.UnaryExpr{OpPos: x.Pos(), Op: st.NOT, X: x}
+}
+
+// isSimple reports whether x is a constant or a variable name
+func isSimple(x st.Expr) bool {
+	switch x := x.(type) {
+	case *st.Literal, *st.Ident:
+		return true
+	case *st.UnaryExpr:
+		_, ok := x.X.(*st.Literal)
+		return ok
+	}
+	return false
+}
+
+// mentions reports whether x reads the variable v
+func mentions(x, v st.Expr) bool {
+	name := exprString(v)
+	found := false
+	st.Inspect(x, func(n st.Node) bool {
+		if e, ok := n.(st.Expr); ok && strings.EqualFold(exprString(e), name) {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func exprString(x st.Expr) string {
+	var sb strings.Builder
+	st.Fprint(&sb, x)
+	return sb.String()
+}
+
+// moveTo returns a copy of x that starts at pos, so that a statement built
+// from instructions on several lines starts at the first of them
+func moveTo(x st.Expr, pos st.Pos) st.Expr {
+	if !pos.IsValid() {
+		return x
+	}
+	switch x := x.(type) {
+	case *st.Ident:
+		c := *x
+		c.NamePos = pos
+		return &c
+	case *st.SelectorExpr:
+		c := *x
+		c.X = moveTo(x.X, pos)
+		return &c
+	case *st.IndexExpr:
+		c := *x
+		c.X = moveTo(x.X, pos)
+		return &c
+	case *st.DerefExpr:
+		c := *x
+		c.X = moveTo(x.X, pos)
+		return &c
+	}
+	return x
+}
@@ -0,0 +1,24 @@
+package plcopen
+
+import (
+	"strings"
+
+	"github.com/suifei/plcopen-go/v2/il"
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// ToST translates the Instruction List program to an equivalent Structured
+// Text body with il.Parse and il.Translate. On syntax errors it returns a
+// nil body. Instructions that cannot be translated are reported as
+// st.Errors along with the body, in which comments "(* IL: ... *)" take
+// their place.
+func (b *BodyIL) ToST() (*BodyST, error) {
+	prog, err := il.Parse(b.Xhtml.PlainText())
+	if err != nil {
+		return nil, err
+	}
+	body, err := il.Translate(prog)
+	var sb strings.Builder
+	st.Fprint(&sb, body)
+	return &BodyST{Xhtml: NewFormattedText(strings.TrimSuffix(sb.String(), "\n"))}, err
+}
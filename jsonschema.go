@@ -0,0 +1,272 @@
+package plcopen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// JSONSchemaID is the $id of the schema returned by JSONSchema, it is
+// published as docs/plcopen.schema.json
+const JSONSchemaID = "https://github.com/suifei/plcopen-go/docs/plcopen.schema.json"
+
+// JSONSchema returns a JSON Schema (draft 2020-12) of the JSON form of
+// Project, generated from the Go types. Every named type is a definition
+// under $defs with its Go name.
+func JSONSchema() ([]byte, error) {
+	g := &schemaGenerator{defs: map[string]interface{}{}}
+	root, err := g.schema(reflect.TypeOf(Project{}))
+	if err != nil {
+		return nil, err
+	}
+	doc := map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$id":     JSONSchemaID,
+		"title":   "PLCopen TC6 XML V1.0B project",
+		"$defs":   g.defs,
+	}
+	for k, v := range root {
+		doc[k] = v
+	}
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// jsonSchemaEnums lists the values of the string types with constants
+var jsonSchemaEnums = map[reflect.Type][]string{
+	reflect.TypeOf(POUType("")): {
+		string(POUTypeFunction), string(POUTypeFunctionBlock), string(POUTypeProgram),
+	},
+	reflect.TypeOf(EdgeModifierType("")): {
+		string(EdgeModifierTypeNone), string(EdgeModifierTypeFalling), string(EdgeModifierTypeRising),
+	},
+	reflect.TypeOf(StorageModifierType("")): {
+		string(StorageModifierTypeNone), string(StorageModifierTypeSet), string(StorageModifierTypeReset),
+	},
+	reflect.TypeOf(BodyFBDActionBlockActionQualifier("")): {
+		string(BodyFBDActionBlockActionQualifierP1), string(BodyFBDActionBlockActionQualifierN),
+		string(BodyFBDActionBlockActionQualifierP0), string(BodyFBDActionBlockActionQualifierR),
+		string(BodyFBDActionBlockActionQualifierS), string(BodyFBDActionBlockActionQualifierL),
+		string(BodyFBDActionBlockActionQualifierD), string(BodyFBDActionBlockActionQualifierP),
+		string(BodyFBDActionBlockActionQualifierDS), string(BodyFBDActionBlockActionQualifierDL),
+		string(BodyFBDActionBlockActionQualifierSD), string(BodyFBDActionBlockActionQualifierSL),
+	},
+	reflect.TypeOf(VarListQualifier("")): {
+		string(VarListQualifierConstant), string(VarListQualifierRetain), string(VarListQualifierNonRetain),
+		string(VarListQualifierPersistent), string(VarListQualifierNonPersistent),
+	},
+	reflect.TypeOf(HandleUnknownType("")): {
+		string(HandleUnknownTypePreserve), string(HandleUnknownTypeDiscard), string(HandleUnknownTypeImplementation),
+	},
+}
+
+// custom returns the schema of the types with their own MarshalJSON, nil for other types
+func (g *schemaGenerator) custom(t reflect.Type) (map[string]interface{}, error) {
+	switch t {
+	case reflect.TypeOf(DataType{}):
+		names := []string{}
+		for _, e := range elementaryTypes {
+			names = append(names, string(e.kind))
+		}
+		names = append(names, "STRING", "WSTRING")
+		alternatives := []interface{}{map[string]interface{}{"type": "string", "enum": names}}
+		var dt DataType
+		for _, kind := range complexKinds {
+			s, err := g.inline(reflect.TypeOf(dt.setComplexKind(kind)).Elem())
+			if err != nil {
+				return nil, err
+			}
+			s["properties"].(map[string]interface{})["kind"] = map[string]interface{}{"const": kind}
+			required, _ := s["required"].([]string)
+			s["required"] = append([]string{"kind"}, required...)
+			alternatives = append(alternatives, s)
+		}
+		return map[string]interface{}{
+			"description": "An elementary type or STRING/WSTRING without length as name, other types as object tagged with kind",
+			"oneOf":       alternatives,
+		}, nil
+	case reflect.TypeOf(DataTypeEnumValues{}):
+		items, err := g.schema(reflect.TypeOf(DataTypeEnumValuesValue{}))
+		return map[string]interface{}{"type": "array", "items": items}, err
+	case reflect.TypeOf(DataTypeEnumValuesValue{}):
+		object, err := g.object(t)
+		return map[string]interface{}{
+			"description": "A value without documentation as name",
+			"oneOf":       []interface{}{map[string]interface{}{"type": "string"}, object},
+		}, err
+	case reflect.TypeOf(Value{}):
+		ref := map[string]interface{}{"$ref": "#/$defs/Value"}
+		return map[string]interface{}{
+			"description": `A simple value as string, number or boolean, an array value as array and a struct value as object. ` +
+				`Repeated array elements are written as {"$repeat": count, "$value": value}, ` +
+				`structs whose member names start with $ or are a single simpleValue, arrayValue or structValue as {"$struct": members}.`,
+			"anyOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "number"},
+				map[string]interface{}{"type": "boolean"},
+				map[string]interface{}{"type": "null"},
+				map[string]interface{}{"type": "array", "items": ref},
+				map[string]interface{}{"type": "object", "additionalProperties": ref},
+			},
+		}, nil
+	case reflect.TypeOf(FormattedText{}):
+		return map[string]interface{}{
+			"description": "A single plain paragraph as string, other XHTML markup as object",
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{
+					"type":                 "object",
+					"properties":           map[string]interface{}{"xhtml": map[string]interface{}{"type": "string"}},
+					"required":             []string{"xhtml"},
+					"additionalProperties": false,
+				},
+			},
+		}, nil
+	case reflect.TypeOf(AddDataData{}):
+		handleUnknown, err := g.schema(reflect.TypeOf(HandleUnknownType("")))
+		return map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":          map[string]interface{}{"type": "string"},
+				"handleUnknown": handleUnknown,
+				"content":       map[string]interface{}{"type": "string", "description": "XML content of the data element"},
+			},
+			"required":             []string{"name", "handleUnknown"},
+			"additionalProperties": false,
+		}, err
+	}
+	return nil, nil
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+type schemaGenerator struct {
+	defs map[string]interface{}
+}
+
+// schema returns the schema of t, a reference for named types
+func (g *schemaGenerator) schema(t reflect.Type) (map[string]interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}, nil
+	}
+	if t.Name() == "" || t.PkgPath() == "" {
+		return g.inline(t)
+	}
+
+	ref := map[string]interface{}{"$ref": "#/$defs/" + t.Name()}
+	if _, ok := g.defs[t.Name()]; ok {
+		return ref, nil
+	}
+	// Reserve the name so recursive types refer to it
+	g.defs[t.Name()] = nil
+	def, err := g.custom(t)
+	switch {
+	case err != nil || def != nil:
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		err = fmt.Errorf("plcopen: no JSON schema for %s, which has its own MarshalJSON", t)
+	default:
+		def, err = g.inline(t)
+	}
+	if err != nil {
+		return nil, err
+	}
+	g.defs[t.Name()] = def
+	return ref, nil
+}
+
+// inline returns the schema of t itself
+func (g *schemaGenerator) inline(t reflect.Type) (map[string]interface{}, error) {
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.object(t)
+	case reflect.Slice, reflect.Array:
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"type": "array", "items": items}, nil
+	case reflect.String:
+		s := map[string]interface{}{"type": "string"}
+		if values, ok := jsonSchemaEnums[t]; ok {
+			s["enum"] = values
+		}
+		return s, nil
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}, nil
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}, nil
+	case reflect.Interface:
+		return map[string]interface{}{}, nil
+	}
+	return nil, fmt.Errorf("plcopen: no JSON schema for %s", t)
+}
+
+// object returns the schema of the struct type t
+func (g *schemaGenerator) object(t reflect.Type) (map[string]interface{}, error) {
+	properties := map[string]interface{}{}
+	required := []string{}
+	if err := g.fields(t, properties, &required); err != nil {
+		return nil, err
+	}
+	s := map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s, nil
+}
+
+// fields adds the JSON members of the fields of t, including those of embedded structs
+func (g *schemaGenerator) fields(t reflect.Type, properties map[string]interface{}, required *[]string) error {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			if err := g.fields(f.Type, properties, required); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := properties[name]; ok {
+			return fmt.Errorf("plcopen: JSON member %q of %s is defined twice", name, t)
+		}
+		s, err := g.schema(f.Type)
+		if err != nil {
+			return err
+		}
+		omitempty := strings.Contains(","+opts+",", ",omitempty,")
+		if !omitempty {
+			*required = append(*required, name)
+			// Nil pointers and slices are written as null
+			if k := f.Type.Kind(); k == reflect.Ptr || k == reflect.Slice || k == reflect.Interface {
+				s = map[string]interface{}{"anyOf": []interface{}{s, map[string]interface{}{"type": "null"}}}
+			}
+		}
+		properties[name] = s
+	}
+	return nil
+}
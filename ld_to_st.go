@@ -0,0 +1,277 @@
+package plcopen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// ToST translates the ladder diagram to Structured Text boolean logic. The
+// power flow is followed from the left power rails through the connections:
+//
+//   - contacts in series become AND, parallel branches joining at an input
+//     become OR, and negated contacts NOT
+//   - rising and falling contacts and coils use R_TRIG and F_TRIG instances
+//   - coils become assignments of the power flow to their variable, negated
+//     coils assign its negation, set and reset coils become IF statements
+//   - blocks and variables placed in rungs are translated as by
+//     BodyFBD.ToST, with TRUE for inputs connected to the left power rail
+//
+// Coils are assigned in the order they are reached from the left power
+// rails, visiting the objects connected to an output from top to bottom.
+// The power flow after a contact used by several objects is assigned to a
+// temporary variable before the coils if it reads a variable written by a
+// coil. The edge instances the body needs are returned for declaration, as
+// are these temporary variables and those of functions used several times.
+//
+// Contacts and coils that are not connected, action blocks, jumps, labels
+// and returns are reported in the returned error, and the rest of the body
+// is translated.
+func (b *BodyLD) ToST() (*BodyST, []VarListVariable, error) {
+	t := newDiagramTranslator("LD", b.Objects())
+	t.rungOrder()
+	return t.translate()
+}
+
+// rungOrder replaces the document order of the objects by the order in
+// which they are reached from the left power rails. Objects that cannot be
+// reached keep their document order after the others.
+func (t *diagramTranslator) rungOrder() {
+	next := map[uint64][]GraphicalObject{}
+	var rails []GraphicalObject
+	for _, obj := range t.objs {
+		if _, ok := obj.(*BodyLDLeftPowerRail); ok {
+			rails = append(rails, obj)
+		}
+		for _, c := range incomingConnections(obj) {
+			next[c.RefLocalID] = append(next[c.RefLocalID], obj)
+		}
+	}
+	byPosition := func(objs []GraphicalObject) {
+		sort.SliceStable(objs, func(i, j int) bool {
+			pi, pj := objectPosition(objs[i]), objectPosition(objs[j])
+			if pi == nil || pj == nil {
+				return pj == nil && pi != nil
+			}
+			if pi.Y != pj.Y {
+				return pi.Y < pj.Y
+			}
+			return pi.X < pj.X
+		})
+	}
+
+	rank := map[uint64]int{}
+	var visit func(obj GraphicalObject)
+	visit = func(obj GraphicalObject) {
+		if _, ok := rank[obj.ObjectID()]; ok {
+			return
+		}
+		rank[obj.ObjectID()] = len(rank)
+		objs := next[obj.ObjectID()]
+		byPosition(objs)
+		for _, o := range objs {
+			visit(o)
+		}
+	}
+	byPosition(rails)
+	for _, rail := range rails {
+		visit(rail)
+	}
+	for i, obj := range t.objs {
+		if r, ok := rank[obj.ObjectID()]; ok {
+			t.index[obj.ObjectID()] = r
+		} else {
+			t.index[obj.ObjectID()] = len(rank) + i
+		}
+	}
+}
+
+// objectPosition returns the position of a graphical object, nil if not set
+func objectPosition(obj GraphicalObject) *Position {
+	switch o := obj.(type) {
+	case *BodyFBDBlock:
+		return o.Position
+	case *BodyFBDActionBlock:
+		return o.Position
+	case *BodyFBDComment:
+		return o.Position
+	case *BodyFBDError:
+		return o.Position
+	case *BodyFBDConnector:
+		return o.Position
+	case *BodyFBDContinuation:
+		return o.Position
+	case *BodyFBDInVariable:
+		return o.Position
+	case *BodyFBDOutVariable:
+		return o.Position
+	case *BodyFBDInOutVariable:
+		return o.Position
+	case *BodyFBDJump:
+		return o.Position
+	case *BodyFBDLabel:
+		return o.Position
+	case *BodyFBDReturn:
+		return o.Position
+	case *BodyLDContact:
+		return o.Position
+	case *BodyLDCoil:
+		return o.Position
+	case *BodyLDLeftPowerRail:
+		return o.Position
+	case *BodyLDRightPowerRail:
+		return o.Position
+	case *BodySFCStep:
+		return o.Position
+	case *BodySFCMacroStep:
+		return o.Position
+	case *BodySFCJumpStep:
+		return o.Position
+	case *BodySFCTransition:
+		return o.Position
+	case *BodySFCSelectionDivergence:
+		return o.Position
+	case *BodySFCSelectionConvergence:
+		return o.Position
+	case *BodySFCSimultaneousDivergence:
+		return o.Position
+	case *BodySFCSimultaneousConvergence:
+		return o.Position
+	}
+	return nil
+}
+
+// power is the value of the left power rail
+func power() st.Expr {
+	return &st.Literal{Kind: st.BoolLit, Value: "TRUE"}
+}
+
+func isPower(x st.Expr) bool {
+	lit, ok := x.(*st.Literal)
+	return ok && lit.Kind == st.BoolLit && lit.Value == "TRUE"
+}
+
+// contact returns the power flow after the contact c
+func (t *diagramTranslator) contact(c *BodyLDContact) st.Expr {
+	if t.expanding[c.LocalID] {
+		t.errorf(c, "feedback loop in the power flow")
+		return nil
+	}
+	t.expanding[c.LocalID] = true
+	defer delete(t.expanding, c.LocalID)
+	in := t.input(c, c.ConnectionPointIn)
+	if in == nil {
+		t.errorf(c, "contact %s is not connected", c.Variable)
+		return nil
+	}
+	x := t.edge(c, c.EdgeModifier, t.variable(c, c.Variable))
+	if c.Negated != nil && *c.Negated {
+		x = &st.UnaryExpr{Op: st.NOT, X: x}
+	}
+	if !isPower(in) {
+		x = &st.BinaryExpr{X: in, Op: st.AND, Y: x}
+	}
+	return x
+}
+
+// sharedContact returns the power flow after the contact c. If it is used
+// by several objects and reads a variable written by a coil, it is assigned
+// to a temporary variable first, so that all uses read the value from
+// before the coils.
+func (t *diagramTranslator) sharedContact(c *BodyLDContact) st.Expr {
+	if t.uses[outputPin{id: c.LocalID}] < 2 {
+		return t.contact(c)
+	}
+	n := t.shared[c.LocalID]
+	if n == nil {
+		cur := t.cur
+		n = &diagramNode{obj: c}
+		t.cur = n
+		n.value = t.contact(c)
+		t.cur = cur
+		t.shared[c.LocalID] = n
+		if n.value != nil && t.readsCoil(n.value) {
+			n.temp = fmt.Sprintf("POWER_%d", c.LocalID)
+			t.declare(n.temp, &DataType{BOOL: &struct{}{}})
+			n.stmts = []st.Stmt{&st.AssignStmt{Target: &st.Ident{Name: n.temp}, Value: n.value}}
+		}
+	}
+	if n.temp == "" {
+		// Evaluated at each use
+		for _, d := range n.deps {
+			t.depend(d)
+		}
+		return n.value
+	}
+	t.depend(n)
+	return &st.Ident{Name: n.temp}
+}
+
+// readsCoil reports whether x reads a variable written by a coil
+func (t *diagramTranslator) readsCoil(x st.Expr) bool {
+	if t.coilVars == nil {
+		t.coilVars = map[string]bool{}
+		for _, obj := range t.objs {
+			if c, ok := obj.(*BodyLDCoil); ok {
+				t.coilVars[strings.ToUpper(exprString(t.variable(c, c.Variable)))] = true
+			}
+		}
+	}
+	found := false
+	st.Inspect(x, func(n st.Node) bool {
+		if e, ok := n.(st.Expr); ok && t.coilVars[strings.ToUpper(exprString(e))] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+func exprString(x st.Expr) string {
+	var sb strings.Builder
+	st.Fprint(&sb, x)
+	return sb.String()
+}
+
+// coilOutput returns the power flow after the coil c, which is that into
+// it. It is read from the variable of plain and negated coils, which may
+// also be read by the contacts before the coil.
+func (t *diagramTranslator) coilOutput(c *BodyLDCoil) st.Expr {
+	plain := (c.EdgeModifier == nil || *c.EdgeModifier == EdgeModifierTypeNone) &&
+		(c.StorageModifier == nil || *c.StorageModifier == StorageModifierTypeNone)
+	if !plain {
+		return t.coilInput(c)
+	}
+	t.depend(t.nodes[c.LocalID])
+	x := t.variable(c, c.Variable)
+	if c.Negated != nil && *c.Negated {
+		return &st.UnaryExpr{Op: st.NOT, X: x}
+	}
+	return x
+}
+
+// coilInput returns the power flow into the coil c
+func (t *diagramTranslator) coilInput(c *BodyLDCoil) st.Expr {
+	if t.expanding[c.LocalID] {
+		t.errorf(c, "feedback loop in the power flow")
+		return nil
+	}
+	t.expanding[c.LocalID] = true
+	defer delete(t.expanding, c.LocalID)
+	return t.input(c, c.ConnectionPointIn)
+}
+
+// coil returns the statements that set the variable of the coil c
+func (t *diagramTranslator) coil(c *BodyLDCoil) []st.Stmt {
+	in := t.coilInput(c)
+	if in == nil {
+		t.errorf(c, "coil %s is not connected", c.Variable)
+		return nil
+	}
+	if c.Negated != nil && *c.Negated {
+		in = &st.UnaryExpr{Op: st.NOT, X: in}
+	}
+	return t.store(c, c.Variable, in, c.EdgeModifier, c.StorageModifier)
+}
@@ -0,0 +1,117 @@
+package plcopen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"reflect"
+	"unicode/utf8"
+)
+
+// legacyJSONNames are the member names only written by versions before
+// lowerCamelCase names. They differ from the current names only in case.
+var legacyJSONNames = map[string]bool{
+	"bOOL": true, "bYTE": true, "dATE": true, "dINT": true, "dT": true, "dWORD": true,
+	"executionOrderID": true, "fBD": true, "iL": true, "iNT": true, "lD": true,
+	"lINT": true, "lREAL": true, "lWORD": true, "localID": true, "pOUInstances": true,
+	"pOUType": true, "pOUs": true, "rEAL": true, "refLocalID": true, "sFC": true,
+	"sINT": true, "sT": true, "tIME": true, "tOD": true, "uDINT": true, "uINT": true,
+	"uLINT": true, "uSINT": true, "wORD": true, "wString": true, "xMLNSXhtml": true,
+}
+
+// UnmarshalJSON decodes the project. Documents written by versions before
+// lowerCamelCase member names, recognised by names such as pOUs or localID,
+// held documentation as base64 encoded bytes, which is decoded to its text.
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type project Project
+	var decoded project
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*p = Project(decoded)
+	if isLegacyJSON(data) {
+		decodeLegacyDocumentation(reflect.ValueOf(p).Elem())
+	}
+	return nil
+}
+
+// isLegacyJSON reports whether data uses any of the legacyJSONNames
+func isLegacyJSON(data []byte) bool {
+	d := json.NewDecoder(bytes.NewReader(data))
+	// arrays holds for each open container whether it is an array
+	var arrays []bool
+	haveKey := false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return false
+		}
+		inObject := len(arrays) > 0 && !arrays[len(arrays)-1]
+		switch tok := tok.(type) {
+		case json.Delim:
+			switch tok {
+			case '{', '[':
+				arrays = append(arrays, tok == '[')
+			default:
+				arrays = arrays[:len(arrays)-1]
+			}
+			haveKey = false
+			continue
+		case string:
+			// In objects every other string is a key
+			if inObject && !haveKey {
+				if legacyJSONNames[tok] {
+					return true
+				}
+				haveKey = true
+				continue
+			}
+		}
+		haveKey = false
+	}
+}
+
+var formattedTextType = reflect.TypeOf(&FormattedText{})
+
+// decodeLegacyDocumentation replaces the documentation fields reachable from
+// v, read as plain text, with the text their base64 encoding holds
+func decodeLegacyDocumentation(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			decodeLegacyDocumentation(v.Elem())
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			decodeLegacyDocumentation(v.Index(i))
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if field.Name == "Documentation" && field.Type == formattedTextType {
+				if doc := legacyDocumentation(v.Field(i).Interface().(*FormattedText)); doc != nil {
+					v.Field(i).Set(reflect.ValueOf(doc))
+				}
+				continue
+			}
+			decodeLegacyDocumentation(v.Field(i))
+		}
+	}
+}
+
+// legacyDocumentation returns the formatted text of documentation written as
+// base64 encoded bytes, or nil if doc does not hold such text
+func legacyDocumentation(doc *FormattedText) *FormattedText {
+	if doc == nil || !doc.isPlainParagraph() {
+		return nil
+	}
+	text, err := base64.StdEncoding.DecodeString(doc.PlainText())
+	if err != nil || !utf8.Valid(text) {
+		return nil
+	}
+	return formattedTextFromString(string(text))
+}
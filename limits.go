@@ -0,0 +1,225 @@
+package plcopen
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Limits bound the resources Load and Stream spend on a document, for
+// documents from untrusted sources. Zero fields are not limited.
+//
+// Only MaxBytes bounds the memory needed for a single attribute or text.
+// MaxAttrLen and MaxTextLen are checked on the decoded tokens, after the XML
+// decoder has read the whole attribute or text into memory, so on their own
+// they reject such documents but do not keep them from being buffered. Set
+// MaxBytes as well when memory has to be bounded.
+type Limits struct {
+	// MaxBytes is the size of the document as read, before transcoding
+	MaxBytes int64
+	// MaxDepth is the nesting depth of elements, or of objects and arrays in
+	// JSON. It bounds recursive structures such as DataType, Value and the
+	// bodies of actions and transitions.
+	MaxDepth int
+	// MaxPOUs is the number of POUs in the project
+	MaxPOUs int
+	// MaxAttrLen is the length in bytes of an attribute value. It is checked
+	// once the value is decoded, see MaxBytes for bounding memory.
+	MaxAttrLen int
+	// MaxTextLen is the length in bytes of the text of an element, or of a
+	// string in JSON. It is checked once the text is decoded, see MaxBytes
+	// for bounding memory.
+	MaxTextLen int
+}
+
+// LimitKind names the limit a LimitError is about
+type LimitKind string
+
+const (
+	LimitBytes   LimitKind = "MaxBytes"
+	LimitDepth   LimitKind = "MaxDepth"
+	LimitPOUs    LimitKind = "MaxPOUs"
+	LimitAttrLen LimitKind = "MaxAttrLen"
+	LimitTextLen LimitKind = "MaxTextLen"
+)
+
+// LimitError is returned by Load and Stream when a document exceeds one of
+// the Limits set with WithLimits
+type LimitError struct {
+	Limit LimitKind
+	Max   int64
+	// Offset is the byte offset in the document at which the limit was exceeded
+	Offset int64
+	// Line is the line in XML documents, 0 for JSON
+	Line int
+}
+
+func (e *LimitError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("plcopen: document exceeds %s of %d at line %d", e.Limit, e.Max, e.Line)
+	}
+	return fmt.Sprintf("plcopen: document exceeds %s of %d at offset %d", e.Limit, e.Max, e.Offset)
+}
+
+// WithLimits makes Load and Stream fail with a *LimitError on documents
+// exceeding limits
+func WithLimits(limits Limits) Option {
+	return func(o *options) { o.limits = &limits }
+}
+
+// limitReader fails with a LimitError once more than max bytes are read
+type limitReader struct {
+	r   io.Reader
+	max int64
+	n   int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n > l.max {
+		return 0, &LimitError{Limit: LimitBytes, Max: l.max, Offset: l.max}
+	}
+	// Read one byte more than allowed to tell a document of exactly max bytes from a longer one
+	if rest := l.max + 1 - l.n; int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.n > l.max {
+		return n, &LimitError{Limit: LimitBytes, Max: l.max, Offset: l.max}
+	}
+	return n, err
+}
+
+// limitInput applies the MaxBytes limit of o to r
+func limitInput(r io.Reader, o *options) io.Reader {
+	if o.limits == nil || o.limits.MaxBytes <= 0 {
+		return r
+	}
+	return &limitReader{r: r, max: o.limits.MaxBytes}
+}
+
+// limitedTokens checks the tokens of d against limits
+type limitedTokens struct {
+	d      *xml.Decoder
+	limits *Limits
+	// names holds the open elements
+	names []string
+	pous  int
+	// text is the length of the text read since the last start or end element
+	text int
+}
+
+// limitDecoder returns a decoder reading the tokens of d that fails when
+// they exceed the limits of o
+func limitDecoder(d *xml.Decoder, o *options) *xml.Decoder {
+	if o.limits == nil {
+		return d
+	}
+	return xml.NewTokenDecoder(&limitedTokens{d: d, limits: o.limits})
+}
+
+func (t *limitedTokens) Token() (xml.Token, error) {
+	tok, err := t.d.Token()
+	if err != nil {
+		return tok, err
+	}
+	switch tok := tok.(type) {
+	case xml.StartElement:
+		t.text = 0
+		if max := t.limits.MaxDepth; max > 0 && len(t.names) >= max {
+			return nil, t.exceeded(LimitDepth, max)
+		}
+		if tok.Name.Local == "pou" && len(t.names) > 0 && t.names[len(t.names)-1] == "pous" {
+			t.pous++
+			if max := t.limits.MaxPOUs; max > 0 && t.pous > max {
+				return nil, t.exceeded(LimitPOUs, max)
+			}
+		}
+		if max := t.limits.MaxAttrLen; max > 0 {
+			for _, attr := range tok.Attr {
+				if len(attr.Value) > max {
+					return nil, t.exceeded(LimitAttrLen, max)
+				}
+			}
+		}
+		t.names = append(t.names, tok.Name.Local)
+	case xml.EndElement:
+		t.text = 0
+		if len(t.names) > 0 {
+			t.names = t.names[:len(t.names)-1]
+		}
+	case xml.CharData:
+		t.text += len(tok)
+		if max := t.limits.MaxTextLen; max > 0 && t.text > max {
+			return nil, t.exceeded(LimitTextLen, max)
+		}
+	}
+	return tok, nil
+}
+
+func (t *limitedTokens) exceeded(limit LimitKind, max int) error {
+	line, _ := t.d.InputPos()
+	return &LimitError{Limit: limit, Max: int64(max), Offset: t.d.InputOffset(), Line: line}
+}
+
+// checkJSONLimits checks a JSON document against limits before it is decoded
+func checkJSONLimits(data []byte, limits *Limits) error {
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	type frame struct {
+		key   string
+		array bool
+	}
+	var (
+		stack   []frame
+		key     string
+		haveKey bool
+		pous    int
+	)
+	exceeded := func(limit LimitKind, max int) error {
+		return &LimitError{Limit: limit, Max: int64(max), Offset: d.InputOffset()}
+	}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			// Syntax errors are reported by decoding
+			return nil
+		}
+		// In objects every other string is a key
+		inObject := len(stack) > 0 && !stack[len(stack)-1].array
+		switch tok := tok.(type) {
+		case json.Delim:
+			switch tok {
+			case '{', '[':
+				if max := limits.MaxDepth; max > 0 && len(stack) >= max {
+					return exceeded(LimitDepth, max)
+				}
+				// POUs are the elements of the array types.pous
+				if n := len(stack); tok == '{' && n >= 2 && stack[n-1].array &&
+					strings.EqualFold(stack[n-1].key, "pous") && strings.EqualFold(stack[n-2].key, "types") {
+					pous++
+					if max := limits.MaxPOUs; max > 0 && pous > max {
+						return exceeded(LimitPOUs, max)
+					}
+				}
+				stack = append(stack, frame{key: key, array: tok == '['})
+			default:
+				stack = stack[:len(stack)-1]
+			}
+			key, haveKey = "", false
+			continue
+		case string:
+			if max := limits.MaxTextLen; max > 0 && len(tok) > max {
+				return exceeded(LimitTextLen, max)
+			}
+			if inObject && !haveKey {
+				key, haveKey = tok, true
+				continue
+			}
+		}
+		key, haveKey = "", false
+	}
+}
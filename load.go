@@ -0,0 +1,337 @@
+package plcopen
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Namespace is the XML namespace of TC6 XML V1.0B documents
+const Namespace = "http://www.plcopen.org/xml/tc6.xsd"
+
+// Format is the serialization format of a project
+type Format int
+
+const (
+	// FormatAuto detects the format when loading and writes XML when saving
+	FormatAuto Format = iota
+	FormatXML
+	FormatJSON
+)
+
+// Option configures Load and Save
+type Option func(*options)
+
+type options struct {
+	format  Format
+	charset string
+	prefix  string
+	indent  string
+	warn    func(path, message string)
+	limits  *Limits
+	// addData writes addData to XML output, see WithAddData
+	addData bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{indent: "  "}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithFormat forces the format instead of detecting it. Save writes XML
+// unless FormatJSON is given.
+func WithFormat(format Format) Option {
+	return func(o *options) { o.format = format }
+}
+
+// WithCharset sets the character encoding of the document, e.g. "gbk" or
+// "windows-1252". Load uses it in place of the encoding declared by the
+// document, which helps with tools that declare UTF-8 but write their local
+// code page. Save encodes XML output with it and declares it.
+func WithCharset(charset string) Option {
+	return func(o *options) { o.charset = charset }
+}
+
+// WithIndent sets the indentation Save uses, two spaces by default. An
+// empty indent writes compact output.
+func WithIndent(prefix, indent string) Option {
+	return func(o *options) { o.prefix, o.indent = prefix, indent }
+}
+
+// WithWarnings accepts documents that Load can only convert to V1.0B with
+// losses and calls warn for every lost part. Without it Load fails on such
+// documents. Save calls warn for every addData element it leaves out of XML
+// output, see WithAddData.
+func WithWarnings(warn func(path, message string)) Option {
+	return func(o *options) { o.warn = warn }
+}
+
+// WithAddData makes Save write addData to XML output. TC6 XML V1.0B does not
+// define addData, so such output is not valid against the V1.0B schema, but
+// tools that wrote the vendor data read it back.
+func WithAddData() Option {
+	return func(o *options) { o.addData = true }
+}
+
+// NamespaceDecoder decodes the project starting at start, the root element of
+// a document in another TC6 XML version, and converts it to V1.0B. Parts
+// that cannot be converted are reported with warn.
+type NamespaceDecoder func(d *xml.Decoder, start xml.StartElement, warn func(path, message string)) (*Project, error)
+
+var (
+	namespacesMu sync.RWMutex
+	namespaces   = map[string]NamespaceDecoder{}
+)
+
+// RegisterNamespace makes Load accept documents whose root element is in
+// namespace ns. Packages for other TC6 XML versions call it from init, so
+// importing them is enough to load their documents.
+func RegisterNamespace(ns string, decode NamespaceDecoder) {
+	namespacesMu.Lock()
+	defer namespacesMu.Unlock()
+	namespaces[ns] = decode
+}
+
+// Load reads a project in XML or JSON from r. The format is detected from
+// the content, a byte order mark and the declared character encoding are
+// honoured, and documents of other TC6 XML versions are converted if their
+// package is imported (see RegisterNamespace). JSON written by earlier
+// versions is accepted: their member names, such as fBD, pOUs and localID,
+// differ from the current names only in case, and their ST and IL markup
+// strings and base64 encoded documentation are decoded (see
+// Project.UnmarshalJSON).
+func Load(r io.Reader, opts ...Option) (*Project, error) {
+	o := newOptions(opts)
+	data, err := io.ReadAll(limitInput(r, o))
+	if err != nil {
+		return nil, err
+	}
+	// Decode the document to UTF-8 up front if the encoding is known from a
+	// byte order mark or an option, the declaration is ignored then
+	transcoded := true
+	switch {
+	case o.charset != "":
+		enc, err := lookupCharset(o.charset)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = enc.NewDecoder().Bytes(data); err != nil {
+			return nil, fmt.Errorf("plcopen: decoding %s: %w", o.charset, err)
+		}
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}), bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		if data, err = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder().Bytes(data); err != nil {
+			return nil, fmt.Errorf("plcopen: decoding UTF-16: %w", err)
+		}
+	default:
+		transcoded = false
+	}
+	data = bytes.TrimPrefix(data, []byte("\xEF\xBB\xBF"))
+
+	format := o.format
+	if format == FormatAuto {
+		format = FormatXML
+		if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '{' {
+			format = FormatJSON
+		}
+	}
+	switch format {
+	case FormatJSON:
+		if !utf8.Valid(data) {
+			return nil, fmt.Errorf("plcopen: JSON document is not valid UTF-8, set its charset with WithCharset")
+		}
+		if o.limits != nil {
+			if err := checkJSONLimits(data, o.limits); err != nil {
+				return nil, err
+			}
+		}
+		var project Project
+		if err := json.Unmarshal(data, &project); err != nil {
+			return nil, err
+		}
+		return &project, nil
+	case FormatXML:
+		return loadXML(data, transcoded, o)
+	}
+	return nil, fmt.Errorf("plcopen: unknown format %d", format)
+}
+
+func loadXML(data []byte, transcoded bool, o *options) (*Project, error) {
+	d := newXMLDecoder(bytes.NewReader(data), transcoded, o)
+	start, err := rootElement(d)
+	if err != nil {
+		return nil, err
+	}
+	if start.Name.Space == Namespace {
+		var project Project
+		if err := d.DecodeElement(&project, &start); err != nil {
+			return nil, err
+		}
+		return &project, nil
+	}
+
+	namespacesMu.RLock()
+	decode := namespaces[start.Name.Space]
+	namespacesMu.RUnlock()
+	if decode == nil {
+		return nil, fmt.Errorf("plcopen: unsupported document namespace %q", start.Name.Space)
+	}
+	var lost []string
+	warn := o.warn
+	if warn == nil {
+		warn = func(path, message string) { lost = append(lost, path+": "+message) }
+	}
+	project, err := decode(d, start, warn)
+	if err != nil {
+		return nil, err
+	}
+	if len(lost) > 0 {
+		return nil, fmt.Errorf("plcopen: document in namespace %q cannot be converted to V1.0B without losses, accept them with WithWarnings: %s",
+			start.Name.Space, strings.Join(lost, "; "))
+	}
+	return project, nil
+}
+
+// OpenXML prepares the XML document read from r for decoding as Load does:
+// a byte order mark and the declared character encoding are honoured, and
+// WithCharset and WithLimits apply. It returns a decoder positioned after
+// the start of the root element, which must be a project, and that element.
+// Elements without a namespace are in Namespace. Packages for other TC6 XML
+// versions use it to decode documents in their own model.
+func OpenXML(r io.Reader, opts ...Option) (*xml.Decoder, xml.StartElement, error) {
+	return openXML(r, newOptions(opts))
+}
+
+func openXML(r io.Reader, o *options) (*xml.Decoder, xml.StartElement, error) {
+	input, transcoded, err := streamInput(limitInput(r, o), o)
+	if err != nil {
+		return nil, xml.StartElement{}, err
+	}
+	d := newXMLDecoder(input, transcoded, o)
+	start, err := rootElement(d)
+	if err != nil {
+		return nil, xml.StartElement{}, err
+	}
+	return d, start, nil
+}
+
+// newXMLDecoder returns a decoder for r, whose content is already UTF-8 if transcoded
+func newXMLDecoder(r io.Reader, transcoded bool, o *options) *xml.Decoder {
+	d := xml.NewDecoder(r)
+	// Documents without a namespace are taken as V1.0B
+	d.DefaultSpace = Namespace
+	if transcoded {
+		d.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+	} else {
+		d.CharsetReader = CharsetReader
+	}
+	return limitDecoder(d, o)
+}
+
+// rootElement returns the root element of the document read by d, which must be a project
+func rootElement(d *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return xml.StartElement{}, fmt.Errorf("plcopen: document has no root element")
+		}
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			if start.Name.Local != "project" {
+				return xml.StartElement{}, fmt.Errorf("plcopen: root element is %q, want \"project\"", start.Name.Local)
+			}
+			return start, nil
+		}
+	}
+}
+
+// CharsetReader converts input in the named character encoding to UTF-8. It
+// knows the encodings of the WHATWG Encoding Standard, among them
+// windows-1252, ISO-8859-x, GBK, GB18030, Big5 and Shift_JIS, and can be
+// used as xml.Decoder.CharsetReader.
+func CharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := lookupCharset(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
+
+func lookupCharset(charset string) (encoding.Encoding, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, fmt.Errorf("plcopen: unsupported charset %q", charset)
+	}
+	return enc, nil
+}
+
+// Save writes the project to w as an XML document with declaration and
+// namespace, or as JSON if FormatJSON is given. XML output is valid against
+// the V1.0B schema, which does not define addData: it is left out and every
+// dropped element is reported to the function set with WithWarnings, unless
+// WithAddData is given. JSON output keeps addData.
+func (p *Project) Save(w io.Writer, opts ...Option) error {
+	o := newOptions(opts)
+	if o.format == FormatJSON {
+		if o.charset != "" && !isUTF8(o.charset) {
+			return fmt.Errorf("plcopen: JSON is always written as UTF-8, got charset %q", o.charset)
+		}
+		e := json.NewEncoder(w)
+		e.SetEscapeHTML(false)
+		e.SetIndent(o.prefix, o.indent)
+		return e.Encode(p)
+	}
+
+	project := p
+	if !o.addData {
+		warn := o.warn
+		if warn == nil {
+			warn = func(path, message string) {}
+		}
+		project = withoutAddData(reflect.ValueOf(p), "project", warn).Interface().(*Project)
+	}
+	var buf bytes.Buffer
+	charset := "utf-8"
+	if o.charset != "" && !isUTF8(o.charset) {
+		charset = o.charset
+	}
+	fmt.Fprintf(&buf, "<?xml version=\"1.0\" encoding=\"%s\"?>\n", charset)
+	e := xml.NewEncoder(&buf)
+	e.Indent(o.prefix, o.indent)
+	if err := e.Encode(project); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+
+	data := buf.Bytes()
+	if charset != "utf-8" {
+		enc, err := lookupCharset(charset)
+		if err != nil {
+			return err
+		}
+		// Characters missing from the charset are written as character references
+		if data, err = encoding.HTMLEscapeUnsupported(enc.NewEncoder()).Bytes(data); err != nil {
+			return fmt.Errorf("plcopen: encoding %s: %w", charset, err)
+		}
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+func isUTF8(charset string) bool {
+	return strings.EqualFold(charset, "utf-8") || strings.EqualFold(charset, "utf8")
+}
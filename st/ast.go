@@ -0,0 +1,364 @@
+package st
+
+import "strings"
+
+// Node is a node of the syntax tree. Pos is the position of its first
+// character and End the position just after its last one. Nodes built in
+// code instead of parsed have invalid positions.
+type Node interface {
+	Pos() Pos
+	End() Pos
+}
+
+// Expr is an expression
+type Expr interface {
+	Node
+	exprNode()
+}
+
+// Stmt is a statement
+type Stmt interface {
+	Node
+	stmtNode()
+}
+
+// Body is the statement list of an ST POU body, transition or action
+type Body struct {
+	Stmts []Stmt
+	// Comments are the comments and pragmas of the source in source order,
+	// they are not part of Stmts
+	Comments []*Comment
+}
+
+// Comment is a comment (* ... *), /* ... */, // ... or a pragma { ... }
+type Comment struct {
+	Start Pos
+	// Text is the comment including its delimiters
+	Text string
+}
+
+// IsPragma reports whether c is a pragma { ... }
+func (c *Comment) IsPragma() bool {
+	return strings.HasPrefix(c.Text, "{")
+}
+
+// Expressions
+type (
+	// Ident is a variable or POU name, or a direct address such as %IX0.0
+	Ident struct {
+		NamePos Pos
+		Name    string
+	}
+
+	// Literal is a constant in its source form, e.g. 16#FF, 'abc' or T#5s
+	Literal struct {
+		ValuePos Pos
+		Kind     LiteralKind
+		Value    string
+	}
+
+	// ParenExpr is an expression in parentheses
+	ParenExpr struct {
+		Lparen Pos
+		X      Expr
+		Rparen Pos
+	}
+
+	// UnaryExpr is -X, +X or NOT X
+	UnaryExpr struct {
+		OpPos Pos
+		Op    Token
+		X     Expr
+	}
+
+	// BinaryExpr is X Op Y
+	BinaryExpr struct {
+		X     Expr
+		OpPos Pos
+		Op    Token
+		Y     Expr
+	}
+
+	// SelectorExpr is X.Sel, a structure member, FB output or bit access
+	// such as word.3
+	SelectorExpr struct {
+		X   Expr
+		Sel *Ident
+	}
+
+	// IndexExpr is X[Indices]
+	IndexExpr struct {
+		X       Expr
+		Lbrack  Pos
+		Indices []Expr
+		Rbrack  Pos
+	}
+
+	// DerefExpr is X^
+	DerefExpr struct {
+		X     Expr
+		Caret Pos
+	}
+
+	// CallExpr is a function or function block call Fun(Args)
+	CallExpr struct {
+		Fun    Expr
+		Lparen Pos
+		Args   []*Arg
+		Rparen Pos
+	}
+
+	// RangeExpr is Low..High, used as CASE label
+	RangeExpr struct {
+		Low  Expr
+		High Expr
+	}
+
+	// BadExpr is a placeholder for an expression with syntax errors
+	BadExpr struct {
+		From, To Pos
+	}
+)
+
+// Arg is an argument of a call: positional (Name is nil), formal input
+// Name := Value, or formal output Name => Value, optionally negated as
+// NOT Name => Value
+type Arg struct {
+	Negated bool
+	NotPos  Pos // position of NOT if Negated
+	Name    *Ident
+	Op      Token // ASSIGN, OUTPUT or ILLEGAL for positional arguments
+	Value   Expr
+}
+
+// Statements
+type (
+	// AssignStmt is Target := Value
+	AssignStmt struct {
+		Target Expr
+		TokPos Pos
+		Value  Expr
+	}
+
+	// CallStmt is a function block or function call used as statement
+	CallStmt struct {
+		Call *CallExpr
+	}
+
+	// IfStmt is IF Cond THEN Then ELSIF ... ELSE Else END_IF
+	IfStmt struct {
+		If     Pos
+		Cond   Expr
+		Then   []Stmt
+		ElsIfs []*ElsIf
+		Else   []Stmt
+		// ElsePos is the position of ELSE, invalid without ELSE
+		ElsePos Pos
+		EndIf   Pos
+	}
+
+	// CaseStmt is CASE X OF Clauses ELSE Else END_CASE
+	CaseStmt struct {
+		Case    Pos
+		X       Expr
+		Clauses []*CaseClause
+		Else    []Stmt
+		// ElsePos is the position of ELSE, invalid without ELSE
+		ElsePos Pos
+		EndCase Pos
+	}
+
+	// ForStmt is FOR Var := From TO To BY By DO Body END_FOR, By is nil
+	// if omitted
+	ForStmt struct {
+		For    Pos
+		Var    *Ident
+		From   Expr
+		To     Expr
+		By     Expr
+		Body   []Stmt
+		EndFor Pos
+	}
+
+	// WhileStmt is WHILE Cond DO Body END_WHILE
+	WhileStmt struct {
+		While    Pos
+		Cond     Expr
+		Body     []Stmt
+		EndWhile Pos
+	}
+
+	// RepeatStmt is REPEAT Body UNTIL Cond END_REPEAT
+	RepeatStmt struct {
+		Repeat    Pos
+		Body      []Stmt
+		Cond      Expr
+		EndRepeat Pos
+	}
+
+	// ExitStmt is EXIT
+	ExitStmt struct {
+		Exit Pos
+	}
+
+	// ContinueStmt is CONTINUE
+	ContinueStmt struct {
+		Continue Pos
+	}
+
+	// ReturnStmt is RETURN
+	ReturnStmt struct {
+		Return Pos
+	}
+
+	// EmptyStmt is a lone semicolon
+	EmptyStmt struct {
+		Semicolon Pos
+	}
+
+	// BadStmt is a placeholder for a statement with syntax errors
+	BadStmt struct {
+		From, To Pos
+	}
+)
+
+// ElsIf is an ELSIF Cond THEN Then part of an IfStmt
+type ElsIf struct {
+	ElsIf Pos
+	Cond  Expr
+	Then  []Stmt
+}
+
+// CaseClause is a Labels: Body part of a CaseStmt, labels are expressions
+// or RangeExprs
+type CaseClause struct {
+	Labels []Expr
+	Colon  Pos
+	Body   []Stmt
+}
+
+func (x *Ident) Pos() Pos        { return x.NamePos }
+func (x *Literal) Pos() Pos      { return x.ValuePos }
+func (x *ParenExpr) Pos() Pos    { return x.Lparen }
+func (x *UnaryExpr) Pos() Pos    { return x.OpPos }
+func (x *BinaryExpr) Pos() Pos   { return x.X.Pos() }
+func (x *SelectorExpr) Pos() Pos { return x.X.Pos() }
+func (x *IndexExpr) Pos() Pos    { return x.X.Pos() }
+func (x *DerefExpr) Pos() Pos    { return x.X.Pos() }
+func (x *CallExpr) Pos() Pos     { return x.Fun.Pos() }
+func (x *RangeExpr) Pos() Pos    { return x.Low.Pos() }
+func (x *BadExpr) Pos() Pos      { return x.From }
+
+func (x *Ident) End() Pos        { return x.NamePos.after(x.Name) }
+func (x *Literal) End() Pos      { return x.ValuePos.after(x.Value) }
+func (x *ParenExpr) End() Pos    { return x.Rparen.after(")") }
+func (x *UnaryExpr) End() Pos    { return x.X.End() }
+func (x *BinaryExpr) End() Pos   { return x.Y.End() }
+func (x *SelectorExpr) End() Pos { return x.Sel.End() }
+func (x *IndexExpr) End() Pos    { return x.Rbrack.after("]") }
+func (x *DerefExpr) End() Pos    { return x.Caret.after("^") }
+func (x *CallExpr) End() Pos     { return x.Rparen.after(")") }
+func (x *RangeExpr) End() Pos    { return x.High.End() }
+func (x *BadExpr) End() Pos      { return x.To }
+
+func (*Ident) exprNode()        {}
+func (*Literal) exprNode()      {}
+func (*ParenExpr) exprNode()    {}
+func (*UnaryExpr) exprNode()    {}
+func (*BinaryExpr) exprNode()   {}
+func (*SelectorExpr) exprNode() {}
+func (*IndexExpr) exprNode()    {}
+func (*DerefExpr) exprNode()    {}
+func (*CallExpr) exprNode()     {}
+func (*RangeExpr) exprNode()    {}
+func (*BadExpr) exprNode()      {}
+
+func (s *AssignStmt) Pos() Pos   { return s.Target.Pos() }
+func (s *CallStmt) Pos() Pos     { return s.Call.Pos() }
+func (s *IfStmt) Pos() Pos       { return s.If }
+func (s *CaseStmt) Pos() Pos     { return s.Case }
+func (s *ForStmt) Pos() Pos      { return s.For }
+func (s *WhileStmt) Pos() Pos    { return s.While }
+func (s *RepeatStmt) Pos() Pos   { return s.Repeat }
+func (s *ExitStmt) Pos() Pos     { return s.Exit }
+func (s *ContinueStmt) Pos() Pos { return s.Continue }
+func (s *ReturnStmt) Pos() Pos   { return s.Return }
+func (s *EmptyStmt) Pos() Pos    { return s.Semicolon }
+func (s *BadStmt) Pos() Pos      { return s.From }
+
+func (s *AssignStmt) End() Pos   { return s.Value.End() }
+func (s *CallStmt) End() Pos     { return s.Call.End() }
+func (s *IfStmt) End() Pos       { return s.EndIf.after("END_IF") }
+func (s *CaseStmt) End() Pos     { return s.EndCase.after("END_CASE") }
+func (s *ForStmt) End() Pos      { return s.EndFor.after("END_FOR") }
+func (s *WhileStmt) End() Pos    { return s.EndWhile.after("END_WHILE") }
+func (s *RepeatStmt) End() Pos   { return s.EndRepeat.after("END_REPEAT") }
+func (s *ExitStmt) End() Pos     { return s.Exit.after("EXIT") }
+func (s *ContinueStmt) End() Pos { return s.Continue.after("CONTINUE") }
+func (s *ReturnStmt) End() Pos   { return s.Return.after("RETURN") }
+func (s *EmptyStmt) End() Pos    { return s.Semicolon.after(";") }
+func (s *BadStmt) End() Pos      { return s.To }
+
+func (*AssignStmt) stmtNode()   {}
+func (*CallStmt) stmtNode()     {}
+func (*IfStmt) stmtNode()       {}
+func (*CaseStmt) stmtNode()     {}
+func (*ForStmt) stmtNode()      {}
+func (*WhileStmt) stmtNode()    {}
+func (*RepeatStmt) stmtNode()   {}
+func (*ExitStmt) stmtNode()     {}
+func (*ContinueStmt) stmtNode() {}
+func (*ReturnStmt) stmtNode()   {}
+func (*EmptyStmt) stmtNode()    {}
+func (*BadStmt) stmtNode()      {}
+
+// Pos returns the position of the first statement
+func (b *Body) Pos() Pos {
+	if len(b.Stmts) == 0 {
+		return Pos{}
+	}
+	return b.Stmts[0].Pos()
+}
+
+// End returns the end of the last statement
+func (b *Body) End() Pos {
+	if len(b.Stmts) == 0 {
+		return Pos{}
+	}
+	return b.Stmts[len(b.Stmts)-1].End()
+}
+
+func (a *Arg) Pos() Pos {
+	switch {
+	case a.Negated && a.NotPos.IsValid():
+		return a.NotPos
+	case a.Name != nil:
+		return a.Name.Pos()
+	}
+	return a.Value.Pos()
+}
+
+func (a *Arg) End() Pos { return a.Value.End() }
+
+func (e *ElsIf) Pos() Pos { return e.ElsIf }
+
+// End returns the end of the last statement, or of the condition
+func (e *ElsIf) End() Pos {
+	if len(e.Then) > 0 {
+		return e.Then[len(e.Then)-1].End()
+	}
+	return e.Cond.End()
+}
+
+func (c *CaseClause) Pos() Pos { return c.Labels[0].Pos() }
+
+// End returns the end of the last statement, or after the colon
+func (c *CaseClause) End() Pos {
+	if len(c.Body) > 0 {
+		return c.Body[len(c.Body)-1].End()
+	}
+	return c.Colon.after(":")
+}
+
+func (c *Comment) Pos() Pos { return c.Start }
+func (c *Comment) End() Pos { return c.Start.after(c.Text) }
@@ -0,0 +1,478 @@
+// Package st parses IEC 61131-3 Structured Text into a syntax tree.
+//
+// Parse reads the statement list of a POU body, action or transition, such
+// as the text of a plcopen.BodyST:
+//
+//	body, err := st.Parse(pou.Body.ST.Xhtml.PlainText())
+//
+// Keywords are case-insensitive. Every node records its source position,
+// comments and pragmas are kept in Body.Comments. Declarations (VAR blocks)
+// are not part of ST bodies in PLCopen XML and are not parsed.
+package st
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Error is a syntax error
+type Error struct {
+	Pos Pos
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos, e.Msg)
+}
+
+// Errors are all syntax errors found in a source, in source order
+type Errors []*Error
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0], len(e)-1)
+}
+
+// maxErrors is the number of errors after which parsing stops
+const maxErrors = 10
+
+// Parse parses the statements in src. On syntax errors it returns Errors
+// along with a Body in which the erroneous statements are BadStmts.
+// Statements end with a semicolon, which is optional after END_IF,
+// END_CASE, END_FOR, END_WHILE and END_REPEAT.
+func Parse(src string) (*Body, error) {
+	p := newParser(src)
+	body := &Body{Stmts: p.stmtList()}
+	if p.tok.tok != EOF {
+		p.errorf(p.tok.pos, "unexpected %s", p.describe())
+	}
+	body.Comments = p.scanner.comments
+	return body, p.err()
+}
+
+// ParseExpr parses a single expression
+func ParseExpr(src string) (Expr, error) {
+	p := newParser(src)
+	x := p.expr()
+	if p.tok.tok != EOF && len(p.errs) == 0 {
+		p.errorf(p.tok.pos, "unexpected %s after expression", p.describe())
+	}
+	return x, p.err()
+}
+
+type parser struct {
+	scanner *scanner
+	items   []item
+	next    int
+	tok     item // current token
+	errs    Errors
+}
+
+func newParser(src string) *parser {
+	s := newScanner(src)
+	p := &parser{scanner: s, items: s.scanAll()}
+	p.errs = s.errs
+	p.tok = p.items[0]
+	return p
+}
+
+func (p *parser) err() error {
+	if len(p.errs) == 0 {
+		return nil
+	}
+	return p.errs
+}
+
+func (p *parser) errorf(pos Pos, format string, args ...interface{}) {
+	p.errs = append(p.errs, &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+// describe returns the current token for error messages
+func (p *parser) describe() string {
+	switch p.tok.tok {
+	case EOF:
+		return "end of input"
+	case IDENT:
+		return "identifier " + p.tok.text
+	case LITERAL:
+		return p.tok.kind.String() + " " + p.tok.text
+	}
+	return "'" + p.tok.tok.String() + "'"
+}
+
+// advance moves to the next token and returns the position of the current one
+func (p *parser) advance() Pos {
+	pos := p.tok.pos
+	if p.next+1 < len(p.items) {
+		p.next++
+		p.tok = p.items[p.next]
+	}
+	return pos
+}
+
+// peek returns the token n positions after the current one
+func (p *parser) peek(n int) Token {
+	if i := p.next + n; i < len(p.items) {
+		return p.items[i].tok
+	}
+	return EOF
+}
+
+// expect consumes tok or reports an error, it returns the position of the token
+func (p *parser) expect(tok Token) Pos {
+	pos := p.tok.pos
+	if p.tok.tok != tok {
+		p.errorf(pos, "expected '%s', found %s", tok, p.describe())
+		return pos
+	}
+	p.advance()
+	return pos
+}
+
+// endsList reports whether the current token ends a statement list
+func (p *parser) endsList() bool {
+	switch p.tok.tok {
+	case EOF, ELSIF, ELSE, END_IF, END_CASE, END_FOR, END_WHILE, UNTIL, END_REPEAT:
+		return true
+	}
+	return false
+}
+
+func (p *parser) stmtList() []Stmt {
+	var list []Stmt
+	for !p.endsList() && len(p.errs) < maxErrors {
+		list = append(list, p.stmt())
+	}
+	return list
+}
+
+// stmt parses a statement. After a syntax error in a simple statement it
+// skips to the next semicolon or statement keyword and returns a BadStmt;
+// compound statements keep their valid parts.
+func (p *parser) stmt() Stmt {
+	start, first, errs := p.tok.pos, p.next, len(p.errs)
+	s := p.stmtNoSync()
+	if len(p.errs) == errs {
+		return s
+	}
+	switch s.(type) {
+	case *IfStmt, *CaseStmt, *ForStmt, *WhileStmt, *RepeatStmt:
+		return s
+	}
+	for (p.next == first || p.items[p.next-1].tok != SEMICOLON) && !p.endsList() {
+		switch p.tok.tok {
+		case IF, CASE, FOR, WHILE, REPEAT, EXIT, CONTINUE, RETURN:
+			if p.next > first {
+				return &BadStmt{From: start, To: p.tok.pos}
+			}
+		}
+		p.advance()
+	}
+	return &BadStmt{From: start, To: p.tok.pos}
+}
+
+func (p *parser) stmtNoSync() Stmt {
+	var s Stmt
+	switch p.tok.tok {
+	case IF:
+		s = p.ifStmt()
+	case CASE:
+		s = p.caseStmt()
+	case FOR:
+		s = p.forStmt()
+	case WHILE:
+		s = p.whileStmt()
+	case REPEAT:
+		s = p.repeatStmt()
+	case SEMICOLON:
+		return &EmptyStmt{Semicolon: p.advance()}
+	case EXIT:
+		s = &ExitStmt{Exit: p.advance()}
+	case CONTINUE:
+		s = &ContinueStmt{Continue: p.advance()}
+	case RETURN:
+		s = &ReturnStmt{Return: p.advance()}
+	default:
+		s = p.simpleStmt()
+	}
+	switch s.(type) {
+	case *IfStmt, *CaseStmt, *ForStmt, *WhileStmt, *RepeatStmt:
+		if p.tok.tok == SEMICOLON {
+			p.advance()
+		}
+	default:
+		p.expect(SEMICOLON)
+	}
+	return s
+}
+
+// simpleStmt parses an assignment or a call
+func (p *parser) simpleStmt() Stmt {
+	start := p.tok.pos
+	if p.tok.tok != IDENT {
+		p.errorf(start, "expected statement, found %s", p.describe())
+		return &BadStmt{From: start, To: p.tok.pos}
+	}
+	x := p.unaryExpr()
+	if p.tok.tok == ASSIGN {
+		pos := p.advance()
+		return &AssignStmt{Target: x, TokPos: pos, Value: p.expr()}
+	}
+	if call, ok := x.(*CallExpr); ok {
+		return &CallStmt{Call: call}
+	}
+	p.errorf(p.tok.pos, "expected ':=' or call, found %s", p.describe())
+	return &BadStmt{From: start, To: p.tok.pos}
+}
+
+func (p *parser) ifStmt() *IfStmt {
+	s := &IfStmt{If: p.advance()}
+	s.Cond = p.expr()
+	p.expect(THEN)
+	s.Then = p.stmtList()
+	for p.tok.tok == ELSIF {
+		e := &ElsIf{ElsIf: p.advance()}
+		e.Cond = p.expr()
+		p.expect(THEN)
+		e.Then = p.stmtList()
+		s.ElsIfs = append(s.ElsIfs, e)
+	}
+	if p.tok.tok == ELSE {
+		s.ElsePos = p.advance()
+		s.Else = p.stmtList()
+	}
+	s.EndIf = p.expect(END_IF)
+	return s
+}
+
+func (p *parser) caseStmt() *CaseStmt {
+	s := &CaseStmt{Case: p.advance()}
+	s.X = p.expr()
+	p.expect(OF)
+	for p.tok.tok != ELSE && p.tok.tok != END_CASE && p.tok.tok != EOF && len(p.errs) < maxErrors {
+		c := &CaseClause{}
+		for {
+			c.Labels = append(c.Labels, p.caseLabel())
+			if p.tok.tok != COMMA {
+				break
+			}
+			p.advance()
+		}
+		c.Colon = p.expect(COLON)
+		for !p.endsList() && !p.isCaseLabel() && len(p.errs) < maxErrors {
+			c.Body = append(c.Body, p.stmt())
+		}
+		s.Clauses = append(s.Clauses, c)
+	}
+	if p.tok.tok == ELSE {
+		s.ElsePos = p.advance()
+		s.Else = p.stmtList()
+	}
+	s.EndCase = p.expect(END_CASE)
+	return s
+}
+
+// caseLabel parses a label, a constant expression or a range
+func (p *parser) caseLabel() Expr {
+	x := p.expr()
+	if p.tok.tok == RANGE {
+		p.advance()
+		return &RangeExpr{Low: x, High: p.expr()}
+	}
+	return x
+}
+
+// isCaseLabel reports whether the tokens ahead are case labels followed by
+// a colon rather than a statement
+func (p *parser) isCaseLabel() bool {
+	if p.tok.tok.IsKeyword() && p.tok.tok != NOT {
+		return false
+	}
+	depth := 0
+	for i := 0; ; i++ {
+		switch p.peek(i) {
+		case LPAREN, LBRACK:
+			depth++
+		case RPAREN, RBRACK:
+			depth--
+		case COLON:
+			if depth == 0 {
+				return true
+			}
+		case ASSIGN, OUTPUT, SEMICOLON, EOF:
+			if depth == 0 {
+				return false
+			}
+		case IF, CASE, FOR, WHILE, REPEAT, END_CASE, ELSE:
+			return false
+		}
+	}
+}
+
+func (p *parser) forStmt() *ForStmt {
+	s := &ForStmt{For: p.advance()}
+	if p.tok.tok == IDENT {
+		s.Var = p.ident()
+	} else {
+		p.errorf(p.tok.pos, "expected control variable, found %s", p.describe())
+		s.Var = &Ident{NamePos: p.tok.pos}
+		if !p.tok.tok.IsKeyword() && p.tok.tok != EOF {
+			p.advance()
+		}
+	}
+	p.expect(ASSIGN)
+	s.From = p.expr()
+	p.expect(TO)
+	s.To = p.expr()
+	if p.tok.tok == BY {
+		p.advance()
+		s.By = p.expr()
+	}
+	p.expect(DO)
+	s.Body = p.stmtList()
+	s.EndFor = p.expect(END_FOR)
+	return s
+}
+
+func (p *parser) whileStmt() *WhileStmt {
+	s := &WhileStmt{While: p.advance()}
+	s.Cond = p.expr()
+	p.expect(DO)
+	s.Body = p.stmtList()
+	s.EndWhile = p.expect(END_WHILE)
+	return s
+}
+
+func (p *parser) repeatStmt() *RepeatStmt {
+	s := &RepeatStmt{Repeat: p.advance()}
+	s.Body = p.stmtList()
+	p.expect(UNTIL)
+	s.Cond = p.expr()
+	s.EndRepeat = p.expect(END_REPEAT)
+	return s
+}
+
+func (p *parser) ident() *Ident {
+	id := &Ident{NamePos: p.tok.pos, Name: p.tok.text}
+	p.advance()
+	return id
+}
+
+func (p *parser) expr() Expr {
+	return p.binaryExpr(1)
+}
+
+// binaryExpr parses operators of at least precedence prec, left-associative
+func (p *parser) binaryExpr(prec int) Expr {
+	x := p.unaryExpr()
+	for {
+		op := p.tok.tok
+		oprec := op.Precedence()
+		if oprec < prec {
+			return x
+		}
+		pos := p.advance()
+		y := p.binaryExpr(oprec + 1)
+		x = &BinaryExpr{X: x, OpPos: pos, Op: op, Y: y}
+	}
+}
+
+func (p *parser) unaryExpr() Expr {
+	switch p.tok.tok {
+	case SUB, ADD, NOT:
+		op := p.tok.tok
+		pos := p.advance()
+		return &UnaryExpr{OpPos: pos, Op: op, X: p.unaryExpr()}
+	}
+	return p.primaryExpr()
+}
+
+// primaryExpr parses an operand with its selectors, indices, dereferences and calls
+func (p *parser) primaryExpr() Expr {
+	var x Expr
+	switch p.tok.tok {
+	case IDENT:
+		x = p.ident()
+	case LITERAL:
+		x = &Literal{ValuePos: p.tok.pos, Kind: p.tok.kind, Value: p.tok.text}
+		p.advance()
+	case LPAREN:
+		lparen := p.advance()
+		inner := p.expr()
+		x = &ParenExpr{Lparen: lparen, X: inner, Rparen: p.expect(RPAREN)}
+	default:
+		pos := p.tok.pos
+		p.errorf(pos, "expected expression, found %s", p.describe())
+		if p.tok.tok != EOF && p.tok.tok != SEMICOLON && !p.tok.tok.IsKeyword() {
+			p.advance()
+		}
+		return &BadExpr{From: pos, To: p.tok.pos}
+	}
+	for {
+		switch p.tok.tok {
+		case PERIOD:
+			p.advance()
+			switch {
+			case p.tok.tok == IDENT:
+				x = &SelectorExpr{X: x, Sel: p.ident()}
+			case p.tok.tok == LITERAL && p.tok.kind == IntLit && !strings.Contains(p.tok.text, "#"):
+				// Bit access such as word.3
+				x = &SelectorExpr{X: x, Sel: p.ident()}
+			default:
+				p.errorf(p.tok.pos, "expected member name, found %s", p.describe())
+				return x
+			}
+		case LBRACK:
+			ix := &IndexExpr{X: x, Lbrack: p.advance()}
+			for {
+				ix.Indices = append(ix.Indices, p.expr())
+				if p.tok.tok != COMMA {
+					break
+				}
+				p.advance()
+			}
+			ix.Rbrack = p.expect(RBRACK)
+			x = ix
+		case CARET:
+			x = &DerefExpr{X: x, Caret: p.advance()}
+		case LPAREN:
+			x = p.call(x)
+		default:
+			return x
+		}
+	}
+}
+
+// call parses the arguments of a call of fun
+func (p *parser) call(fun Expr) *CallExpr {
+	c := &CallExpr{Fun: fun, Lparen: p.advance()}
+	for p.tok.tok != RPAREN && p.tok.tok != EOF {
+		c.Args = append(c.Args, p.arg())
+		if p.tok.tok != COMMA {
+			break
+		}
+		p.advance()
+	}
+	c.Rparen = p.expect(RPAREN)
+	return c
+}
+
+func (p *parser) arg() *Arg {
+	a := &Arg{}
+	if p.tok.tok == NOT && p.peek(1) == IDENT && p.peek(2) == OUTPUT {
+		a.Negated = true
+		a.NotPos = p.advance()
+	}
+	if p.tok.tok == IDENT && (p.peek(1) == ASSIGN || p.peek(1) == OUTPUT) {
+		a.Name = p.ident()
+		a.Op = p.tok.tok
+		p.advance()
+	}
+	a.Value = p.expr()
+	return a
+}
@@ -0,0 +1,509 @@
+package st
+
+import (
+	"io"
+	"strings"
+)
+
+// KeywordCase selects how the printer writes keywords
+type KeywordCase int
+
+const (
+	// UpperCase writes keywords as IF, END_IF, AND
+	UpperCase KeywordCase = iota
+	// LowerCase writes keywords as if, end_if, and
+	LowerCase
+)
+
+// Config controls the output of the printer. The zero Config indents with
+// four spaces, writes keywords in upper case and surrounds all binary
+// operators with spaces.
+type Config struct {
+	// Indent is one level of indentation, four spaces if empty
+	Indent string
+	// KeywordCase applies to keywords, word operators and TRUE and FALSE.
+	// Identifiers and other literals are written as in the source.
+	KeywordCase KeywordCase
+	// CompactOperators omits the spaces around the symbolic binary
+	// operators, as in a+b*c or x<>0. AND, OR, XOR, MOD and := are always
+	// surrounded by spaces.
+	CompactOperators bool
+}
+
+// Format parses src and prints it in canonical form using the zero Config
+func Format(src string) (string, error) {
+	var c Config
+	return c.Format(src)
+}
+
+// Format parses src and prints it in canonical form: one statement per
+// line, nested statements indented, a semicolon after every statement and
+// at most one blank line between statements. Comments and pragmas are kept
+// before or after the statements they are next to. Sources with syntax
+// errors are not formatted.
+func (c *Config) Format(src string) (string, error) {
+	body, err := Parse(src)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	c.Fprint(&sb, body)
+	return sb.String(), nil
+}
+
+// FormatExpr parses src as a single expression, such as an inline
+// transition condition, and prints it without a trailing newline. Comments
+// are moved after the expression.
+func (c *Config) FormatExpr(src string) (string, error) {
+	p := newParser(src)
+	x := p.expr()
+	if p.tok.tok != EOF && len(p.errs) == 0 {
+		p.errorf(p.tok.pos, "unexpected %s after expression", p.describe())
+	}
+	if err := p.err(); err != nil {
+		return "", err
+	}
+	pr := c.printer()
+	pr.expr(x, 0)
+	sep := " "
+	for _, com := range p.scanner.comments {
+		pr.write(sep, com.Text)
+		if isLineComment(com) {
+			sep = "\n"
+		} else {
+			sep = " "
+		}
+	}
+	return pr.out.String(), nil
+}
+
+// Fprint prints node, a *Body, statement or expression, to w using the
+// zero Config
+func Fprint(w io.Writer, node Node) error {
+	var c Config
+	return c.Fprint(w, node)
+}
+
+// Fprint prints node, a *Body, statement or expression, to w. Statements
+// end with a newline, expressions do not. Parentheses are added where the
+// precedence of operators requires them, so trees built in code print
+// correctly. Syntax errors in the tree print as BadExpr and BadStmt.
+func (c *Config) Fprint(w io.Writer, node Node) error {
+	p := c.printer()
+	switch n := node.(type) {
+	case *Body:
+		p.comments = n.Comments
+		p.stmtList(n.Stmts, Pos{})
+		for len(p.comments) > 0 {
+			p.comment(p.comments[0])
+		}
+	case Stmt:
+		p.stmt(n, Pos{})
+	case Expr:
+		p.expr(n, 0)
+	case *Arg:
+		p.arg(n)
+	}
+	_, err := io.WriteString(w, p.out.String())
+	return err
+}
+
+func (c *Config) printer() *printer {
+	p := &printer{Config: *c}
+	if p.Indent == "" {
+		p.Indent = "    "
+	}
+	return p
+}
+
+type printer struct {
+	Config
+	out   strings.Builder
+	level int
+	// comments are the comments not printed yet
+	comments []*Comment
+	// last is the source line on which the last printed item ends, 0 at
+	// the start of a block or if unknown
+	last int
+}
+
+func (p *printer) write(s ...string) {
+	for _, x := range s {
+		p.out.WriteString(x)
+	}
+}
+
+// startLine starts an indented line for an item at pos, preceded by a blank
+// line if there is one in the source
+func (p *printer) startLine(pos Pos) {
+	if p.last > 0 && pos.Line > p.last+1 {
+		p.write("\n")
+	}
+	p.write(strings.Repeat(p.Indent, p.level))
+}
+
+func (p *printer) endLine() {
+	p.write("\n")
+}
+
+func (p *printer) keyword(t Token) string {
+	if p.KeywordCase == LowerCase {
+		return strings.ToLower(t.String())
+	}
+	return t.String()
+}
+
+// comment prints the next comment on its own line
+func (p *printer) comment(c *Comment) {
+	p.comments = p.comments[1:]
+	p.startLine(c.Start)
+	p.write(c.Text)
+	p.endLine()
+	p.last = c.End().Line
+}
+
+// leadingComments prints the comments before pos on their own lines
+func (p *printer) leadingComments(pos Pos) {
+	for len(p.comments) > 0 && pos.IsValid() && p.comments[0].Start.Offset < pos.Offset {
+		p.comment(p.comments[0])
+	}
+}
+
+// trailingComments appends the comments before end, and those starting on
+// line before next, to the current line and returns the last line of the
+// printed item
+func (p *printer) trailingComments(line int, end, next Pos) int {
+	for len(p.comments) > 0 {
+		c := p.comments[0]
+		if next.IsValid() && c.Start.Offset >= next.Offset {
+			break
+		}
+		if c.Start.Line > line || c.Start.Offset >= end.Offset && c.Start.Line != line {
+			break
+		}
+		p.comments = p.comments[1:]
+		p.write(" ", c.Text)
+		line = max(line, c.End().Line)
+		if isLineComment(c) {
+			// Anything after it would be commented out
+			break
+		}
+	}
+	return line
+}
+
+func isLineComment(c *Comment) bool {
+	return strings.HasPrefix(c.Text, "//")
+}
+
+// header ends the first line of a compound statement part that starts at
+// pos and whose body starts at next
+func (p *printer) header(pos, next Pos) {
+	if pos.IsValid() {
+		p.trailingComments(pos.Line, pos, next)
+	}
+	p.endLine()
+	p.last = 0
+}
+
+// block prints a statement list one level deeper
+func (p *printer) block(list []Stmt, end Pos) {
+	p.level++
+	p.stmtList(list, end)
+	p.leadingComments(end)
+	p.level--
+}
+
+// stmtList prints list, end is the position of the token after it
+func (p *printer) stmtList(list []Stmt, end Pos) {
+	for i, s := range list {
+		if _, ok := s.(*EmptyStmt); ok {
+			continue
+		}
+		next := end
+		if i+1 < len(list) {
+			next = list[i+1].Pos()
+		}
+		p.leadingComments(s.Pos())
+		p.startLine(s.Pos())
+		p.stmt(s, next)
+	}
+}
+
+// firstPos returns the position of the first statement of list, or end
+func firstPos(list []Stmt, end Pos) Pos {
+	for _, s := range list {
+		if _, ok := s.(*EmptyStmt); !ok {
+			return s.Pos()
+		}
+	}
+	return end
+}
+
+// stmt prints s from the current position up to its end of line, next is
+// the position of the following token
+func (p *printer) stmt(s Stmt, next Pos) {
+	switch s := s.(type) {
+	case *AssignStmt:
+		p.expr(s.Target, 0)
+		p.write(" := ")
+		p.expr(s.Value, 0)
+		p.write(";")
+	case *CallStmt:
+		p.expr(s.Call, 0)
+		p.write(";")
+	case *IfStmt:
+		p.ifStmt(s)
+	case *CaseStmt:
+		p.caseStmt(s)
+	case *ForStmt:
+		p.write(p.keyword(FOR), " ")
+		p.expr(s.Var, 0)
+		p.write(" := ")
+		p.expr(s.From, 0)
+		p.write(" ", p.keyword(TO), " ")
+		p.expr(s.To, 0)
+		if s.By != nil {
+			p.write(" ", p.keyword(BY), " ")
+			p.expr(s.By, 0)
+		}
+		p.write(" ", p.keyword(DO))
+		p.header(s.For, firstPos(s.Body, s.EndFor))
+		p.block(s.Body, s.EndFor)
+		p.end(END_FOR, s.EndFor)
+	case *WhileStmt:
+		p.write(p.keyword(WHILE), " ")
+		p.expr(s.Cond, 0)
+		p.write(" ", p.keyword(DO))
+		p.header(s.While, firstPos(s.Body, s.EndWhile))
+		p.block(s.Body, s.EndWhile)
+		p.end(END_WHILE, s.EndWhile)
+	case *RepeatStmt:
+		p.write(p.keyword(REPEAT))
+		until := s.Cond.Pos()
+		p.header(s.Repeat, firstPos(s.Body, until))
+		p.block(s.Body, until)
+		p.startLine(Pos{})
+		p.write(p.keyword(UNTIL), " ")
+		p.expr(s.Cond, 0)
+		p.endLine()
+		p.end(END_REPEAT, s.EndRepeat)
+	case *ExitStmt:
+		p.write(p.keyword(EXIT), ";")
+	case *ContinueStmt:
+		p.write(p.keyword(CONTINUE), ";")
+	case *ReturnStmt:
+		p.write(p.keyword(RETURN), ";")
+	case *EmptyStmt:
+		p.write(";")
+	default:
+		p.write("BadStmt;")
+	}
+	switch s.(type) {
+	case *IfStmt, *CaseStmt, *ForStmt, *WhileStmt, *RepeatStmt:
+		// end has printed the last line
+	default:
+		end := s.End()
+		if a, ok := s.(*AssignStmt); ok && a.TokPos.Offset > end.Offset {
+			// In trees built from other languages, such as IL, the
+			// assignment may come after the value
+			end = a.TokPos
+		}
+		p.last = p.trailingComments(end.Line, end, next)
+		p.endLine()
+	}
+}
+
+// end prints the closing keyword of a compound statement at pos
+func (p *printer) end(tok Token, pos Pos) {
+	p.startLine(Pos{})
+	p.write(p.keyword(tok), ";")
+	if pos.IsValid() {
+		end := pos.after(tok.String())
+		p.last = p.trailingComments(end.Line, end, Pos{})
+	} else {
+		p.last = 0
+	}
+	p.endLine()
+}
+
+func (p *printer) ifStmt(s *IfStmt) {
+	// next returns the position of the part after part i of the IF
+	// statement, where 0 is the THEN part and the last is ELSE
+	next := func(i int) Pos {
+		if i < len(s.ElsIfs) {
+			return s.ElsIfs[i].ElsIf
+		}
+		if s.Else != nil || s.ElsePos.IsValid() {
+			return s.ElsePos
+		}
+		return s.EndIf
+	}
+	p.write(p.keyword(IF), " ")
+	p.expr(s.Cond, 0)
+	p.write(" ", p.keyword(THEN))
+	p.header(s.If, firstPos(s.Then, next(0)))
+	p.block(s.Then, next(0))
+	for i, e := range s.ElsIfs {
+		p.startLine(Pos{})
+		p.write(p.keyword(ELSIF), " ")
+		p.expr(e.Cond, 0)
+		p.write(" ", p.keyword(THEN))
+		p.header(e.ElsIf, firstPos(e.Then, next(i+1)))
+		p.block(e.Then, next(i+1))
+	}
+	if s.Else != nil || s.ElsePos.IsValid() {
+		p.startLine(Pos{})
+		p.write(p.keyword(ELSE))
+		p.header(s.ElsePos, firstPos(s.Else, s.EndIf))
+		p.block(s.Else, s.EndIf)
+	}
+	p.end(END_IF, s.EndIf)
+}
+
+func (p *printer) caseStmt(s *CaseStmt) {
+	// next returns the position of the part after clause i
+	next := func(i int) Pos {
+		if i+1 < len(s.Clauses) {
+			return s.Clauses[i+1].Pos()
+		}
+		if s.Else != nil || s.ElsePos.IsValid() {
+			return s.ElsePos
+		}
+		return s.EndCase
+	}
+	p.write(p.keyword(CASE), " ")
+	p.expr(s.X, 0)
+	p.write(" ", p.keyword(OF))
+	first := s.EndCase
+	if len(s.Clauses) > 0 {
+		first = s.Clauses[0].Pos()
+	}
+	p.header(s.Case, first)
+	p.level++
+	for i, c := range s.Clauses {
+		p.leadingComments(c.Pos())
+		p.startLine(c.Pos())
+		for j, l := range c.Labels {
+			if j > 0 {
+				p.write(", ")
+			}
+			p.expr(l, 0)
+		}
+		p.write(":")
+		p.header(c.Pos(), firstPos(c.Body, next(i)))
+		p.level++
+		p.stmtList(c.Body, next(i))
+		p.level--
+	}
+	p.leadingComments(next(len(s.Clauses) - 1))
+	p.level--
+	if s.Else != nil || s.ElsePos.IsValid() {
+		p.startLine(Pos{})
+		p.write(p.keyword(ELSE))
+		p.header(s.ElsePos, firstPos(s.Else, s.EndCase))
+		p.block(s.Else, s.EndCase)
+	}
+	p.end(END_CASE, s.EndCase)
+}
+
+// expr prints x in a context of precedence prec, adding parentheses if x
+// binds less tightly
+func (p *printer) expr(x Expr, prec int) {
+	switch x := x.(type) {
+	case *Ident:
+		p.write(x.Name)
+	case *Literal:
+		if x.Kind == BoolLit && !strings.Contains(x.Value, "#") {
+			if p.KeywordCase == LowerCase {
+				p.write(strings.ToLower(x.Value))
+			} else {
+				p.write(strings.ToUpper(x.Value))
+			}
+			return
+		}
+		p.write(x.Value)
+	case *ParenExpr:
+		p.write("(")
+		p.expr(x.X, 0)
+		p.write(")")
+	case *UnaryExpr:
+		if prec > UnaryPrecedence {
+			p.write("(")
+			defer p.write(")")
+		}
+		if x.Op == NOT {
+			p.write(p.keyword(NOT), " ")
+		} else {
+			p.write(x.Op.String())
+		}
+		if u, ok := x.X.(*UnaryExpr); ok && x.Op != NOT && u.Op != NOT {
+			// -(-x) instead of --x
+			p.write("(")
+			p.expr(u, 0)
+			p.write(")")
+			return
+		}
+		p.expr(x.X, UnaryPrecedence)
+	case *BinaryExpr:
+		oprec := x.Op.Precedence()
+		if oprec < prec {
+			p.write("(")
+			defer p.write(")")
+		}
+		p.expr(x.X, oprec)
+		switch {
+		case x.Op.IsKeyword():
+			p.write(" ", p.keyword(x.Op), " ")
+		case p.CompactOperators:
+			p.write(x.Op.String())
+		default:
+			p.write(" ", x.Op.String(), " ")
+		}
+		p.expr(x.Y, oprec+1)
+	case *SelectorExpr:
+		p.expr(x.X, UnaryPrecedence+1)
+		p.write(".")
+		p.expr(x.Sel, 0)
+	case *IndexExpr:
+		p.expr(x.X, UnaryPrecedence+1)
+		p.write("[")
+		for i, ix := range x.Indices {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expr(ix, 0)
+		}
+		p.write("]")
+	case *DerefExpr:
+		p.expr(x.X, UnaryPrecedence+1)
+		p.write("^")
+	case *CallExpr:
+		p.expr(x.Fun, UnaryPrecedence+1)
+		p.write("(")
+		for i, a := range x.Args {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.arg(a)
+		}
+		p.write(")")
+	case *RangeExpr:
+		p.expr(x.Low, 0)
+		p.write("..")
+		p.expr(x.High, 0)
+	default:
+		p.write("BadExpr")
+	}
+}
+
+func (p *printer) arg(a *Arg) {
+	if a.Negated {
+		p.write(p.keyword(NOT), " ")
+	}
+	if a.Name != nil {
+		p.expr(a.Name, 0)
+		p.write(" ", a.Op.String(), " ")
+	}
+	p.expr(a.Value, 0)
+}
@@ -0,0 +1,332 @@
+package st
+
+import (
+	"fmt"
+	"strings"
+)
+
+// item is a token read by the scanner
+type item struct {
+	tok  Token
+	pos  Pos
+	text string
+	kind LiteralKind
+}
+
+// Item is a token read by a Scanner
+type Item struct {
+	Tok  Token
+	Pos  Pos
+	Text string
+	// Kind is the kind of LITERAL tokens
+	Kind LiteralKind
+}
+
+// Scanner reads the tokens of a source one at a time, collecting comments
+// and pragmas. Parse uses it internally; it is exported for the other
+// textual language of IEC 61131-3, Instruction List, which shares the
+// identifiers, literals and comments of ST. Newlines are skipped, use the
+// line of Item.Pos to detect them.
+type Scanner struct {
+	s *scanner
+}
+
+// NewScanner returns a scanner reading src
+func NewScanner(src string) *Scanner {
+	return &Scanner{s: newScanner(src)}
+}
+
+// Scan returns the next token, or EOF at the end of the source. Lexical
+// errors are collected in Errors, unexpected characters are ILLEGAL tokens.
+func (s *Scanner) Scan() Item {
+	it := s.s.scan()
+	return Item{Tok: it.tok, Pos: it.pos, Text: it.text, Kind: it.kind}
+}
+
+// Comments returns the comments and pragmas read so far, in source order
+func (s *Scanner) Comments() []*Comment {
+	return s.s.comments
+}
+
+// Errors returns the errors found so far
+func (s *Scanner) Errors() Errors {
+	return s.s.errs
+}
+
+// scanner splits the source into tokens, collecting comments and pragmas
+type scanner struct {
+	src      string
+	pos      Pos
+	comments []*Comment
+	errs     Errors
+}
+
+func newScanner(src string) *scanner {
+	return &scanner{src: src, pos: Pos{Line: 1, Column: 1}}
+}
+
+func (s *scanner) errorf(pos Pos, format string, args ...interface{}) {
+	s.errs = append(s.errs, &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (s *scanner) peekByte(n int) byte {
+	if i := s.pos.Offset + n; i < len(s.src) {
+		return s.src[i]
+	}
+	return 0
+}
+
+func (s *scanner) rest() string {
+	return s.src[s.pos.Offset:]
+}
+
+// advance moves past n bytes and returns them
+func (s *scanner) advance(n int) string {
+	text := s.src[s.pos.Offset : s.pos.Offset+n]
+	s.pos = s.pos.after(text)
+	return text
+}
+
+// scanAll returns all tokens up to and including EOF
+func (s *scanner) scanAll() []item {
+	var items []item
+	for {
+		it := s.scan()
+		items = append(items, it)
+		if it.tok == EOF {
+			return items
+		}
+	}
+}
+
+func (s *scanner) scan() item {
+	for {
+		s.skipSpace()
+		pos := s.pos
+		rest := s.rest()
+		switch {
+		case rest == "":
+			return item{tok: EOF, pos: pos}
+		case strings.HasPrefix(rest, "(*"):
+			s.comment(pos, "(*", "*)")
+		case strings.HasPrefix(rest, "/*"):
+			s.comment(pos, "/*", "*/")
+		case strings.HasPrefix(rest, "//"):
+			n := strings.IndexByte(rest, '\n')
+			if n < 0 {
+				n = len(rest)
+			}
+			s.comments = append(s.comments, &Comment{Start: pos, Text: strings.TrimRight(s.advance(n), "\r")})
+		case rest[0] == '{':
+			s.comment(pos, "{", "}")
+		default:
+			return s.token(pos, rest)
+		}
+	}
+}
+
+func (s *scanner) skipSpace() {
+	for {
+		switch s.peekByte(0) {
+		case ' ', '\t', '\r', '\n', '\f', '\v':
+			s.advance(1)
+		default:
+			return
+		}
+	}
+}
+
+// comment reads a comment or pragma up to the closing delimiter. Comments
+// may be nested as in (* a (* b *) c *), pragmas end at the first closing
+// brace.
+func (s *scanner) comment(pos Pos, opening, closing string) {
+	rest := s.rest()
+	n := len(opening)
+	for depth := 1; depth > 0; {
+		switch {
+		case n >= len(rest):
+			s.errorf(pos, "comment not terminated")
+			depth = 0
+		case strings.HasPrefix(rest[n:], closing):
+			n += len(closing)
+			depth--
+		case opening != "{" && strings.HasPrefix(rest[n:], opening):
+			n += len(opening)
+			depth++
+		default:
+			n++
+		}
+	}
+	s.comments = append(s.comments, &Comment{Start: pos, Text: s.advance(n)})
+}
+
+var operators = []struct {
+	text string
+	tok  Token
+}{
+	// Longer operators first
+	{":=", ASSIGN}, {"=>", OUTPUT}, {"..", RANGE}, {"**", POW}, {"<>", NEQ}, {"<=", LEQ}, {">=", GEQ},
+	{":", COLON}, {";", SEMICOLON}, {",", COMMA}, {".", PERIOD}, {"(", LPAREN}, {")", RPAREN},
+	{"[", LBRACK}, {"]", RBRACK}, {"^", CARET}, {"+", ADD}, {"-", SUB}, {"*", MUL}, {"/", DIV},
+	{"&", AMP}, {"=", EQL}, {"<", LSS}, {">", GTR},
+}
+
+func (s *scanner) token(pos Pos, rest string) item {
+	c := rest[0]
+	switch {
+	case isLetter(c):
+		name := rest[:wordLen(rest)]
+		if len(name) < len(rest) && rest[len(name)] == '#' {
+			return s.typedLiteral(pos, name)
+		}
+		s.advance(len(name))
+		switch tok := Lookup(name); {
+		case tok != IDENT:
+			return item{tok: tok, pos: pos, text: name}
+		case strings.EqualFold(name, "TRUE") || strings.EqualFold(name, "FALSE"):
+			return item{tok: LITERAL, pos: pos, text: name, kind: BoolLit}
+		}
+		return item{tok: IDENT, pos: pos, text: name}
+	case isDigit(c):
+		kind, n := numberLen(rest)
+		return item{tok: LITERAL, pos: pos, text: s.advance(n), kind: kind}
+	case c == '\'' || c == '"':
+		kind := StringLit
+		if c == '"' {
+			kind = WStringLit
+		}
+		return item{tok: LITERAL, pos: pos, text: s.advance(s.stringLen(pos, rest)), kind: kind}
+	case c == '%':
+		// Directly represented variable such as %IX0.0 or %MW10
+		n := 1
+		for n < len(rest) && (isLetter(rest[n]) || isDigit(rest[n]) || rest[n] == '*' ||
+			rest[n] == '.' && n+1 < len(rest) && isDigit(rest[n+1])) {
+			n++
+		}
+		return item{tok: IDENT, pos: pos, text: s.advance(n)}
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op.text) {
+			return item{tok: op.tok, pos: pos, text: s.advance(len(op.text))}
+		}
+	}
+	s.errorf(pos, "unexpected character %q", c)
+	return item{tok: ILLEGAL, pos: pos, text: s.advance(1)}
+}
+
+// typedLiteral reads a literal with type prefix, e.g. T#5s, INT#16#FF or Color#Red
+func (s *scanner) typedLiteral(pos Pos, prefix string) item {
+	rest := s.rest()
+	value := rest[len(prefix)+1:]
+	var kind LiteralKind
+	var n int
+	switch strings.ToUpper(prefix) {
+	case "T", "TIME", "LT", "LTIME":
+		kind = DurationLit
+		if value != "" && (value[0] == '-' || value[0] == '+') {
+			n = 1
+		}
+		n += spanOf(value[n:], func(c byte) bool { return isLetter(c) || isDigit(c) || c == '.' })
+	case "D", "DATE", "LD", "LDATE":
+		kind = DateLit
+		n = spanOf(value, func(c byte) bool { return isDigit(c) || c == '-' })
+	case "TOD", "TIME_OF_DAY", "LTOD", "LTIME_OF_DAY":
+		kind = TimeOfDayLit
+		n = spanOf(value, func(c byte) bool { return isDigit(c) || c == ':' || c == '.' })
+	case "DT", "DATE_AND_TIME", "LDT", "LDATE_AND_TIME":
+		kind = DateTimeLit
+		n = spanOf(value, func(c byte) bool { return isDigit(c) || c == '-' || c == ':' || c == '.' })
+	default:
+		sign := 0
+		if value != "" && (value[0] == '-' || value[0] == '+') {
+			sign = 1
+		}
+		switch {
+		case sign < len(value) && isDigit(value[sign]):
+			kind, n = numberLen(value[sign:])
+			n += sign
+		case sign == 0 && value != "" && isLetter(value[0]):
+			n = wordLen(value)
+			kind = EnumLit
+			if v := strings.ToUpper(value[:n]); v == "TRUE" || v == "FALSE" {
+				kind = BoolLit
+			}
+		}
+		switch p := strings.ToUpper(prefix); {
+		case kind == IntLit && p == "BOOL":
+			kind = BoolLit
+		case kind == IntLit && (p == "REAL" || p == "LREAL"):
+			kind = RealLit
+		}
+	}
+	if n == 0 {
+		s.errorf(pos, "missing value after %s#", prefix)
+	}
+	return item{tok: LITERAL, pos: pos, text: s.advance(len(prefix) + 1 + n), kind: kind}
+}
+
+// stringLen returns the length of the string literal at the start of rest,
+// quotes included. The character $ escapes the next one.
+func (s *scanner) stringLen(pos Pos, rest string) int {
+	quote := rest[0]
+	for i := 1; i < len(rest); i++ {
+		switch rest[i] {
+		case '$':
+			i++
+		case quote:
+			return i + 1
+		case '\n':
+			s.errorf(pos, "string literal not terminated")
+			return i
+		}
+	}
+	s.errorf(pos, "string literal not terminated")
+	return len(rest)
+}
+
+// numberLen returns the kind and length of the number at the start of s,
+// e.g. 1_000, 16#FF, 1.5 or 2.0E-3
+func numberLen(s string) (LiteralKind, int) {
+	n := spanOf(s, func(c byte) bool { return isDigit(c) || c == '_' })
+	if n < len(s) && s[n] == '#' {
+		// Based integer such as 2#1010 or 16#FF
+		m := spanOf(s[n+1:], func(c byte) bool { return isDigit(c) || c == '_' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' })
+		return IntLit, n + 1 + m
+	}
+	kind := IntLit
+	if n+1 < len(s) && s[n] == '.' && isDigit(s[n+1]) {
+		kind = RealLit
+		n += 1 + spanOf(s[n+1:], func(c byte) bool { return isDigit(c) || c == '_' })
+	}
+	if n < len(s) && (s[n] == 'e' || s[n] == 'E') {
+		m := n + 1
+		if m < len(s) && (s[m] == '+' || s[m] == '-') {
+			m++
+		}
+		if m < len(s) && isDigit(s[m]) {
+			kind = RealLit
+			n = m + spanOf(s[m:], isDigit)
+		}
+	}
+	return kind, n
+}
+
+func spanOf(s string, f func(byte) bool) int {
+	n := 0
+	for n < len(s) && f(s[n]) {
+		n++
+	}
+	return n
+}
+
+func wordLen(s string) int {
+	return spanOf(s, func(c byte) bool { return isLetter(c) || isDigit(c) })
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
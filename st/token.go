@@ -0,0 +1,272 @@
+package st
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pos is a position in the source, lines and columns start at 1 and
+// columns count bytes. The zero Pos is not valid.
+type Pos struct {
+	Offset int
+	Line   int
+	Column int
+}
+
+// IsValid reports whether p is a position in the source
+func (p Pos) IsValid() bool {
+	return p.Line > 0
+}
+
+func (p Pos) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// after returns the position after text starting at p
+func (p Pos) after(text string) Pos {
+	if !p.IsValid() {
+		return p
+	}
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			p.Line++
+			p.Column = 0
+		}
+		p.Column++
+	}
+	p.Offset += len(text)
+	return p
+}
+
+// Token is the kind of a lexical token
+type Token int
+
+const (
+	ILLEGAL Token = iota
+	EOF
+
+	IDENT   // Motor1, %IX0.0
+	LITERAL // 12, 16#FF, 1.5, 'abc', T#5s, TRUE, Color#Red
+
+	operatorBegin
+	ASSIGN    // :=
+	OUTPUT    // =>
+	COLON     // :
+	SEMICOLON // ;
+	COMMA     // ,
+	PERIOD    // .
+	RANGE     // ..
+	LPAREN    // (
+	RPAREN    // )
+	LBRACK    // [
+	RBRACK    // ]
+	CARET     // ^
+	ADD       // +
+	SUB       // -
+	MUL       // *
+	DIV       // /
+	POW       // **
+	AMP       // &
+	EQL       // =
+	NEQ       // <>
+	LSS       // <
+	LEQ       // <=
+	GTR       // >
+	GEQ       // >=
+	operatorEnd
+
+	keywordBegin
+	AND
+	OR
+	XOR
+	NOT
+	MOD
+	IF
+	THEN
+	ELSIF
+	ELSE
+	END_IF
+	CASE
+	OF
+	END_CASE
+	FOR
+	TO
+	BY
+	DO
+	END_FOR
+	WHILE
+	END_WHILE
+	REPEAT
+	UNTIL
+	END_REPEAT
+	EXIT
+	CONTINUE
+	RETURN
+	keywordEnd
+)
+
+var tokens = [...]string{
+	ILLEGAL: "ILLEGAL",
+	EOF:     "EOF",
+	IDENT:   "IDENT",
+	LITERAL: "LITERAL",
+
+	ASSIGN:    ":=",
+	OUTPUT:    "=>",
+	COLON:     ":",
+	SEMICOLON: ";",
+	COMMA:     ",",
+	PERIOD:    ".",
+	RANGE:     "..",
+	LPAREN:    "(",
+	RPAREN:    ")",
+	LBRACK:    "[",
+	RBRACK:    "]",
+	CARET:     "^",
+	ADD:       "+",
+	SUB:       "-",
+	MUL:       "*",
+	DIV:       "/",
+	POW:       "**",
+	AMP:       "&",
+	EQL:       "=",
+	NEQ:       "<>",
+	LSS:       "<",
+	LEQ:       "<=",
+	GTR:       ">",
+	GEQ:       ">=",
+
+	AND:        "AND",
+	OR:         "OR",
+	XOR:        "XOR",
+	NOT:        "NOT",
+	MOD:        "MOD",
+	IF:         "IF",
+	THEN:       "THEN",
+	ELSIF:      "ELSIF",
+	ELSE:       "ELSE",
+	END_IF:     "END_IF",
+	CASE:       "CASE",
+	OF:         "OF",
+	END_CASE:   "END_CASE",
+	FOR:        "FOR",
+	TO:         "TO",
+	BY:         "BY",
+	DO:         "DO",
+	END_FOR:    "END_FOR",
+	WHILE:      "WHILE",
+	END_WHILE:  "END_WHILE",
+	REPEAT:     "REPEAT",
+	UNTIL:      "UNTIL",
+	END_REPEAT: "END_REPEAT",
+	EXIT:       "EXIT",
+	CONTINUE:   "CONTINUE",
+	RETURN:     "RETURN",
+}
+
+// String returns the source text of operators and keywords and the name of
+// other tokens
+func (t Token) String() string {
+	if t >= 0 && int(t) < len(tokens) && tokens[t] != "" {
+		return tokens[t]
+	}
+	return fmt.Sprintf("Token(%d)", int(t))
+}
+
+// IsKeyword reports whether t is a keyword, including the word operators
+// AND, OR, XOR, NOT and MOD
+func (t Token) IsKeyword() bool {
+	return t > keywordBegin && t < keywordEnd
+}
+
+// IsOperator reports whether t is an operator or delimiter
+func (t Token) IsOperator() bool {
+	return t > operatorBegin && t < operatorEnd
+}
+
+// Precedence returns the precedence of t as binary operator, higher binds
+// tighter, and 0 for other tokens. The order is that of IEC 61131-3:
+// ** before * / MOD before + - before comparisons before = <> before
+// AND & before XOR before OR. Unary - and NOT bind tighter than all
+// binary operators.
+func (t Token) Precedence() int {
+	switch t {
+	case OR:
+		return 1
+	case XOR:
+		return 2
+	case AND, AMP:
+		return 3
+	case EQL, NEQ:
+		return 4
+	case LSS, LEQ, GTR, GEQ:
+		return 5
+	case ADD, SUB:
+		return 6
+	case MUL, DIV, MOD:
+		return 7
+	case POW:
+		return 8
+	}
+	return 0
+}
+
+// UnaryPrecedence is the precedence of the unary operators - + and NOT
+const UnaryPrecedence = 9
+
+var keywords map[string]Token
+
+func init() {
+	keywords = map[string]Token{}
+	for t := keywordBegin + 1; t < keywordEnd; t++ {
+		keywords[tokens[t]] = t
+	}
+}
+
+// Lookup returns the keyword token of ident, compared case-insensitively,
+// or IDENT
+func Lookup(ident string) Token {
+	if t, ok := keywords[strings.ToUpper(ident)]; ok {
+		return t
+	}
+	return IDENT
+}
+
+// LiteralKind is the kind of a literal
+type LiteralKind int
+
+const (
+	IntLit       LiteralKind = iota + 1 // 12, 1_000, 16#FF, INT#5
+	RealLit                             // 1.5, 1.0E-3, REAL#2
+	BoolLit                             // TRUE, FALSE, BOOL#1
+	StringLit                           // 'abc'
+	WStringLit                          // "abc"
+	DurationLit                         // T#5s, TIME#1h_30m
+	DateLit                             // D#2024-01-31
+	TimeOfDayLit                        // TOD#12:00:00
+	DateTimeLit                         // DT#2024-01-31-12:00:00
+	EnumLit                             // Color#Red
+)
+
+var literalKinds = [...]string{
+	IntLit:       "integer",
+	RealLit:      "real",
+	BoolLit:      "boolean",
+	StringLit:    "string",
+	WStringLit:   "wide string",
+	DurationLit:  "duration",
+	DateLit:      "date",
+	TimeOfDayLit: "time of day",
+	DateTimeLit:  "date and time",
+	EnumLit:      "enumerated value",
+}
+
+func (k LiteralKind) String() string {
+	if k > 0 && int(k) < len(literalKinds) {
+		return literalKinds[k]
+	}
+	return fmt.Sprintf("LiteralKind(%d)", int(k))
+}
@@ -0,0 +1,92 @@
+package st
+
+// Inspect traverses the tree rooted at node in depth-first order. It calls
+// f for each node, including Args, ElsIfs and CaseClauses; if f returns
+// true, Inspect visits the children of the node and then calls f(nil).
+// Comments are not visited.
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+	switch n := node.(type) {
+	case *Body:
+		inspectStmts(n.Stmts, f)
+	case *ParenExpr:
+		Inspect(n.X, f)
+	case *UnaryExpr:
+		Inspect(n.X, f)
+	case *BinaryExpr:
+		Inspect(n.X, f)
+		Inspect(n.Y, f)
+	case *SelectorExpr:
+		Inspect(n.X, f)
+		Inspect(n.Sel, f)
+	case *IndexExpr:
+		Inspect(n.X, f)
+		for _, x := range n.Indices {
+			Inspect(x, f)
+		}
+	case *DerefExpr:
+		Inspect(n.X, f)
+	case *CallExpr:
+		Inspect(n.Fun, f)
+		for _, a := range n.Args {
+			Inspect(a, f)
+		}
+	case *Arg:
+		if n.Name != nil {
+			Inspect(n.Name, f)
+		}
+		Inspect(n.Value, f)
+	case *RangeExpr:
+		Inspect(n.Low, f)
+		Inspect(n.High, f)
+	case *AssignStmt:
+		Inspect(n.Target, f)
+		Inspect(n.Value, f)
+	case *CallStmt:
+		Inspect(n.Call, f)
+	case *IfStmt:
+		Inspect(n.Cond, f)
+		inspectStmts(n.Then, f)
+		for _, e := range n.ElsIfs {
+			Inspect(e, f)
+		}
+		inspectStmts(n.Else, f)
+	case *ElsIf:
+		Inspect(n.Cond, f)
+		inspectStmts(n.Then, f)
+	case *CaseStmt:
+		Inspect(n.X, f)
+		for _, c := range n.Clauses {
+			Inspect(c, f)
+		}
+		inspectStmts(n.Else, f)
+	case *CaseClause:
+		for _, x := range n.Labels {
+			Inspect(x, f)
+		}
+		inspectStmts(n.Body, f)
+	case *ForStmt:
+		Inspect(n.Var, f)
+		Inspect(n.From, f)
+		Inspect(n.To, f)
+		if n.By != nil {
+			Inspect(n.By, f)
+		}
+		inspectStmts(n.Body, f)
+	case *WhileStmt:
+		Inspect(n.Cond, f)
+		inspectStmts(n.Body, f)
+	case *RepeatStmt:
+		inspectStmts(n.Body, f)
+		Inspect(n.Cond, f)
+	}
+	f(nil)
+}
+
+func inspectStmts(list []Stmt, f func(Node) bool) {
+	for _, s := range list {
+		Inspect(s, f)
+	}
+}
@@ -0,0 +1,118 @@
+package plcopen
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// FormatST rewrites all Structured Text in the project in place, in the
+// canonical form of st.Config.Format: the ST bodies of POUs, actions and
+// transitions, the inline ST actions of action blocks and the inline ST
+// conditions of SFC transitions. Transition conditions that are a single
+// expression are formatted with st.Config.FormatExpr. A nil cfg uses the
+// zero st.Config.
+//
+// Texts that are already formatted keep their markup. Texts with syntax
+// errors are left unchanged and reported in the returned error, one error
+// per text, after all others have been formatted.
+func (p *Project) FormatST(cfg *st.Config) error {
+	if cfg == nil {
+		cfg = &st.Config{}
+	}
+	f := &stFormatter{cfg: cfg}
+	if p.Types != nil {
+		for i := range p.Types.POUs {
+			pou := &p.Types.POUs[i]
+			path := namePath("project/types/pous", "pou", pou.Name)
+			for _, a := range pou.Actions {
+				f.body(namePath(path+"/actions", "action", a.Name)+"/body", a.Body)
+			}
+			for _, t := range pou.Transitions {
+				f.condition(namePath(path+"/transitions", "transition", t.Name)+"/body", t.Body)
+			}
+			f.body(path+"/body", pou.Body)
+		}
+	}
+	return errors.Join(f.errs...)
+}
+
+type stFormatter struct {
+	cfg  *st.Config
+	errs []error
+}
+
+// body formats the ST of body and of the objects of graphical bodies
+func (f *stFormatter) body(path string, body *Body) {
+	if body == nil {
+		return
+	}
+	switch {
+	case body.ST != nil:
+		f.text(path+"/ST", body.ST.Xhtml, f.cfg.Format)
+	case body.FBD != nil:
+		f.actionBlocks(path+"/FBD", body.FBD.ActionBlocks)
+	case body.LD != nil:
+		f.actionBlocks(path+"/LD", body.LD.ActionBlocks)
+	case body.SFC != nil:
+		sfc := body.SFC
+		path += "/SFC"
+		f.actionBlocks(path, sfc.ActionBlocks)
+		for i := range sfc.MacroSteps {
+			f.body(objectPath(path, &sfc.MacroSteps[i])+"/body", sfc.MacroSteps[i].Body)
+		}
+		for i := range sfc.Transitions {
+			t := &sfc.Transitions[i]
+			if t.Condition != nil && t.Condition.Inline != nil {
+				f.condition(objectPath(path, t)+"/condition/inline", t.Condition.Inline.Body)
+			}
+		}
+	}
+}
+
+// condition formats the body of a transition, whose ST may be statements
+// or a single expression
+func (f *stFormatter) condition(path string, body *Body) {
+	if body == nil || body.ST == nil {
+		f.body(path, body)
+		return
+	}
+	f.text(path+"/ST", body.ST.Xhtml, func(src string) (string, error) {
+		out, err := f.cfg.Format(src)
+		if err != nil {
+			if x, xerr := f.cfg.FormatExpr(src); xerr == nil {
+				return x, nil
+			}
+		}
+		return out, err
+	})
+}
+
+func (f *stFormatter) actionBlocks(path string, blocks []BodyFBDActionBlock) {
+	for i := range blocks {
+		for j, a := range blocks[i].Actions {
+			if a.Inline != nil {
+				f.body(fmt.Sprintf("%s/action[%d]/inline", objectPath(path, &blocks[i]), j+1), a.Inline.Body)
+			}
+		}
+	}
+}
+
+// text replaces the content of text with its formatted form
+func (f *stFormatter) text(path string, text *FormattedText, format func(string) (string, error)) {
+	if text == nil {
+		return
+	}
+	src := text.PlainText()
+	out, err := format(src)
+	if err != nil {
+		f.errs = append(f.errs, fmt.Errorf("plcopen: %s: %w", path, err))
+		return
+	}
+	out = strings.TrimSuffix(out, "\n")
+	if out != src {
+		*text = *NewFormattedText(out)
+	}
+}
@@ -0,0 +1,181 @@
+package plcopen
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"golang.org/x/text/encoding/unicode"
+)
+
+// StreamHandler receives the parts of a project read by Stream. Parts whose
+// handler is nil are skipped without being decoded. A handler error stops
+// Stream and is returned by it. The parts are V1.0B types, Stream does not
+// convert documents of other TC6 XML versions.
+type StreamHandler struct {
+	FileHeader    func(*ProjectFileHeader) error
+	ContentHeader func(*ProjectContentHeader) error
+	DataType      func(*ProjectTypesDataType) error
+	POU           func(*ProjectTypesPOU) error
+	Configuration func(*ProjectInstancesConfiguration) error
+	AddData       func(*AddData) error
+}
+
+// Stream reads a TC6 XML V1.0B document from r and passes its parts to h one
+// at a time, in document order. Only the part being handled is held in
+// memory, so very large projects can be processed. A byte order mark and the
+// declared character encoding are honoured; WithCharset overrides them and
+// WithLimits applies, other options are ignored.
+//
+// Only V1.0B documents, and documents without a namespace, can be streamed.
+// Stream returns an error for any other root namespace, including TC6 XML
+// 2.0 and 2.01 even if the v201 package is imported: those documents have to
+// be read whole with Load, which converts them, or with v201.Decode. JSON
+// documents cannot be streamed either.
+func Stream(r io.Reader, h StreamHandler, opts ...Option) error {
+	d, start, err := openXML(r, newOptions(opts))
+	if err != nil {
+		return err
+	}
+	if start.Name.Space != Namespace {
+		return fmt.Errorf("plcopen: Stream reads only TC6 XML V1.0B documents, got namespace %q, use Load or v201.Decode", start.Name.Space)
+	}
+	return eachChild(d, func(start xml.StartElement) error {
+		switch start.Name.Local {
+		case "fileHeader":
+			return decodeOrSkip(d, start, h.FileHeader)
+		case "contentHeader":
+			return decodeOrSkip(d, start, h.ContentHeader)
+		case "types":
+			return eachChild(d, func(start xml.StartElement) error {
+				switch start.Name.Local {
+				case "dataTypes":
+					return eachChild(d, func(start xml.StartElement) error {
+						return decodeOrSkip(d, start, h.DataType)
+					})
+				case "pous":
+					return eachChild(d, func(start xml.StartElement) error {
+						return decodeOrSkip(d, start, h.POU)
+					})
+				}
+				return d.Skip()
+			})
+		case "instances":
+			return eachChild(d, func(start xml.StartElement) error {
+				if start.Name.Local != "configurations" {
+					return d.Skip()
+				}
+				return eachChild(d, func(start xml.StartElement) error {
+					return decodeOrSkip(d, start, h.Configuration)
+				})
+			})
+		case "addData":
+			return decodeOrSkip(d, start, h.AddData)
+		}
+		return d.Skip()
+	})
+}
+
+// streamInput prepares r for decoding like Load does, without reading it all
+func streamInput(r io.Reader, o *options) (io.Reader, bool, error) {
+	if o.charset != "" {
+		enc, err := lookupCharset(o.charset)
+		if err != nil {
+			return nil, false, err
+		}
+		return enc.NewDecoder().Reader(r), true, nil
+	}
+	br := bufio.NewReader(r)
+	bom, _ := br.Peek(3)
+	switch {
+	case bytes.HasPrefix(bom, []byte{0xFE, 0xFF}), bytes.HasPrefix(bom, []byte{0xFF, 0xFE}):
+		return unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder().Reader(br), true, nil
+	case bytes.Equal(bom, []byte("\xEF\xBB\xBF")):
+		br.Discard(3)
+	}
+	return br, false, nil
+}
+
+// eachChild calls f for every child element of the element just started,
+// f has to consume the child up to its end element
+func eachChild(d *xml.Decoder, f func(xml.StartElement) error) error {
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if err := f(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// decodeOrSkip decodes the element start into a new T and passes it to f,
+// or skips it if f is nil
+func decodeOrSkip[T any](d *xml.Decoder, start xml.StartElement, f func(*T) error) error {
+	if f == nil {
+		return d.Skip()
+	}
+	v := new(T)
+	if err := d.DecodeElement(v, &start); err != nil {
+		return err
+	}
+	return f(v)
+}
+
+// errStopStream ends Stream when the consumer of an iterator stops early
+var errStopStream = errors.New("plcopen: stream stopped")
+
+// streamSeq returns an iterator over the parts set in the handler by set.
+// A read error is yielded once, with a nil part, after the parts read before it.
+func streamSeq[T any](r io.Reader, opts []Option, set func(*StreamHandler, func(*T) error)) iter.Seq2[*T, error] {
+	return func(yield func(*T, error) bool) {
+		var h StreamHandler
+		set(&h, func(v *T) error {
+			if !yield(v, nil) {
+				return errStopStream
+			}
+			return nil
+		})
+		if err := Stream(r, h, opts...); err != nil && err != errStopStream {
+			yield(nil, err)
+		}
+	}
+}
+
+// StreamPOUs returns an iterator over the POUs of the V1.0B document read
+// from r, see Stream for the documents it accepts:
+//
+//	for pou, err := range plcopen.StreamPOUs(f) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(pou.Name)
+//	}
+//
+// The iterator reads r, so it can be used only once.
+func StreamPOUs(r io.Reader, opts ...Option) iter.Seq2[*ProjectTypesPOU, error] {
+	return streamSeq(r, opts, func(h *StreamHandler, f func(*ProjectTypesPOU) error) { h.POU = f })
+}
+
+// StreamDataTypes returns an iterator over the data types of the document read from r, see StreamPOUs
+func StreamDataTypes(r io.Reader, opts ...Option) iter.Seq2[*ProjectTypesDataType, error] {
+	return streamSeq(r, opts, func(h *StreamHandler, f func(*ProjectTypesDataType) error) { h.DataType = f })
+}
+
+// StreamConfigurations returns an iterator over the configurations of the document read from r, see StreamPOUs
+func StreamConfigurations(r io.Reader, opts ...Option) iter.Seq2[*ProjectInstancesConfiguration, error] {
+	return streamSeq(r, opts, func(h *StreamHandler, f func(*ProjectInstancesConfiguration) error) { h.Configuration = f })
+}
@@ -14,6 +14,7 @@ type Project struct {
 	ContentHeader *ProjectContentHeader `xml:"contentHeader" json:"contentHeader,omitempty"`
 	Types         *ProjectTypes         `xml:"types" json:"types,omitempty"`
 	Instances     *ProjectInstances     `xml:"instances" json:"instances,omitempty"`
+	AddData       *AddData              `xml:"addData,omitempty" json:"addData,omitempty"`
 }
 
 // ProjectFileHeader contains file metadata
@@ -42,9 +43,9 @@ type ProjectContentHeader struct {
 // ProjectContentHeaderCoordinateInfo contains coordinate information
 type ProjectContentHeaderCoordinateInfo struct {
 	PageSize *ProjectContentHeaderCoordinateInfoPageSize `xml:"pageSize,omitempty" json:"pageSize,omitempty"`
-	FBD      *ProjectContentHeaderCoordinateInfoFBD      `xml:"fbd" json:"fBD,omitempty"`
-	LD       *ProjectContentHeaderCoordinateInfoLD       `xml:"ld" json:"lD,omitempty"`
-	SFC      *ProjectContentHeaderCoordinateInfoSFC      `xml:"sfc" json:"sFC,omitempty"`
+	FBD      *ProjectContentHeaderCoordinateInfoFBD      `xml:"fbd" json:"fbd,omitempty"`
+	LD       *ProjectContentHeaderCoordinateInfoLD       `xml:"ld" json:"ld,omitempty"`
+	SFC      *ProjectContentHeaderCoordinateInfoSFC      `xml:"sfc" json:"sfc,omitempty"`
 }
 
 // ProjectContentHeaderCoordinateInfoPageSize represents page size
@@ -89,26 +90,27 @@ type ProjectContentHeaderCoordinateInfoSFCScaling struct {
 // ProjectTypes contains type definitions
 type ProjectTypes struct {
 	DataTypes []ProjectTypesDataType `xml:"dataTypes>dataType,omitempty" json:"dataTypes,omitempty"`
-	POUs      []ProjectTypesPOU      `xml:"pous>pou,omitempty" json:"pOUs,omitempty"`
+	POUs      []ProjectTypesPOU      `xml:"pous>pou,omitempty" json:"pous,omitempty"`
 }
 
 // ProjectTypesDataType represents a data type definition
 type ProjectTypesDataType struct {
-	Name          string    `xml:"name,attr" json:"name"`
-	BaseType      *DataType `xml:"baseType" json:"baseType,omitempty"`
-	InitialValue  *Value    `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
-	Documentation []byte    `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	BaseType      *DataType      `xml:"baseType" json:"baseType,omitempty"`
+	InitialValue  *Value         `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // ProjectTypesPOU represents a Program Organization Unit
 type ProjectTypesPOU struct {
 	Name          string                      `xml:"name,attr" json:"name"`
-	POUType       POUType                     `xml:"pouType,attr" json:"pOUType"`
+	POUType       POUType                     `xml:"pouType,attr" json:"pouType"`
 	Interface     *ProjectTypesPOUInterface   `xml:"interface,omitempty" json:"interface,omitempty"`
 	Actions       []ProjectTypesPOUAction     `xml:"actions>action,omitempty" json:"actions,omitempty"`
 	Transitions   []ProjectTypesPOUTransition `xml:"transitions>transition,omitempty" json:"transitions,omitempty"`
 	Body          *Body                       `xml:"body,omitempty" json:"body,omitempty"`
-	Documentation []byte                      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	AddData       *AddData                    `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText              `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // POUType represents the type of POU
@@ -131,21 +133,21 @@ type ProjectTypesPOUInterface struct {
 	GlobalVars    *ProjectTypesPOUInterfaceGlobalVars   `xml:"globalVars,omitempty" json:"globalVars,omitempty"`
 	TempVars      *ProjectTypesPOUInterfaceTempVars     `xml:"tempVars,omitempty" json:"tempVars,omitempty"`
 	AccessVars    *VarList                              `xml:"accessVars,omitempty" json:"accessVars,omitempty"`
-	Documentation []byte                                `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Documentation *FormattedText                        `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // ProjectTypesPOUAction represents an action within a POU
 type ProjectTypesPOUAction struct {
-	Name          string `xml:"name,attr" json:"name"`
-	Body          *Body  `xml:"body" json:"body,omitempty"`
-	Documentation []byte `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	Body          *Body          `xml:"body" json:"body,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // ProjectTypesPOUTransition represents a transition within a POU
 type ProjectTypesPOUTransition struct {
-	Name          string `xml:"name,attr" json:"name"`
-	Body          *Body  `xml:"body" json:"body,omitempty"`
-	Documentation []byte `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	Body          *Body          `xml:"body" json:"body,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // ProjectInstances contains configuration and resource instances
@@ -158,7 +160,7 @@ type ProjectInstancesConfiguration struct {
 	Name          string                                  `xml:"name,attr" json:"name"`
 	Resources     []ProjectInstancesConfigurationResource `xml:"resource,omitempty" json:"resources,omitempty"`
 	GlobalVars    *VarList                                `xml:"globalVars,omitempty" json:"globalVars,omitempty"`
-	Documentation []byte                                  `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Documentation *FormattedText                          `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // ProjectInstancesConfigurationResource represents a resource within a configuration
@@ -166,8 +168,8 @@ type ProjectInstancesConfigurationResource struct {
 	Name          string                                      `xml:"name,attr" json:"name"`
 	Tasks         []ProjectInstancesConfigurationResourceTask `xml:"task,omitempty" json:"tasks,omitempty"`
 	GlobalVars    *VarList                                    `xml:"globalVars,omitempty" json:"globalVars,omitempty"`
-	POUInstances  []POUInstance                               `xml:"pouInstance,omitempty" json:"pOUInstances,omitempty"`
-	Documentation []byte                                      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	POUInstances  []POUInstance                               `xml:"pouInstance,omitempty" json:"pouInstances,omitempty"`
+	Documentation *FormattedText                              `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // ProjectInstancesConfigurationResourceTask represents a task within a resource
@@ -176,68 +178,75 @@ type ProjectInstancesConfigurationResourceTask struct {
 	Priority     uint64        `xml:"priority,attr" json:"priority"`
 	Interval     *string       `xml:"interval,attr,omitempty" json:"interval,omitempty"`
 	Single       *string       `xml:"single,attr,omitempty" json:"single,omitempty"`
-	POUInstances []POUInstance `xml:"pouInstance,omitempty" json:"pOUInstances,omitempty"`
+	POUInstances []POUInstance `xml:"pouInstance,omitempty" json:"pouInstances,omitempty"`
 }
 
 // POUInstance represents an instance of a POU
 type POUInstance struct {
-	Name          string `xml:"name,attr" json:"name"`
-	TypeName      string `xml:"type,attr" json:"typeName"`
-	Documentation []byte `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	TypeName      string         `xml:"type,attr" json:"typeName"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
-// VarList represents a list of variables
+// VarList represents a list of variables sharing the same memory attributes
+// (CONSTANT, RETAIN, NON_RETAIN, PERSISTENT, NON_PERSISTENT)
 type VarList struct {
 	Variables     []VarListVariable `xml:"variable,omitempty" json:"variables,omitempty"`
-	Documentation []byte            `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Documentation *FormattedText    `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string            `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Constant      *bool             `xml:"constant,attr,omitempty" json:"constant,omitempty"`
+	Retain        *bool             `xml:"retain,attr,omitempty" json:"retain,omitempty"`
+	NonRetain     *bool             `xml:"nonretain,attr,omitempty" json:"nonRetain,omitempty"`
+	Persistent    *bool             `xml:"persistent,attr,omitempty" json:"persistent,omitempty"`
+	NonPersistent *bool             `xml:"nonpersistent,attr,omitempty" json:"nonPersistent,omitempty"`
 }
 
 // VarListPlain represents a plain variable list (extends VarList)
 type VarListPlain struct {
 	Variables     []VarListPlainVariable `xml:"variable,omitempty" json:"variables,omitempty"`
-	Documentation []byte                 `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Documentation *FormattedText         `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // VarListPlainVariable represents a plain variable
 type VarListPlainVariable struct {
-	Name          string    `xml:"name,attr" json:"name"`
-	Address       string    `xml:"address,attr,omitempty" json:"address,omitempty"`
-	Type          *DataType `xml:"type" json:"type,omitempty"`
-	InitialValue  *Value    `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
-	Documentation []byte    `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	Address       string         `xml:"address,attr,omitempty" json:"address,omitempty"`
+	Type          *DataType      `xml:"type" json:"type,omitempty"`
+	InitialValue  *Value         `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // VarListVariable represents a variable with additional attributes
 type VarListVariable struct {
-	Name          string    `xml:"name,attr" json:"name"`
-	Address       string    `xml:"address,attr,omitempty" json:"address,omitempty"`
-	Type          *DataType `xml:"type" json:"type,omitempty"`
-	InitialValue  *Value    `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
-	Documentation []byte    `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	Address       string         `xml:"address,attr,omitempty" json:"address,omitempty"`
+	Type          *DataType      `xml:"type" json:"type,omitempty"`
+	InitialValue  *Value         `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // DataType represents a data type with choice-style content
 type DataType struct {
 	// Basic data types (as empty elements)
-	BOOL  *struct{} `xml:"BOOL,omitempty" json:"bOOL,omitempty"`
-	BYTE  *struct{} `xml:"BYTE,omitempty" json:"bYTE,omitempty"`
-	DATE  *struct{} `xml:"DATE,omitempty" json:"dATE,omitempty"`
-	DINT  *struct{} `xml:"DINT,omitempty" json:"dINT,omitempty"`
-	DT    *struct{} `xml:"DT,omitempty" json:"dT,omitempty"`
-	DWORD *struct{} `xml:"DWORD,omitempty" json:"dWORD,omitempty"`
-	INT   *struct{} `xml:"INT,omitempty" json:"iNT,omitempty"`
-	LINT  *struct{} `xml:"LINT,omitempty" json:"lINT,omitempty"`
-	LREAL *struct{} `xml:"LREAL,omitempty" json:"lREAL,omitempty"`
-	LWORD *struct{} `xml:"LWORD,omitempty" json:"lWORD,omitempty"`
-	REAL  *struct{} `xml:"REAL,omitempty" json:"rEAL,omitempty"`
-	SINT  *struct{} `xml:"SINT,omitempty" json:"sINT,omitempty"`
-	TIME  *struct{} `xml:"TIME,omitempty" json:"tIME,omitempty"`
-	TOD   *struct{} `xml:"TOD,omitempty" json:"tOD,omitempty"`
-	UDINT *struct{} `xml:"UDINT,omitempty" json:"uDINT,omitempty"`
-	UINT  *struct{} `xml:"UINT,omitempty" json:"uINT,omitempty"`
-	ULINT *struct{} `xml:"ULINT,omitempty" json:"uLINT,omitempty"`
-	USINT *struct{} `xml:"USINT,omitempty" json:"uSINT,omitempty"`
-	WORD  *struct{} `xml:"WORD,omitempty" json:"wORD,omitempty"`
+	BOOL  *struct{} `xml:"BOOL,omitempty" json:"bool,omitempty"`
+	BYTE  *struct{} `xml:"BYTE,omitempty" json:"byte,omitempty"`
+	DATE  *struct{} `xml:"DATE,omitempty" json:"date,omitempty"`
+	DINT  *struct{} `xml:"DINT,omitempty" json:"dint,omitempty"`
+	DT    *struct{} `xml:"DT,omitempty" json:"dt,omitempty"`
+	DWORD *struct{} `xml:"DWORD,omitempty" json:"dword,omitempty"`
+	INT   *struct{} `xml:"INT,omitempty" json:"int,omitempty"`
+	LINT  *struct{} `xml:"LINT,omitempty" json:"lint,omitempty"`
+	LREAL *struct{} `xml:"LREAL,omitempty" json:"lreal,omitempty"`
+	LWORD *struct{} `xml:"LWORD,omitempty" json:"lword,omitempty"`
+	REAL  *struct{} `xml:"REAL,omitempty" json:"real,omitempty"`
+	SINT  *struct{} `xml:"SINT,omitempty" json:"sint,omitempty"`
+	TIME  *struct{} `xml:"TIME,omitempty" json:"time,omitempty"`
+	TOD   *struct{} `xml:"TOD,omitempty" json:"tod,omitempty"`
+	UDINT *struct{} `xml:"UDINT,omitempty" json:"udint,omitempty"`
+	UINT  *struct{} `xml:"UINT,omitempty" json:"uint,omitempty"`
+	ULINT *struct{} `xml:"ULINT,omitempty" json:"ulint,omitempty"`
+	USINT *struct{} `xml:"USINT,omitempty" json:"usint,omitempty"`
+	WORD  *struct{} `xml:"WORD,omitempty" json:"word,omitempty"`
 
 	// Complex data types
 	Array            *DataTypeArray            `xml:"array,omitempty" json:"array,omitempty"`
@@ -248,7 +257,7 @@ type DataType struct {
 	Struct           *VarListPlain             `xml:"struct,omitempty" json:"struct,omitempty"`
 	SubrangeSigned   *DataTypeSubrangeSigned   `xml:"subrangeSigned,omitempty" json:"subrangeSigned,omitempty"`
 	SubrangeUnsigned *DataTypeSubrangeUnsigned `xml:"subrangeUnsigned,omitempty" json:"subrangeUnsigned,omitempty"`
-	WString          *DataTypeWString          `xml:"wstring,omitempty" json:"wString,omitempty"`
+	WString          *DataTypeWString          `xml:"wstring,omitempty" json:"wstring,omitempty"`
 }
 
 // DataTypeArray represents an array data type
@@ -287,8 +296,9 @@ type DataTypeEnumValues struct {
 
 // DataTypeEnumValuesValue represents an enumerated value
 type DataTypeEnumValuesValue struct {
-	Name          string `xml:"name,attr" json:"name"`
-	Documentation []byte `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	Value         *string        `xml:"value,attr,omitempty" json:"value,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
 }
 
 // DataTypePointer represents a pointer data type
@@ -330,10 +340,11 @@ type ValueArrayValue struct {
 	Values []ValueArrayValueValue `xml:"value" json:"values,omitempty"`
 }
 
-// ValueArrayValueValue represents a value within an array
+// ValueArrayValueValue represents a value within an array, repeated
+// RepeatCount times (repetitionValue attribute, default 1)
 type ValueArrayValueValue struct {
-	RepeatCount *uint64 `xml:"repetition,attr,omitempty" json:"repeatCount,omitempty"`
-	Value       *Value  `xml:",innerxml" json:"value,omitempty"`
+	RepeatCount *uint64 `xml:"repetitionValue,attr,omitempty" json:"repeatCount,omitempty"`
+	Value       *Value  `xml:"-" json:"value,omitempty"`
 }
 
 // ValueSimpleValue represents a simple value
@@ -349,23 +360,26 @@ type ValueStructValue struct {
 // ValueStructValueValue represents a value within a structure
 type ValueStructValueValue struct {
 	Member string `xml:"member,attr" json:"member"`
-	Value  *Value `xml:",innerxml" json:"value,omitempty"`
+	Value  *Value `xml:"-" json:"value,omitempty"`
 }
 
 // Body represents a body with choice-style content for different languages
 type Body struct {
-	FBD *BodyFBD `xml:"FBD,omitempty" json:"fBD,omitempty"`
-	LD  *BodyLD  `xml:"LD,omitempty" json:"lD,omitempty"`
-	SFC *BodySFC `xml:"SFC,omitempty" json:"sFC,omitempty"`
-	IL  *BodyIL  `xml:"IL,omitempty" json:"iL,omitempty"`
-	ST  *BodyST  `xml:"ST,omitempty" json:"sT,omitempty"`
+	FBD     *BodyFBD `xml:"FBD,omitempty" json:"fbd,omitempty"`
+	LD      *BodyLD  `xml:"LD,omitempty" json:"ld,omitempty"`
+	SFC     *BodySFC `xml:"SFC,omitempty" json:"sfc,omitempty"`
+	IL      *BodyIL  `xml:"IL,omitempty" json:"il,omitempty"`
+	ST      *BodyST  `xml:"ST,omitempty" json:"st,omitempty"`
+	AddData *AddData `xml:"addData,omitempty" json:"addData,omitempty"`
 }
 
-// BodyFBD represents a Function Block Diagram body
+// BodyFBD represents a Function Block Diagram body.
+// Objects are grouped by element kind; use Objects for document order.
 type BodyFBD struct {
 	Blocks         []BodyFBDBlock         `xml:"block,omitempty" json:"blocks,omitempty"`
 	ActionBlocks   []BodyFBDActionBlock   `xml:"actionBlock,omitempty" json:"actionBlocks,omitempty"`
 	Comments       []BodyFBDComment       `xml:"comment,omitempty" json:"comments,omitempty"`
+	Errors         []BodyFBDError         `xml:"error,omitempty" json:"errors,omitempty"`
 	Connectors     []BodyFBDConnector     `xml:"connector,omitempty" json:"connectors,omitempty"`
 	Continuations  []BodyFBDContinuation  `xml:"continuation,omitempty" json:"continuations,omitempty"`
 	InVariables    []BodyFBDInVariable    `xml:"inVariable,omitempty" json:"inVariables,omitempty"`
@@ -374,32 +388,88 @@ type BodyFBD struct {
 	Jumps          []BodyFBDJump          `xml:"jump,omitempty" json:"jumps,omitempty"`
 	Labels         []BodyFBDLabel         `xml:"label,omitempty" json:"labels,omitempty"`
 	Returns        []BodyFBDReturn        `xml:"return,omitempty" json:"returns,omitempty"`
+
+	order []objectKey // document order of decoded objects, see Objects
 }
 
-// BodyLD represents a Ladder Diagram body
+// BodyLD represents a Ladder Diagram body.
+// Objects are grouped by element kind; use Objects for document order.
 type BodyLD struct {
 	Contacts        []BodyLDContact        `xml:"contact,omitempty" json:"contacts,omitempty"`
 	Coils           []BodyLDCoil           `xml:"coil,omitempty" json:"coils,omitempty"`
 	LeftPowerRails  []BodyLDLeftPowerRail  `xml:"leftPowerRail,omitempty" json:"leftPowerRails,omitempty"`
 	RightPowerRails []BodyLDRightPowerRail `xml:"rightPowerRail,omitempty" json:"rightPowerRails,omitempty"`
+
+	// FBD objects placed directly in a rung (timers, counters, MOVE, ...)
+	Blocks         []BodyFBDBlock         `xml:"block,omitempty" json:"blocks,omitempty"`
+	InVariables    []BodyFBDInVariable    `xml:"inVariable,omitempty" json:"inVariables,omitempty"`
+	OutVariables   []BodyFBDOutVariable   `xml:"outVariable,omitempty" json:"outVariables,omitempty"`
+	InOutVariables []BodyFBDInOutVariable `xml:"inOutVariable,omitempty" json:"inOutVariables,omitempty"`
+	Labels         []BodyFBDLabel         `xml:"label,omitempty" json:"labels,omitempty"`
+	Jumps          []BodyFBDJump          `xml:"jump,omitempty" json:"jumps,omitempty"`
+	Returns        []BodyFBDReturn        `xml:"return,omitempty" json:"returns,omitempty"`
+
+	// Common objects shared with FBD and SFC bodies
+	Comments      []BodyFBDComment      `xml:"comment,omitempty" json:"comments,omitempty"`
+	Errors        []BodyFBDError        `xml:"error,omitempty" json:"errors,omitempty"`
+	Connectors    []BodyFBDConnector    `xml:"connector,omitempty" json:"connectors,omitempty"`
+	Continuations []BodyFBDContinuation `xml:"continuation,omitempty" json:"continuations,omitempty"`
+	ActionBlocks  []BodyFBDActionBlock  `xml:"actionBlock,omitempty" json:"actionBlocks,omitempty"`
+
+	order []objectKey // document order of decoded objects, see Objects
 }
 
-// BodySFC represents a Sequential Function Chart body
+// BodySFC represents a Sequential Function Chart body.
+// Objects are grouped by element kind; use Objects for document order.
 type BodySFC struct {
-	Steps       []BodySFCStep       `xml:"step,omitempty" json:"steps,omitempty"`
-	Transitions []BodySFCTransition `xml:"transition,omitempty" json:"transitions,omitempty"`
+	Steps                    []BodySFCStep                    `xml:"step,omitempty" json:"steps,omitempty"`
+	MacroSteps               []BodySFCMacroStep               `xml:"macroStep,omitempty" json:"macroSteps,omitempty"`
+	JumpSteps                []BodySFCJumpStep                `xml:"jumpStep,omitempty" json:"jumpSteps,omitempty"`
+	Transitions              []BodySFCTransition              `xml:"transition,omitempty" json:"transitions,omitempty"`
+	SelectionDivergences     []BodySFCSelectionDivergence     `xml:"selectionDivergence,omitempty" json:"selectionDivergences,omitempty"`
+	SelectionConvergences    []BodySFCSelectionConvergence    `xml:"selectionConvergence,omitempty" json:"selectionConvergences,omitempty"`
+	SimultaneousDivergences  []BodySFCSimultaneousDivergence  `xml:"simultaneousDivergence,omitempty" json:"simultaneousDivergences,omitempty"`
+	SimultaneousConvergences []BodySFCSimultaneousConvergence `xml:"simultaneousConvergence,omitempty" json:"simultaneousConvergences,omitempty"`
+
+	// FBD objects, e.g. variables and blocks feeding transition conditions
+	Blocks         []BodyFBDBlock         `xml:"block,omitempty" json:"blocks,omitempty"`
+	InVariables    []BodyFBDInVariable    `xml:"inVariable,omitempty" json:"inVariables,omitempty"`
+	OutVariables   []BodyFBDOutVariable   `xml:"outVariable,omitempty" json:"outVariables,omitempty"`
+	InOutVariables []BodyFBDInOutVariable `xml:"inOutVariable,omitempty" json:"inOutVariables,omitempty"`
+	Labels         []BodyFBDLabel         `xml:"label,omitempty" json:"labels,omitempty"`
+	Jumps          []BodyFBDJump          `xml:"jump,omitempty" json:"jumps,omitempty"`
+	Returns        []BodyFBDReturn        `xml:"return,omitempty" json:"returns,omitempty"`
+
+	// LD objects, e.g. contacts feeding transition conditions
+	LeftPowerRails  []BodyLDLeftPowerRail  `xml:"leftPowerRail,omitempty" json:"leftPowerRails,omitempty"`
+	RightPowerRails []BodyLDRightPowerRail `xml:"rightPowerRail,omitempty" json:"rightPowerRails,omitempty"`
+	Coils           []BodyLDCoil           `xml:"coil,omitempty" json:"coils,omitempty"`
+	Contacts        []BodyLDContact        `xml:"contact,omitempty" json:"contacts,omitempty"`
+
+	// Common objects shared with FBD and LD bodies
+	Comments      []BodyFBDComment      `xml:"comment,omitempty" json:"comments,omitempty"`
+	Errors        []BodyFBDError        `xml:"error,omitempty" json:"errors,omitempty"`
+	Connectors    []BodyFBDConnector    `xml:"connector,omitempty" json:"connectors,omitempty"`
+	Continuations []BodyFBDContinuation `xml:"continuation,omitempty" json:"continuations,omitempty"`
+	ActionBlocks  []BodyFBDActionBlock  `xml:"actionBlock,omitempty" json:"actionBlocks,omitempty"`
+
+	order []objectKey // document order of decoded objects, see Objects
 }
 
-// BodyIL represents an Instruction List body
+// BodyIL represents an Instruction List body; the program text is formatted
+// text and is encoded by MarshalXML/UnmarshalXML
 type BodyIL struct {
-	XMLNSXhtml string `xml:"xmlns:xhtml,attr" json:"xMLNSXhtml"`
-	Xhtml      string `xml:",innerxml" json:"xhtml"`
+	// Deprecated: the xhtml namespace is always declared on marshal
+	XMLNSXhtml string         `xml:"-" json:"xmlnsXhtml"`
+	Xhtml      *FormattedText `xml:"-" json:"xhtml"`
 }
 
-// BodyST represents a Structured Text body
+// BodyST represents a Structured Text body; the program text is formatted
+// text and is encoded by MarshalXML/UnmarshalXML
 type BodyST struct {
-	XMLNSXhtml string `xml:"xmlns:xhtml,attr" json:"xMLNSXhtml"`
-	Xhtml      string `xml:",innerxml" json:"xhtml"`
+	// Deprecated: the xhtml namespace is always declared on marshal
+	XMLNSXhtml string         `xml:"-" json:"xmlnsXhtml"`
+	Xhtml      *FormattedText `xml:"-" json:"xhtml"`
 }
 
 // Position represents a position coordinate
@@ -408,20 +478,27 @@ type Position struct {
 	Y float64 `xml:"y,attr" json:"y"`
 }
 
-// Connection represents a connection
+// Connection represents a connection. Positions is the routing path of the
+// wire, from the input pin of the consumer to the output pin of the producer.
 type Connection struct {
-	RefLocalID      uint64  `xml:"refLocalId,attr" json:"refLocalID"`
-	FormalParameter *string `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
+	Positions       []Position `xml:"position,omitempty" json:"positions,omitempty"`
+	RefLocalID      uint64     `xml:"refLocalId,attr" json:"refLocalId"`
+	FormalParameter *string    `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
 }
 
-// ConnectionPointIn represents an input connection point
+// ConnectionPointIn represents an input connection point. RelPosition is
+// relative to the anchor position of the owning object.
 type ConnectionPointIn struct {
+	RelPosition *Position    `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
 	Connections []Connection `xml:"connection,omitempty" json:"connections,omitempty"`
+	Expression  *string      `xml:"expression,omitempty" json:"expression,omitempty"`
 }
 
 // ConnectionPointOut represents an output connection point
 type ConnectionPointOut struct {
-	FormalParameter *string `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
+	RelPosition     *Position `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
+	Expression      *string   `xml:"expression,omitempty" json:"expression,omitempty"`
+	FormalParameter *string   `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
 }
 
 // EdgeModifierType represents edge modifier types
@@ -466,13 +543,14 @@ type BodyFBDBlock struct {
 	InputVariables   []BodyFBDBlockVariable  `xml:"inputVariables>variable,omitempty" json:"inputVariables,omitempty"`
 	InOutVariables   []BodyFBDBlockVariable2 `xml:"inOutVariables>variable,omitempty" json:"inOutVariables,omitempty"`
 	OutputVariables  []BodyFBDBlockVariable1 `xml:"outputVariables>variable,omitempty" json:"outputVariables,omitempty"`
-	Documentation    []byte                  `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID          uint64                  `xml:"localId,attr" json:"localID"`
+	AddData          *AddData                `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation    *FormattedText          `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID          uint64                  `xml:"localId,attr" json:"localId"`
 	Width            *float64                `xml:"width,attr,omitempty" json:"width,omitempty"`
 	Height           *float64                `xml:"height,attr,omitempty" json:"height,omitempty"`
 	TypeName         string                  `xml:"typeName,attr" json:"typeName"`
 	InstanceName     *string                 `xml:"instanceName,attr,omitempty" json:"instanceName,omitempty"`
-	ExecutionOrderID *uint64                 `xml:"executionOrderId,attr,omitempty" json:"executionOrderID,omitempty"`
+	ExecutionOrderID *uint64                 `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
 }
 
 // BodyFBDBlockVariable represents a variable in a block
@@ -494,21 +572,29 @@ type BodyFBDBlockVariable2 struct {
 	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
 }
 
-// BodyFBDActionBlock represents an action block in FBD
+// BodyFBDActionBlock represents an action block in FBD, LD or SFC.
+// In SFC the ConnectionPointIn usually refers to the step the actions belong to.
 type BodyFBDActionBlock struct {
-	Position      *Position                  `xml:"position,omitempty" json:"position,omitempty"`
-	Actions       []BodyFBDActionBlockAction `xml:"action,omitempty" json:"actions,omitempty"`
-	Documentation []byte                     `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID       uint64                     `xml:"localId,attr" json:"localID"`
-	Width         *float64                   `xml:"width,attr,omitempty" json:"width,omitempty"`
-	Height        *float64                   `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Position           *Position                  `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn         `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut        `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Actions            []BodyFBDActionBlockAction `xml:"action,omitempty" json:"actions,omitempty"`
+	AddData            *AddData                   `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText             `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64                     `xml:"localId,attr" json:"localId"`
+	Width              *float64                   `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Height             *float64                   `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Negated            *bool                      `xml:"negated,attr,omitempty" json:"negated,omitempty"`
 }
 
 // BodyFBDActionBlockAction represents an action in an action block
 type BodyFBDActionBlockAction struct {
-	Reference *BodyFBDActionBlockActionReference `xml:"reference,omitempty" json:"reference,omitempty"`
-	Inline    *BodyFBDActionBlockActionInline    `xml:"inline,omitempty" json:"inline,omitempty"`
-	Qualifier *BodyFBDActionBlockActionQualifier `xml:"qualifier,attr,omitempty" json:"qualifier,omitempty"`
+	Reference     *BodyFBDActionBlockActionReference `xml:"reference,omitempty" json:"reference,omitempty"`
+	Inline        *BodyFBDActionBlockActionInline    `xml:"inline,omitempty" json:"inline,omitempty"`
+	Documentation *FormattedText                     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Qualifier     *BodyFBDActionBlockActionQualifier `xml:"qualifier,attr,omitempty" json:"qualifier,omitempty"`
+	Duration      *string                            `xml:"duration,attr,omitempty" json:"duration,omitempty"`
+	Indicator     *string                            `xml:"indicator,attr,omitempty" json:"indicator,omitempty"`
 }
 
 // BodyFBDActionBlockActionReference represents an action reference
@@ -524,57 +610,80 @@ type BodyFBDActionBlockActionInline struct {
 
 // BodyFBDComment represents a comment in FBD
 type BodyFBDComment struct {
-	Position      *Position `xml:"position,omitempty" json:"position,omitempty"`
-	Content       string    `xml:"content,omitempty" json:"content,omitempty"`
-	Documentation []byte    `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID       uint64    `xml:"localId,attr" json:"localID"`
-	Height        *float64  `xml:"height,attr,omitempty" json:"height,omitempty"`
-	Width         *float64  `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Position      *Position      `xml:"position,omitempty" json:"position,omitempty"`
+	Content       *FormattedText `xml:"content,omitempty" json:"content,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID       uint64         `xml:"localId,attr" json:"localId"`
+	Height        *float64       `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width         *float64       `xml:"width,attr,omitempty" json:"width,omitempty"`
+}
+
+// BodyFBDError represents an error object in FBD, LD or SFC, used by tools to
+// mark a part of the diagram that could not be processed
+type BodyFBDError struct {
+	Position      *Position      `xml:"position,omitempty" json:"position,omitempty"`
+	Content       *FormattedText `xml:"content,omitempty" json:"content,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID       uint64         `xml:"localId,attr" json:"localId"`
+	Height        *float64       `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width         *float64       `xml:"width,attr,omitempty" json:"width,omitempty"`
+}
+
+// BodyFBDConnector represents a connector in FBD, the sink side of a named
+// connection that is continued elsewhere by a BodyFBDContinuation
+type BodyFBDConnector struct {
+	Position          *Position          `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	AddData           *AddData           `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name              string             `xml:"name,attr" json:"name"`
+	LocalID           uint64             `xml:"localId,attr" json:"localId"`
+	Height            *float64           `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64           `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
 
-// BodyFBDConnector represents a connector in FBD
-type BodyFBDConnector struct {
+// BodyFBDContinuation represents a continuation in FBD, the source side of a
+// named connection started by a BodyFBDConnector
+type BodyFBDContinuation struct {
 	Position           *Position           `xml:"position,omitempty" json:"position,omitempty"`
 	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
-	Documentation      []byte              `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
 	Name               string              `xml:"name,attr" json:"name"`
-	LocalID            uint64              `xml:"localId,attr" json:"localID"`
-}
-
-// BodyFBDContinuation represents a continuation in FBD
-type BodyFBDContinuation struct {
-	Position          *Position          `xml:"position,omitempty" json:"position,omitempty"`
-	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
-	Documentation     []byte             `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	Name              string             `xml:"name,attr" json:"name"`
-	LocalID           uint64             `xml:"localId,attr" json:"localID"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
 
 // BodyFBDInVariable represents an input variable in FBD
 type BodyFBDInVariable struct {
 	Position           *Position           `xml:"position,omitempty" json:"position,omitempty"`
 	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
-	Documentation      []byte              `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	Expression         string              `xml:"expression,attr" json:"expression"`
-	LocalID            uint64              `xml:"localId,attr" json:"localID"`
+	Expression         string              `xml:"expression" json:"expression"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
 	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
 	EdgeModifier       *EdgeModifierType   `xml:"edgeModifier,attr,omitempty" json:"edgeModifier,omitempty"`
-	ExecutionOrderID   *uint64             `xml:"executionOrderId,attr,omitempty" json:"executionOrderID,omitempty"`
+	ExecutionOrderID   *uint64             `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
 }
 
 // BodyFBDOutVariable represents an output variable in FBD
 type BodyFBDOutVariable struct {
 	Position          *Position            `xml:"position,omitempty" json:"position,omitempty"`
 	ConnectionPointIn *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
-	Documentation     []byte               `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	Expression        string               `xml:"expression,attr" json:"expression"`
-	LocalID           uint64               `xml:"localId,attr" json:"localID"`
+	Expression        string               `xml:"expression" json:"expression"`
+	AddData           *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64               `xml:"localId,attr" json:"localId"`
 	Height            *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width             *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
 	EdgeModifier      *EdgeModifierType    `xml:"edgeModifier,attr,omitempty" json:"edgeModifier,omitempty"`
 	StorageModifier   *StorageModifierType `xml:"storageModifier,attr,omitempty" json:"storageModifier,omitempty"`
-	ExecutionOrderID  *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderID,omitempty"`
+	ExecutionOrderID  *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
 }
 
 // BodyFBDInOutVariable represents an in-out variable in FBD
@@ -582,43 +691,47 @@ type BodyFBDInOutVariable struct {
 	Position           *Position            `xml:"position,omitempty" json:"position,omitempty"`
 	ConnectionPointIn  *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
 	ConnectionPointOut *ConnectionPointOut  `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
-	Documentation      []byte               `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	Expression         string               `xml:"expression,attr" json:"expression"`
-	LocalID            uint64               `xml:"localId,attr" json:"localID"`
+	Expression         string               `xml:"expression" json:"expression"`
+	AddData            *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64               `xml:"localId,attr" json:"localId"`
 	Height             *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width              *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
 	EdgeModifier       *EdgeModifierType    `xml:"edgeModifier,attr,omitempty" json:"edgeModifier,omitempty"`
 	StorageModifier    *StorageModifierType `xml:"storageModifier,attr,omitempty" json:"storageModifier,omitempty"`
-	ExecutionOrderID   *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderID,omitempty"`
+	ExecutionOrderID   *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
 }
 
 // BodyFBDJump represents a jump in FBD
 type BodyFBDJump struct {
-	Position      *Position `xml:"position,omitempty" json:"position,omitempty"`
-	Documentation []byte    `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	Label         string    `xml:"label,attr" json:"label"`
-	LocalID       uint64    `xml:"localId,attr" json:"localID"`
-	Height        *float64  `xml:"height,attr,omitempty" json:"height,omitempty"`
-	Width         *float64  `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Position      *Position      `xml:"position,omitempty" json:"position,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Label         string         `xml:"label,attr" json:"label"`
+	LocalID       uint64         `xml:"localId,attr" json:"localId"`
+	Height        *float64       `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width         *float64       `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
 
 // BodyFBDLabel represents a label in FBD
 type BodyFBDLabel struct {
-	Position      *Position `xml:"position,omitempty" json:"position,omitempty"`
-	Documentation []byte    `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	Label         string    `xml:"label,attr" json:"label"`
-	LocalID       uint64    `xml:"localId,attr" json:"localID"`
-	Height        *float64  `xml:"height,attr,omitempty" json:"height,omitempty"`
-	Width         *float64  `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Position      *Position      `xml:"position,omitempty" json:"position,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Label         string         `xml:"label,attr" json:"label"`
+	LocalID       uint64         `xml:"localId,attr" json:"localId"`
+	Height        *float64       `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width         *float64       `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
 
 // BodyFBDReturn represents a return in FBD
 type BodyFBDReturn struct {
-	Position      *Position `xml:"position,omitempty" json:"position,omitempty"`
-	Documentation []byte    `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID       uint64    `xml:"localId,attr" json:"localID"`
-	Height        *float64  `xml:"height,attr,omitempty" json:"height,omitempty"`
-	Width         *float64  `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Position      *Position      `xml:"position,omitempty" json:"position,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID       uint64         `xml:"localId,attr" json:"localId"`
+	Height        *float64       `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width         *float64       `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
 
 // BodyLDContact represents a contact in LD
@@ -627,8 +740,9 @@ type BodyLDContact struct {
 	ConnectionPointIn  *ConnectionPointIn  `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
 	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
 	Variable           string              `xml:"variable" json:"variable"`
-	Documentation      []byte              `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID            uint64              `xml:"localId,attr" json:"localID"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
 	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
 	EdgeModifier       *EdgeModifierType   `xml:"edgeModifier,attr,omitempty" json:"edgeModifier,omitempty"`
@@ -641,8 +755,9 @@ type BodyLDCoil struct {
 	ConnectionPointIn  *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
 	ConnectionPointOut *ConnectionPointOut  `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
 	Variable           string               `xml:"variable" json:"variable"`
-	Documentation      []byte               `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID            uint64               `xml:"localId,attr" json:"localID"`
+	AddData            *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64               `xml:"localId,attr" json:"localId"`
 	Height             *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width              *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
 	EdgeModifier       *EdgeModifierType    `xml:"edgeModifier,attr,omitempty" json:"edgeModifier,omitempty"`
@@ -654,8 +769,9 @@ type BodyLDCoil struct {
 type BodyLDLeftPowerRail struct {
 	Position           *Position           `xml:"position,omitempty" json:"position,omitempty"`
 	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
-	Documentation      []byte              `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID            uint64              `xml:"localId,attr" json:"localID"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
 	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
@@ -664,8 +780,9 @@ type BodyLDLeftPowerRail struct {
 type BodyLDRightPowerRail struct {
 	Position          *Position          `xml:"position,omitempty" json:"position,omitempty"`
 	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
-	Documentation     []byte             `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID           uint64             `xml:"localId,attr" json:"localID"`
+	AddData           *AddData           `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64             `xml:"localId,attr" json:"localId"`
 	Height            *float64           `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width             *float64           `xml:"width,attr,omitempty" json:"width,omitempty"`
 }
@@ -676,9 +793,10 @@ type BodySFCStep struct {
 	ConnectionPointIn        *BodySFCStepConnectionPointIn        `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
 	ConnectionPointOut       *BodySFCStepConnectionPointOut       `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
 	ConnectionPointOutAction *BodySFCStepConnectionPointOutAction `xml:"connectionPointOutAction,omitempty" json:"connectionPointOutAction,omitempty"`
-	Documentation            []byte                               `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	AddData                  *AddData                             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation            *FormattedText                       `xml:"documentation,omitempty" json:"documentation,omitempty"`
 	Name                     string                               `xml:"name,attr" json:"name"`
-	LocalID                  uint64                               `xml:"localId,attr" json:"localID"`
+	LocalID                  uint64                               `xml:"localId,attr" json:"localId"`
 	Height                   *float64                             `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width                    *float64                             `xml:"width,attr,omitempty" json:"width,omitempty"`
 	InitialStep              *bool                                `xml:"initialStep,attr,omitempty" json:"initialStep,omitempty"`
@@ -686,17 +804,49 @@ type BodySFCStep struct {
 
 // BodySFCStepConnectionPointIn represents a step's input connection point
 type BodySFCStepConnectionPointIn struct {
+	RelPosition *Position    `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
 	Connections []Connection `xml:"connection,omitempty" json:"connections,omitempty"`
+	Expression  *string      `xml:"expression,omitempty" json:"expression,omitempty"`
 }
 
 // BodySFCStepConnectionPointOut represents a step's output connection point
 type BodySFCStepConnectionPointOut struct {
-	FormalParameter *string `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
+	RelPosition     *Position `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
+	Expression      *string   `xml:"expression,omitempty" json:"expression,omitempty"`
+	FormalParameter *string   `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
 }
 
 // BodySFCStepConnectionPointOutAction represents a step's action output connection point
 type BodySFCStepConnectionPointOutAction struct {
-	FormalParameter *string `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
+	RelPosition     *Position `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
+	Expression      *string   `xml:"expression,omitempty" json:"expression,omitempty"`
+	FormalParameter *string   `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
+}
+
+// BodySFCMacroStep represents a macro step in SFC whose body holds a nested sequence
+type BodySFCMacroStep struct {
+	Position           *Position           `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn  `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Body               *Body               `xml:"body,omitempty" json:"body,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Name               *string             `xml:"name,attr,omitempty" json:"name,omitempty"`
+}
+
+// BodySFCJumpStep represents a jump to the step named by TargetName in SFC
+type BodySFCJumpStep struct {
+	Position          *Position          `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	AddData           *AddData           `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64             `xml:"localId,attr" json:"localId"`
+	Height            *float64           `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64           `xml:"width,attr,omitempty" json:"width,omitempty"`
+	TargetName        string             `xml:"targetName,attr" json:"targetName"`
 }
 
 // BodySFCTransition represents a transition in SFC
@@ -705,8 +855,9 @@ type BodySFCTransition struct {
 	ConnectionPointIn  *ConnectionPointIn          `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
 	ConnectionPointOut *ConnectionPointOut         `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
 	Condition          *BodySFCTransitionCondition `xml:"condition,omitempty" json:"condition,omitempty"`
-	Documentation      []byte                      `xml:"documentation,omitempty" json:"documentation,omitempty"`
-	LocalID            uint64                      `xml:"localId,attr" json:"localID"`
+	AddData            *AddData                    `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText              `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64                      `xml:"localId,attr" json:"localId"`
 	Height             *float64                    `xml:"height,attr,omitempty" json:"height,omitempty"`
 	Width              *float64                    `xml:"width,attr,omitempty" json:"width,omitempty"`
 	Priority           *uint64                     `xml:"priority,attr,omitempty" json:"priority,omitempty"`
@@ -729,6 +880,69 @@ type BodySFCTransitionConditionReference struct {
 	Name string `xml:"name,attr" json:"name"`
 }
 
+// BodySFCSelectionDivergence represents a selection divergence (alternative branch start) in SFC
+type BodySFCSelectionDivergence struct {
+	Position           *Position                                      `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn                             `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut []BodySFCSelectionDivergenceConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData                                       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText                                 `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64                                         `xml:"localId,attr" json:"localId"`
+	Height             *float64                                       `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64                                       `xml:"width,attr,omitempty" json:"width,omitempty"`
+}
+
+// BodySFCSelectionDivergenceConnectionPointOut represents one outgoing branch of a selection divergence
+type BodySFCSelectionDivergenceConnectionPointOut struct {
+	RelPosition     *Position `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
+	Expression      *string   `xml:"expression,omitempty" json:"expression,omitempty"`
+	FormalParameter string    `xml:"formalParameter,attr" json:"formalParameter"`
+}
+
+// BodySFCSelectionConvergence represents a selection convergence (alternative branch end) in SFC
+type BodySFCSelectionConvergence struct {
+	Position           *Position           `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn  []ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+}
+
+// BodySFCSimultaneousDivergence represents a simultaneous divergence (parallel branch start) in SFC
+type BodySFCSimultaneousDivergence struct {
+	Position           *Position                                         `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn                                `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut []BodySFCSimultaneousDivergenceConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData                                          `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText                                    `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64                                            `xml:"localId,attr" json:"localId"`
+	Height             *float64                                          `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64                                          `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Name               *string                                           `xml:"name,attr,omitempty" json:"name,omitempty"`
+}
+
+// BodySFCSimultaneousDivergenceConnectionPointOut represents one outgoing branch of a simultaneous divergence
+type BodySFCSimultaneousDivergenceConnectionPointOut struct {
+	RelPosition     *Position `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
+	Expression      *string   `xml:"expression,omitempty" json:"expression,omitempty"`
+	FormalParameter string    `xml:"formalParameter,attr" json:"formalParameter"`
+}
+
+// BodySFCSimultaneousConvergence represents a simultaneous convergence (parallel branch end) in SFC
+type BodySFCSimultaneousConvergence struct {
+	Position           *Position           `xml:"position,omitempty" json:"position,omitempty"`
+	ConnectionPointIn  []ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+}
+
 // Variable list types for different scopes (type aliases to VarList)
 type ProjectTypesPOUInterfaceExternalVars = VarList
 type ProjectTypesPOUInterfaceGlobalVars = VarList
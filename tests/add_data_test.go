@@ -0,0 +1,207 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/validate"
+)
+
+const addDataProject = `<project xmlns="http://www.plcopen.org/xml/tc6.xsd">
+  <fileHeader companyName="Test" productName="Test" productVersion="1.0" creationDateTime="2025-01-01T00:00:00Z"/>
+  <contentHeader name="AddData"><coordinateInfo><fbd><scaling x="1" y="1"/></fbd><ld><scaling x="1" y="1"/></ld><sfc><scaling x="1" y="1"/></sfc></coordinateInfo></contentHeader>
+  <types>
+    <dataTypes/>
+    <pous>
+      <pou name="Main" pouType="program">
+        <body>
+          <FBD>
+            <block localId="1" typeName="ADD">
+              <position x="0" y="0"/>
+              <inputVariables/><inOutVariables/><outputVariables/>
+              <addData>
+                <data name="http://www.beremiz.org/block" handleUnknown="discard">
+                  <executionOrder value="3"/>
+                </data>
+              </addData>
+            </block>
+          </FBD>
+          <addData><data name="http://example.com/body" handleUnknown="preserve"><zoom level="2"/></data></addData>
+        </body>
+        <addData>
+          <data name="http://www.3s-software.com/plcopenxml/objectid" handleUnknown="discard">
+            <ObjectId xmlns:v="urn:vendor">1f1e0a2c-<v:b kind="x">id</v:b></ObjectId>
+          </data>
+        </addData>
+      </pou>
+    </pous>
+  </types>
+  <instances><configurations/></instances>
+  <addData><data name="http://example.com/project" handleUnknown="implementation"><settings mode="fast"/></data></addData>
+</project>`
+
+// executionOrder is a typed decoder target for the Beremiz block data
+type executionOrder struct {
+	XMLName xml.Name `xml:"executionOrder"`
+	Value   int      `xml:"value,attr"`
+}
+
+// TestAddDataRoundTrip checks that vendor data is kept on project, POU, body and objects
+func TestAddDataRoundTrip(t *testing.T) {
+	var project plcopen.Project
+	if err := xml.Unmarshal([]byte(addDataProject), &project); err != nil {
+		t.Fatalf("Failed to unmarshal project: %v", err)
+	}
+
+	check := func(project *plcopen.Project) {
+		t.Helper()
+		if d := project.AddData.Find("http://example.com/project"); d == nil || d.HandleUnknown != plcopen.HandleUnknownTypeImplementation {
+			t.Errorf("Project addData lost: %+v", project.AddData)
+		}
+		pou := project.Types.POUs[0]
+		d := pou.AddData.Find("http://www.3s-software.com/plcopenxml/objectid")
+		if d == nil {
+			t.Fatalf("POU addData lost")
+		}
+		content, err := d.Content()
+		if err != nil {
+			t.Fatalf("Content: %v", err)
+		}
+		if !strings.Contains(content, `<ObjectId xmlns:v="urn:vendor">1f1e0a2c-<v:b kind="x">id</v:b></ObjectId>`) {
+			t.Errorf("POU addData content = %s", content)
+		}
+		if pou.Body.AddData.Find("http://example.com/body") == nil {
+			t.Errorf("Body addData lost")
+		}
+		block := pou.Body.FBD.Blocks[0]
+		if block.AddData.Find("http://www.beremiz.org/block") == nil {
+			t.Errorf("Block addData lost")
+		}
+	}
+	check(&project)
+
+	data, err := xml.Marshal(&project)
+	if err != nil {
+		t.Fatalf("Failed to marshal project: %v", err)
+	}
+	var reparsed plcopen.Project
+	if err := xml.Unmarshal(data, &reparsed); err != nil {
+		t.Fatalf("Failed to unmarshal re-marshaled project: %v\n%s", err, data)
+	}
+	check(&reparsed)
+
+	jsonData, err := json.Marshal(&project)
+	if err != nil {
+		t.Fatalf("Failed to marshal project to JSON: %v", err)
+	}
+	var fromJSON plcopen.Project
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatalf("Failed to unmarshal project from JSON: %v", err)
+	}
+	check(&fromJSON)
+}
+
+// TestAddDataRegisteredDecoder checks decoding of data into registered types
+func TestAddDataRegisteredDecoder(t *testing.T) {
+	plcopen.RegisterAddData("http://www.beremiz.org/block", func() interface{} { return &executionOrder{} })
+	defer plcopen.RegisterAddData("http://www.beremiz.org/block", nil)
+
+	var project plcopen.Project
+	if err := xml.Unmarshal([]byte(addDataProject), &project); err != nil {
+		t.Fatalf("Failed to unmarshal project: %v", err)
+	}
+	d := project.Types.POUs[0].Body.FBD.Blocks[0].AddData.Find("http://www.beremiz.org/block")
+	order, ok := d.Value.(*executionOrder)
+	if !ok || order.Value != 3 {
+		t.Fatalf("Value = %#v, want *executionOrder{Value: 3}", d.Value)
+	}
+	if project.Types.POUs[0].AddData.Data[0].Value != nil {
+		t.Errorf("Unregistered data must not get a value")
+	}
+
+	// The typed value is the source of the encoded content
+	order.Value = 7
+	data, err := xml.Marshal(d)
+	if err != nil {
+		t.Fatalf("Failed to marshal data: %v", err)
+	}
+	want := `<AddDataData name="http://www.beremiz.org/block" handleUnknown="discard"><executionOrder value="7"></executionOrder></AddDataData>`
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+}
+
+// TestAddDataSave checks that Save leaves addData out of V1.0B XML, which
+// does not define it, and reports it, and that WithAddData keeps it like
+// xml.Marshal
+func TestAddDataSave(t *testing.T) {
+	project, err := plcopen.Load(strings.NewReader(addDataProject))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	var warnings []string
+	var buf bytes.Buffer
+	if err := project.Save(&buf, plcopen.WithWarnings(func(path, message string) {
+		warnings = append(warnings, path+": "+message)
+	})); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	const pou = "project/types/pous/pou[@name='Main']"
+	want := []string{
+		pou + "/body/FBD/block[@localId='1']",
+		pou + "/body",
+		pou,
+		"project",
+	}
+	for i := range want {
+		want[i] += ": addData dropped, V1.0B does not define it"
+	}
+	if got := strings.Join(warnings, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("Save warnings =\n%s\nwant\n%s", got, strings.Join(want, "\n"))
+	}
+	if strings.Contains(buf.String(), "addData") {
+		t.Errorf("Saved XML contains addData:\n%s", buf.String())
+	}
+	if err := validate.Bytes(buf.Bytes()); err != nil {
+		t.Errorf("Saved XML is not valid: %v\n%s", err, buf.String())
+	}
+	if project.AddData == nil || project.Types.POUs[0].Body.FBD.Blocks[0].AddData == nil {
+		t.Errorf("Save modified the saved project")
+	}
+	buf.Reset()
+	if err := project.Save(&buf); err != nil {
+		t.Fatalf("Save without WithWarnings: %v", err)
+	}
+
+	buf.Reset()
+	if err := project.Save(&buf, plcopen.WithAddData()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	marshaled, err := xml.MarshalIndent(project, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), string(marshaled)) {
+		t.Errorf("Save with WithAddData and xml.Marshal disagree:\n%s\nwant\n%s", buf.String(), marshaled)
+	}
+	saved, err := plcopen.Load(&buf)
+	if err != nil {
+		t.Fatalf("Load saved project: %v", err)
+	}
+	if data := saved.Types.POUs[0].Body.FBD.Blocks[0].AddData.Find("http://www.beremiz.org/block"); data == nil {
+		t.Errorf("Block addData lost in Load/Save round trip with WithAddData")
+	}
+
+	buf.Reset()
+	if err := project.Save(&buf, plcopen.WithFormat(plcopen.FormatJSON)); err != nil {
+		t.Fatalf("Save JSON: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"addData"`) {
+		t.Errorf("Saved JSON lost addData")
+	}
+}
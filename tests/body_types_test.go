@@ -5,10 +5,12 @@ import (
 	"encoding/xml"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/suifei/plcopen-go"
+	"github.com/suifei/plcopen-go/v2"
 )
 
 // TestAllBodyTypes tests all body types defined in the PLCopen schema
@@ -55,7 +57,7 @@ func TestAllBodyTypes(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>counter := counter + 1;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>counter := counter + 1;</xhtml:p>"),
 						},
 					},
 				},
@@ -149,7 +151,7 @@ func TestAllBodyTypes(t *testing.T) {
 								{
 									LocalID:  5,
 									Position: &plcopen.Position{X: 150, Y: 20},
-									Content:  "This is an AND gate",
+									Content:  plcopen.NewFormattedText("This is an AND gate"),
 								},
 							},
 							// Connectors
@@ -217,7 +219,7 @@ func TestAllBodyTypes(t *testing.T) {
 							Body: &plcopen.Body{
 								ST: &plcopen.BodyST{
 									XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-									Xhtml:      "<xhtml:p>// Action code</xhtml:p>",
+									Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>// Action code</xhtml:p>"),
 								},
 							},
 						},
@@ -341,7 +343,7 @@ func TestAllBodyTypes(t *testing.T) {
 											Body: &plcopen.Body{
 												ST: &plcopen.BodyST{
 													XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-													Xhtml:      "<xhtml:p>counter > 0</xhtml:p>",
+													Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>counter > 0</xhtml:p>"),
 												},
 											},
 										},
@@ -367,7 +369,7 @@ func TestAllBodyTypes(t *testing.T) {
 							Body: &plcopen.Body{
 								ST: &plcopen.BodyST{
 									XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-									Xhtml:      "<xhtml:p>done</xhtml:p>",
+									Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>done</xhtml:p>"),
 								},
 							},
 						},
@@ -391,7 +393,7 @@ func TestAllBodyTypes(t *testing.T) {
 					Body: &plcopen.Body{
 						IL: &plcopen.BodyIL{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>LD 10<xhtml:br/>ADD 5<xhtml:br/>ST result</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>LD 10<xhtml:br/>ADD 5<xhtml:br/>ST result</xhtml:p>"),
 						},
 					},
 				},
@@ -495,3 +497,640 @@ func TestAllBodyTypes(t *testing.T) {
 		t.Logf("Body types XML written to: %s", bodyTypesXMLFile)
 	}
 }
+
+// TestSFCBranchRoundTrip tests selection/simultaneous divergence and convergence
+// elements, which carry multiple connection points per SFC branch
+func TestSFCBranchRoundTrip(t *testing.T) {
+	sfc := &plcopen.BodySFC{
+		Steps: []plcopen.BodySFCStep{
+			{Name: "Init", LocalID: 1, Position: &plcopen.Position{X: 100, Y: 50}, InitialStep: boolPtr(true)},
+			{Name: "FillA", LocalID: 3, Position: &plcopen.Position{X: 50, Y: 150}},
+			{Name: "FillB", LocalID: 4, Position: &plcopen.Position{X: 150, Y: 150}},
+		},
+		SelectionDivergences: []plcopen.BodySFCSelectionDivergence{
+			{
+				LocalID:  2,
+				Position: &plcopen.Position{X: 100, Y: 100},
+				ConnectionPointIn: &plcopen.ConnectionPointIn{
+					Connections: []plcopen.Connection{{RefLocalID: 1}},
+				},
+				ConnectionPointOut: []plcopen.BodySFCSelectionDivergenceConnectionPointOut{
+					{FormalParameter: "0"},
+					{FormalParameter: "1"},
+				},
+			},
+		},
+		SelectionConvergences: []plcopen.BodySFCSelectionConvergence{
+			{
+				LocalID:  5,
+				Position: &plcopen.Position{X: 100, Y: 200},
+				ConnectionPointIn: []plcopen.ConnectionPointIn{
+					{Connections: []plcopen.Connection{{RefLocalID: 3}}},
+					{Connections: []plcopen.Connection{{RefLocalID: 4}}},
+				},
+				ConnectionPointOut: &plcopen.ConnectionPointOut{},
+			},
+		},
+		SimultaneousDivergences: []plcopen.BodySFCSimultaneousDivergence{
+			{
+				LocalID:  6,
+				Position: &plcopen.Position{X: 100, Y: 250},
+				Name:     stringPtr("Parallel"),
+				ConnectionPointIn: &plcopen.ConnectionPointIn{
+					Connections: []plcopen.Connection{{RefLocalID: 5}},
+				},
+				ConnectionPointOut: []plcopen.BodySFCSimultaneousDivergenceConnectionPointOut{
+					{FormalParameter: "0"},
+					{FormalParameter: "1"},
+					{FormalParameter: "2"},
+				},
+			},
+		},
+		SimultaneousConvergences: []plcopen.BodySFCSimultaneousConvergence{
+			{
+				LocalID:  7,
+				Position: &plcopen.Position{X: 100, Y: 350},
+				ConnectionPointIn: []plcopen.ConnectionPointIn{
+					{Connections: []plcopen.Connection{{RefLocalID: 6, FormalParameter: stringPtr("0")}}},
+					{Connections: []plcopen.Connection{{RefLocalID: 6, FormalParameter: stringPtr("1")}}},
+					{Connections: []plcopen.Connection{{RefLocalID: 6, FormalParameter: stringPtr("2")}}},
+				},
+			},
+		},
+	}
+
+	data, err := xml.MarshalIndent(sfc, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal SFC branches: %v", err)
+	}
+
+	var parsed plcopen.BodySFC
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Failed to unmarshal SFC branches: %v", err)
+	}
+
+	if len(parsed.SelectionDivergences) != 1 || len(parsed.SelectionDivergences[0].ConnectionPointOut) != 2 {
+		t.Errorf("SelectionDivergences not preserved: %+v", parsed.SelectionDivergences)
+	}
+	if len(parsed.SelectionConvergences) != 1 || len(parsed.SelectionConvergences[0].ConnectionPointIn) != 2 {
+		t.Errorf("SelectionConvergences not preserved: %+v", parsed.SelectionConvergences)
+	}
+	if len(parsed.SimultaneousDivergences) != 1 || len(parsed.SimultaneousDivergences[0].ConnectionPointOut) != 3 {
+		t.Errorf("SimultaneousDivergences not preserved: %+v", parsed.SimultaneousDivergences)
+	}
+	if name := parsed.SimultaneousDivergences[0].Name; name == nil || *name != "Parallel" {
+		t.Errorf("SimultaneousDivergence name = %v, want Parallel", name)
+	}
+	if len(parsed.SimultaneousConvergences) != 1 || len(parsed.SimultaneousConvergences[0].ConnectionPointIn) != 3 {
+		t.Errorf("SimultaneousConvergences not preserved: %+v", parsed.SimultaneousConvergences)
+	}
+	in := parsed.SimultaneousConvergences[0].ConnectionPointIn[2]
+	if len(in.Connections) != 1 || in.Connections[0].RefLocalID != 6 || *in.Connections[0].FormalParameter != "2" {
+		t.Errorf("SimultaneousConvergence connection = %+v, want refLocalId 6 formalParameter 2", in.Connections)
+	}
+
+	// Branches exported by engineering tools must not be dropped on unmarshal
+	exported := `<SFC>
+  <step localId="1" name="Init" initialStep="true"><position x="0" y="0"/></step>
+  <selectionDivergence localId="2">
+    <position x="0" y="50"/>
+    <connectionPointIn><connection refLocalId="1"/></connectionPointIn>
+    <connectionPointOut formalParameter="a"/>
+    <connectionPointOut formalParameter="b"/>
+  </selectionDivergence>
+  <selectionConvergence localId="3">
+    <position x="0" y="150"/>
+    <connectionPointIn><connection refLocalId="2" formalParameter="a"/></connectionPointIn>
+    <connectionPointIn><connection refLocalId="2" formalParameter="b"/></connectionPointIn>
+  </selectionConvergence>
+</SFC>`
+	var imported plcopen.BodySFC
+	if err := xml.Unmarshal([]byte(exported), &imported); err != nil {
+		t.Fatalf("Failed to unmarshal exported SFC: %v", err)
+	}
+	if len(imported.SelectionDivergences) != 1 || len(imported.SelectionDivergences[0].ConnectionPointOut) != 2 {
+		t.Errorf("Exported selectionDivergence dropped: %+v", imported.SelectionDivergences)
+	}
+	if len(imported.SelectionConvergences) != 1 || len(imported.SelectionConvergences[0].ConnectionPointIn) != 2 {
+		t.Errorf("Exported selectionConvergence dropped: %+v", imported.SelectionConvergences)
+	}
+}
+
+// TestSFCJumpAndMacroSteps tests jumpStep and macroStep elements and their
+// position in the marshaled element sequence
+func TestSFCJumpAndMacroSteps(t *testing.T) {
+	sfc := &plcopen.BodySFC{
+		Steps: []plcopen.BodySFCStep{
+			{Name: "Init", LocalID: 1, Position: &plcopen.Position{X: 100, Y: 50}, InitialStep: boolPtr(true)},
+		},
+		MacroSteps: []plcopen.BodySFCMacroStep{
+			{
+				LocalID:  2,
+				Name:     stringPtr("Filling"),
+				Position: &plcopen.Position{X: 100, Y: 150},
+				ConnectionPointIn: &plcopen.ConnectionPointIn{
+					Connections: []plcopen.Connection{{RefLocalID: 3}},
+				},
+				ConnectionPointOut: &plcopen.ConnectionPointOut{},
+				Body: &plcopen.Body{
+					SFC: &plcopen.BodySFC{
+						Steps: []plcopen.BodySFCStep{
+							{Name: "OpenValve", LocalID: 10, Position: &plcopen.Position{X: 0, Y: 0}},
+						},
+					},
+				},
+			},
+		},
+		JumpSteps: []plcopen.BodySFCJumpStep{
+			{
+				LocalID:    5,
+				TargetName: "Init",
+				Position:   &plcopen.Position{X: 100, Y: 300},
+				ConnectionPointIn: &plcopen.ConnectionPointIn{
+					Connections: []plcopen.Connection{{RefLocalID: 4}},
+				},
+			},
+		},
+		Transitions: []plcopen.BodySFCTransition{
+			{LocalID: 3, Position: &plcopen.Position{X: 100, Y: 100}},
+			{LocalID: 4, Position: &plcopen.Position{X: 100, Y: 250}},
+		},
+	}
+
+	data, err := xml.MarshalIndent(sfc, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal SFC: %v", err)
+	}
+
+	// Element order follows the sfcObjects group: step, macroStep, jumpStep, transition
+	xmlStr := string(data)
+	order := []string{"<step ", "<macroStep ", "<jumpStep ", "<transition "}
+	last := -1
+	for _, tag := range order {
+		idx := strings.Index(xmlStr, tag)
+		if idx < 0 {
+			t.Fatalf("Marshaled SFC missing %s", tag)
+		}
+		if idx < last {
+			t.Errorf("Element %s marshaled out of order", tag)
+		}
+		last = idx
+	}
+
+	var parsed plcopen.BodySFC
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Failed to unmarshal SFC: %v", err)
+	}
+	if len(parsed.JumpSteps) != 1 || parsed.JumpSteps[0].TargetName != "Init" {
+		t.Errorf("JumpSteps not preserved: %+v", parsed.JumpSteps)
+	}
+	if len(parsed.MacroSteps) != 1 {
+		t.Fatalf("MacroSteps length = %d, want 1", len(parsed.MacroSteps))
+	}
+	macro := parsed.MacroSteps[0]
+	if macro.Name == nil || *macro.Name != "Filling" {
+		t.Errorf("MacroStep name = %v, want Filling", macro.Name)
+	}
+	if macro.Body == nil || macro.Body.SFC == nil || len(macro.Body.SFC.Steps) != 1 {
+		t.Fatalf("MacroStep body not preserved: %+v", macro.Body)
+	}
+	if macro.Body.SFC.Steps[0].Name != "OpenValve" {
+		t.Errorf("MacroStep nested step = %q, want OpenValve", macro.Body.SFC.Steps[0].Name)
+	}
+}
+
+// TestCommonObjectsInGraphicalBodies tests that comment, error, connector,
+// continuation and actionBlock objects are accepted by FBD, LD and SFC bodies
+func TestCommonObjectsInGraphicalBodies(t *testing.T) {
+	exported := `<SFC>
+  <step localId="1" name="Fill"><position x="0" y="0"/><connectionPointOutAction formalParameter=""/></step>
+  <actionBlock localId="2">
+    <position x="60" y="0"/>
+    <connectionPointIn><connection refLocalId="1"/></connectionPointIn>
+    <action qualifier="L" duration="T#5s" indicator="busy"><reference name="OpenValve"/></action>
+  </actionBlock>
+  <comment localId="3" height="20" width="80"><position x="0" y="100"/><content>fill the tank</content></comment>
+  <error localId="4" height="20" width="80"><position x="0" y="150"/><content>unresolved</content></error>
+  <connector localId="5" name="C1"><position x="0" y="200"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn></connector>
+  <continuation localId="6" name="C1"><position x="100" y="200"/><connectionPointOut/></continuation>
+</SFC>`
+
+	var sfc plcopen.BodySFC
+	if err := xml.Unmarshal([]byte(exported), &sfc); err != nil {
+		t.Fatalf("Failed to unmarshal SFC common objects: %v", err)
+	}
+	if len(sfc.ActionBlocks) != 1 {
+		t.Fatalf("ActionBlocks length = %d, want 1", len(sfc.ActionBlocks))
+	}
+	ab := sfc.ActionBlocks[0]
+	if ab.ConnectionPointIn == nil || len(ab.ConnectionPointIn.Connections) != 1 || ab.ConnectionPointIn.Connections[0].RefLocalID != 1 {
+		t.Errorf("ActionBlock is not tied to step 1: %+v", ab.ConnectionPointIn)
+	}
+	if len(ab.Actions) != 1 || ab.Actions[0].Duration == nil || *ab.Actions[0].Duration != "T#5s" {
+		t.Errorf("ActionBlock action duration not preserved: %+v", ab.Actions)
+	}
+	if ab.Actions[0].Indicator == nil || *ab.Actions[0].Indicator != "busy" {
+		t.Errorf("ActionBlock action indicator not preserved: %+v", ab.Actions[0])
+	}
+	if len(sfc.Comments) != 1 || sfc.Comments[0].Content.PlainText() != "fill the tank" {
+		t.Errorf("Comments not preserved: %+v", sfc.Comments)
+	}
+	if len(sfc.Errors) != 1 || sfc.Errors[0].Content.PlainText() != "unresolved" {
+		t.Errorf("Errors not preserved: %+v", sfc.Errors)
+	}
+	if len(sfc.Connectors) != 1 || sfc.Connectors[0].ConnectionPointIn == nil {
+		t.Errorf("Connector connection point not preserved: %+v", sfc.Connectors)
+	}
+	if len(sfc.Continuations) != 1 || sfc.Continuations[0].ConnectionPointOut == nil {
+		t.Errorf("Continuation connection point not preserved: %+v", sfc.Continuations)
+	}
+
+	ld := &plcopen.BodyLD{
+		LeftPowerRails: []plcopen.BodyLDLeftPowerRail{{LocalID: 1, Position: &plcopen.Position{X: 0, Y: 0}}},
+		Comments:       []plcopen.BodyFBDComment{{LocalID: 2, Position: &plcopen.Position{X: 0, Y: 50}, Content: plcopen.NewFormattedText("rung 1")}},
+		Errors:         []plcopen.BodyFBDError{{LocalID: 3, Position: &plcopen.Position{X: 0, Y: 80}, Content: plcopen.NewFormattedText("bad")}},
+		Connectors:     []plcopen.BodyFBDConnector{{LocalID: 4, Name: "C", Position: &plcopen.Position{X: 10, Y: 10}}},
+		Continuations:  []plcopen.BodyFBDContinuation{{LocalID: 5, Name: "C", Position: &plcopen.Position{X: 20, Y: 10}}},
+		ActionBlocks:   []plcopen.BodyFBDActionBlock{{LocalID: 6, Position: &plcopen.Position{X: 30, Y: 10}}},
+	}
+	data, err := xml.Marshal(ld)
+	if err != nil {
+		t.Fatalf("Failed to marshal LD common objects: %v", err)
+	}
+	var parsedLD plcopen.BodyLD
+	if err := xml.Unmarshal(data, &parsedLD); err != nil {
+		t.Fatalf("Failed to unmarshal LD common objects: %v", err)
+	}
+	if len(parsedLD.Comments) != 1 || len(parsedLD.Errors) != 1 || len(parsedLD.Connectors) != 1 ||
+		len(parsedLD.Continuations) != 1 || len(parsedLD.ActionBlocks) != 1 {
+		t.Errorf("LD common objects not preserved: %+v", parsedLD)
+	}
+
+	fbd := &plcopen.BodyFBD{
+		Errors: []plcopen.BodyFBDError{{LocalID: 1, Position: &plcopen.Position{X: 0, Y: 0}, Content: plcopen.NewFormattedText("bad")}},
+	}
+	data, err = xml.Marshal(fbd)
+	if err != nil {
+		t.Fatalf("Failed to marshal FBD error: %v", err)
+	}
+	var parsedFBD plcopen.BodyFBD
+	if err := xml.Unmarshal(data, &parsedFBD); err != nil {
+		t.Fatalf("Failed to unmarshal FBD error: %v", err)
+	}
+	if len(parsedFBD.Errors) != 1 || parsedFBD.Errors[0].Content.PlainText() != "bad" {
+		t.Errorf("FBD errors not preserved: %+v", parsedFBD.Errors)
+	}
+}
+
+// TestErrorObjectXHTMLContent tests that the formatted text content of
+// comment and error objects survives a round trip and is translated as text
+func TestErrorObjectXHTMLContent(t *testing.T) {
+	exported := `<FBD>
+  <comment localId="2" height="20" width="80">
+    <position x="0" y="40"/>
+    <content><xhtml:p xmlns:xhtml="http://www.w3.org/1999/xhtml">check <xhtml:i>level</xhtml:i> first</xhtml:p></content>
+  </comment>
+  <error localId="1" height="20" width="80">
+    <position x="0" y="0"/>
+    <content><xhtml:p xmlns:xhtml="http://www.w3.org/1999/xhtml">unknown <xhtml:b>vendorBlock</xhtml:b></xhtml:p></content>
+  </error>
+</FBD>`
+	want := "<xhtml:p>unknown <xhtml:b>vendorBlock</xhtml:b></xhtml:p>"
+	wantComment := "<xhtml:p>check <xhtml:i>level</xhtml:i> first</xhtml:p>"
+	var fbd plcopen.BodyFBD
+	if err := xml.Unmarshal([]byte(exported), &fbd); err != nil {
+		t.Fatalf("Failed to unmarshal error object: %v", err)
+	}
+	if len(fbd.Errors) != 1 || fbd.Errors[0].Content.XHTML() != want {
+		t.Fatalf("Error content = %q, want %q", fbd.Errors[0].Content.XHTML(), want)
+	}
+	if len(fbd.Comments) != 1 || fbd.Comments[0].Content.XHTML() != wantComment {
+		t.Fatalf("Comment content = %q, want %q", fbd.Comments[0].Content.XHTML(), wantComment)
+	}
+
+	data, err := xml.Marshal(fbd)
+	if err != nil {
+		t.Fatalf("Failed to marshal error object: %v", err)
+	}
+	var parsed plcopen.BodyFBD
+	if err := xml.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Failed to unmarshal marshaled error object: %v", err)
+	}
+	if len(parsed.Errors) != 1 || parsed.Errors[0].Content.XHTML() != want {
+		t.Errorf("Error content after round trip = %q, want %q\n%s", parsed.Errors[0].Content.XHTML(), want, data)
+	}
+	if len(parsed.Comments) != 1 || parsed.Comments[0].Content.XHTML() != wantComment {
+		t.Errorf("Comment content after round trip = %q, want %q\n%s", parsed.Comments[0].Content.XHTML(), wantComment, data)
+	}
+
+	body, _, _ := parsed.ToST()
+	if got := body.Xhtml.PlainText(); !strings.HasPrefix(got, "(* check level first *)") {
+		t.Errorf("ToST comment = %q, want the text of the comment", got)
+	}
+}
+
+// TestLDRungWithFBDBlocks tests a ladder rung that drives a TON timer block
+// with in/out variables placed directly in the LD network
+func TestLDRungWithFBDBlocks(t *testing.T) {
+	exported := `<LD>
+  <leftPowerRail localId="1"><position x="0" y="0"/><connectionPointOut formalParameter=""/></leftPowerRail>
+  <contact localId="2"><position x="20" y="0"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>start</variable></contact>
+  <inVariable localId="3"><position x="20" y="40"/><connectionPointOut/><expression>T#2s</expression></inVariable>
+  <block localId="4" typeName="TON" instanceName="delay">
+    <position x="80" y="0"/>
+    <inputVariables>
+      <variable formalParameter="IN"><connectionPointIn><connection refLocalId="2"/></connectionPointIn></variable>
+      <variable formalParameter="PT"><connectionPointIn><connection refLocalId="3"/></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables>
+      <variable formalParameter="Q"><connectionPointOut/></variable>
+      <variable formalParameter="ET"><connectionPointOut/></variable>
+    </outputVariables>
+  </block>
+  <outVariable localId="5"><position x="160" y="40"/><connectionPointIn><connection refLocalId="4" formalParameter="ET"/></connectionPointIn><expression>elapsed</expression></outVariable>
+  <inOutVariable localId="6"><position x="160" y="80"/><connectionPointIn/><connectionPointOut/><expression>buffer</expression></inOutVariable>
+  <coil localId="7"><position x="200" y="0"/><connectionPointIn><connection refLocalId="4" formalParameter="Q"/></connectionPointIn><connectionPointOut/><variable>lamp</variable></coil>
+</LD>`
+
+	var ld plcopen.BodyLD
+	if err := xml.Unmarshal([]byte(exported), &ld); err != nil {
+		t.Fatalf("Failed to unmarshal LD rung: %v", err)
+	}
+	if len(ld.Blocks) != 1 {
+		t.Fatalf("Blocks length = %d, want 1", len(ld.Blocks))
+	}
+	if ld.Blocks[0].TypeName != "TON" || ld.Blocks[0].InstanceName == nil || *ld.Blocks[0].InstanceName != "delay" {
+		t.Errorf("TON block not preserved: %+v", ld.Blocks[0])
+	}
+	if len(ld.Blocks[0].InputVariables) != 2 || len(ld.Blocks[0].OutputVariables) != 2 {
+		t.Errorf("TON block variables not preserved: %+v", ld.Blocks[0])
+	}
+	if len(ld.InVariables) != 1 || ld.InVariables[0].Expression != "T#2s" {
+		t.Errorf("InVariables not preserved: %+v", ld.InVariables)
+	}
+	if len(ld.OutVariables) != 1 || ld.OutVariables[0].Expression != "elapsed" {
+		t.Errorf("OutVariables not preserved: %+v", ld.OutVariables)
+	}
+	if len(ld.InOutVariables) != 1 || ld.InOutVariables[0].Expression != "buffer" {
+		t.Errorf("InOutVariables not preserved: %+v", ld.InOutVariables)
+	}
+
+	data, err := xml.Marshal(&ld)
+	if err != nil {
+		t.Fatalf("Failed to marshal LD rung: %v", err)
+	}
+	var reparsed plcopen.BodyLD
+	if err := xml.Unmarshal(data, &reparsed); err != nil {
+		t.Fatalf("Failed to unmarshal re-marshaled LD rung: %v", err)
+	}
+	if len(reparsed.Blocks) != 1 || len(reparsed.InVariables) != 1 || len(reparsed.OutVariables) != 1 ||
+		len(reparsed.InOutVariables) != 1 || len(reparsed.Coils) != 1 || len(reparsed.Contacts) != 1 {
+		t.Errorf("LD rung objects lost on round trip: %+v", reparsed)
+	}
+}
+
+// childElementNames returns the names of the direct children of the root element
+func childElementNames(t *testing.T, data []byte) []string {
+	t.Helper()
+	decoder := xml.NewDecoder(strings.NewReader(string(data)))
+	var names []string
+	depth := 0
+	for {
+		tok, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			if depth == 1 {
+				names = append(names, tok.Name.Local)
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return names
+}
+
+// TestGraphicalObjectOrder tests that unmarshal followed by marshal keeps the
+// original element order of FBD, LD and SFC bodies
+func TestGraphicalObjectOrder(t *testing.T) {
+	inputs := map[string]string{
+		"FBD": `<FBD>
+  <inVariable localId="1"><position x="0" y="0"/><connectionPointOut/><expression>a</expression></inVariable>
+  <block localId="3" typeName="AND"><position x="50" y="0"/><inputVariables/><inOutVariables/><outputVariables/></block>
+  <comment localId="9" height="10" width="10"><position x="0" y="80"/><content>note</content></comment>
+  <inVariable localId="2"><position x="0" y="40"/><connectionPointOut/><expression>b</expression></inVariable>
+  <outVariable localId="4"><position x="100" y="0"/><connectionPointIn><connection refLocalId="3"/></connectionPointIn><expression>c</expression></outVariable>
+</FBD>`,
+		"LD": `<LD>
+  <leftPowerRail localId="1"><position x="0" y="0"/></leftPowerRail>
+  <contact localId="2"><position x="10" y="0"/><variable>a</variable></contact>
+  <coil localId="4"><position x="50" y="0"/><variable>q</variable></coil>
+  <contact localId="3"><position x="30" y="0"/><variable>b</variable></contact>
+  <rightPowerRail localId="5"><position x="80" y="0"/></rightPowerRail>
+</LD>`,
+		"SFC": `<SFC>
+  <step localId="1" name="Init" initialStep="true"><position x="0" y="0"/></step>
+  <transition localId="2"><position x="0" y="20"/></transition>
+  <step localId="3" name="Run"><position x="0" y="40"/></step>
+  <actionBlock localId="5"><position x="40" y="40"/></actionBlock>
+  <transition localId="4"><position x="0" y="60"/></transition>
+  <jumpStep localId="6" targetName="Init"><position x="0" y="80"/></jumpStep>
+</SFC>`,
+	}
+
+	for kind, input := range inputs {
+		var data []byte
+		var err error
+		switch kind {
+		case "FBD":
+			var body plcopen.BodyFBD
+			if err = xml.Unmarshal([]byte(input), &body); err == nil {
+				data, err = xml.Marshal(&body)
+			}
+		case "LD":
+			var body plcopen.BodyLD
+			if err = xml.Unmarshal([]byte(input), &body); err == nil {
+				data, err = xml.Marshal(&body)
+			}
+		case "SFC":
+			var body plcopen.BodySFC
+			if err = xml.Unmarshal([]byte(input), &body); err == nil {
+				data, err = xml.Marshal(&body)
+			}
+		}
+		if err != nil {
+			t.Fatalf("%s round trip failed: %v", kind, err)
+		}
+
+		want := childElementNames(t, []byte(input))
+		got := childElementNames(t, data)
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s element order = %v, want %v", kind, got, want)
+		}
+	}
+
+	// Objects are reported in document order and can be edited in place
+	var fbd plcopen.BodyFBD
+	if err := xml.Unmarshal([]byte(inputs["FBD"]), &fbd); err != nil {
+		t.Fatalf("Failed to unmarshal FBD: %v", err)
+	}
+	var ids []uint64
+	for _, obj := range fbd.Objects() {
+		ids = append(ids, obj.ObjectID())
+	}
+	if len(ids) != 5 || ids[0] != 1 || ids[1] != 3 || ids[2] != 9 || ids[3] != 2 || ids[4] != 4 {
+		t.Errorf("Objects order = %v, want [1 3 9 2 4]", ids)
+	}
+	fbd.Objects()[1].(*plcopen.BodyFBDBlock).TypeName = "OR"
+	if fbd.Blocks[0].TypeName != "OR" {
+		t.Errorf("Objects did not return pointers into the body")
+	}
+
+	// SetObjects builds a body with a caller-defined order
+	var sfc plcopen.BodySFC
+	err := sfc.SetObjects([]plcopen.GraphicalObject{
+		&plcopen.BodySFCTransition{LocalID: 2, Position: &plcopen.Position{X: 0, Y: 20}},
+		&plcopen.BodySFCStep{LocalID: 1, Name: "Init", Position: &plcopen.Position{X: 0, Y: 0}},
+	})
+	if err != nil {
+		t.Fatalf("SetObjects failed: %v", err)
+	}
+	data, err := xml.Marshal(&sfc)
+	if err != nil {
+		t.Fatalf("Failed to marshal SFC: %v", err)
+	}
+	if got := strings.Join(childElementNames(t, data), ","); got != "transition,step" {
+		t.Errorf("SetObjects order = %s, want transition,step", got)
+	}
+	var ld plcopen.BodyLD
+	if err := ld.SetObjects([]plcopen.GraphicalObject{&plcopen.BodySFCStep{LocalID: 1}}); err == nil {
+		t.Errorf("SetObjects accepted a step in an LD body")
+	}
+}
+
+// TestLDJumpsAndSFCGraphicalObjects tests ladder jumps, labels and returns,
+// and FBD and LD objects feeding the transitions of an SFC body
+func TestLDJumpsAndSFCGraphicalObjects(t *testing.T) {
+	ladder := `<LD>
+  <leftPowerRail localId="1"><position x="0" y="0"/><connectionPointOut formalParameter=""/></leftPowerRail>
+  <contact localId="2"><position x="20" y="0"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>skip</variable></contact>
+  <jump localId="3" label="End"><position x="60" y="0"/><connectionPointIn><connection refLocalId="2"/></connectionPointIn></jump>
+  <coil localId="4"><position x="60" y="40"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>lamp</variable></coil>
+  <label localId="5" label="End"><position x="0" y="80"/></label>
+  <return localId="6"><position x="60" y="80"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn></return>
+</LD>`
+	var ld plcopen.BodyLD
+	if err := xml.Unmarshal([]byte(ladder), &ld); err != nil {
+		t.Fatalf("Failed to unmarshal LD jumps: %v", err)
+	}
+	if len(ld.Jumps) != 1 || ld.Jumps[0].Label != "End" || len(ld.Labels) != 1 || len(ld.Returns) != 1 {
+		t.Errorf("LD jumps, labels and returns not preserved: %+v", ld)
+	}
+	data, err := xml.Marshal(&ld)
+	if err != nil {
+		t.Fatalf("Failed to marshal LD jumps: %v", err)
+	}
+	want := "leftPowerRail,contact,jump,coil,label,return"
+	if got := strings.Join(childElementNames(t, data), ","); got != want {
+		t.Errorf("LD order = %s, want %s", got, want)
+	}
+
+	chart := `<SFC>
+  <step localId="1" name="Fill" initialStep="true"><position x="0" y="0"/><connectionPointOut/></step>
+  <leftPowerRail localId="2"><position x="40" y="40"/><connectionPointOut formalParameter=""/></leftPowerRail>
+  <contact localId="3"><position x="60" y="40"/><connectionPointIn><connection refLocalId="2"/></connectionPointIn><connectionPointOut/><variable>full</variable></contact>
+  <inVariable localId="4"><position x="60" y="60"/><connectionPointOut/><expression>level &gt; 90</expression></inVariable>
+  <block localId="5" typeName="AND"><position x="100" y="40"/>
+    <inputVariables>
+      <variable formalParameter="IN1"><connectionPointIn><connection refLocalId="3"/></connectionPointIn></variable>
+      <variable formalParameter="IN2"><connectionPointIn><connection refLocalId="4"/></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables><variable formalParameter="OUT"><connectionPointOut/></variable></outputVariables>
+  </block>
+  <transition localId="6"><position x="0" y="40"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/>
+    <condition><connectionPointIn><connection refLocalId="5" formalParameter="OUT"/></connectionPointIn></condition>
+  </transition>
+</SFC>`
+	var sfc plcopen.BodySFC
+	if err := xml.Unmarshal([]byte(chart), &sfc); err != nil {
+		t.Fatalf("Failed to unmarshal SFC: %v", err)
+	}
+	if len(sfc.LeftPowerRails) != 1 || len(sfc.Contacts) != 1 || len(sfc.InVariables) != 1 || len(sfc.Blocks) != 1 {
+		t.Errorf("SFC graphical objects not preserved: %+v", sfc)
+	}
+	data, err = xml.Marshal(&sfc)
+	if err != nil {
+		t.Fatalf("Failed to marshal SFC: %v", err)
+	}
+	want = "step,leftPowerRail,contact,inVariable,block,transition"
+	if got := strings.Join(childElementNames(t, data), ","); got != want {
+		t.Errorf("SFC order = %s, want %s", got, want)
+	}
+}
+
+// TestConnectionRoutingRoundTrip checks that wire route points and pin positions survive a round trip
+func TestConnectionRoutingRoundTrip(t *testing.T) {
+	exported := `<FBD>
+  <inVariable localId="1">
+    <position x="10" y="20"/>
+    <connectionPointOut><relPosition x="40" y="10"/></connectionPointOut>
+    <expression>a</expression>
+  </inVariable>
+  <outVariable localId="2">
+    <position x="150" y="60"/>
+    <connectionPointIn>
+      <relPosition x="0" y="10"/>
+      <connection refLocalId="1">
+        <position x="150" y="70"/>
+        <position x="100" y="70"/>
+        <position x="100" y="30"/>
+        <position x="50" y="30"/>
+      </connection>
+    </connectionPointIn>
+    <expression>b</expression>
+  </outVariable>
+</FBD>`
+
+	var fbd plcopen.BodyFBD
+	if err := xml.Unmarshal([]byte(exported), &fbd); err != nil {
+		t.Fatalf("Failed to unmarshal FBD: %v", err)
+	}
+
+	check := func(fbd plcopen.BodyFBD) {
+		t.Helper()
+		if len(fbd.InVariables) != 1 || len(fbd.OutVariables) != 1 {
+			t.Fatalf("Variables lost: %+v", fbd)
+		}
+		out := fbd.InVariables[0].ConnectionPointOut
+		if out == nil || out.RelPosition == nil || *out.RelPosition != (plcopen.Position{X: 40, Y: 10}) {
+			t.Errorf("connectionPointOut relPosition not preserved: %+v", out)
+		}
+		in := fbd.OutVariables[0].ConnectionPointIn
+		if in == nil || in.RelPosition == nil || *in.RelPosition != (plcopen.Position{X: 0, Y: 10}) {
+			t.Fatalf("connectionPointIn relPosition not preserved: %+v", in)
+		}
+		want := []plcopen.Position{{X: 150, Y: 70}, {X: 100, Y: 70}, {X: 100, Y: 30}, {X: 50, Y: 30}}
+		if len(in.Connections) != 1 || !reflect.DeepEqual(in.Connections[0].Positions, want) {
+			t.Errorf("Connection route = %+v, want %+v", in.Connections, want)
+		}
+	}
+	check(fbd)
+
+	data, err := xml.Marshal(&fbd)
+	if err != nil {
+		t.Fatalf("Failed to marshal FBD: %v", err)
+	}
+	if !strings.Contains(string(data), `<relPosition x="0" y="10"></relPosition><connection refLocalId="1"><position x="150" y="70">`) {
+		t.Errorf("relPosition must precede connections in schema order:\n%s", data)
+	}
+	var reparsed plcopen.BodyFBD
+	if err := xml.Unmarshal(data, &reparsed); err != nil {
+		t.Fatalf("Failed to unmarshal re-marshaled FBD: %v", err)
+	}
+	check(reparsed)
+}
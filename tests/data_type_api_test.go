@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// TestParseDataType checks that declarations parse to the expected kind and
+// render back in canonical IEC syntax
+func TestParseDataType(t *testing.T) {
+	tests := []struct {
+		decl string
+		kind plcopen.DataTypeKind
+		want string
+	}{
+		{"INT", plcopen.DataTypeKindINT, "INT"},
+		{"lreal", plcopen.DataTypeKindLREAL, "LREAL"},
+		{"TIME_OF_DAY", plcopen.DataTypeKindTOD, "TOD"},
+		{"STRING", plcopen.DataTypeKindString, "STRING"},
+		{"STRING(80)", plcopen.DataTypeKindString, "STRING(80)"},
+		{"wstring[20]", plcopen.DataTypeKindWString, "WSTRING(20)"},
+		{"ARRAY[0..9] OF INT", plcopen.DataTypeKindArray, "ARRAY[0..9] OF INT"},
+		{"array [1..3, -2..2] of array[0..1] of REAL", plcopen.DataTypeKindArray, "ARRAY[1..3, -2..2] OF ARRAY[0..1] OF REAL"},
+		{"POINTER TO MyStruct", plcopen.DataTypeKindPointer, "POINTER TO MyStruct"},
+		{"MyStruct", plcopen.DataTypeKindDerived, "MyStruct"},
+		{"(Red, Green,Blue)", plcopen.DataTypeKindEnum, "(Red, Green, Blue)"},
+		{"INT (Idle, Running)", plcopen.DataTypeKindEnum, "INT (Idle, Running)"},
+		{"(Idle:=0, Running := 1)", plcopen.DataTypeKindEnum, "(Idle := 0, Running := 1)"},
+		{"WORD (A := 16#FF, B)", plcopen.DataTypeKindEnum, "WORD (A := 16#FF, B)"},
+		{"INT(0..100)", plcopen.DataTypeKindSubrangeSigned, "INT(0..100)"},
+		{"SINT (-10 .. 10)", plcopen.DataTypeKindSubrangeSigned, "SINT(-10..10)"},
+		{"UINT(1..4)", plcopen.DataTypeKindSubrangeUnsigned, "UINT(1..4)"},
+		{"STRUCT x : BOOL; y AT %MW2 : INT := 5; END_STRUCT", plcopen.DataTypeKindStruct, "STRUCT x : BOOL; y AT %MW2 : INT := 5; END_STRUCT"},
+		{"STRUCT\n  a : ARRAY[1..3] OF INT := [1, 2(0)];\n  t : TOD := TOD#12:00:00;\n  p : Point := (x := 1, y := -1.5);\nEND_STRUCT", plcopen.DataTypeKindStruct,
+			"STRUCT a : ARRAY[1..3] OF INT := [1, 2(0)]; t : TOD := TOD#12:00:00; p : Point := (x := 1, y := -1.5); END_STRUCT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.decl, func(t *testing.T) {
+			dt, err := plcopen.ParseDataType(tt.decl)
+			if err != nil {
+				t.Fatalf("ParseDataType: %v", err)
+			}
+			if dt.Kind() != tt.kind {
+				t.Errorf("Kind = %q, want %q", dt.Kind(), tt.kind)
+			}
+			if got := dt.IEC(); got != tt.want {
+				t.Errorf("IEC = %q, want %q", got, tt.want)
+			}
+			if got := fmt.Sprintf("%v", dt); got != tt.want {
+				t.Errorf("%%v = %q, want %q", got, tt.want)
+			}
+			again, err := plcopen.ParseDataType(dt.IEC())
+			if err != nil {
+				t.Fatalf("ParseDataType(%q): %v", dt.IEC(), err)
+			}
+			if !again.Equal(dt) {
+				t.Errorf("%q does not parse to an equal type", dt.IEC())
+			}
+		})
+	}
+}
+
+// TestParseDataTypeErrors checks that invalid declarations are rejected
+func TestParseDataTypeErrors(t *testing.T) {
+	for _, decl := range []string{
+		"",
+		"ARRAY[0..9] INT",
+		"ARRAY[0..] OF INT",
+		"STRING(x)",
+		"REAL(0..10)",
+		"UINT(-1..10)",
+		"POINTER MyStruct",
+		"INT INT",
+		"STRUCT x : INT",
+		"(Red, )",
+		"(Red := )",
+		"(Red := , Green)",
+		"3Phase",
+	} {
+		if dt, err := plcopen.ParseDataType(decl); err == nil {
+			t.Errorf("ParseDataType(%q) = %v, want an error", decl, dt)
+		} else if !strings.HasPrefix(err.Error(), "plcopen: ") {
+			t.Errorf("ParseDataType(%q) error %q has no package prefix", decl, err)
+		}
+	}
+
+	// Types without a V1.0B equivalent must not be taken as derived types
+	for _, decl := range []string{"LTIME", "ldt", "WCHAR", "REF_TO INT", "ARRAY[*] OF INT", "ARRAY[0..1] OF LTIME", "STRUCT r : REF_TO Motor; END_STRUCT"} {
+		if dt, err := plcopen.ParseDataType(decl); err == nil || !strings.Contains(err.Error(), "is not supported by TC6 XML V1.0B") {
+			t.Errorf("ParseDataType(%q) = %v, %v, want unsupported type error", decl, dt, err)
+		}
+	}
+}
+
+// TestDataTypeEqual checks the comparison of data types
+func TestDataTypeEqual(t *testing.T) {
+	parse := func(s string) *plcopen.DataType {
+		dt, err := plcopen.ParseDataType(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dt
+	}
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"INT", "int", true},
+		{"INT", "DINT", false},
+		{"STRING", "STRING(80)", false},
+		{"ARRAY[0..9] OF INT", "ARRAY[0..9] OF INT", true},
+		{"ARRAY[0..9] OF INT", "ARRAY[1..9] OF INT", false},
+		{"ARRAY[0..9] OF INT", "ARRAY[0..9] OF UINT", false},
+		{"Motor", "MOTOR", true},
+		{"(A, B)", "(a, b)", true},
+		{"(A, B)", "(B, A)", false},
+		{"(A := 1, B)", "(A := 1, B)", true},
+		{"(A := 1, B)", "(A := 2, B)", false},
+		{"(A := 1, B)", "(A, B)", false},
+		{"INT(0..10)", "INT(0..11)", false},
+		{"STRUCT x : INT; END_STRUCT", "STRUCT X : INT; END_STRUCT", true},
+		{"STRUCT x : INT := 1; END_STRUCT", "STRUCT x : INT; END_STRUCT", false},
+	}
+	for _, tt := range tests {
+		if got := parse(tt.a).Equal(parse(tt.b)); got != tt.want {
+			t.Errorf("%s Equal %s = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	var empty *plcopen.DataType
+	if !empty.Equal(&plcopen.DataType{}) || empty.Kind() != "" || empty.IEC() != "" {
+		t.Errorf("nil DataType is not empty")
+	}
+	if !(&plcopen.DataType{INT: &struct{}{}}).Equal(parse("INT")) {
+		t.Errorf("Literal INT differs from parsed INT")
+	}
+}
+
+// TestDataTypeKindAll checks that Kind covers every field of DataType
+func TestDataTypeKindAll(t *testing.T) {
+	kinds := map[plcopen.DataTypeKind]bool{}
+	typ := reflect.TypeOf(plcopen.DataType{})
+	for i := 0; i < typ.NumField(); i++ {
+		var dt plcopen.DataType
+		f := reflect.ValueOf(&dt).Elem().Field(i)
+		f.Set(reflect.New(f.Type().Elem()))
+		kind := dt.Kind()
+		if kind == "" || kinds[kind] {
+			t.Errorf("Field %s has kind %q", typ.Field(i).Name, kind)
+		}
+		kinds[kind] = true
+		if elementary := typ.Field(i).Type.Elem().NumField() == 0; kind.IsElementary() != elementary {
+			t.Errorf("Field %s: IsElementary = %v", typ.Field(i).Name, kind.IsElementary())
+		}
+	}
+}
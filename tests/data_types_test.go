@@ -8,7 +8,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/suifei/plcopen-go"
+	"github.com/suifei/plcopen-go/v2"
 )
 
 // TestAllDataTypes tests all data types defined in the PLCopen schema
@@ -105,7 +105,7 @@ func TestAllDataTypes(t *testing.T) {
 								{Name: "y", Type: &plcopen.DataType{REAL: &struct{}{}}},
 								{Name: "z", Type: &plcopen.DataType{REAL: &struct{}{}}},
 							},
-							Documentation: []byte("<documentation>3D point</documentation>"),
+							Documentation: plcopen.NewFormattedText("3D point"),
 						},
 					},
 				},
@@ -127,7 +127,7 @@ func TestAllDataTypes(t *testing.T) {
 						Enum: &plcopen.DataTypeEnum{
 							Values: &plcopen.DataTypeEnumValues{
 								Values: []plcopen.DataTypeEnumValuesValue{
-									{Name: "RED", Documentation: []byte("<documentation>Red color</documentation>")},
+									{Name: "RED", Documentation: plcopen.NewFormattedText("Red color")},
 									{Name: "GREEN"},
 									{Name: "BLUE"},
 									{Name: "YELLOW"},
@@ -224,7 +224,7 @@ func TestAllDataTypes(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>// Data types test program</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>// Data types test program</xhtml:p>"),
 						},
 					},
 				},
@@ -241,7 +241,7 @@ func TestAllDataTypes(t *testing.T) {
 								{
 									Name:          "DataTypesInstance",
 									TypeName:      "DataTypesProgram",
-									Documentation: []byte("<documentation>Types test instance</documentation>"),
+									Documentation: plcopen.NewFormattedText("Types test instance"),
 								},
 							},
 						},
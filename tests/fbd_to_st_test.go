@@ -0,0 +1,164 @@
+package tests
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+const fbdNetwork = `<FBD>
+  <comment localId="30" height="20" width="100"><position x="0" y="0"/><content>Alarm after 5 s</content></comment>
+  <inVariable localId="1"><position x="0" y="20"/><connectionPointOut/><expression>a</expression></inVariable>
+  <inVariable localId="2"><position x="0" y="40"/><connectionPointOut/><expression>b</expression></inVariable>
+  <inVariable localId="5"><position x="0" y="60"/><connectionPointOut/><expression>100</expression></inVariable>
+  <inVariable localId="6"><position x="0" y="80"/><connectionPointOut/><expression>T#5s</expression></inVariable>
+  <inVariable localId="4" edgeModifier="rising"><position x="0" y="100"/><connectionPointOut/><expression>start</expression></inVariable>
+  <block localId="10" typeName="ADD"><position x="50" y="20"/>
+    <inputVariables>
+      <variable formalParameter="IN1"><connectionPointIn><connection refLocalId="1"/></connectionPointIn></variable>
+      <variable formalParameter="IN2"><connectionPointIn><connection refLocalId="2"/></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables><variable formalParameter="OUT"><connectionPointOut/></variable></outputVariables>
+  </block>
+  <block localId="11" typeName="GT"><position x="100" y="20"/>
+    <inputVariables>
+      <variable formalParameter="IN1"><connectionPointIn><connection refLocalId="10" formalParameter="OUT"/></connectionPointIn></variable>
+      <variable formalParameter="IN2"><connectionPointIn><connection refLocalId="5"/></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables><variable formalParameter="OUT"><connectionPointOut/></variable></outputVariables>
+  </block>
+  <block localId="12" typeName="TON" instanceName="timer1"><position x="150" y="20"/>
+    <inputVariables>
+      <variable formalParameter="IN"><connectionPointIn><connection refLocalId="11"/></connectionPointIn></variable>
+      <variable formalParameter="PT"><connectionPointIn><connection refLocalId="6"/></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables>
+      <variable formalParameter="Q"><connectionPointOut/></variable>
+      <variable formalParameter="ET"><connectionPointOut/></variable>
+    </outputVariables>
+  </block>
+  <block localId="13" typeName="AND"><position x="50" y="120"/>
+    <inputVariables>
+      <variable formalParameter="IN1"><connectionPointIn><connection refLocalId="1"/></connectionPointIn></variable>
+      <variable formalParameter="IN2"><connectionPointIn><connection refLocalId="14"/></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables><variable formalParameter="OUT"><connectionPointOut/></variable></outputVariables>
+  </block>
+  <block localId="14" typeName="NOT"><position x="20" y="140"/>
+    <inputVariables>
+      <variable formalParameter="IN"><connectionPointIn><connection refLocalId="2"/></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables><variable formalParameter="OUT"><connectionPointOut/></variable></outputVariables>
+  </block>
+  <block localId="15" typeName="LIMIT"><position x="50" y="200"/>
+    <inputVariables>
+      <variable formalParameter="MN"><connectionPointIn><connection refLocalId="5"/></connectionPointIn></variable>
+      <variable formalParameter="IN"><connectionPointIn><connection refLocalId="1"/></connectionPointIn></variable>
+      <variable formalParameter="MX"><connectionPointIn><expression>200</expression></connectionPointIn></variable>
+    </inputVariables>
+    <inOutVariables/>
+    <outputVariables><variable formalParameter="OUT"><connectionPointOut/></variable></outputVariables>
+  </block>
+  <connector localId="40" name="c"><position x="100" y="120"/><connectionPointIn><connection refLocalId="13"/></connectionPointIn></connector>
+  <continuation localId="41" name="c"><position x="0" y="160"/><connectionPointOut/></continuation>
+  <outVariable localId="20"><position x="200" y="0"/><connectionPointIn><connection refLocalId="10"/></connectionPointIn><expression>sum</expression></outVariable>
+  <outVariable localId="21"><position x="200" y="20"/><connectionPointIn><connection refLocalId="12" formalParameter="Q"/></connectionPointIn><expression>alarm</expression></outVariable>
+  <outVariable localId="22"><position x="200" y="40"/><connectionPointIn><connection refLocalId="12" formalParameter="ET"/></connectionPointIn><expression>elapsed</expression></outVariable>
+  <outVariable localId="23" storageModifier="set"><position x="200" y="100"/><connectionPointIn><connection refLocalId="4"/></connectionPointIn><expression>motor</expression></outVariable>
+  <outVariable localId="24"><position x="200" y="120"/><connectionPointIn><connection refLocalId="13"/></connectionPointIn><expression>x</expression></outVariable>
+  <outVariable localId="25"><position x="200" y="160"/><connectionPointIn><connection refLocalId="41"/></connectionPointIn><expression>y</expression></outVariable>
+  <outVariable localId="26"><position x="200" y="200"/><connectionPointIn><connection refLocalId="15"/></connectionPointIn><expression>speed[1]</expression></outVariable>
+</FBD>`
+
+// TestFBDToST translates a network with functions, a function block
+// instance, fan-out, connectors and an edge
+func TestFBDToST(t *testing.T) {
+	var fbd plcopen.BodyFBD
+	if err := xml.Unmarshal([]byte(fbdNetwork), &fbd); err != nil {
+		t.Fatalf("Failed to unmarshal FBD: %v", err)
+	}
+	body, vars, err := fbd.ToST()
+	if err != nil {
+		t.Fatalf("ToST: %v", err)
+	}
+	want := `(* Alarm after 5 s *)
+ADD_10 := a + b;
+timer1(IN := ADD_10 > 100, PT := T#5s);
+AND_13 := a AND NOT b;
+sum := ADD_10;
+alarm := timer1.Q;
+elapsed := timer1.ET;
+R_TRIG_4(CLK := start);
+IF R_TRIG_4.Q THEN
+    motor := TRUE;
+END_IF;
+x := AND_13;
+y := AND_13;
+speed[1] := LIMIT(MN := 100, IN := a, MX := 200);`
+	if got := body.Xhtml.PlainText(); got != want {
+		t.Errorf("ToST =\n%s\nwant\n%s", got, want)
+	}
+	var decls []string
+	for _, v := range vars {
+		decl := v.Name
+		if v.Type != nil {
+			decl += " : " + v.Type.IEC()
+		}
+		decls = append(decls, decl)
+	}
+	if got := strings.Join(decls, "; "); got != "ADD_10; AND_13; R_TRIG_4 : R_TRIG" {
+		t.Errorf("ToST variables = %s", got)
+	}
+}
+
+// TestFBDToSTOrder checks that execution order IDs are followed and that
+// function block calls move ahead of the statements reading their outputs
+func TestFBDToSTOrder(t *testing.T) {
+	order := func(id uint64) *uint64 { return &id }
+	conn := func(id uint64, param string) *plcopen.ConnectionPointIn {
+		c := plcopen.Connection{RefLocalID: id}
+		if param != "" {
+			c.FormalParameter = &param
+		}
+		return &plcopen.ConnectionPointIn{Connections: []plcopen.Connection{c}}
+	}
+	name := "ctr"
+	fbd := &plcopen.BodyFBD{
+		InVariables: []plcopen.BodyFBDInVariable{{LocalID: 1, Expression: "pulse"}},
+		Blocks: []plcopen.BodyFBDBlock{{
+			LocalID:         2,
+			TypeName:        "CTU",
+			InstanceName:    &name,
+			InputVariables:  []plcopen.BodyFBDBlockVariable{{FormalParameter: "CU", ConnectionPointIn: conn(1, "")}},
+			OutputVariables: []plcopen.BodyFBDBlockVariable1{{FormalParameter: "Q"}, {FormalParameter: "CV"}},
+		}},
+		OutVariables: []plcopen.BodyFBDOutVariable{
+			{LocalID: 3, Expression: "count", ConnectionPointIn: conn(2, "CV"), ExecutionOrderID: order(2)},
+			{LocalID: 4, Expression: "copy", ConnectionPointIn: conn(1, ""), ExecutionOrderID: order(1)},
+			{LocalID: 5, Expression: "lost", ConnectionPointIn: conn(99, "")},
+		},
+		Jumps: []plcopen.BodyFBDJump{{LocalID: 6, Label: "end"}},
+	}
+	body, _, err := fbd.ToST()
+	if got := body.Xhtml.PlainText(); got != "copy := pulse;\nctr(CU := pulse);\ncount := ctr.CV;" {
+		t.Errorf("ToST =\n%s", got)
+	}
+	if err == nil {
+		t.Fatal("ToST reported no errors")
+	}
+	for _, want := range []string{
+		"plcopen: FBD/outVariable[@localId='5']: connection refers to missing localId 99",
+		"plcopen: FBD/jump[@localId='6']: jump cannot be translated to ST",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ToST error = %v, want %s", err, want)
+		}
+	}
+}
@@ -1,23 +1,25 @@
 package tests
 
 import (
+	"bytes"
+	"encoding/json"
 	"encoding/xml"
+	"strings"
 	"testing"
 
-	"github.com/suifei/plcopen-go"
+	"github.com/suifei/plcopen-go/v2"
 )
 
 // TestFormattedText 验证formattedText类型在Go代码中的处理方式
 func TestFormattedText(t *testing.T) {
-	// 在XSD中，formattedText是一个复杂类型，允许混合内容
-	// 在Go中，它通常表示为[]byte类型，可以保存任何XML内容
-	// 创建一个包含formattedText的POU文档
+	// 在XSD中，formattedText是一个复杂类型，内容为xhtml命名空间中的元素
+	// 在Go中，它表示为*plcopen.FormattedText，统一使用xhtml前缀保存标记
 	pou := plcopen.ProjectTypesPOU{
 		Name:    "TestPOU",
 		POUType: "function",
 		Body: &plcopen.Body{
 			ST: &plcopen.BodyST{
-				Xhtml: "// 这是一个带格式的文本\nIF a > b THEN\n  c := a;\nEND_IF;",
+				Xhtml: plcopen.NewFormattedText("// 这是一个带格式的文本\nIF a > b THEN\n  c := a;\nEND_IF;"),
 			},
 		},
 	}
@@ -30,24 +32,40 @@ func TestFormattedText(t *testing.T) {
 				Name: "INT",
 			},
 		},
-		Documentation: []byte("<documentation>这是带<b>格式</b>的<i>文档</i>内容</documentation>"),
+		Documentation: plcopen.MustParseFormattedText("<xhtml:p>这是带<xhtml:b>格式</xhtml:b>的<xhtml:i>文档</xhtml:i>内容</xhtml:p>"),
 	}
 
 	// 确保可以序列化为XML
 	pouXML, err := xml.MarshalIndent(pou, "", "  ")
 	if err != nil {
-		t.Errorf("无法序列化带有formattedText的POU: %v", err)
+		t.Fatalf("无法序列化带有formattedText的POU: %v", err)
+	}
+	if !strings.Contains(string(pouXML), `<ST xmlns:xhtml="http://www.w3.org/1999/xhtml">`) ||
+		!strings.Contains(string(pouXML), "<xhtml:p>// 这是一个带格式的文本") {
+		t.Errorf("ST内容应为带xhtml命名空间声明的段落:\n%s", pouXML)
+	}
+	if !strings.Contains(string(pouXML), "IF a &gt; b THEN\n  c := a;") {
+		t.Errorf("ST文本应保留换行并转义:\n%s", pouXML)
 	}
 
 	dataTypeXML, err := xml.MarshalIndent(dataType, "", "  ")
 	if err != nil {
-		t.Errorf("无法序列化带有formattedText的DataType: %v", err)
+		t.Fatalf("无法序列化带有formattedText的DataType: %v", err)
+	}
+	if !strings.Contains(string(dataTypeXML), "<xhtml:b>格式</xhtml:b>") {
+		t.Errorf("Documentation应保留xhtml标记:\n%s", dataTypeXML)
 	}
 
-	t.Logf("带formattedText的POU序列化为:\n%s", string(pouXML))
-	t.Logf("带formattedText的DataType序列化为:\n%s", string(dataTypeXML))
+	// 往返：序列化后的文本应能被原样解析
+	var roundTrip plcopen.ProjectTypesPOU
+	if err := xml.Unmarshal(pouXML, &roundTrip); err != nil {
+		t.Fatalf("无法解析序列化后的POU: %v", err)
+	}
+	if got, want := roundTrip.Body.ST.Xhtml.PlainText(), pou.Body.ST.Xhtml.PlainText(); got != want {
+		t.Errorf("ST往返不一致: 期望 %q, 实际 %q", want, got)
+	}
 
-	// 解析包含formattedText的XML
+	// 解析旧工具写出的CDATA文本，不带任何xhtml元素
 	xmlString := `
 	<pou name="TestPOU" pouType="function">
 		<body>
@@ -61,18 +79,130 @@ END_IF;]]></ST>
 
 	var parsedPOU plcopen.ProjectTypesPOU
 	if err := xml.Unmarshal([]byte(xmlString), &parsedPOU); err != nil {
-		t.Errorf("无法解析包含formattedText的XML: %v", err)
+		t.Fatalf("无法解析包含formattedText的XML: %v", err)
+	}
+	if parsedPOU.Body == nil || parsedPOU.Body.ST == nil {
+		t.Fatal("解析错误，Body或ST为nil")
+	}
+	wantST := "// 这是XML中的带格式文本\nIF a > b THEN\n  c := a;\nEND_IF;"
+	if got := parsedPOU.Body.ST.Xhtml.PlainText(); got != wantST {
+		t.Errorf("ST纯文本错误: 期望 %q, 实际 %q", wantST, got)
+	}
+	if got := parsedPOU.Documentation.PlainText(); got != "这是<b>带格式</b>的文档" {
+		t.Errorf("CDATA文档应作为纯文本保留，实际 %q", got)
 	}
-	// 验证解析结果
-	if parsedPOU.Name != "TestPOU" {
-		t.Errorf("解析错误，期望name='TestPOU'，实际为'%s'", parsedPOU.Name)
+}
+
+// TestFormattedTextNamespaces 验证不同命名空间写法被规范化为xhtml前缀
+func TestFormattedTextNamespaces(t *testing.T) {
+	inputs := []string{
+		`<documentation><xhtml:p xmlns:xhtml="http://www.w3.org/1999/xhtml">a<xhtml:br/>b</xhtml:p></documentation>`,
+		`<documentation><p xmlns="http://www.w3.org/1999/xhtml">a<br/>b</p></documentation>`,
+		`<documentation xmlns:h="http://www.w3.org/1999/xhtml"><h:p>a<h:br/>b</h:p></documentation>`,
+	}
+	for _, input := range inputs {
+		var ft plcopen.FormattedText
+		if err := xml.Unmarshal([]byte(input), &ft); err != nil {
+			t.Fatalf("解析 %s 失败: %v", input, err)
+		}
+		if got := ft.XHTML(); got != "<xhtml:p>a<xhtml:br/>b</xhtml:p>" {
+			t.Errorf("%s 规范化结果错误: %q", input, got)
+		}
+		if got := ft.PlainText(); got != "a\nb" {
+			t.Errorf("%s 纯文本错误: %q", input, got)
+		}
 	}
+}
 
-	if parsedPOU.Body == nil || parsedPOU.Body.ST == nil {
-		t.Error("解析错误，Body或ST为nil")
-	} else {
-		t.Logf("成功解析formattedText内容: %s", parsedPOU.Body.ST.Xhtml)
+// TestFormattedTextForeignNamespaces 验证非XHTML命名空间的元素和属性保留其命名空间
+func TestFormattedTextForeignNamespaces(t *testing.T) {
+	input := `<documentation xmlns:v="urn:vendor"><xhtml:p xmlns:xhtml="http://www.w3.org/1999/xhtml" xml:lang="de">Hallo <v:mark v:color="red">Welt</v:mark></xhtml:p></documentation>`
+	want := `<xhtml:p xml:lang="de">Hallo <v:mark xmlns:v="urn:vendor" v:color="red">Welt</v:mark></xhtml:p>`
+	var ft plcopen.FormattedText
+	if err := xml.Unmarshal([]byte(input), &ft); err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if got := ft.XHTML(); got != want {
+		t.Errorf("XHTML() = %q, want %q", got, want)
+	}
+	if got := ft.PlainText(); got != "Hallo Welt" {
+		t.Errorf("纯文本错误: %q", got)
+	}
+
+	data, err := xml.Marshal(struct {
+		XMLName xml.Name               `xml:"pou"`
+		Doc     *plcopen.FormattedText `xml:"documentation"`
+	}{Doc: &ft})
+	if err != nil {
+		t.Fatalf("序列化失败: %v", err)
+	}
+	// 重新解析时命名空间必须与原文档一致
+	d := xml.NewDecoder(bytes.NewReader(data))
+	var spaces []string
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			spaces = append(spaces, start.Name.Space+" "+start.Name.Local)
+			for _, attr := range start.Attr {
+				if attr.Name.Space != "xmlns" {
+					spaces = append(spaces, "@"+attr.Name.Space+" "+attr.Name.Local)
+				}
+			}
+		}
+	}
+	wantSpaces := []string{" pou", " documentation", "http://www.w3.org/1999/xhtml p",
+		"@http://www.w3.org/XML/1998/namespace lang", "urn:vendor mark", "@urn:vendor color"}
+	if strings.Join(spaces, "\n") != strings.Join(wantSpaces, "\n") {
+		t.Errorf("序列化后的命名空间 =\n%s\nwant\n%s\n%s", strings.Join(spaces, "\n"), strings.Join(wantSpaces, "\n"), data)
+	}
+
+	var again struct {
+		Doc plcopen.FormattedText `xml:"documentation"`
+	}
+	if err := xml.Unmarshal(data, &again); err != nil {
+		t.Fatalf("重新解析失败: %v", err)
+	}
+	if got := again.Doc.XHTML(); got != want {
+		t.Errorf("往返后 XHTML() = %q, want %q", got, want)
+	}
+	parsed, err := plcopen.ParseFormattedText(want)
+	if err != nil || parsed.XHTML() != want {
+		t.Errorf("ParseFormattedText(%q) = %q, %v", want, parsed.XHTML(), err)
+	}
+}
+
+// TestFormattedTextJSON 验证formattedText的JSON形式
+func TestFormattedTextJSON(t *testing.T) {
+	plain := plcopen.NewFormattedText("x := 1;\ny := 2;")
+	data, err := json.Marshal(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `"x := 1;\ny := 2;"` {
+		t.Errorf("纯文本段落应序列化为JSON字符串，实际 %s", data)
 	}
 
-	t.Log("formattedText类型测试通过，在Go中使用[]byte类型正确处理")
+	rich := plcopen.MustParseFormattedText("<xhtml:p>a <xhtml:b>b</xhtml:b></xhtml:p>")
+	data, err = json.Marshal(rich)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var obj map[string]string
+	if err := json.Unmarshal(data, &obj); err != nil || obj["xhtml"] != "<xhtml:p>a <xhtml:b>b</xhtml:b></xhtml:p>" {
+		t.Errorf("富文本应序列化为xhtml对象，实际 %s", data)
+	}
+
+	for _, ft := range []*plcopen.FormattedText{plain, rich} {
+		data, _ := json.Marshal(ft)
+		var decoded plcopen.FormattedText
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("无法解析 %s: %v", data, err)
+		}
+		if decoded.XHTML() != ft.XHTML() {
+			t.Errorf("JSON往返不一致: 期望 %q, 实际 %q", ft.XHTML(), decoded.XHTML())
+		}
+	}
 }
@@ -0,0 +1,247 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/il"
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+const ilMotor = `(* Motor control *)
+      LD    start
+      OR    running     (* latch *)
+      ANDN  stop
+      ST    running
+      ST    lamp
+
+      LD    count
+      ADD   1
+      MUL   factor
+      ST    count
+      GT    100
+      JMPCN skip
+      LD    0
+      ST    count
+skip: LD    a
+      AND(  b
+      OR    c
+      )
+      ST    d
+      CAL   timer(IN := d, PT := T#5s, Q => done)
+      LD    done
+      R     busy
+      LD    x
+      SHL   2
+      LIMIT 0, 100
+      STN   y
+      LD    ready
+      CALC  fb1
+      LD    err
+      RETC
+`
+
+const stMotor = `(* Motor control *)
+running := (start OR running) AND NOT stop; (* latch *)
+lamp := running;
+
+count := (count + 1) * factor;
+IF count > 100 THEN
+    count := 0;
+END_IF;
+d := a AND (b OR c);
+timer(IN := d, PT := T#5s, Q => done);
+IF done THEN
+    busy := FALSE;
+END_IF;
+y := NOT LIMIT(SHL(x, 2), 0, 100);
+IF ready THEN
+    fb1();
+END_IF;
+IF err THEN
+    RETURN;
+END_IF;
+`
+
+// TestILParse checks instructions with their modifiers and positions
+func TestILParse(t *testing.T) {
+	prog, err := il.Parse(ilMotor)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(prog.Instrs) != 27 || len(prog.Comments) != 2 {
+		t.Fatalf("Parse = %d instructions, %d comments", len(prog.Instrs), len(prog.Comments))
+	}
+	want := map[int]string{
+		2:  "ANDN stop",
+		10: "JMPCN skip",
+		13: "skip: LD a",
+		14: "AND(b OR c)",
+		16: "CAL timer(IN := d, PT := T#5s, Q => done)",
+		21: "LIMIT 0, 100",
+		22: "STN y",
+		24: "CALC fb1",
+		26: "RETC",
+	}
+	for i, s := range want {
+		if got := prog.Instrs[i].String(); got != s {
+			t.Errorf("Instrs[%d] = %q, want %q", i, got, s)
+		}
+	}
+	in := prog.Instrs[10]
+	if in.Op != il.JMP || !in.Cond || !in.Negated || in.Pos().String() != "13:7" {
+		t.Errorf("JMPCN = %+v at %s", in, in.Pos())
+	}
+	if in := prog.Instrs[14]; !in.Deferred || len(in.Nested) != 1 || in.Nested[0].Op != il.OR {
+		t.Errorf("AND( = %+v", in)
+	}
+
+	prog, err = il.Parse("lbl:\n  ld %IX0.1\n  & N\n  &N b[1].c^\n  jmpc lbl\n")
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	var got []string
+	for _, in := range prog.Instrs {
+		got = append(got, in.String())
+	}
+	if s := strings.Join(got, "; "); s != "lbl:; LD %IX0.1; AND N; ANDN b[1].c^; JMPC lbl" {
+		t.Errorf("Parse = %s", s)
+	}
+
+	tests := []struct {
+		src string
+		err string
+	}{
+		{"LD", "1:3: missing operand of LD"},
+		{"LD a b", "1:6: expected end of line, found identifier b"},
+		{"LD a\n:= b", "2:1: expected operator, found ':='"},
+		{"LD a\nJMP 3", "2:5: expected label, found integer 3"},
+		{"AND( a\nOR b", "2:5: expected ')', found end of input"},
+		{"LD a\nST\nST b", "2:3: missing operand of ST"},
+	}
+	for _, tt := range tests {
+		_, err := il.Parse(tt.src)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q) error = %v, want %s", tt.src, err, tt.err)
+		}
+	}
+}
+
+// TestILTranslate checks the ST of straight-line code, jumps and
+// instructions that cannot be translated
+func TestILTranslate(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+		errs []string
+	}{
+		{"motor", ilMotor, stMotor, nil},
+		{
+			"repeat",
+			"LD 0\nST i\nloop: LD i\nADD 1\nST i\nLT 10\nJMPC loop\n",
+			"i := 0;\nREPEAT\n    i := i + 1;\nUNTIL i >= 10\nEND_REPEAT;\n",
+			nil,
+		},
+		{
+			"if else",
+			"LD mode\nEQ 1\nJMPCN other\nLD 100\nST speed\nJMP done\nother: LD 0\nST speed\ndone: LD speed\nST out\n",
+			"IF mode = 1 THEN\n    speed := 100;\nELSE\n    speed := 0;\nEND_IF;\nout := speed;\n",
+			nil,
+		},
+		{
+			"fb input",
+			"LD start\nS1 latch\nLD t#2s\nPT ton1\n",
+			"latch(S1 := start);\nton1(PT := t#2s);\n",
+			nil,
+		},
+		{
+			"undefined label",
+			"LD a\nST b\nJMP nowhere\n",
+			"b := a;\n(* IL: JMP nowhere *)\n",
+			[]string{"3:1: undefined label nowhere"},
+		},
+		{
+			"jump into block",
+			"LD a\nJMPC inner\nLD b\nJMPC skip\ninner: LD c\nST d\nskip: LD e\nST f\n",
+			"(* IL: JMPC inner *)\n",
+			[]string{"2:1: jump to inner is not an IF or REPEAT statement"},
+		},
+		{
+			"stale result",
+			"LD x\nR x\nAND y\nST z\n",
+			"IF x THEN\n    x := FALSE;\nEND_IF;\n(* IL: AND y *)\n",
+			[]string{"3:1: current result depends on a variable changed by R at 2:1", "4:1: current result depends on a variable changed by R at 2:1"},
+		},
+		{
+			"unused function result",
+			"LD a\nST b\nFOO x\n",
+			"b := a;\n(* IL: FOO x *)\n",
+			[]string{"3:1: result of FOO is never used"},
+		},
+		{
+			"overwritten function result",
+			"LD a\nFOO x\nLD c\nBAR a, b\nST e\n",
+			"(* IL: FOO x *)\ne := BAR(c, a, b);\n",
+			[]string{"2:1: result of FOO is never used"},
+		},
+		{
+			"function result as condition",
+			"LD a\nFOO x\nCALC fb1\n",
+			"IF FOO(a, x) THEN\n    fb1();\nEND_IF;\n",
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		prog, err := il.Parse(tt.src)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tt.name, err)
+			continue
+		}
+		body, err := il.Translate(prog)
+		var errs st.Errors
+		if err != nil {
+			errs = err.(st.Errors)
+		}
+		if len(errs) != len(tt.errs) {
+			t.Errorf("%s: Translate error = %v, want %v", tt.name, err, tt.errs)
+		}
+		for i := range tt.errs {
+			if i < len(errs) && !strings.HasPrefix(errs[i].Error(), tt.errs[i]) {
+				t.Errorf("%s: error %d = %v, want %s", tt.name, i, errs[i], tt.errs[i])
+			}
+		}
+		var sb strings.Builder
+		if err := st.Fprint(&sb, body); err != nil {
+			t.Fatalf("Fprint: %v", err)
+		}
+		if got := sb.String(); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("%s: Translate =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+		if _, err := st.Parse(sb.String()); err != nil {
+			t.Errorf("%s: translated ST does not parse: %v", tt.name, err)
+		}
+	}
+}
+
+// TestBodyILToST converts the IL body of a POU
+func TestBodyILToST(t *testing.T) {
+	body := &plcopen.BodyIL{Xhtml: plcopen.NewFormattedText(ilMotor)}
+	got, err := body.ToST()
+	if err != nil {
+		t.Fatalf("ToST: %v", err)
+	}
+	if text := got.Xhtml.PlainText(); text != strings.TrimSuffix(stMotor, "\n") {
+		t.Errorf("ToST =\n%s", text)
+	}
+
+	body = &plcopen.BodyIL{Xhtml: plcopen.NewFormattedText("LD a\nJMP x\nST b")}
+	got, err = body.ToST()
+	if err == nil || got == nil || !strings.Contains(got.Xhtml.PlainText(), "(* IL: JMP x *)") {
+		t.Errorf("ToST = %v, %v", got, err)
+	}
+	if got, err := (&plcopen.BodyIL{Xhtml: plcopen.NewFormattedText("LD")}).ToST(); got != nil || err == nil {
+		t.Errorf("ToST of invalid IL = %v, %v", got, err)
+	}
+}
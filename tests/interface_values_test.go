@@ -2,13 +2,15 @@
 package tests
 
 import (
+	"encoding/json"
 	"encoding/xml"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
-	"github.com/suifei/plcopen-go"
+	"github.com/suifei/plcopen-go/v2"
 )
 
 // TestInterfaceAndValues tests all variable interfaces and value types in the PLCopen schema
@@ -84,7 +86,7 @@ func TestInterfaceAndValues(t *testing.T) {
 									InitialValue: &plcopen.Value{SimpleValue: &plcopen.ValueSimpleValue{Value: "10"}},
 								},
 							},
-							Documentation: []byte("<documentation>Input variables</documentation>"),
+							Documentation: plcopen.NewFormattedText("Input variables"),
 						},
 
 						// Output variables
@@ -178,12 +180,12 @@ func TestInterfaceAndValues(t *testing.T) {
 						},
 
 						// Documentation
-						Documentation: []byte("<documentation>Function interface documentation</documentation>"),
+						Documentation: plcopen.NewFormattedText("Function interface documentation"),
 					},
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>// Function body</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>// Function body</xhtml:p>"),
 						},
 					},
 				},
@@ -224,7 +226,7 @@ func TestInterfaceAndValues(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>// Function block body</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>// Function block body</xhtml:p>"),
 						},
 					},
 				},
@@ -283,7 +285,7 @@ func TestInterfaceAndValues(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>// Program body</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>// Program body</xhtml:p>"),
 						},
 					},
 				},
@@ -503,3 +505,72 @@ func TestInterfaceAndValues(t *testing.T) {
 		t.Logf("Interface XML written to: %s", interfaceXMLFile)
 	}
 }
+
+// TestVarListQualifiers tests the name and memory qualifier attributes of varList
+func TestVarListQualifiers(t *testing.T) {
+	input := `<localVars name="Counters" retain="true" constant="false" persistent="true">
+  <variable name="count"><type><DINT/></type></variable>
+</localVars>`
+
+	var vars plcopen.ProjectTypesPOUInterfaceLocalVars
+	if err := xml.Unmarshal([]byte(input), &vars); err != nil {
+		t.Fatalf("Failed to unmarshal varList: %v", err)
+	}
+	if vars.Name != "Counters" {
+		t.Errorf("Name = %q, want Counters", vars.Name)
+	}
+	qualifiers := vars.Qualifiers()
+	if len(qualifiers) != 2 || qualifiers[0] != plcopen.VarListQualifierRetain || qualifiers[1] != plcopen.VarListQualifierPersistent {
+		t.Errorf("Qualifiers() = %v, want [RETAIN PERSISTENT]", qualifiers)
+	}
+	if vars.HasQualifier(plcopen.VarListQualifierConstant) {
+		t.Errorf("HasQualifier(CONSTANT) = true for constant=\"false\"")
+	}
+
+	// XML round trip keeps the explicit attributes
+	data, err := xml.Marshal(&vars)
+	if err != nil {
+		t.Fatalf("Failed to marshal varList: %v", err)
+	}
+	xmlStr := string(data)
+	for _, attr := range []string{`name="Counters"`, `retain="true"`, `constant="false"`, `persistent="true"`} {
+		if !strings.Contains(xmlStr, attr) {
+			t.Errorf("Marshaled varList missing %s: %s", attr, xmlStr)
+		}
+	}
+
+	// JSON round trip keeps the qualifiers
+	jsonData, err := json.Marshal(&vars)
+	if err != nil {
+		t.Fatalf("Failed to marshal varList to JSON: %v", err)
+	}
+	var fromJSON plcopen.VarList
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatalf("Failed to unmarshal varList from JSON: %v", err)
+	}
+	if fromJSON.Name != "Counters" || !fromJSON.HasQualifier(plcopen.VarListQualifierRetain) || !fromJSON.HasQualifier(plcopen.VarListQualifierPersistent) {
+		t.Errorf("JSON round trip lost qualifiers: %s", string(jsonData))
+	}
+
+	// SetQualifiers replaces previous qualifiers
+	vars.SetQualifiers(plcopen.VarListQualifierConstant)
+	if got := vars.Qualifiers(); len(got) != 1 || got[0] != plcopen.VarListQualifierConstant {
+		t.Errorf("Qualifiers() after SetQualifiers = %v, want [CONSTANT]", got)
+	}
+	if vars.Retain != nil || vars.Persistent != nil {
+		t.Errorf("SetQualifiers did not clear previous qualifiers")
+	}
+
+	// JSON member names are camel case like the others, XML attributes stay lower case
+	vars.SetQualifiers(plcopen.VarListQualifierNonRetain)
+	jsonData, err = json.Marshal(&vars)
+	if err != nil {
+		t.Fatalf("Failed to marshal varList to JSON: %v", err)
+	}
+	if !strings.Contains(string(jsonData), `"nonRetain":true`) {
+		t.Errorf("JSON varList = %s, want nonRetain member", jsonData)
+	}
+	if data, err = xml.Marshal(&vars); err != nil || !strings.Contains(string(data), `nonretain="true"`) {
+		t.Errorf("XML varList = %s, %v, want nonretain attribute", data, err)
+	}
+}
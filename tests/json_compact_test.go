@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/utils"
+)
+
+// TestDataTypeJSON checks the compact JSON form of data types
+func TestDataTypeJSON(t *testing.T) {
+	length := uint64(20)
+	tests := []struct {
+		name string
+		typ  plcopen.DataType
+		want string
+	}{
+		{"elementary", plcopen.DataType{INT: &struct{}{}}, `"INT"`},
+		{"string", plcopen.DataType{String: &plcopen.DataTypeString{}}, `"STRING"`},
+		{"string with length", plcopen.DataType{String: &plcopen.DataTypeString{Length: &length}}, `{"kind":"string","length":20}`},
+		{"derived", plcopen.DataType{Derived: &plcopen.DataTypeDerived{Name: "Motor"}}, `{"kind":"derived","name":"Motor"}`},
+		{"array", plcopen.DataType{Array: &plcopen.DataTypeArray{
+			Dimensions: []plcopen.RangeSigned{{Lower: 1, Upper: 10}},
+			BaseType:   &plcopen.DataType{REAL: &struct{}{}},
+		}}, `{"kind":"array","dimensions":[{"lower":1,"upper":10}],"baseType":"REAL"}`},
+		{"enum", plcopen.DataType{Enum: &plcopen.DataTypeEnum{
+			Values: &plcopen.DataTypeEnumValues{Values: []plcopen.DataTypeEnumValuesValue{{Name: "Red"}, {Name: "Green"}}},
+		}}, `{"kind":"enum","values":["Red","Green"]}`},
+		{"struct", plcopen.DataType{Struct: &plcopen.VarListPlain{
+			Variables: []plcopen.VarListPlainVariable{{Name: "x", Type: &plcopen.DataType{BOOL: &struct{}{}}}},
+		}}, `{"kind":"struct","variables":[{"name":"x","type":"BOOL"}]}`},
+		{"empty", plcopen.DataType{}, `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.typ)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("Marshal = %s, want %s", data, tt.want)
+			}
+			var got plcopen.DataType
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.typ) {
+				t.Errorf("Unmarshal(%s) = %+v, want %+v", data, got, tt.typ)
+			}
+		})
+	}
+
+	legacy := map[string]func(plcopen.DataType) bool{
+		`{"iNT":{}}`:                   func(d plcopen.DataType) bool { return d.INT != nil },
+		`"lreal"`:                      func(d plcopen.DataType) bool { return d.LREAL != nil },
+		`{"derived":{"name":"Motor"}}`: func(d plcopen.DataType) bool { return d.Derived != nil && d.Derived.Name == "Motor" },
+		`{"enum":{"values":{"values":[{"name":"A"}]}}}`: func(d plcopen.DataType) bool {
+			return d.Enum != nil && d.Enum.Values.Values[0].Name == "A"
+		},
+	}
+	for doc, ok := range legacy {
+		var got plcopen.DataType
+		if err := json.Unmarshal([]byte(doc), &got); err != nil || !ok(got) {
+			t.Errorf("Unmarshal(%s) = %+v, %v", doc, got, err)
+		}
+	}
+	for _, doc := range []string{`"INTEGER"`, `{"kind":"list"}`} {
+		var got plcopen.DataType
+		if err := json.Unmarshal([]byte(doc), &got); err == nil {
+			t.Errorf("Unmarshal(%s) succeeded", doc)
+		}
+	}
+}
+
+// TestValueJSON checks the natural JSON form of values
+func TestValueJSON(t *testing.T) {
+	simple := func(s string) *plcopen.Value {
+		return &plcopen.Value{SimpleValue: &plcopen.ValueSimpleValue{Value: s}}
+	}
+	three := uint64(3)
+	tests := []struct {
+		name  string
+		value *plcopen.Value
+		want  string
+	}{
+		{"integer", simple("42"), `42`},
+		{"real", simple("-1.50"), `-1.50`},
+		{"boolean", simple("TRUE"), `true`},
+		{"time", simple("T#5s"), `"T#5s"`},
+		{"hex", simple("16#FF"), `"16#FF"`},
+		{"array", &plcopen.Value{ArrayValue: &plcopen.ValueArrayValue{Values: []plcopen.ValueArrayValueValue{
+			{Value: simple("1")},
+			{RepeatCount: &three, Value: simple("0")},
+		}}}, `[1,{"$repeat":3,"$value":0}]`},
+		{"struct", &plcopen.Value{StructValue: &plcopen.ValueStructValue{Values: []plcopen.ValueStructValueValue{
+			{Member: "z", Value: simple("FALSE")},
+			{Member: "a", Value: &plcopen.Value{ArrayValue: &plcopen.ValueArrayValue{Values: []plcopen.ValueArrayValueValue{{Value: simple("'x<y'")}}}}},
+		}}}, `{"z":false,"a":["'x<y'"]}`},
+		// Member names that collide with markers or the earlier form are written in $struct
+		{"struct like legacy", &plcopen.Value{StructValue: &plcopen.ValueStructValue{Values: []plcopen.ValueStructValueValue{
+			{Member: "structValue", Value: &plcopen.Value{StructValue: &plcopen.ValueStructValue{Values: []plcopen.ValueStructValueValue{
+				{Member: "value", Value: simple("1")},
+			}}}},
+		}}}, `{"$struct":{"structValue":{"value":1}}}`},
+		{"struct like repetition", &plcopen.Value{ArrayValue: &plcopen.ValueArrayValue{Values: []plcopen.ValueArrayValueValue{
+			{Value: &plcopen.Value{StructValue: &plcopen.ValueStructValue{Values: []plcopen.ValueStructValueValue{
+				{Member: "$repeat", Value: simple("2")},
+				{Member: "$value", Value: simple("0")},
+			}}}},
+		}}}, `[{"$struct":{"$repeat":2,"$value":0}}]`},
+		{"struct with $struct member", &plcopen.Value{StructValue: &plcopen.ValueStructValue{Values: []plcopen.ValueStructValueValue{
+			{Member: "$struct", Value: simple("5")},
+		}}}, `{"$struct":{"$struct":5}}`},
+		{"repeated struct", &plcopen.Value{ArrayValue: &plcopen.ValueArrayValue{Values: []plcopen.ValueArrayValueValue{
+			{RepeatCount: &three, Value: &plcopen.Value{StructValue: &plcopen.ValueStructValue{Values: []plcopen.ValueStructValueValue{
+				{Member: "arrayValue", Value: &plcopen.Value{ArrayValue: &plcopen.ValueArrayValue{}}},
+			}}}},
+		}}}, `[{"$repeat":3,"$value":{"$struct":{"arrayValue":[]}}}]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := utils.ToJSON(tt.value)
+			if err != nil {
+				t.Fatalf("ToJSON: %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("ToJSON = %s, want %s", data, tt.want)
+			}
+			got, err := utils.FromJSON[*plcopen.Value](data)
+			if err != nil {
+				t.Fatalf("FromJSON: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.value) {
+				t.Errorf("FromJSON(%s) = %s, want %s", data, got, tt.value)
+			}
+		})
+	}
+
+	var legacy plcopen.Value
+	if err := json.Unmarshal([]byte(`{"simpleValue":{"value":"0"}}`), &legacy); err != nil || legacy.SimpleValue == nil || legacy.SimpleValue.Value != "0" {
+		t.Errorf("Legacy simple value = %+v, %v", legacy, err)
+	}
+	for _, data := range []string{
+		`[{"$repeat":"x","$value":0}]`,
+		`[{"$repeat":2}]`,
+		`[{"$repeat":2,"$value":0,"a":1}]`,
+		`{"$repeat":2,"$value":0}`,
+		`{"$struct":1}`,
+		`{"$other":1}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &legacy); err == nil {
+			t.Errorf("Invalid markers in %s were accepted", data)
+		}
+	}
+}
+
+// TestProjectJSONReadable checks that utils.ToJSONIndent writes project JSON
+// that reads like the program, and that it converts back to the same XML
+func TestProjectJSONReadable(t *testing.T) {
+	project := createComprehensiveProject()
+	project.Types.POUs[0].Body = &plcopen.Body{ST: &plcopen.BodyST{
+		XMLNSXhtml: "http://www.w3.org/1999/xhtml",
+		Xhtml:      plcopen.NewFormattedText("IF a < b AND c > 0 THEN x := 1; END_IF;"),
+	}}
+	data, err := utils.ToJSONIndent(project)
+	if err != nil {
+		t.Fatalf("ToJSONIndent: %v", err)
+	}
+	text := string(data)
+	for _, want := range []string{`"IF a < b AND c > 0 THEN x := 1; END_IF;"`, `"type": "BOOL"`} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Project JSON does not contain %s", want)
+		}
+	}
+	if strings.Contains(text, `\u003c`) || strings.Contains(text, `"simpleValue"`) {
+		t.Errorf("Project JSON is not compact:\n%s", text)
+	}
+
+	decoded, err := utils.FromJSON[plcopen.Project](data)
+	if err != nil {
+		t.Fatalf("FromJSON: %v", err)
+	}
+	var want, got bytes.Buffer
+	if err := project.Save(&want); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Save(&got); err != nil {
+		t.Fatal(err)
+	}
+	if want.String() != got.String() {
+		t.Errorf("XML after JSON round trip differs:\n%s\nwant:\n%s", got.String(), want.String())
+	}
+}
@@ -0,0 +1,293 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+var update = flag.Bool("update", false, "update generated files in docs")
+
+// TestJSONSchemaDocument checks that docs/plcopen.schema.json is up to date,
+// run go test ./tests -run JSONSchemaDocument -update to regenerate it
+func TestJSONSchemaDocument(t *testing.T) {
+	schema, err := plcopen.JSONSchema()
+	if err != nil {
+		t.Fatalf("JSONSchema: %v", err)
+	}
+	schema = append(schema, '\n')
+	file := filepath.Join("..", "docs", "plcopen.schema.json")
+	if *update {
+		if err := os.WriteFile(file, schema, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	published, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(published, schema) {
+		t.Errorf("%s is out of date, regenerate it with -update", file)
+	}
+}
+
+// TestJSONSchemaValidates checks JSON written by the package against the schema
+func TestJSONSchemaValidates(t *testing.T) {
+	data, err := plcopen.JSONSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var schema map[string]interface{}
+	if err := json.Unmarshal(data, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := createComprehensiveProject().Save(&buf, plcopen.WithFormat(plcopen.FormatJSON)); err != nil {
+		t.Fatal(err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range checkJSONSchema(schema, schema, doc, "") {
+		t.Errorf("Schema violation: %s", e)
+	}
+
+	var legacy interface{}
+	if err := json.Unmarshal([]byte(legacyJSON), &legacy); err != nil {
+		t.Fatal(err)
+	}
+	if errs := checkJSONSchema(schema, schema, legacy, ""); len(errs) == 0 {
+		t.Errorf("Document with old member names is valid against the schema")
+	}
+}
+
+// legacyJSON uses the member names written before lowerCamelCase names
+const legacyJSON = `{
+  "fileHeader": {"companyName": "c", "productName": "p", "productVersion": "1", "creationDateTime": "2025-01-01T00:00:00Z"},
+  "contentHeader": {"name": "n", "coordinateInfo": {"fBD": {"scaling": {"x": 1, "y": 1}}, "lD": {"scaling": {"x": 1, "y": 1}}, "sFC": {"scaling": {"x": 1, "y": 1}}}},
+  "types": {
+    "dataTypes": [{"name": "W", "baseType": {"wString": {"length": 10}}}],
+    "pOUs": [
+      {
+        "name": "Main",
+        "pOUType": "program",
+        "interface": {"localVars": {"variables": [{"name": "x", "type": {"bOOL": {}}}]}},
+        "body": {"fBD": {"blocks": [{"localID": 2, "typeName": "AND", "executionOrderID": 1,
+          "inputVariables": [{"formalParameter": "IN1", "connectionPointIn": {"connections": [{"refLocalID": 1}]}}]}]}}
+      },
+      {"name": "F", "pOUType": "function", "documentation": "SGVsbG8=",
+        "body": {"sT": {"xMLNSXhtml": "http://www.w3.org/1999/xhtml", "xhtml": "<xhtml:p xmlns:xhtml=\"http://www.w3.org/1999/xhtml\">F := 1;</xhtml:p>"}}}
+    ]
+  },
+  "instances": {"configurations": [{"name": "C", "resources": [{"name": "R", "pOUInstances": [{"name": "I", "typeName": "Main"}]}]}]}
+}`
+
+// TestJSONLegacyNames checks that JSON with the old member names still decodes
+func TestJSONLegacyNames(t *testing.T) {
+	p, err := plcopen.Load(strings.NewReader(legacyJSON))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	main := p.Types.POUs[0]
+	block := main.Body.FBD.Blocks[0]
+	checks := []struct {
+		name string
+		ok   bool
+	}{
+		{"fBD", p.ContentHeader.CoordinateInfo.FBD != nil && p.ContentHeader.CoordinateInfo.LD != nil && p.ContentHeader.CoordinateInfo.SFC != nil},
+		{"wString", p.Types.DataTypes[0].BaseType.WString != nil},
+		{"pOUs", len(p.Types.POUs) == 2},
+		{"pOUType", main.POUType == plcopen.POUTypeProgram},
+		{"bOOL", main.Interface.LocalVars.Variables[0].Type.BOOL != nil},
+		{"localID", block.LocalID == 2},
+		{"executionOrderID", block.ExecutionOrderID != nil && *block.ExecutionOrderID == 1},
+		{"refLocalID", block.InputVariables[0].ConnectionPointIn.Connections[0].RefLocalID == 1},
+		{"sT", p.Types.POUs[1].Body.ST != nil && p.Types.POUs[1].Body.ST.XMLNSXhtml == "http://www.w3.org/1999/xhtml"},
+		{"pOUInstances", len(p.Instances.Configurations[0].Resources[0].POUInstances) == 1},
+		{"xhtml", p.Types.POUs[1].Body.ST.Xhtml.XHTML() == "<xhtml:p>F := 1;</xhtml:p>"},
+		{"documentation", p.Types.POUs[1].Documentation.XHTML() == "<xhtml:p>Hello</xhtml:p>"},
+	}
+	for _, c := range checks {
+		if !c.ok {
+			t.Errorf("Member %q was not decoded", c.name)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := p.Save(&buf, plcopen.WithFormat(plcopen.FormatJSON)); err != nil {
+		t.Fatal(err)
+	}
+	for _, old := range []string{`"fBD"`, `"pOUs"`, `"pOUType"`, `"bOOL"`, `"localID"`, `"refLocalID"`, `"xMLNSXhtml"`, `"wString"`} {
+		if strings.Contains(buf.String(), old) {
+			t.Errorf("Saved JSON contains old member name %s", old)
+		}
+	}
+}
+
+// baselineJSON is a project as encoding/json wrote it before FormattedText,
+// with the ST body as a markup string and documentation as base64
+const baselineJSON = `{"fileHeader":{"companyName":"c","productName":"p","productVersion":"1","creationDateTime":"2025-01-01T00:00:00Z"},"contentHeader":{"name":"n","coordinateInfo":{"fBD":{"scaling":{"x":1,"y":1}},"lD":{"scaling":{"x":1,"y":1}},"sFC":{"scaling":{"x":1,"y":1}}}},"types":{"pOUs":[{"name":"F","pOUType":"function","body":{"sT":{"xMLNSXhtml":"","xhtml":"\u003cxhtml:p xmlns:xhtml=\"http://www.w3.org/1999/xhtml\"\u003eF := 1;\u003c/xhtml:p\u003e"}},"documentation":"SGVsbG8="}]},"instances":{}}`
+
+// TestJSONLegacyValues checks that the value shapes of JSON written before
+// FormattedText decode to the same text, both with Load and json.Unmarshal
+func TestJSONLegacyValues(t *testing.T) {
+	loaded, err := plcopen.Load(strings.NewReader(baselineJSON))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var unmarshaled plcopen.Project
+	if err := json.Unmarshal([]byte(baselineJSON), &unmarshaled); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	for _, p := range []*plcopen.Project{loaded, &unmarshaled} {
+		pou := p.Types.POUs[0]
+		if got := pou.Body.ST.Xhtml.XHTML(); got != "<xhtml:p>F := 1;</xhtml:p>" {
+			t.Errorf("ST body = %q", got)
+		}
+		if got := pou.Documentation.PlainText(); got != "Hello" {
+			t.Errorf("Documentation = %q, want %q", got, "Hello")
+		}
+
+		var buf bytes.Buffer
+		if err := p.Save(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(buf.String(), "&lt;xhtml:p&gt;") || !strings.Contains(buf.String(), "<xhtml:p>F := 1;</xhtml:p>") {
+			t.Errorf("ST body markup is not written as elements:\n%s", buf.String())
+		}
+	}
+
+	// Current JSON is not taken as legacy, documentation that happens to be
+	// valid base64 stays as it is
+	current := strings.Replace(baselineJSON, `"pOUs"`, `"pous"`, 1)
+	current = strings.NewReplacer(`"fBD"`, `"fbd"`, `"lD"`, `"ld"`, `"sFC"`, `"sfc"`, `"pOUType"`, `"pouType"`, `"sT"`, `"st"`, `"xMLNSXhtml"`, `"xmlnsXhtml"`).Replace(current)
+	p, err := plcopen.Load(strings.NewReader(current))
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := p.Types.POUs[0].Documentation.PlainText(); got != "SGVsbG8=" {
+		t.Errorf("Documentation of current JSON = %q, want it unchanged", got)
+	}
+}
+
+// TestFormattedTextJSONMarkupLikeText checks that plain text starting like
+// markup survives a JSON round trip
+func TestFormattedTextJSONMarkupLikeText(t *testing.T) {
+	text := plcopen.NewFormattedText("<xhtml:p> is a paragraph")
+	data, err := json.Marshal(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded plcopen.FormattedText
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.XHTML() != text.XHTML() {
+		t.Errorf("Round trip of %s = %q, want %q", data, decoded.XHTML(), text.XHTML())
+	}
+}
+
+// checkJSONSchema validates v against the subset of JSON Schema written by
+// plcopen.JSONSchema and returns the violations
+func checkJSONSchema(root, s map[string]interface{}, v interface{}, path string) []string {
+	if ref, ok := s["$ref"].(string); ok {
+		def := root["$defs"].(map[string]interface{})[strings.TrimPrefix(ref, "#/$defs/")]
+		return checkJSONSchema(root, def.(map[string]interface{}), v, path)
+	}
+	for _, key := range []string{"anyOf", "oneOf"} {
+		if alternatives, ok := s[key].([]interface{}); ok {
+			matches := 0
+			for _, alt := range alternatives {
+				if len(checkJSONSchema(root, alt.(map[string]interface{}), v, path)) == 0 {
+					matches++
+				}
+			}
+			if matches == 0 || (key == "oneOf" && matches > 1) {
+				return []string{fmt.Sprintf("%s: %d alternatives of %s match", path, matches, key)}
+			}
+		}
+	}
+	if c, ok := s["const"]; ok && c != v {
+		return []string{fmt.Sprintf("%s: %v is not %v", path, v, c)}
+	}
+	if values, ok := s["enum"].([]interface{}); ok {
+		found := false
+		for _, e := range values {
+			found = found || e == v
+		}
+		if !found {
+			return []string{fmt.Sprintf("%s: %v is not one of %v", path, v, values)}
+		}
+	}
+
+	typ, _ := s["type"].(string)
+	var errs []string
+	switch typ {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: want object, got %T", path, v)}
+		}
+		properties, _ := s["properties"].(map[string]interface{})
+		required, _ := s["required"].([]interface{})
+		for _, name := range required {
+			if _, ok := obj[name.(string)]; !ok {
+				errs = append(errs, fmt.Sprintf("%s: missing member %q", path, name))
+			}
+		}
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := properties[name]
+			if !ok {
+				prop, ok = s["additionalProperties"].(map[string]interface{})
+			}
+			if !ok {
+				errs = append(errs, fmt.Sprintf("%s: member %q is not allowed", path, name))
+				continue
+			}
+			errs = append(errs, checkJSONSchema(root, prop.(map[string]interface{}), obj[name], path+"/"+name)...)
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: want array, got %T", path, v)}
+		}
+		for i, item := range arr {
+			errs = append(errs, checkJSONSchema(root, s["items"].(map[string]interface{}), item, fmt.Sprintf("%s/%d", path, i))...)
+		}
+	case "string":
+		if _, ok := v.(string); !ok {
+			errs = append(errs, fmt.Sprintf("%s: want string, got %T", path, v))
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			errs = append(errs, fmt.Sprintf("%s: want boolean, got %T", path, v))
+		}
+	case "integer", "number":
+		n, ok := v.(float64)
+		if !ok || (typ == "integer" && n != float64(int64(n))) {
+			errs = append(errs, fmt.Sprintf("%s: want %s, got %v", path, typ, v))
+		} else if min, ok := s["minimum"].(float64); ok && n < min {
+			errs = append(errs, fmt.Sprintf("%s: %v is less than %v", path, n, min))
+		}
+	case "null":
+		if v != nil {
+			errs = append(errs, fmt.Sprintf("%s: want null", path))
+		}
+	}
+	return errs
+}
@@ -6,7 +6,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/suifei/plcopen-go"
+	"github.com/suifei/plcopen-go/v2"
 )
 
 // TestJSONSerialization tests JSON marshaling and unmarshaling
@@ -48,7 +48,7 @@ func TestJSONSerialization(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>TestFunction := TRUE;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>TestFunction := TRUE;</xhtml:p>"),
 						},
 					},
 				},
@@ -71,7 +71,7 @@ func TestJSONSerialization(t *testing.T) {
 		`"productName": "JSON Test Product"`,
 		`"name": "JSONTestProject"`,
 		`"organization": "PLCopen-Go"`,
-		`"pOUType": "function"`,
+		`"pouType": "function"`,
 	}
 	for _, field := range expectedFields {
 		if !jsonContainsString(jsonStr, field) {
@@ -179,7 +179,7 @@ func TestJSONXMLCompatibility(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>counter := counter + 1;<xhtml:br/>flag := counter > 10;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>counter := counter + 1;<xhtml:br/>flag := counter > 10;</xhtml:p>"),
 						},
 					},
 				},
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// The coils are listed before the contacts, the rungs are ordered by the
+// traversal from the left power rail
+const ldRungs = `<LD>
+  <leftPowerRail localId="1"><position x="0" y="0"/><connectionPointOut/></leftPowerRail>
+  <coil localId="10" storageModifier="reset"><position x="100" y="100"/><connectionPointIn><connection refLocalId="9"/></connectionPointIn><connectionPointOut/><variable>alarm</variable></coil>
+  <coil localId="11" negated="true"><position x="100" y="120"/><connectionPointIn><connection refLocalId="9"/></connectionPointIn><connectionPointOut/><variable>ready</variable></coil>
+  <coil localId="12" edgeModifier="falling"><position x="100" y="140"/><connectionPointIn><connection refLocalId="9"/></connectionPointIn><connectionPointOut/><variable>done</variable></coil>
+  <coil localId="5"><position x="100" y="20"/><connectionPointIn><connection refLocalId="4"/></connectionPointIn><connectionPointOut/><variable>running</variable></coil>
+  <coil localId="6"><position x="140" y="20"/><connectionPointIn><connection refLocalId="5"/></connectionPointIn><connectionPointOut/><variable>lamp</variable></coil>
+  <coil localId="8" storageModifier="set"><position x="100" y="60"/><connectionPointIn><connection refLocalId="7"/></connectionPointIn><connectionPointOut/><variable>alarm</variable></coil>
+  <contact localId="2"><position x="20" y="20"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>start</variable></contact>
+  <contact localId="3"><position x="20" y="40"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>running</variable></contact>
+  <contact localId="4" negated="true"><position x="60" y="20"/><connectionPointIn><connection refLocalId="2"/><connection refLocalId="3"/></connectionPointIn><connectionPointOut/><variable>stop</variable></contact>
+  <contact localId="7" edgeModifier="rising"><position x="20" y="60"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>button</variable></contact>
+  <contact localId="9"><position x="20" y="100"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>reset</variable></contact>
+  <comment localId="30" height="20" width="100"><position x="0" y="200"/><content>Motor latch</content></comment>
+  <rightPowerRail localId="20"><position x="200" y="0"/><connectionPointIn><connection refLocalId="6"/><connection refLocalId="8"/><connection refLocalId="10"/></connectionPointIn></rightPowerRail>
+</LD>`
+
+// TestLDToST translates rungs with series and parallel contacts, edges and
+// set, reset and negated coils
+func TestLDToST(t *testing.T) {
+	var ld plcopen.BodyLD
+	if err := xml.Unmarshal([]byte(ldRungs), &ld); err != nil {
+		t.Fatalf("Failed to unmarshal LD: %v", err)
+	}
+	body, vars, err := ld.ToST()
+	if err != nil {
+		t.Fatalf("ToST: %v", err)
+	}
+	want := `(* Motor latch *)
+running := (start OR running) AND NOT stop;
+lamp := running;
+R_TRIG_7(CLK := button);
+IF R_TRIG_7.Q THEN
+    alarm := TRUE;
+END_IF;
+IF reset THEN
+    alarm := FALSE;
+END_IF;
+ready := NOT reset;
+F_TRIG_12(CLK := reset);
+done := F_TRIG_12.Q;`
+	if got := body.Xhtml.PlainText(); got != want {
+		t.Errorf("ToST =\n%s\nwant\n%s", got, want)
+	}
+	var decls []string
+	for _, v := range vars {
+		decls = append(decls, v.Name+" : "+v.Type.IEC())
+	}
+	if got := strings.Join(decls, "; "); got != "F_TRIG_12 : F_TRIG; R_TRIG_7 : R_TRIG" {
+		t.Errorf("ToST variables = %s", got)
+	}
+}
+
+// TestLDToSTBlocks checks blocks in rungs and unconnected objects
+func TestLDToSTBlocks(t *testing.T) {
+	conn := func(ids ...uint64) *plcopen.ConnectionPointIn {
+		in := &plcopen.ConnectionPointIn{}
+		for _, id := range ids {
+			in.Connections = append(in.Connections, plcopen.Connection{RefLocalID: id})
+		}
+		return in
+	}
+	timer := "t1"
+	q := "Q"
+	ld := &plcopen.BodyLD{
+		LeftPowerRails: []plcopen.BodyLDLeftPowerRail{{LocalID: 1}},
+		Contacts: []plcopen.BodyLDContact{
+			{LocalID: 2, Variable: "a", ConnectionPointIn: conn(1)},
+			{LocalID: 3, Variable: "b", ConnectionPointIn: conn(2)},
+			{LocalID: 4, Variable: "c"},
+		},
+		InVariables: []plcopen.BodyFBDInVariable{{LocalID: 5, Expression: "T#2s"}},
+		Blocks: []plcopen.BodyFBDBlock{{
+			LocalID:      6,
+			TypeName:     "TON",
+			InstanceName: &timer,
+			InputVariables: []plcopen.BodyFBDBlockVariable{
+				{FormalParameter: "IN", ConnectionPointIn: conn(3)},
+				{FormalParameter: "PT", ConnectionPointIn: conn(5)},
+			},
+			OutputVariables: []plcopen.BodyFBDBlockVariable1{{FormalParameter: "Q"}, {FormalParameter: "ET"}},
+		}},
+		Coils: []plcopen.BodyLDCoil{
+			{LocalID: 7, Variable: "out", ConnectionPointIn: &plcopen.ConnectionPointIn{Connections: []plcopen.Connection{{RefLocalID: 6, FormalParameter: &q}}}},
+			{LocalID: 8, Variable: "other", ConnectionPointIn: conn(4)},
+			{LocalID: 9, Variable: "always", ConnectionPointIn: conn(1)},
+		},
+	}
+	body, _, err := ld.ToST()
+	if got := body.Xhtml.PlainText(); got != "t1(IN := a AND b, PT := T#2s);\nout := t1.Q;\nalways := TRUE;" {
+		t.Errorf("ToST =\n%s", got)
+	}
+	if err == nil {
+		t.Fatal("ToST reported no errors")
+	}
+	for _, want := range []string{
+		"plcopen: LD/contact[@localId='4']: contact c is not connected",
+		"plcopen: LD/coil[@localId='8']: coil other is not connected",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("ToST error = %v, want %s", err, want)
+		}
+	}
+}
+
+// TestLDToSTSharedPowerFlow checks that a contact used by several objects
+// is read before a coil on the same rung overwrites its variable
+func TestLDToSTSharedPowerFlow(t *testing.T) {
+	const rung = `<LD>
+  <leftPowerRail localId="1"><position x="0" y="0"/><connectionPointOut/></leftPowerRail>
+  <contact localId="2"><position x="20" y="20"/><connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>A</variable></contact>
+  <coil localId="3" storageModifier="reset"><position x="100" y="20"/><connectionPointIn><connection refLocalId="2"/></connectionPointIn><connectionPointOut/><variable>A</variable></coil>
+  <contact localId="4"><position x="60" y="40"/><connectionPointIn><connection refLocalId="2"/></connectionPointIn><connectionPointOut/><variable>C</variable></contact>
+  <coil localId="5"><position x="100" y="40"/><connectionPointIn><connection refLocalId="4"/></connectionPointIn><connectionPointOut/><variable>D</variable></coil>
+</LD>`
+	var ld plcopen.BodyLD
+	if err := xml.Unmarshal([]byte(rung), &ld); err != nil {
+		t.Fatalf("Failed to unmarshal LD: %v", err)
+	}
+	body, vars, err := ld.ToST()
+	if err != nil {
+		t.Fatalf("ToST: %v", err)
+	}
+	want := "POWER_2 := A;\nIF POWER_2 THEN\n    A := FALSE;\nEND_IF;\nD := POWER_2 AND C;"
+	if got := body.Xhtml.PlainText(); got != want {
+		t.Errorf("ToST =\n%s\nwant\n%s", got, want)
+	}
+	if len(vars) != 1 || vars[0].Name != "POWER_2" || vars[0].Type.IEC() != "BOOL" {
+		t.Errorf("ToST variables = %+v", vars)
+	}
+}
@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+	_ "github.com/suifei/plcopen-go/v2/v201"
+)
+
+// nestedArrayType returns a data type of n nested arrays
+func nestedArrayType(n int) *plcopen.DataType {
+	t := &plcopen.DataType{INT: &struct{}{}}
+	for i := 0; i < n; i++ {
+		t = &plcopen.DataType{Array: &plcopen.DataTypeArray{
+			Dimensions: []plcopen.RangeSigned{{Lower: 0, Upper: 1}},
+			BaseType:   t,
+		}}
+	}
+	return t
+}
+
+// TestLoadLimits checks that documents exceeding limits fail with a LimitError
+func TestLoadLimits(t *testing.T) {
+	project := createComprehensiveProject()
+	var xmlDoc, jsonDoc bytes.Buffer
+	if err := project.Save(&xmlDoc); err != nil {
+		t.Fatal(err)
+	}
+	if err := project.Save(&jsonDoc, plcopen.WithFormat(plcopen.FormatJSON)); err != nil {
+		t.Fatal(err)
+	}
+
+	deep := createComprehensiveProject()
+	deep.Types.DataTypes[0].BaseType = nestedArrayType(100)
+	var deepXML, deepJSON bytes.Buffer
+	if err := deep.Save(&deepXML); err != nil {
+		t.Fatal(err)
+	}
+	if err := deep.Save(&deepJSON, plcopen.WithFormat(plcopen.FormatJSON)); err != nil {
+		t.Fatal(err)
+	}
+
+	long := createComprehensiveProject()
+	long.Types.POUs[0].Name = strings.Repeat("x", 2000)
+	long.ContentHeader.Comment = strings.Repeat("y", 5000)
+	var longXML bytes.Buffer
+	if err := long.Save(&longXML); err != nil {
+		t.Fatal(err)
+	}
+
+	// The comprehensive project has 5 POUs and is nested about 12 levels deep
+	generous := plcopen.Limits{MaxBytes: 1 << 20, MaxDepth: 32, MaxPOUs: 5, MaxAttrLen: 1024, MaxTextLen: 4096}
+	tests := []struct {
+		name   string
+		doc    []byte
+		limits plcopen.Limits
+		want   plcopen.LimitKind
+	}{
+		{"XML within limits", xmlDoc.Bytes(), generous, ""},
+		{"JSON within limits", jsonDoc.Bytes(), generous, ""},
+		{"XML size", xmlDoc.Bytes(), plcopen.Limits{MaxBytes: int64(xmlDoc.Len() - 1)}, plcopen.LimitBytes},
+		{"XML exact size", xmlDoc.Bytes(), plcopen.Limits{MaxBytes: int64(xmlDoc.Len())}, ""},
+		{"JSON size", jsonDoc.Bytes(), plcopen.Limits{MaxBytes: 100}, plcopen.LimitBytes},
+		{"XML depth", deepXML.Bytes(), generous, plcopen.LimitDepth},
+		{"JSON depth", deepJSON.Bytes(), generous, plcopen.LimitDepth},
+		{"XML POUs", xmlDoc.Bytes(), plcopen.Limits{MaxPOUs: 4}, plcopen.LimitPOUs},
+		{"JSON POUs", jsonDoc.Bytes(), plcopen.Limits{MaxPOUs: 4}, plcopen.LimitPOUs},
+		{"XML attribute length", longXML.Bytes(), plcopen.Limits{MaxAttrLen: 1024}, plcopen.LimitAttrLen},
+		{"XML text length", longXML.Bytes(), plcopen.Limits{MaxTextLen: 4096}, plcopen.LimitTextLen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := plcopen.Load(bytes.NewReader(tt.doc), plcopen.WithLimits(tt.limits))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				return
+			}
+			var limitErr *plcopen.LimitError
+			if !errors.As(err, &limitErr) {
+				t.Fatalf("Load = %v, want a LimitError", err)
+			}
+			if limitErr.Limit != tt.want {
+				t.Errorf("Limit = %s, want %s", limitErr.Limit, tt.want)
+			}
+		})
+	}
+}
+
+// TestLimitsOtherPaths checks limits in Stream and when converting 2.01 documents
+func TestLimitsOtherPaths(t *testing.T) {
+	var buf bytes.Buffer
+	if err := createComprehensiveProject().Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var limitErr *plcopen.LimitError
+
+	// Skipped POUs count as well
+	err := plcopen.Stream(bytes.NewReader(buf.Bytes()), plcopen.StreamHandler{}, plcopen.WithLimits(plcopen.Limits{MaxPOUs: 2}))
+	if !errors.As(err, &limitErr) || limitErr.Limit != plcopen.LimitPOUs || limitErr.Line == 0 {
+		t.Errorf("Stream = %v, want a MaxPOUs error with line", err)
+	}
+	count := 0
+	for _, err := range plcopen.StreamPOUs(bytes.NewReader(buf.Bytes()), plcopen.WithLimits(plcopen.Limits{MaxBytes: 2000})) {
+		if err != nil && !errors.As(err, &limitErr) {
+			t.Errorf("StreamPOUs = %v, want a LimitError", err)
+		}
+		count++
+	}
+	if count == 0 {
+		t.Errorf("StreamPOUs yielded nothing")
+	}
+
+	doc := `<project xmlns="http://www.plcopen.org/xml/tc6_0201"><fileHeader companyName="c" productName="p" productVersion="1" creationDateTime="2025-01-01T00:00:00Z"/>` +
+		`<contentHeader name="n"><coordinateInfo><fbd><scaling x="1" y="1"/></fbd><ld><scaling x="1" y="1"/></ld><sfc><scaling x="1" y="1"/></sfc></coordinateInfo></contentHeader>` +
+		`<types><dataTypes/><pous>` + strings.Repeat(`<pou name="P" pouType="program"/>`, 3) + `</pous></types><instances><configurations/></instances></project>`
+	if _, err := plcopen.Load(strings.NewReader(doc)); err != nil {
+		t.Fatalf("Load 2.01 document: %v", err)
+	}
+	_, err = plcopen.Load(strings.NewReader(doc), plcopen.WithLimits(plcopen.Limits{MaxPOUs: 2}))
+	if !errors.As(err, &limitErr) || limitErr.Limit != plcopen.LimitPOUs {
+		t.Errorf("Load 2.01 document = %v, want a MaxPOUs error", err)
+	}
+}
@@ -0,0 +1,160 @@
+package tests
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suifei/plcopen-go/v2"
+	_ "github.com/suifei/plcopen-go/v2/v201"
+)
+
+// TestSaveLoadRoundTrip checks that Save writes complete documents that Load reads back
+func TestSaveLoadRoundTrip(t *testing.T) {
+	project := createComprehensiveProject()
+
+	var xmlOut bytes.Buffer
+	if err := project.Save(&xmlOut); err != nil {
+		t.Fatalf("Save XML: %v", err)
+	}
+	if !strings.HasPrefix(xmlOut.String(), "<?xml version=\"1.0\" encoding=\"utf-8\"?>\n<project xmlns=\"http://www.plcopen.org/xml/tc6.xsd\">") {
+		t.Errorf("Saved XML lacks declaration or namespace:\n%.200s", xmlOut.String())
+	}
+	xmlFile := filepath.Join(t.TempDir(), "saved.xml")
+	if err := os.WriteFile(xmlFile, xmlOut.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	validateWithXmllint(t, xmlFile, filepath.Join("..", "docs", "TC6_XML_V10_B.xsd"))
+
+	// Compare compact documents, indentation inside formatted text is kept as content
+	compact := plcopen.WithIndent("", "")
+	var want, jsonOut bytes.Buffer
+	if err := project.Save(&want, compact); err != nil {
+		t.Fatalf("Save compact XML: %v", err)
+	}
+	if err := project.Save(&jsonOut, plcopen.WithFormat(plcopen.FormatJSON)); err != nil {
+		t.Fatalf("Save JSON: %v", err)
+	}
+
+	for name, data := range map[string][]byte{"xml": want.Bytes(), "json": jsonOut.Bytes()} {
+		loaded, err := plcopen.Load(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Load %s: %v", name, err)
+		}
+		var again bytes.Buffer
+		if err := loaded.Save(&again, compact); err != nil {
+			t.Fatalf("Save loaded %s: %v", name, err)
+		}
+		if again.String() != want.String() {
+			t.Errorf("Load %s changed the project\nwant:\n%s\ngot:\n%s", name, want.String(), again.String())
+		}
+	}
+}
+
+// TestLoadEncodings checks byte order marks and declared or forced charsets
+func TestLoadEncodings(t *testing.T) {
+	const doc = `<project xmlns="http://www.plcopen.org/xml/tc6.xsd"><fileHeader companyName="%s" productName="P" productVersion="1" creationDateTime="2025-01-01T00:00:00Z"/></project>`
+	document := func(decl, company string) []byte {
+		return []byte(decl + strings.Replace(doc, "%s", company, 1))
+	}
+	utf16 := func(s string) []byte {
+		out := []byte{0xFF, 0xFE}
+		for _, r := range s {
+			out = append(out, byte(r), byte(r>>8))
+		}
+		return out
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		opts []plcopen.Option
+		want string
+	}{
+		{"utf-8 BOM", append([]byte("\xEF\xBB\xBF"), document(`<?xml version="1.0" encoding="UTF-8"?>`, "Straße")...), nil, "Straße"},
+		{"utf-16 BOM", utf16(string(document(`<?xml version="1.0" encoding="UTF-16"?>`, "Straße"))), nil, "Straße"},
+		{"windows-1252", document(`<?xml version="1.0" encoding="windows-1252"?>`, "Caf\xE9 \x80"), nil, "Café €"},
+		{"gbk", document(`<?xml version="1.0" encoding="GBK"?>`, "\xD6\xD0\xCE\xC4"), nil, "中文"},
+		{"gb2312", document(`<?xml version="1.0" encoding="gb2312"?>`, "\xD6\xD0\xCE\xC4"), nil, "中文"},
+		{"gbk declared as utf-8", document(`<?xml version="1.0" encoding="UTF-8"?>`, "\xD6\xD0\xCE\xC4"), []plcopen.Option{plcopen.WithCharset("gbk")}, "中文"},
+		{"no namespace", []byte(strings.Replace(string(document("", "Plain")), ` xmlns="http://www.plcopen.org/xml/tc6.xsd"`, "", 1)), nil, "Plain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, err := plcopen.Load(bytes.NewReader(tt.data), tt.opts...)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if project.FileHeader == nil || project.FileHeader.CompanyName != tt.want {
+				t.Errorf("companyName = %+v, want %q", project.FileHeader, tt.want)
+			}
+		})
+	}
+
+	if _, err := plcopen.Load(bytes.NewReader(document(`<?xml version="1.0" encoding="x-unknown"?>`, "A"))); err == nil {
+		t.Errorf("Load must reject unknown charsets")
+	}
+	if _, err := plcopen.Load(strings.NewReader(`<project xmlns="urn:other"/>`)); err == nil {
+		t.Errorf("Load must reject unknown namespaces")
+	}
+	if _, err := plcopen.Load(strings.NewReader(`<pou xmlns="http://www.plcopen.org/xml/tc6.xsd"/>`)); err == nil {
+		t.Errorf("Load must reject root elements other than project")
+	}
+}
+
+// TestSaveCharset checks that Save encodes and declares a non-UTF-8 charset
+func TestSaveCharset(t *testing.T) {
+	project := createTestProject()
+	project.ContentHeader.Name = "中文项目 ✓"
+
+	var out bytes.Buffer
+	if err := project.Save(&out, plcopen.WithCharset("gbk")); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	if !bytes.HasPrefix(out.Bytes(), []byte(`<?xml version="1.0" encoding="gbk"?>`)) {
+		t.Errorf("Saved XML lacks the gbk declaration:\n%.100s", out.String())
+	}
+	if !bytes.Contains(out.Bytes(), []byte("\xD6\xD0\xCE\xC4")) || !bytes.Contains(out.Bytes(), []byte("&#10003;")) {
+		t.Errorf("Saved XML is not GBK encoded:\n%q", out.String())
+	}
+
+	loaded, err := plcopen.Load(&out)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if loaded.ContentHeader.Name != project.ContentHeader.Name {
+		t.Errorf("name = %q, want %q", loaded.ContentHeader.Name, project.ContentHeader.Name)
+	}
+
+	if err := project.Save(&out, plcopen.WithFormat(plcopen.FormatJSON), plcopen.WithCharset("gbk")); err == nil {
+		t.Errorf("Save must reject non-UTF-8 JSON")
+	}
+}
+
+// TestLoadOtherVersions checks that registered versions are converted to V1.0B
+func TestLoadOtherVersions(t *testing.T) {
+	if _, err := plcopen.Load(strings.NewReader(v201Project)); err == nil || !strings.Contains(err.Error(), "WithWarnings") {
+		t.Errorf("Load of a lossy 2.01 document = %v, want an error pointing to WithWarnings", err)
+	}
+
+	for name, doc := range map[string]string{
+		"2.01": v201Project,
+		"2.0":  strings.Replace(v201Project, "tc6_0201", "tc6_0200", 1),
+	} {
+		var warnings []string
+		project, err := plcopen.Load(strings.NewReader(doc), plcopen.WithWarnings(func(path, message string) {
+			warnings = append(warnings, path+": "+message)
+		}))
+		if err != nil {
+			t.Fatalf("Load %s: %v", name, err)
+		}
+		if len(warnings) == 0 {
+			t.Errorf("Load %s reported no warnings", name)
+		}
+		if project.Types.POUs[0].Name != "Main" || project.Types.POUs[0].Body.LD == nil {
+			t.Errorf("Load %s did not convert the POU: %+v", name, project.Types.POUs[0])
+		}
+	}
+}
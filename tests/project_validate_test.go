@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// TestProjectValidateClean checks that the comprehensive test project has no diagnostics
+func TestProjectValidateClean(t *testing.T) {
+	for _, d := range createComprehensiveProject().Validate() {
+		t.Errorf("Unexpected diagnostic: %s", d)
+	}
+}
+
+// TestProjectValidate checks the semantic rules on broken projects
+func TestProjectValidate(t *testing.T) {
+	const (
+		myFunction = "project/types/pous/pou[@name='MyFunction']"
+		resource   = "project/instances/configurations/configuration[@name='DefaultConfig']/resource[@name='DefaultResource']"
+	)
+	sfcBody := func(p *plcopen.Project) *plcopen.BodySFC {
+		sfc := &plcopen.BodySFC{
+			Steps: []plcopen.BodySFCStep{{Name: "Init", LocalID: 1}},
+			JumpSteps: []plcopen.BodySFCJumpStep{{
+				LocalID:           3,
+				TargetName:        "init",
+				ConnectionPointIn: &plcopen.ConnectionPointIn{Connections: []plcopen.Connection{{RefLocalID: 2}}},
+			}},
+			Transitions: []plcopen.BodySFCTransition{{
+				LocalID:           2,
+				ConnectionPointIn: &plcopen.ConnectionPointIn{Connections: []plcopen.Connection{{RefLocalID: 1}}},
+				Condition: &plcopen.BodySFCTransitionCondition{
+					Reference: &plcopen.BodySFCTransitionConditionReference{Name: "Go"},
+				},
+			}},
+		}
+		pou := &p.Types.POUs[0]
+		pou.Body = &plcopen.Body{SFC: sfc}
+		pou.Transitions = []plcopen.ProjectTypesPOUTransition{{Name: "GO"}}
+		return sfc
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(p *plcopen.Project)
+		rule     string
+		severity plcopen.Severity
+		path     string
+	}{
+		{
+			name: "duplicate POU name",
+			modify: func(p *plcopen.Project) {
+				p.Types.POUs[1].Name = "myfunction"
+			},
+			rule:     plcopen.RuleDuplicateName,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MyFunction']",
+		},
+		{
+			name: "duplicate variable name",
+			modify: func(p *plcopen.Project) {
+				p.Types.POUs[0].Interface.OutputVars.Variables[0].Name = "Counter"
+			},
+			rule:     plcopen.RuleDuplicateName,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MainProgram']/interface/outputVars/variable[@name='Counter']",
+		},
+		{
+			name: "duplicate localId",
+			modify: func(p *plcopen.Project) {
+				p.Types.POUs[4].Body.LD.Coils[0].LocalID = 1
+			},
+			rule:     plcopen.RuleDuplicateLocalID,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MyFunctionBlock']/body/LD/coil[@localId='1']",
+		},
+		{
+			name: "dangling connection",
+			modify: func(p *plcopen.Project) {
+				p.Types.POUs[2].Body.FBD.Blocks[0].InOutVariables[0].ConnectionPointIn = &plcopen.ConnectionPointIn{
+					Connections: []plcopen.Connection{{RefLocalID: 9}},
+				}
+			},
+			rule:     plcopen.RuleDanglingConnection,
+			severity: plcopen.SeverityError,
+			path:     myFunction + "/body/FBD/block[@localId='1']",
+		},
+		{
+			name: "undefined derived type",
+			modify: func(p *plcopen.Project) {
+				p.Types.POUs[2].Interface.InputVars.Variables[1].Type = &plcopen.DataType{
+					Array: &plcopen.DataTypeArray{BaseType: &plcopen.DataType{Derived: &plcopen.DataTypeDerived{Name: "Missing"}}},
+				}
+			},
+			rule:     plcopen.RuleUndefinedType,
+			severity: plcopen.SeverityError,
+			path:     myFunction + "/interface/inputVars/variable[@name='input2']/type/array/baseType/derived",
+		},
+		{
+			name: "undefined POU",
+			modify: func(p *plcopen.Project) {
+				p.Instances.Configurations[0].Resources[0].POUInstances[0].TypeName = "Missing"
+			},
+			rule:     plcopen.RuleUndefinedPOU,
+			severity: plcopen.SeverityError,
+			path:     resource + "/pouInstance[@name='MainInstance']",
+		},
+		{
+			name: "instance of function block",
+			modify: func(p *plcopen.Project) {
+				p.Instances.Configurations[0].Resources[0].Tasks[0].POUInstances[0].TypeName = "MyFunctionBlock"
+			},
+			rule:     plcopen.RuleInstanceNotProgram,
+			severity: plcopen.SeverityWarning,
+			path:     resource + "/task[@name='MainTask']/pouInstance[@name='MainInstance']",
+		},
+		{
+			name: "task without trigger",
+			modify: func(p *plcopen.Project) {
+				p.Instances.Configurations[0].Resources[0].Tasks[0].Interval = nil
+			},
+			rule:     plcopen.RuleTaskWithoutTrigger,
+			severity: plcopen.SeverityError,
+			path:     resource + "/task[@name='MainTask']",
+		},
+		{
+			name: "undefined label",
+			modify: func(p *plcopen.Project) {
+				p.Types.POUs[2].Body.FBD.Jumps = []plcopen.BodyFBDJump{{LocalID: 2, Label: "End"}}
+			},
+			rule:     plcopen.RuleUndefinedLabel,
+			severity: plcopen.SeverityError,
+			path:     myFunction + "/body/FBD/jump[@localId='2']",
+		},
+		{
+			name: "undefined step",
+			modify: func(p *plcopen.Project) {
+				sfcBody(p).JumpSteps[0].TargetName = "Done"
+			},
+			rule:     plcopen.RuleUndefinedStep,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MainProgram']/body/SFC/jumpStep[@localId='3']",
+		},
+		{
+			name: "undefined step in macro step",
+			modify: func(p *plcopen.Project) {
+				sfcBody(p).MacroSteps = []plcopen.BodySFCMacroStep{{
+					LocalID: 4,
+					Body: &plcopen.Body{SFC: &plcopen.BodySFC{
+						JumpSteps: []plcopen.BodySFCJumpStep{{LocalID: 1, TargetName: "Inner"}},
+					}},
+				}}
+			},
+			rule:     plcopen.RuleUndefinedStep,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MainProgram']/body/SFC/macroStep[@localId='4']/body/SFC/jumpStep[@localId='1']",
+		},
+		{
+			name: "undefined transition",
+			modify: func(p *plcopen.Project) {
+				sfcBody(p).Transitions[0].Condition.Reference.Name = "Stop"
+			},
+			rule:     plcopen.RuleUndefinedTransition,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MainProgram']/body/SFC/transition[@localId='2']",
+		},
+		{
+			name: "dangling step connection",
+			modify: func(p *plcopen.Project) {
+				sfcBody(p).Steps[0].ConnectionPointIn = &plcopen.BodySFCStepConnectionPointIn{
+					Connections: []plcopen.Connection{{RefLocalID: 9}},
+				}
+			},
+			rule:     plcopen.RuleDanglingConnection,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MainProgram']/body/SFC/step[@localId='1']",
+		},
+		{
+			name: "undefined label in inline action",
+			modify: func(p *plcopen.Project) {
+				p.Types.POUs[2].Body.FBD.ActionBlocks = []plcopen.BodyFBDActionBlock{{
+					LocalID: 5,
+					Actions: []plcopen.BodyFBDActionBlockAction{{
+						Inline: &plcopen.BodyFBDActionBlockActionInline{Body: &plcopen.Body{FBD: &plcopen.BodyFBD{
+							Jumps: []plcopen.BodyFBDJump{{LocalID: 1, Label: "End"}},
+						}}},
+					}},
+				}}
+			},
+			rule:     plcopen.RuleUndefinedLabel,
+			severity: plcopen.SeverityError,
+			path:     myFunction + "/body/FBD/actionBlock[@localId='5']/action[1]/inline/FBD/jump[@localId='1']",
+		},
+		{
+			name: "undefined label in inline condition",
+			modify: func(p *plcopen.Project) {
+				sfcBody(p).Transitions[0].Condition = &plcopen.BodySFCTransitionCondition{
+					Inline: &plcopen.BodySFCTransitionConditionInline{Body: &plcopen.Body{LD: &plcopen.BodyLD{
+						Jumps: []plcopen.BodyFBDJump{{LocalID: 1, Label: "End"}},
+					}}},
+				}
+			},
+			rule:     plcopen.RuleUndefinedLabel,
+			severity: plcopen.SeverityError,
+			path:     "project/types/pous/pou[@name='MainProgram']/body/SFC/transition[@localId='2']/condition/inline/LD/jump[@localId='1']",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := createComprehensiveProject()
+			tt.modify(p)
+			diags := p.Validate()
+			if len(diags) != 1 {
+				t.Fatalf("Got %d diagnostics, want 1: %v", len(diags), diags)
+			}
+			d := diags[0]
+			if d.Rule != tt.rule || d.Severity != tt.severity || d.Path != tt.path {
+				t.Errorf("Diagnostic = %s, want %s %s at %s", d, tt.severity, tt.rule, tt.path)
+			}
+			if d.Message == "" {
+				t.Errorf("Diagnostic %s has no message", d)
+			}
+		})
+	}
+}
+
+// TestProjectValidateSFC checks that a consistent SFC body has no diagnostics
+func TestProjectValidateSFC(t *testing.T) {
+	p := createComprehensiveProject()
+	fork := "Fork"
+	p.Types.POUs[0].Body = &plcopen.Body{SFC: &plcopen.BodySFC{
+		Steps:                   []plcopen.BodySFCStep{{Name: "Init", LocalID: 1}},
+		SimultaneousDivergences: []plcopen.BodySFCSimultaneousDivergence{{LocalID: 3, Name: &fork}},
+		JumpSteps: []plcopen.BodySFCJumpStep{
+			{LocalID: 2, TargetName: "INIT"},
+			{LocalID: 4, TargetName: "Fork"},
+		},
+	}}
+	for _, d := range p.Validate() {
+		t.Errorf("Unexpected diagnostic: %s", d)
+	}
+
+	p.Types.POUs[0].Body.SFC.JumpSteps[1].TargetName = "Join"
+	diags := p.Validate()
+	if len(diags) != 1 || diags[0].Rule != plcopen.RuleUndefinedStep {
+		t.Errorf("Jump to an undefined target: diagnostics = %v", diags)
+	}
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// TestSTFormat checks the canonical layout of formatted ST
+func TestSTFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			"spacing",
+			"x:=a+b*(c-d);y:=NOT a AND b;fb(IN:=x,PT:=t#5s,Q=>q,NOT e=>f);",
+			"x := a + b * (c - d);\ny := NOT a AND b;\nfb(IN := x, PT := t#5s, Q => q, NOT e => f);\n",
+		},
+		{
+			"keywords",
+			"if a then x := true; elsif b then x := false; else ; end_if while x do exit; end_while",
+			"IF a THEN\n    x := TRUE;\nELSIF b THEN\n    x := FALSE;\nELSE\nEND_IF;\nWHILE x DO\n    EXIT;\nEND_WHILE;\n",
+		},
+		{
+			"case",
+			"CASE s OF 1,2: x:=1; 3..5: ; ELSE x:=0; END_CASE",
+			"CASE s OF\n    1, 2:\n        x := 1;\n    3..5:\nELSE\n    x := 0;\nEND_CASE;\n",
+		},
+		{
+			"loops",
+			"FOR i:=1 TO 10 BY 2 DO a[i,j]:=p^.v.3; END_FOR REPEAT i:=i+1; UNTIL i>=10 END_REPEAT",
+			"FOR i := 1 TO 10 BY 2 DO\n    a[i, j] := p^.v.3;\nEND_FOR;\nREPEAT\n    i := i + 1;\nUNTIL i >= 10\nEND_REPEAT;\n",
+		},
+		{
+			"comments",
+			"(* head *)\n{pragma}\nx := 1;   // one\nIF a THEN (* then *)\n\n\n  y := 2;\n  (* last *)\nEND_IF; (* end *)\nz := a +\n  (* inner *) b;",
+			"(* head *)\n{pragma}\nx := 1; // one\nIF a THEN (* then *)\n    y := 2;\n    (* last *)\nEND_IF; (* end *)\nz := a + b; (* inner *)\n",
+		},
+		{
+			"blank lines",
+			"x := 1;\n\n\n\ny := 2;\nz := 3;",
+			"x := 1;\n\ny := 2;\nz := 3;\n",
+		},
+		{
+			"line comment inside statement",
+			"x := a + // one\n  b; (* two *)",
+			"x := a + b; // one\n(* two *)\n",
+		},
+	}
+	for _, tt := range tests {
+		got, err := st.Format(tt.src)
+		if err != nil {
+			t.Errorf("%s: Format: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: Format =\n%s\nwant\n%s", tt.name, got, tt.want)
+		}
+		again, _ := st.Format(got)
+		if again != got {
+			t.Errorf("%s: Format is not idempotent:\n%s", tt.name, again)
+		}
+	}
+
+	// The parse test program keeps its statements and comments, except for
+	// the empty statement in its ELSE part
+	formatted, err := st.Format(stProgram)
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	before, _ := st.Parse(stProgram)
+	after, err := st.Parse(formatted)
+	if err != nil {
+		t.Fatalf("Parse of formatted program: %v\n%s", err, formatted)
+	}
+	want := strings.Replace(sexprStmts(before.Stmts), "(ELSE ;)", "(ELSE )", 1)
+	if sexprStmts(after.Stmts) != want || len(after.Comments) != len(before.Comments) {
+		t.Errorf("Formatted program differs:\n%s", formatted)
+	}
+}
+
+// TestSTFormatConfig checks indentation, keyword case and operator spacing
+func TestSTFormatConfig(t *testing.T) {
+	cfg := &st.Config{Indent: "\t", KeywordCase: st.LowerCase, CompactOperators: true}
+	got, err := cfg.Format("IF A AND x <> 0 THEN y := x MOD 2 + 1; Z := TRUE; END_IF;")
+	if err != nil {
+		t.Fatalf("Format: %v", err)
+	}
+	want := "if A and x<>0 then\n\ty := x mod 2+1;\n\tZ := true;\nend_if;\n"
+	if got != want {
+		t.Errorf("Format =\n%s\nwant\n%s", got, want)
+	}
+
+	expr, err := cfg.FormatExpr("step1.X AND (* done *) NOT busy")
+	if err != nil || expr != "step1.X and not busy (* done *)" {
+		t.Errorf("FormatExpr = %q, %v", expr, err)
+	}
+	if _, err := st.Format("IF a THEN"); err == nil {
+		t.Error("Format of invalid source succeeded")
+	}
+}
+
+// TestSTFprint checks the parentheses added for trees built in code
+func TestSTFprint(t *testing.T) {
+	a, b, c := &st.Ident{Name: "a"}, &st.Ident{Name: "b"}, &st.Ident{Name: "c"}
+	tests := []struct {
+		node st.Node
+		want string
+	}{
+		{&st.BinaryExpr{X: &st.BinaryExpr{X: a, Op: st.ADD, Y: b}, Op: st.MUL, Y: c}, "(a + b) * c"},
+		{&st.BinaryExpr{X: a, Op: st.SUB, Y: &st.BinaryExpr{X: b, Op: st.SUB, Y: c}}, "a - (b - c)"},
+		{&st.BinaryExpr{X: &st.BinaryExpr{X: a, Op: st.SUB, Y: b}, Op: st.SUB, Y: c}, "a - b - c"},
+		{&st.UnaryExpr{Op: st.NOT, X: &st.BinaryExpr{X: a, Op: st.OR, Y: b}}, "NOT (a OR b)"},
+		{&st.UnaryExpr{Op: st.SUB, X: &st.UnaryExpr{Op: st.SUB, X: a}}, "-(-a)"},
+		{&st.SelectorExpr{X: &st.UnaryExpr{Op: st.SUB, X: a}, Sel: b}, "(-a).b"},
+		{&st.AssignStmt{Target: a, Value: &st.Literal{Kind: st.BoolLit, Value: "true"}}, "a := TRUE;\n"},
+		{&st.IfStmt{Cond: a, Then: []st.Stmt{&st.CallStmt{Call: &st.CallExpr{Fun: b, Args: []*st.Arg{{Name: c, Op: st.ASSIGN, Value: a}}}}}}, "IF a THEN\n    b(c := a);\nEND_IF;\n"},
+	}
+	for _, tt := range tests {
+		var sb strings.Builder
+		if err := st.Fprint(&sb, tt.node); err != nil {
+			t.Fatalf("Fprint: %v", err)
+		}
+		if sb.String() != tt.want {
+			t.Errorf("Fprint = %q, want %q", sb.String(), tt.want)
+		}
+	}
+}
+
+// TestProjectFormatST formats the ST of a project in place
+func TestProjectFormatST(t *testing.T) {
+	formatted := plcopen.MustParseFormattedText("<xhtml:p>x := 1;</xhtml:p><xhtml:p>y := 2;</xhtml:p>")
+	project := &plcopen.Project{
+		Types: &plcopen.ProjectTypes{
+			POUs: []plcopen.ProjectTypesPOU{
+				{
+					Name:    "Main",
+					POUType: plcopen.POUTypeProgram,
+					Body:    &plcopen.Body{ST: &plcopen.BodyST{Xhtml: plcopen.NewFormattedText("if a then x:=1; end_if")}},
+					Actions: []plcopen.ProjectTypesPOUAction{
+						{Name: "Done", Body: &plcopen.Body{ST: &plcopen.BodyST{Xhtml: formatted}}},
+						{Name: "Broken", Body: &plcopen.Body{ST: &plcopen.BodyST{Xhtml: plcopen.NewFormattedText("x := ;")}}},
+					},
+					Transitions: []plcopen.ProjectTypesPOUTransition{
+						{Name: "Go", Body: &plcopen.Body{ST: &plcopen.BodyST{Xhtml: plcopen.NewFormattedText("a  and(b>1)")}}},
+					},
+				},
+			},
+		},
+	}
+
+	err := project.FormatST(nil)
+	if err == nil || !strings.Contains(err.Error(), "project/types/pous/pou[@name='Main']/actions/action[@name='Broken']/body/ST: 1:6: expected expression") {
+		t.Errorf("FormatST error = %v", err)
+	}
+	pou := project.Types.POUs[0]
+	if got := pou.Body.ST.Xhtml.PlainText(); got != "IF a THEN\n    x := 1;\nEND_IF;" {
+		t.Errorf("Body = %q", got)
+	}
+	if pou.Actions[0].Body.ST.Xhtml != formatted || formatted.XHTML() != "<xhtml:p>x := 1;</xhtml:p><xhtml:p>y := 2;</xhtml:p>" {
+		t.Errorf("Formatted action was rewritten: %s", pou.Actions[0].Body.ST.Xhtml.XHTML())
+	}
+	if got := pou.Actions[1].Body.ST.Xhtml.PlainText(); got != "x := ;" {
+		t.Errorf("Invalid action = %q", got)
+	}
+	if got := pou.Transitions[0].Body.ST.Xhtml.PlainText(); got != "a AND (b > 1)" {
+		t.Errorf("Transition = %q", got)
+	}
+}
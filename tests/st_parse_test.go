@@ -0,0 +1,354 @@
+package tests
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/st"
+)
+
+// sexpr renders an ST node as S-expression to compare trees in tests
+func sexpr(n st.Node) string {
+	switch n := n.(type) {
+	case *st.Ident:
+		return n.Name
+	case *st.Literal:
+		return n.Value
+	case *st.ParenExpr:
+		return sexpr(n.X)
+	case *st.UnaryExpr:
+		return fmt.Sprintf("(%s %s)", n.Op, sexpr(n.X))
+	case *st.BinaryExpr:
+		return fmt.Sprintf("(%s %s %s)", n.Op, sexpr(n.X), sexpr(n.Y))
+	case *st.SelectorExpr:
+		return fmt.Sprintf("(. %s %s)", sexpr(n.X), n.Sel.Name)
+	case *st.IndexExpr:
+		return fmt.Sprintf("([] %s %s)", sexpr(n.X), sexprList(n.Indices))
+	case *st.DerefExpr:
+		return fmt.Sprintf("(^ %s)", sexpr(n.X))
+	case *st.CallExpr:
+		args := make([]string, len(n.Args))
+		for i, a := range n.Args {
+			args[i] = sexpr(a.Value)
+			if a.Name != nil {
+				args[i] = fmt.Sprintf("(%s %s %s)", a.Op, a.Name.Name, args[i])
+			}
+			if a.Negated {
+				args[i] = "(NOT " + args[i] + ")"
+			}
+		}
+		return fmt.Sprintf("(call %s%s)", sexpr(n.Fun), prefixSpace(strings.Join(args, " ")))
+	case *st.RangeExpr:
+		return fmt.Sprintf("(.. %s %s)", sexpr(n.Low), sexpr(n.High))
+	case *st.BadExpr:
+		return "BAD"
+	case *st.AssignStmt:
+		return fmt.Sprintf("(:= %s %s)", sexpr(n.Target), sexpr(n.Value))
+	case *st.CallStmt:
+		return sexpr(n.Call)
+	case *st.IfStmt:
+		s := fmt.Sprintf("(IF %s (%s)", sexpr(n.Cond), sexprStmts(n.Then))
+		for _, e := range n.ElsIfs {
+			s += fmt.Sprintf(" (ELSIF %s (%s))", sexpr(e.Cond), sexprStmts(e.Then))
+		}
+		if n.ElsePos.IsValid() {
+			s += fmt.Sprintf(" (ELSE %s)", sexprStmts(n.Else))
+		}
+		return s + ")"
+	case *st.CaseStmt:
+		s := "(CASE " + sexpr(n.X)
+		for _, c := range n.Clauses {
+			s += fmt.Sprintf(" ((%s) %s)", sexprList(c.Labels), sexprStmts(c.Body))
+		}
+		if n.ElsePos.IsValid() {
+			s += fmt.Sprintf(" (ELSE %s)", sexprStmts(n.Else))
+		}
+		return s + ")"
+	case *st.ForStmt:
+		by := ""
+		if n.By != nil {
+			by = " " + sexpr(n.By)
+		}
+		return fmt.Sprintf("(FOR %s %s %s%s (%s))", n.Var.Name, sexpr(n.From), sexpr(n.To), by, sexprStmts(n.Body))
+	case *st.WhileStmt:
+		return fmt.Sprintf("(WHILE %s (%s))", sexpr(n.Cond), sexprStmts(n.Body))
+	case *st.RepeatStmt:
+		return fmt.Sprintf("(REPEAT (%s) %s)", sexprStmts(n.Body), sexpr(n.Cond))
+	case *st.ExitStmt:
+		return "EXIT"
+	case *st.ContinueStmt:
+		return "CONTINUE"
+	case *st.ReturnStmt:
+		return "RETURN"
+	case *st.EmptyStmt:
+		return ";"
+	case *st.BadStmt:
+		return "BAD"
+	}
+	return fmt.Sprintf("?%T", n)
+}
+
+func sexprList(list []st.Expr) string {
+	s := make([]string, len(list))
+	for i, x := range list {
+		s[i] = sexpr(x)
+	}
+	return strings.Join(s, " ")
+}
+
+func sexprStmts(list []st.Stmt) string {
+	s := make([]string, len(list))
+	for i, x := range list {
+		s[i] = sexpr(x)
+	}
+	return strings.Join(s, " ")
+}
+
+func prefixSpace(s string) string {
+	if s == "" {
+		return ""
+	}
+	return " " + s
+}
+
+// TestSTParseExpr checks operator precedence, operands and literals
+func TestSTParseExpr(t *testing.T) {
+	tests := []struct {
+		src, want string
+	}{
+		{"a + b * c", "(+ a (* b c))"},
+		{"(a + b) * c", "(* (+ a b) c)"},
+		{"a - b - c", "(- (- a b) c)"},
+		{"a OR b AND c XOR d", "(OR a (XOR (AND b c) d))"},
+		{"a & b OR c", "(OR (& a b) c)"},
+		{"NOT a AND b", "(AND (NOT a) b)"},
+		{"-2 ** 2", "(** (- 2) 2)"},
+		{"x MOD 4 = 0 AND y <> 1", "(AND (= (MOD x 4) 0) (<> y 1))"},
+		{"a < b = c >= d", "(= (< a b) (>= c d))"},
+		{"m.pos[1, i + 1].x^.3", "(. (^ (. ([] (. m pos) 1 (+ i 1)) x)) 3)"},
+		{"LIMIT(0, v, 100)", "(call LIMIT 0 v 100)"},
+		{"f()", "(call f)"},
+		{"fb.Method(IN := a, Q => b, NOT QN => c)", "(call (. fb Method) (:= IN a) (=> Q b) (NOT (=> QN c)))"},
+		{"T#5s + TIME#1h_30m - t#-1.5ms", "(- (+ T#5s TIME#1h_30m) t#-1.5ms)"},
+		{"16#FF_FF + 2#1010 + INT#-5 + 1.5E-3", "(+ (+ (+ 16#FF_FF 2#1010) INT#-5) 1.5E-3)"},
+		{"%IX0.1 AND %QW4.2", "(AND %IX0.1 %QW4.2)"},
+		{"c = Color#Red", "(= c Color#Red)"},
+		{"s = 'it$'s' OR w = \"wide\"", "(OR (= s 'it$'s') (= w \"wide\"))"},
+		{"TRUE XOR false", "(XOR TRUE false)"},
+	}
+	for _, tt := range tests {
+		x, err := st.ParseExpr(tt.src)
+		if err != nil {
+			t.Errorf("ParseExpr(%q): %v", tt.src, err)
+			continue
+		}
+		if got := sexpr(x); got != tt.want {
+			t.Errorf("ParseExpr(%q) = %s, want %s", tt.src, got, tt.want)
+		}
+	}
+}
+
+// TestSTLiteralKinds checks the kinds of literals
+func TestSTLiteralKinds(t *testing.T) {
+	tests := map[string]st.LiteralKind{
+		"42": st.IntLit, "16#FF": st.IntLit, "UINT#16#FF": st.IntLit, "1.0": st.RealLit, "REAL#2": st.RealLit,
+		"2E3": st.RealLit, "TRUE": st.BoolLit, "BOOL#1": st.BoolLit, "'a'": st.StringLit, `"a"`: st.WStringLit,
+		"T#1s": st.DurationLit, "LTIME#5ns": st.DurationLit, "D#2024-01-31": st.DateLit,
+		"TOD#12:30:00.5": st.TimeOfDayLit, "DT#2024-01-31-12:00:00": st.DateTimeLit, "Color#Red": st.EnumLit,
+	}
+	for src, kind := range tests {
+		x, err := st.ParseExpr(src)
+		if err != nil {
+			t.Errorf("ParseExpr(%q): %v", src, err)
+			continue
+		}
+		lit, ok := x.(*st.Literal)
+		if !ok || lit.Kind != kind || lit.Value != src {
+			t.Errorf("ParseExpr(%q) = %#v, want %s literal", src, x, kind)
+		}
+	}
+}
+
+const stProgram = `(* Conveyor control *)
+{attribute 'hide'}
+IF start AND NOT fault THEN
+    running := TRUE; // latch
+ELSIF stop THEN
+    running := FALSE;
+ELSE
+    ;
+END_IF;
+
+CASE mode OF
+    0: speed := 0;
+    1, 2: speed := 10 * mode;
+    3..5, 7:
+        speed := 50;
+        ramp(IN := speed, PT := T#2s, Q => ready);
+ELSE
+    speed := -1;
+END_CASE
+
+FOR i := 1 TO 10 BY 2 DO
+    IF buf[i] = 0 THEN CONTINUE; END_IF;
+    sum := sum + buf[i];
+END_FOR;
+while count > 0 do
+    count := count - 1;
+    IF count = 5 THEN EXIT; END_IF;
+end_while;
+REPEAT
+    x := x * 2;
+UNTIL x > 100
+END_REPEAT;
+RETURN;
+`
+
+// TestSTParseProgram checks the statements of a program
+func TestSTParseProgram(t *testing.T) {
+	body, err := st.Parse(stProgram)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	want := []string{
+		"(IF (AND start (NOT fault)) ((:= running TRUE)) (ELSIF stop ((:= running FALSE))) (ELSE ;))",
+		"(CASE mode ((0) (:= speed 0)) ((1 2) (:= speed (* 10 mode))) (((.. 3 5) 7) (:= speed 50) (call ramp (:= IN speed) (:= PT T#2s) (=> Q ready))) (ELSE (:= speed (- 1))))",
+		"(FOR i 1 10 2 ((IF (= ([] buf i) 0) (CONTINUE)) (:= sum (+ sum ([] buf i)))))",
+		"(WHILE (> count 0) ((:= count (- count 1)) (IF (= count 5) (EXIT))))",
+		"(REPEAT ((:= x (* x 2))) (> x 100))",
+		"RETURN",
+	}
+	if len(body.Stmts) != len(want) {
+		t.Fatalf("Parsed %d statements, want %d", len(body.Stmts), len(want))
+	}
+	for i, s := range body.Stmts {
+		if got := sexpr(s); got != want[i] {
+			t.Errorf("Statement %d = %s\nwant %s", i, got, want[i])
+		}
+	}
+
+	if len(body.Comments) != 3 {
+		t.Fatalf("Comments = %d, want 3", len(body.Comments))
+	}
+	c := body.Comments
+	if c[0].Text != "(* Conveyor control *)" || c[0].IsPragma() || c[0].Pos().String() != "1:1" {
+		t.Errorf("Comment 0 = %q at %s", c[0].Text, c[0].Pos())
+	}
+	if c[1].Text != "{attribute 'hide'}" || !c[1].IsPragma() || c[1].Pos().String() != "2:1" {
+		t.Errorf("Pragma = %q at %s", c[1].Text, c[1].Pos())
+	}
+	if c[2].Text != "// latch" || c[2].Pos().String() != "4:22" {
+		t.Errorf("Line comment = %q at %s", c[2].Text, c[2].Pos())
+	}
+
+	// Positions
+	ifStmt := body.Stmts[0].(*st.IfStmt)
+	assign := ifStmt.Then[0].(*st.AssignStmt)
+	checks := []struct {
+		name string
+		pos  st.Pos
+		want string
+	}{
+		{"IF", ifStmt.Pos(), "3:1"},
+		{"END_IF", ifStmt.EndIf, "9:1"},
+		{"end of IF", ifStmt.End(), "9:7"},
+		{"ELSE", ifStmt.ElsePos, "7:1"},
+		{"assignment", assign.Pos(), "4:5"},
+		{":=", assign.TokPos, "4:13"},
+		{"end of assignment", assign.End(), "4:20"},
+		{"while", body.Stmts[3].Pos(), "25:1"},
+	}
+	for _, c := range checks {
+		if c.pos.String() != c.want {
+			t.Errorf("Position of %s = %s, want %s", c.name, c.pos, c.want)
+		}
+	}
+	if off := assign.TokPos.Offset; stProgram[off:off+2] != ":=" {
+		t.Errorf("Offset of := is %d", off)
+	}
+
+	// Cross-reference with Inspect
+	refs := map[string]int{}
+	st.Inspect(body, func(n st.Node) bool {
+		if id, ok := n.(*st.Ident); ok {
+			refs[strings.ToLower(id.Name)]++
+		}
+		return true
+	})
+	for name, want := range map[string]int{"speed": 5, "i": 3, "count": 4, "ramp": 1, "in": 1} {
+		if refs[name] != want {
+			t.Errorf("References of %s = %d, want %d", name, refs[name], want)
+		}
+	}
+}
+
+// TestSTParseNestedComments checks that nested comments end at the matching
+// closing delimiter
+func TestSTParseNestedComments(t *testing.T) {
+	src := "(* outer (* inner *) still outer *) x := 1;\n/* a /* b */ c */ {pragma (* }\ny := 2; (***)"
+	body, err := st.Parse(src)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := sexprStmts(body.Stmts); got != "(:= x 1) (:= y 2)" {
+		t.Errorf("Parsed %s", got)
+	}
+	var got []string
+	for _, c := range body.Comments {
+		got = append(got, c.Text)
+	}
+	want := []string{"(* outer (* inner *) still outer *)", "/* a /* b */ c */", "{pragma (* }", "(***)"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Comments = %q, want %q", got, want)
+	}
+}
+
+// TestSTParseErrors checks error positions and recovery
+func TestSTParseErrors(t *testing.T) {
+	tests := []struct {
+		src   string
+		want  string
+		stmts string
+	}{
+		{"x := 1\ny := 2;", "2:1: expected ';', found identifier y", "BAD"},
+		{"x := ;\ny := 2;", "1:6: expected expression, found ';'", "BAD (:= y 2)"},
+		{"IF a THEN x := 1;", "1:18: expected 'END_IF', found end of input", "(IF a ((:= x 1)))"},
+		{"IF a THEN x := ; END_IF; y := 1;", "1:16: expected expression, found ';'", "(IF a (BAD)) (:= y 1)"},
+		{"x + 1;", "1:3: expected ':=' or call, found '+'", "BAD"},
+		{"x := 1 $ 2;", "1:8: unexpected character '$'", "BAD"},
+		{"(* open", "1:1: comment not terminated", ""},
+		{"(* outer (* inner *) x := 1;", "1:1: comment not terminated", ""},
+		{"s := 'abc;", "1:6: string literal not terminated", "BAD"},
+		{"END_IF;", "1:1: unexpected 'END_IF'", ""},
+		{"FOR 1 := 2 TO 3 DO END_FOR;", "1:5: expected control variable, found integer 1", "(FOR  2 3 ())"},
+	}
+	for _, tt := range tests {
+		body, err := st.Parse(tt.src)
+		var errs st.Errors
+		if !errors.As(err, &errs) || len(errs) == 0 {
+			t.Errorf("Parse(%q) = %v, want errors", tt.src, err)
+			continue
+		}
+		if errs[0].Error() != tt.want {
+			t.Errorf("Parse(%q) first error = %q, want %q", tt.src, errs[0], tt.want)
+		}
+		if got := sexprStmts(body.Stmts); got != tt.stmts {
+			t.Errorf("Parse(%q) statements = %s, want %s", tt.src, got, tt.stmts)
+		}
+	}
+}
+
+// TestSTParseBodies parses the ST bodies of a project
+func TestSTParseBodies(t *testing.T) {
+	body := &plcopen.BodyST{Xhtml: plcopen.NewFormattedText("IF counter < 10 THEN\n  counter := counter + 1;\nEND_IF;")}
+	parsed, err := st.Parse(body.Xhtml.PlainText())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got := sexprStmts(parsed.Stmts); got != "(IF (< counter 10) ((:= counter (+ counter 1))))" {
+		t.Errorf("Parsed %s", got)
+	}
+}
@@ -0,0 +1,195 @@
+package tests
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// TestStream checks that Stream yields the same parts as Load
+func TestStream(t *testing.T) {
+	project := createComprehensiveProject()
+	var buf bytes.Buffer
+	if err := project.Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := plcopen.Load(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		header         *plcopen.ProjectFileHeader
+		dataTypes      []plcopen.ProjectTypesDataType
+		pous           []plcopen.ProjectTypesPOU
+		configurations []plcopen.ProjectInstancesConfiguration
+	)
+	err = plcopen.Stream(bytes.NewReader(buf.Bytes()), plcopen.StreamHandler{
+		FileHeader: func(h *plcopen.ProjectFileHeader) error {
+			header = h
+			return nil
+		},
+		DataType: func(dt *plcopen.ProjectTypesDataType) error {
+			dataTypes = append(dataTypes, *dt)
+			return nil
+		},
+		POU: func(pou *plcopen.ProjectTypesPOU) error {
+			pous = append(pous, *pou)
+			return nil
+		},
+		Configuration: func(c *plcopen.ProjectInstancesConfiguration) error {
+			configurations = append(configurations, *c)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if !reflect.DeepEqual(header, loaded.FileHeader) {
+		t.Errorf("FileHeader = %+v, want %+v", header, loaded.FileHeader)
+	}
+	if !reflect.DeepEqual(dataTypes, loaded.Types.DataTypes) {
+		t.Errorf("DataTypes differ from Load")
+	}
+	if !reflect.DeepEqual(pous, loaded.Types.POUs) {
+		t.Errorf("POUs differ from Load")
+	}
+	if !reflect.DeepEqual(configurations, loaded.Instances.Configurations) {
+		t.Errorf("Configurations differ from Load")
+	}
+
+	stop := errors.New("stop")
+	err = plcopen.Stream(bytes.NewReader(buf.Bytes()), plcopen.StreamHandler{
+		POU: func(*plcopen.ProjectTypesPOU) error { return stop },
+	})
+	if err != stop {
+		t.Errorf("Stream with failing handler = %v, want %v", err, stop)
+	}
+}
+
+// TestStreamIterators checks the iterator functions, early stop and errors
+func TestStreamIterators(t *testing.T) {
+	var buf bytes.Buffer
+	if err := createComprehensiveProject().Save(&buf); err != nil {
+		t.Fatal(err)
+	}
+	doc := buf.String()
+
+	// Leaving the loop early stops reading
+	var names []string
+	for pou, err := range plcopen.StreamPOUs(strings.NewReader(doc)) {
+		if err != nil {
+			t.Fatalf("StreamPOUs: %v", err)
+		}
+		names = append(names, pou.Name)
+		if len(names) == 2 {
+			break
+		}
+	}
+	if want := []string{"MainProgram", "TestFunction"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("POUs = %v, want %v", names, want)
+	}
+
+	count := 0
+	for _, err := range plcopen.StreamDataTypes(strings.NewReader(doc)) {
+		if err != nil {
+			t.Fatalf("StreamDataTypes: %v", err)
+		}
+		count++
+	}
+	if count != 7 {
+		t.Errorf("Got %d data types, want 7", count)
+	}
+
+	for c, err := range plcopen.StreamConfigurations(strings.NewReader(doc)) {
+		if err != nil || c.Name != "DefaultConfig" {
+			t.Errorf("StreamConfigurations = %v, %v", c, err)
+		}
+	}
+
+	// A truncated document yields the parts before the error, then the error
+	truncated := doc[:strings.Index(doc, `<pou name="MyFunction"`)+10]
+	var got []string
+	for pou, err := range plcopen.StreamPOUs(strings.NewReader(truncated)) {
+		if err != nil {
+			got = append(got, "error")
+			continue
+		}
+		got = append(got, pou.Name)
+	}
+	if want := []string{"MainProgram", "TestFunction", "error"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Truncated document = %v, want %v", got, want)
+	}
+
+	v201 := strings.Replace(doc, plcopen.Namespace, "http://www.plcopen.org/xml/tc6_0201", 1)
+	if err := plcopen.Stream(strings.NewReader(v201), plcopen.StreamHandler{}); err == nil || !strings.Contains(err.Error(), "use Load") {
+		t.Errorf("Stream of 2.01 document = %v, want an error", err)
+	}
+}
+
+// pouGenerator generates a project document with n POUs without holding it in memory
+type pouGenerator struct {
+	n, next int
+	buf     bytes.Buffer
+}
+
+func (g *pouGenerator) Read(p []byte) (int, error) {
+	for g.buf.Len() < len(p) && g.next <= g.n+1 {
+		switch {
+		case g.next == 0:
+			g.buf.WriteString(`<?xml version="1.0" encoding="utf-8"?><project xmlns="http://www.plcopen.org/xml/tc6.xsd"><types><dataTypes/><pous>`)
+		case g.next == g.n+1:
+			g.buf.WriteString(`</pous></types><instances><configurations/></instances></project>`)
+		default:
+			fmt.Fprintf(&g.buf, `<pou name="P%d" pouType="program"><interface><localVars>`, g.next)
+			for i := 0; i < 20; i++ {
+				fmt.Fprintf(&g.buf, `<variable name="v%d"><type><INT/></type></variable>`, i)
+			}
+			g.buf.WriteString(`</localVars></interface><body><ST><xhtml:p xmlns:xhtml="http://www.w3.org/1999/xhtml">v0 := v1 + 1;</xhtml:p></ST></body></pou>`)
+		}
+		g.next++
+	}
+	if g.buf.Len() == 0 {
+		return 0, io.EOF
+	}
+	return g.buf.Read(p)
+}
+
+// TestStreamLargeProject checks that memory stays bounded on a large document
+func TestStreamLargeProject(t *testing.T) {
+	if testing.Short() {
+		t.Skip("large document")
+	}
+	const n = 20000
+	runtime.GC()
+	var stats runtime.MemStats
+	var peak uint64
+	count := 0
+	for _, err := range plcopen.StreamPOUs(&pouGenerator{n: n}) {
+		if err != nil {
+			t.Fatalf("StreamPOUs: %v", err)
+		}
+		count++
+		if count%1000 == 0 {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapAlloc > peak {
+				peak = stats.HeapAlloc
+			}
+		}
+	}
+	if count != n {
+		t.Errorf("Got %d POUs, want %d", count, n)
+	}
+	// The document is about 20 MB, loading it at once takes several times that
+	if peak > 16<<20 {
+		t.Errorf("Peak heap %d MB, want bounded memory", peak>>20)
+	}
+	t.Logf("Peak heap %d KB", peak>>10)
+}
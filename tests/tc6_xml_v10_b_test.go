@@ -6,7 +6,7 @@ import (
 	"testing"
 	"time"
 
-	"github.com/suifei/plcopen-go"
+	"github.com/suifei/plcopen-go/v2"
 )
 
 // TestPOUTypeConstants tests all POUType constants
@@ -162,7 +162,7 @@ func TestProjectXMLMarshaling(t *testing.T) {
 							Value: "TRUE",
 						},
 					},
-					Documentation: []byte("Test documentation"),
+					Documentation: plcopen.NewFormattedText("Test documentation"),
 				},
 			},
 			POUs: []plcopen.ProjectTypesPOU{
@@ -187,7 +187,7 @@ func TestProjectXMLMarshaling(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "RETURN input1;",
+							Xhtml:      plcopen.NewFormattedText("RETURN input1;"),
 						},
 					},
 				},
@@ -436,6 +436,62 @@ func TestValueTypes(t *testing.T) {
 	}
 }
 
+// TestNestedValueRoundTrip tests nested array/struct values, including the
+// repetitionValue attribute, against the XML layout defined by the XSD
+func TestNestedValueRoundTrip(t *testing.T) {
+	input := `<initialValue>
+  <arrayValue>
+    <value><simpleValue value="1"/></value>
+    <value repetitionValue="2"><simpleValue value="2"/></value>
+    <value>
+      <structValue>
+        <value member="a"><simpleValue value="3"/></value>
+        <value member="b"><arrayValue><value><simpleValue value="4"/></value></arrayValue></value>
+      </structValue>
+    </value>
+  </arrayValue>
+</initialValue>`
+
+	var value plcopen.Value
+	if err := xml.Unmarshal([]byte(input), &value); err != nil {
+		t.Fatalf("Failed to unmarshal nested value: %v", err)
+	}
+	if value.ArrayValue == nil || len(value.ArrayValue.Values) != 3 {
+		t.Fatalf("ArrayValue not decoded: %+v", value)
+	}
+	if rc := value.ArrayValue.Values[1].RepeatCount; rc == nil || *rc != 2 {
+		t.Errorf("RepeatCount = %v, want 2", rc)
+	}
+	nested := value.ArrayValue.Values[2].Value
+	if nested == nil || nested.StructValue == nil || len(nested.StructValue.Values) != 2 {
+		t.Fatalf("Nested struct value not decoded: %+v", nested)
+	}
+	if member := nested.StructValue.Values[0]; member.Member != "a" || member.Value == nil || member.Value.SimpleValue.Value != "3" {
+		t.Errorf("Struct member a = %+v, want 3", member)
+	}
+
+	want := "[1, 2(2), (a := 3, b := [4])]"
+	if got := value.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// Marshal then unmarshal again must not lose anything
+	data, err := xml.Marshal(&value)
+	if err != nil {
+		t.Fatalf("Failed to marshal nested value: %v", err)
+	}
+	if !strings.Contains(string(data), `repetitionValue="2"`) {
+		t.Errorf("Marshaled value missing repetitionValue: %s", data)
+	}
+	var reparsed plcopen.Value
+	if err := xml.Unmarshal(data, &reparsed); err != nil {
+		t.Fatalf("Failed to unmarshal re-marshaled value: %v", err)
+	}
+	if got := reparsed.String(); got != want {
+		t.Errorf("Round trip String() = %q, want %q", got, want)
+	}
+}
+
 // TestBodyTypes tests all Body types
 func TestBodyTypes(t *testing.T) {
 	tests := []struct {
@@ -560,7 +616,7 @@ func TestBodyTypes(t *testing.T) {
 									Name: "condition1",
 									Body: &plcopen.Body{
 										ST: &plcopen.BodyST{
-											Xhtml: "TRUE",
+											Xhtml: plcopen.NewFormattedText("TRUE"),
 										},
 									},
 								},
@@ -575,7 +631,7 @@ func TestBodyTypes(t *testing.T) {
 			&plcopen.Body{
 				IL: &plcopen.BodyIL{
 					XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-					Xhtml:      "LD input1\nST output1",
+					Xhtml:      plcopen.NewFormattedText("LD input1\nST output1"),
 				},
 			},
 		},
@@ -584,7 +640,7 @@ func TestBodyTypes(t *testing.T) {
 			&plcopen.Body{
 				ST: &plcopen.BodyST{
 					XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-					Xhtml:      "output1 := input1;",
+					Xhtml:      plcopen.NewFormattedText("output1 := input1;"),
 				},
 			},
 		},
@@ -625,10 +681,10 @@ func TestComplexStructures(t *testing.T) {
 						InitialValue: &plcopen.Value{
 							SimpleValue: &plcopen.ValueSimpleValue{Value: "0"},
 						},
-						Documentation: []byte("Local variable"),
+						Documentation: plcopen.NewFormattedText("Local variable"),
 					},
 				},
-				Documentation: []byte("Local variables"),
+				Documentation: plcopen.NewFormattedText("Local variables"),
 			},
 			InputVars: &plcopen.VarList{
 				Variables: []plcopen.VarListVariable{
@@ -658,17 +714,17 @@ func TestComplexStructures(t *testing.T) {
 			GlobalVars:    &plcopen.VarList{},
 			TempVars:      &plcopen.VarList{},
 			AccessVars:    &plcopen.VarList{},
-			Documentation: []byte("Interface documentation"),
+			Documentation: plcopen.NewFormattedText("Interface documentation"),
 		},
 		Actions: []plcopen.ProjectTypesPOUAction{
 			{
 				Name: "Action1",
 				Body: &plcopen.Body{
 					ST: &plcopen.BodyST{
-						Xhtml: "// Action code",
+						Xhtml: plcopen.NewFormattedText("// Action code"),
 					},
 				},
-				Documentation: []byte("Action documentation"),
+				Documentation: plcopen.NewFormattedText("Action documentation"),
 			},
 		},
 		Transitions: []plcopen.ProjectTypesPOUTransition{
@@ -676,10 +732,10 @@ func TestComplexStructures(t *testing.T) {
 				Name: "Transition1",
 				Body: &plcopen.Body{
 					ST: &plcopen.BodyST{
-						Xhtml: "// Transition code",
+						Xhtml: plcopen.NewFormattedText("// Transition code"),
 					},
 				},
-				Documentation: []byte("Transition documentation"),
+				Documentation: plcopen.NewFormattedText("Transition documentation"),
 			},
 		},
 		Body: &plcopen.Body{
@@ -701,23 +757,23 @@ func TestComplexStructures(t *testing.T) {
 					{
 						LocalID:  2,
 						Position: &plcopen.Position{X: 200, Y: 200},
-						Content:  "This is a comment",
+						Content:  plcopen.NewFormattedText("This is a comment"),
 					},
 				},
 				Connectors: []plcopen.BodyFBDConnector{
 					{
-						LocalID:            3,
-						Name:               "Connector1",
-						Position:           &plcopen.Position{X: 150, Y: 150},
-						ConnectionPointOut: &plcopen.ConnectionPointOut{},
+						LocalID:           3,
+						Name:              "Connector1",
+						Position:          &plcopen.Position{X: 150, Y: 150},
+						ConnectionPointIn: &plcopen.ConnectionPointIn{},
 					},
 				},
 				Continuations: []plcopen.BodyFBDContinuation{
 					{
-						LocalID:           4,
-						Name:              "Connector1",
-						Position:          &plcopen.Position{X: 250, Y: 150},
-						ConnectionPointIn: &plcopen.ConnectionPointIn{},
+						LocalID:            4,
+						Name:               "Connector1",
+						Position:           &plcopen.Position{X: 250, Y: 150},
+						ConnectionPointOut: &plcopen.ConnectionPointOut{},
 					},
 				},
 				InOutVariables: []plcopen.BodyFBDInOutVariable{
@@ -751,7 +807,7 @@ func TestComplexStructures(t *testing.T) {
 				},
 			},
 		},
-		Documentation: []byte("POU documentation"),
+		Documentation: plcopen.NewFormattedText("POU documentation"),
 	}
 
 	// Test marshaling
@@ -817,7 +873,7 @@ func TestAllFBDElements(t *testing.T) {
 						ConnectionPointOut: &plcopen.ConnectionPointOut{},
 					},
 				},
-				Documentation: []byte("Block documentation"),
+				Documentation: plcopen.NewFormattedText("Block documentation"),
 			},
 		},
 		ActionBlocks: []plcopen.BodyFBDActionBlock{
@@ -838,14 +894,14 @@ func TestAllFBDElements(t *testing.T) {
 							Name: "InlineAction",
 							Body: &plcopen.Body{
 								ST: &plcopen.BodyST{
-									Xhtml: "// Inline action code",
+									Xhtml: plcopen.NewFormattedText("// Inline action code"),
 								},
 							},
 						},
 						Qualifier: actionQualifierPtr(plcopen.BodyFBDActionBlockActionQualifierN),
 					},
 				},
-				Documentation: []byte("Action block documentation"),
+				Documentation: plcopen.NewFormattedText("Action block documentation"),
 			},
 		},
 	}
@@ -882,7 +938,7 @@ func TestLDElements(t *testing.T) {
 				Width:              float64Ptr(10),
 				Height:             float64Ptr(100),
 				ConnectionPointOut: &plcopen.ConnectionPointOut{},
-				Documentation:      []byte("Left power rail"),
+				Documentation:      plcopen.NewFormattedText("Left power rail"),
 			},
 		},
 		Contacts: []plcopen.BodyLDContact{
@@ -896,7 +952,7 @@ func TestLDElements(t *testing.T) {
 				ConnectionPointOut: &plcopen.ConnectionPointOut{},
 				EdgeModifier:       edgeModifierPtr(plcopen.EdgeModifierTypeRising),
 				Negated:            boolPtr(false),
-				Documentation:      []byte("Contact documentation"),
+				Documentation:      plcopen.NewFormattedText("Contact documentation"),
 			},
 		},
 		Coils: []plcopen.BodyLDCoil{
@@ -910,7 +966,7 @@ func TestLDElements(t *testing.T) {
 				ConnectionPointOut: &plcopen.ConnectionPointOut{}, EdgeModifier: edgeModifierPtr(plcopen.EdgeModifierTypeFalling),
 				StorageModifier: storageModifierPtr(plcopen.StorageModifierTypeSet),
 				Negated:         boolPtr(true),
-				Documentation:   []byte("Coil documentation"),
+				Documentation:   plcopen.NewFormattedText("Coil documentation"),
 			},
 		},
 		RightPowerRails: []plcopen.BodyLDRightPowerRail{
@@ -920,7 +976,7 @@ func TestLDElements(t *testing.T) {
 				Width:             float64Ptr(10),
 				Height:            float64Ptr(100),
 				ConnectionPointIn: &plcopen.ConnectionPointIn{},
-				Documentation:     []byte("Right power rail"),
+				Documentation:     plcopen.NewFormattedText("Right power rail"),
 			},
 		},
 	}
@@ -975,7 +1031,7 @@ func TestSFCElements(t *testing.T) {
 				ConnectionPointOutAction: &plcopen.BodySFCStepConnectionPointOutAction{
 					FormalParameter: stringPtr("ACTION"),
 				},
-				Documentation: []byte("Initial step"),
+				Documentation: plcopen.NewFormattedText("Initial step"),
 			},
 		},
 		Transitions: []plcopen.BodySFCTransition{
@@ -992,7 +1048,7 @@ func TestSFCElements(t *testing.T) {
 						Name: "condition1",
 					},
 				},
-				Documentation: []byte("Transition documentation"),
+				Documentation: plcopen.NewFormattedText("Transition documentation"),
 			},
 		},
 	}
@@ -1040,7 +1096,7 @@ func TestProjectInstances(t *testing.T) {
 									{
 										Name:          "MainProgram",
 										TypeName:      "MAIN",
-										Documentation: []byte("Main program instance"),
+										Documentation: plcopen.NewFormattedText("Main program instance"),
 									},
 								},
 							},
@@ -1070,7 +1126,7 @@ func TestProjectInstances(t *testing.T) {
 								TypeName: "MyFunctionBlock",
 							},
 						},
-						Documentation: []byte("Resource documentation"),
+						Documentation: plcopen.NewFormattedText("Resource documentation"),
 					},
 				},
 				GlobalVars: &plcopen.VarList{
@@ -1081,7 +1137,7 @@ func TestProjectInstances(t *testing.T) {
 						},
 					},
 				},
-				Documentation: []byte("Configuration documentation"),
+				Documentation: plcopen.NewFormattedText("Configuration documentation"),
 			},
 		},
 	}
@@ -1259,14 +1315,14 @@ func TestFullProjectXML(t *testing.T) {
 						Enum: &plcopen.DataTypeEnum{
 							Values: &plcopen.DataTypeEnumValues{
 								Values: []plcopen.DataTypeEnumValuesValue{
-									{Name: "VALUE1", Documentation: []byte("First value")},
-									{Name: "VALUE2", Documentation: []byte("Second value")},
+									{Name: "VALUE1", Documentation: plcopen.NewFormattedText("First value")},
+									{Name: "VALUE2", Documentation: plcopen.NewFormattedText("Second value")},
 								},
 							},
 							BaseType: &plcopen.DataType{INT: &struct{}{}},
 						},
 					},
-					Documentation: []byte("Custom enumeration"),
+					Documentation: plcopen.NewFormattedText("Custom enumeration"),
 				},
 			},
 			POUs: []plcopen.ProjectTypesPOU{
@@ -1298,10 +1354,10 @@ func TestFullProjectXML(t *testing.T) {
 					Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "Output1 := Input1 > 0;",
+							Xhtml:      plcopen.NewFormattedText("Output1 := Input1 > 0;"),
 						},
 					},
-					Documentation: []byte("Test function"),
+					Documentation: plcopen.NewFormattedText("Test function"),
 				},
 			},
 		},
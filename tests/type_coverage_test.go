@@ -4,7 +4,7 @@ import (
 	"reflect"
 	"testing"
 
-	"github.com/suifei/plcopen-go"
+	"github.com/suifei/plcopen-go/v2"
 )
 
 // TestAllTypesDeclared checks that all types in tc6_xml_v10_b.go are tested
@@ -34,6 +34,8 @@ func TestAllTypesDeclared(t *testing.T) {
 		plcopen.ProjectInstancesConfigurationResource{},
 		plcopen.ProjectInstancesConfigurationResourceTask{},
 		plcopen.POUInstance{},
+		plcopen.AddData{},
+		plcopen.AddDataData{},
 
 		// Variable types
 		plcopen.VarList{},
@@ -82,6 +84,7 @@ func TestAllTypesDeclared(t *testing.T) {
 		plcopen.BodyFBDActionBlockActionReference{},
 		plcopen.BodyFBDActionBlockActionInline{},
 		plcopen.BodyFBDComment{},
+		plcopen.BodyFBDError{},
 		plcopen.BodyFBDConnector{},
 		plcopen.BodyFBDContinuation{},
 		plcopen.BodyFBDInVariable{},
@@ -98,16 +101,25 @@ func TestAllTypesDeclared(t *testing.T) {
 		plcopen.BodySFCStepConnectionPointIn{},
 		plcopen.BodySFCStepConnectionPointOut{},
 		plcopen.BodySFCStepConnectionPointOutAction{},
+		plcopen.BodySFCMacroStep{},
+		plcopen.BodySFCJumpStep{},
 		plcopen.BodySFCTransition{},
 		plcopen.BodySFCTransitionCondition{},
 		plcopen.BodySFCTransitionConditionInline{},
 		plcopen.BodySFCTransitionConditionReference{},
+		plcopen.BodySFCSelectionDivergence{},
+		plcopen.BodySFCSelectionDivergenceConnectionPointOut{},
+		plcopen.BodySFCSelectionConvergence{},
+		plcopen.BodySFCSimultaneousDivergence{},
+		plcopen.BodySFCSimultaneousDivergenceConnectionPointOut{},
+		plcopen.BodySFCSimultaneousConvergence{},
 
 		// Constant types
 		plcopen.POUType(""),
 		plcopen.EdgeModifierType(""),
 		plcopen.StorageModifierType(""),
 		plcopen.BodyFBDActionBlockActionQualifier(""),
+		plcopen.HandleUnknownType(""),
 
 		// Type aliases
 		plcopen.ProjectTypesPOUInterfaceExternalVars{},
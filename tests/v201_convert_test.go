@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/v201"
+)
+
+// TestUpgradeDowngradeRoundTrip checks that V1.0B projects survive a
+// conversion to 2.01 and back without changes
+func TestUpgradeDowngradeRoundTrip(t *testing.T) {
+	var decoded plcopen.Project
+	if err := xml.Unmarshal([]byte(addDataProject), &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal project: %v", err)
+	}
+	sources := []struct {
+		name    string
+		project *plcopen.Project
+		// schemaValid is false for projects using addData where V1.0B does not allow it
+		schemaValid bool
+	}{
+		{"comprehensive", createComprehensiveProject(), true},
+		{"addData", &decoded, false},
+	}
+
+	for _, source := range sources {
+		project := source.project
+		t.Run(source.name, func(t *testing.T) {
+			want, err := xml.MarshalIndent(project, "", "  ")
+			if err != nil {
+				t.Fatalf("Failed to marshal project: %v", err)
+			}
+
+			upgraded, warnings := v201.Upgrade(project)
+			for _, w := range warnings {
+				t.Errorf("Upgrade warning: %s", w)
+			}
+			// Go through the 2.01 document to check that nothing is only held in memory
+			data, err := xml.Marshal(upgraded)
+			if err != nil {
+				t.Fatalf("Failed to marshal 2.01 project: %v", err)
+			}
+			doc, err := v201.Decode(strings.NewReader(string(data)))
+			if err != nil || doc.V201 == nil {
+				t.Fatalf("Failed to decode 2.01 project: %v\n%s", err, data)
+			}
+			upgradedFile := filepath.Join(t.TempDir(), "upgraded.xml")
+			if err := os.WriteFile(upgradedFile, data, 0644); err != nil {
+				t.Fatal(err)
+			}
+			validateWithXmllint(t, upgradedFile, filepath.Join("..", "docs", "TC6_XML_V201.xsd"))
+
+			downgraded, warnings := v201.Downgrade(doc.V201)
+			for _, w := range warnings {
+				t.Errorf("Downgrade warning: %s", w)
+			}
+			got, err := xml.MarshalIndent(downgraded, "", "  ")
+			if err != nil {
+				t.Fatalf("Failed to marshal downgraded project: %v", err)
+			}
+			if string(got) != string(want) {
+				t.Errorf("Round trip changed the project\nwant:\n%s\ngot:\n%s", want, got)
+			}
+
+			if !source.schemaValid {
+				return
+			}
+			xmlFile := filepath.Join(t.TempDir(), "downgraded.xml")
+			if err := os.WriteFile(xmlFile, got, 0644); err != nil {
+				t.Fatal(err)
+			}
+			validateWithXmllint(t, xmlFile, filepath.Join("..", "docs", "TC6_XML_V10_B.xsd"))
+		})
+	}
+}
+
+// TestDowngradeWarnings checks that 2.01 content without a V1.0B equivalent is reported
+func TestDowngradeWarnings(t *testing.T) {
+	var project v201.Project
+	if err := xml.Unmarshal([]byte(v201Project), &project); err != nil {
+		t.Fatalf("Failed to unmarshal project: %v", err)
+	}
+	downgraded, warnings := v201.Downgrade(&project)
+
+	pou := downgraded.Types.POUs[0]
+	if pou.Body == nil || pou.Body.LD == nil || len(pou.Body.LD.Blocks) != 1 {
+		t.Errorf("First body not converted: %+v", pou.Body)
+	}
+	if pou.Interface.LocalVars == nil || len(pou.Interface.LocalVars.Variables) != 1 {
+		t.Errorf("First localVars section not converted: %+v", pou.Interface.LocalVars)
+	}
+	if got := downgraded.Types.DataTypes[0].BaseType.IEC(); got != "INT (Off := 0, On := 1)" {
+		t.Errorf("Enumeration = %s, want its values kept", got)
+	}
+	if got := downgraded.Instances.Configurations[0].Resources[0].Tasks[0].POUInstances[0].TypeName; got != "Main" {
+		t.Errorf("pouInstance type = %q, want Main", got)
+	}
+
+	const pouPath = "project/types/pous/pou[@name='Main']"
+	for _, want := range []v201.Warning{
+		{Path: "project/contentHeader", Message: "addDataInfo dropped"},
+		{Path: "project/types/dataTypes/dataType[@name='Mode']", Message: `globalId "dt-1" dropped`},
+		{Path: pouPath, Message: `globalId "pou-1" dropped`},
+		{Path: pouPath + "/interface/localVars[2]", Message: "V1.0B allows a single variable section per kind, section with other attributes dropped"},
+		{Path: pouPath + "/body[1]", Message: `worksheet name "Rung" dropped`},
+		{Path: pouPath + "/body[2]", Message: "V1.0B allows a single body per POU, body dropped"},
+		{Path: pouPath + "/body[1]/LD/block[@localId='3']/inputVariables/variable[@formalParameter='CU']", Message: "documentation and modifiers of block variable dropped"},
+	} {
+		found := false
+		for _, w := range warnings {
+			if w == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Missing warning %s\ngot: %v", want, warnings)
+		}
+	}
+}
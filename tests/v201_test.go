@@ -0,0 +1,283 @@
+package tests
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"strings"
+	"testing"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/v201"
+)
+
+const v201Project = `<?xml version="1.0" encoding="utf-8"?>
+<project xmlns="http://www.plcopen.org/xml/tc6_0201" xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <fileHeader companyName="CODESYS" productName="CODESYS" productVersion="V3.5" creationDateTime="2025-01-01T00:00:00Z"/>
+  <contentHeader name="Demo">
+    <coordinateInfo><fbd><scaling x="1" y="1"/></fbd><ld><scaling x="1" y="1"/></ld><sfc><scaling x="1" y="1"/></sfc></coordinateInfo>
+    <addDataInfo><info name="http://www.3s-software.com/plcopenxml/objectid" version="1.0" vendor="3S-Smart Software Solutions"/></addDataInfo>
+  </contentHeader>
+  <types>
+    <dataTypes>
+      <dataType name="Mode" globalId="dt-1">
+        <baseType><enum><values><value name="Off" value="0"/><value name="On" value="1"/></values><baseType><INT/></baseType></enum></baseType>
+      </dataType>
+    </dataTypes>
+    <pous>
+      <pou name="Main" pouType="program" globalId="pou-1">
+        <interface>
+          <localVars><variable name="start" globalId="v-1"><type><BOOL/></type></variable></localVars>
+          <localVars retain="true"><variable name="count"><type><INT/></type></variable></localVars>
+        </interface>
+        <body WorksheetName="Rung">
+          <LD>
+            <leftPowerRail localId="1"><position x="0" y="0"/><connectionPointOut formalParameter="none"/></leftPowerRail>
+            <contact localId="2" negated="true" edge="rising"><position x="20" y="0"/>
+              <connectionPointIn><connection refLocalId="1"/></connectionPointIn><connectionPointOut/><variable>start</variable></contact>
+            <block localId="3" typeName="CTU" instanceName="ctu" executionOrderId="1"><position x="40" y="0"/>
+              <inputVariables><variable formalParameter="CU" negated="false"><connectionPointIn><connection refLocalId="2"/></connectionPointIn></variable></inputVariables>
+              <inOutVariables/><outputVariables/>
+            </block>
+          </LD>
+        </body>
+        <body WorksheetName="Text"><ST><xhtml:p>count := count + 1;</xhtml:p></ST></body>
+        <addData><data name="http://www.3s-software.com/plcopenxml/objectid" handleUnknown="discard"><ObjectId>1f1e</ObjectId></data></addData>
+      </pou>
+    </pous>
+  </types>
+  <instances>
+    <configurations>
+      <configuration name="Config">
+        <resource name="Res">
+          <task name="Cyclic" interval="T#10ms" priority="1"><pouInstance name="main" typeName="Main"/></task>
+        </resource>
+      </configuration>
+    </configurations>
+  </instances>
+</project>`
+
+// TestV201RoundTrip checks the TC6 XML 2.01 specific elements and attributes
+func TestV201RoundTrip(t *testing.T) {
+	var project v201.Project
+	if err := xml.Unmarshal([]byte(v201Project), &project); err != nil {
+		t.Fatalf("Failed to unmarshal project: %v", err)
+	}
+
+	check := func(project *v201.Project) {
+		t.Helper()
+		if info := project.ContentHeader.AddDataInfo; info == nil || len(info.Info) != 1 || info.Info[0].Vendor != "3S-Smart Software Solutions" {
+			t.Errorf("addDataInfo lost: %+v", info)
+		}
+		enum := project.Types.DataTypes[0].BaseType.Enum
+		if enum == nil || len(enum.Values) != 2 || enum.Values[1].Value == nil || *enum.Values[1].Value != "1" {
+			t.Errorf("Enum values lost: %+v", enum)
+		}
+		pou := project.Types.POUs[0]
+		if pou.GlobalID == nil || *pou.GlobalID != "pou-1" {
+			t.Errorf("POU globalId lost")
+		}
+		if len(pou.Interface.LocalVars) != 2 || pou.Interface.LocalVars[1].Retain == nil {
+			t.Errorf("Variable sections lost: %+v", pou.Interface.LocalVars)
+		}
+		if len(pou.Bodies) != 2 || pou.Bodies[1].ST.PlainText() != "count := count + 1;" {
+			t.Fatalf("Bodies lost: %+v", pou.Bodies)
+		}
+		ld := pou.Bodies[0].LD
+		objs := ld.Objects()
+		if len(objs) != 3 || objs[0].ElementName() != "leftPowerRail" || objs[2].ElementName() != "block" {
+			t.Errorf("LD objects lost or reordered: %v", objs)
+		}
+		contact := ld.Contacts[0]
+		if contact.Negated == nil || !*contact.Negated || contact.Edge == nil || *contact.Edge != "rising" {
+			t.Errorf("Contact modifiers lost: %+v", contact)
+		}
+		if ld.LeftPowerRails[0].ConnectionPointOuts[0].FormalParameter == nil {
+			t.Errorf("Power rail formalParameter lost")
+		}
+		if pou.AddData.Find("http://www.3s-software.com/plcopenxml/objectid") == nil {
+			t.Errorf("POU addData lost")
+		}
+		task := project.Instances.Configurations[0].Resources[0].Tasks[0]
+		if task.POUInstances[0].TypeName != "Main" {
+			t.Errorf("pouInstance typeName lost: %+v", task.POUInstances)
+		}
+	}
+	check(&project)
+
+	data, err := xml.MarshalIndent(&project, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal project: %v", err)
+	}
+	if !strings.Contains(string(data), `<project xmlns="http://www.plcopen.org/xml/tc6_0201">`) {
+		t.Errorf("Marshaled project lacks the 2.01 namespace:\n%s", data)
+	}
+	var reparsed v201.Project
+	if err := xml.Unmarshal(data, &reparsed); err != nil {
+		t.Fatalf("Failed to unmarshal re-marshaled project: %v\n%s", err, data)
+	}
+	check(&reparsed)
+
+	jsonData, err := json.Marshal(&project)
+	if err != nil {
+		t.Fatalf("Failed to marshal project to JSON: %v", err)
+	}
+	var fromJSON v201.Project
+	if err := json.Unmarshal(jsonData, &fromJSON); err != nil {
+		t.Fatalf("Failed to unmarshal project from JSON: %v", err)
+	}
+	if fromJSON.Types.POUs[0].Bodies[0].LD.Blocks[0].TypeName != "CTU" {
+		t.Errorf("JSON round trip lost the block")
+	}
+}
+
+// TestV201Decode checks that Decode detects the version of a document
+func TestV201Decode(t *testing.T) {
+	doc, err := v201.Decode(strings.NewReader(v201Project))
+	if err != nil {
+		t.Fatalf("Decode 2.01: %v", err)
+	}
+	if doc.Version != v201.Version201 || doc.V201 == nil || doc.V10 != nil {
+		t.Errorf("Decode 2.01 = %+v", doc)
+	}
+
+	v200 := strings.Replace(v201Project, v201.Namespace, v201.Namespace200, 1)
+	doc, err = v201.Decode(strings.NewReader(v200))
+	if err != nil {
+		t.Fatalf("Decode 2.0: %v", err)
+	}
+	if doc.Version != v201.Version200 || doc.V201 == nil || len(doc.V201.Types.POUs[0].Bodies) != 2 {
+		t.Errorf("Decode 2.0 = %+v", doc)
+	}
+
+	doc, err = v201.Decode(strings.NewReader(addDataProject))
+	if err != nil {
+		t.Fatalf("Decode V1.0: %v", err)
+	}
+	if doc.Version != v201.Version10 || doc.V10 == nil || doc.V10.Types.POUs[0].Name != "Main" {
+		t.Errorf("Decode V1.0 = %+v", doc)
+	}
+
+	if _, err := v201.Decode(strings.NewReader(`<project xmlns="urn:other"/>`)); err == nil {
+		t.Errorf("Decode must reject unknown namespaces")
+	}
+
+	// The input is prepared as by plcopen.Load
+	document := func(encoding, company string) string {
+		doc := strings.Replace(v201Project, `encoding="utf-8"`, `encoding="`+encoding+`"`, 1)
+		return strings.Replace(doc, `companyName="CODESYS"`, `companyName="`+company+`"`, 1)
+	}
+	utf16 := func(s string) string {
+		out := []byte{0xFF, 0xFE}
+		for _, r := range s {
+			out = append(out, byte(r), byte(r>>8))
+		}
+		return string(out)
+	}
+	for _, tt := range []struct {
+		name string
+		data string
+		opts []plcopen.Option
+		want string
+	}{
+		{"utf-16 BOM", utf16(document("UTF-16", "Straße")), nil, "Straße"},
+		{"iso-8859-1", document("ISO-8859-1", "Caf\xE9"), nil, "Café"},
+		{"gbk declared as utf-8", document("utf-8", "\xD6\xD0\xCE\xC4"), []plcopen.Option{plcopen.WithCharset("gbk")}, "中文"},
+	} {
+		doc, err := v201.Decode(strings.NewReader(tt.data), tt.opts...)
+		if err != nil {
+			t.Errorf("Decode %s: %v", tt.name, err)
+			continue
+		}
+		if doc.V201 == nil || doc.V201.FileHeader.CompanyName != tt.want {
+			t.Errorf("Decode %s: companyName = %+v, want %q", tt.name, doc.V201, tt.want)
+		}
+	}
+	doc, err = v201.Decode(strings.NewReader(`<project><fileHeader companyName="Plain" productName="P" productVersion="1" creationDateTime="2025-01-01T00:00:00Z"/></project>`))
+	if err != nil {
+		t.Fatalf("Decode without namespace: %v", err)
+	}
+	if doc.Version != v201.Version10 || doc.V10.FileHeader.CompanyName != "Plain" {
+		t.Errorf("Decode without namespace = %+v", doc)
+	}
+	_, err = v201.Decode(strings.NewReader(v201Project), plcopen.WithLimits(plcopen.Limits{MaxDepth: 3}))
+	var limitErr *plcopen.LimitError
+	if !errors.As(err, &limitErr) || limitErr.Limit != plcopen.LimitDepth {
+		t.Errorf("Decode with MaxDepth = %v, want a LimitError", err)
+	}
+}
+
+// TestV201VendorElement checks that vendor elements and children the schema
+// does not define survive a round trip and are reported by Downgrade
+func TestV201VendorElement(t *testing.T) {
+	body := `<FBD xmlns="http://www.plcopen.org/xml/tc6_0201">
+  <inVariable localId="1"><position x="0" y="0"/><connectionPointOut/><expression>speed</expression></inVariable>
+  <vendorElement localId="2" width="80" height="40">
+    <position x="40" y="0"/>
+    <alternativeText><xhtml:p xmlns:xhtml="http://www.w3.org/1999/xhtml">PID controller</xhtml:p></alternativeText>
+    <inputVariables><variable formalParameter="PV"><connectionPointIn><connection refLocalId="1"/></connectionPointIn></variable></inputVariables>
+    <addData><data name="http://example.com/pid" handleUnknown="preserve"><gain kp="1.5"/></data></addData>
+  </vendorElement>
+  <sketch localId="3" xmlns:v="urn:vendor" v:layer="2"><v:stroke/></sketch>
+</FBD>`
+	var fbd v201.BodyFBD
+	if err := xml.Unmarshal([]byte(body), &fbd); err != nil {
+		t.Fatalf("Failed to unmarshal FBD body: %v", err)
+	}
+	if len(fbd.VendorElements) != 1 {
+		t.Fatalf("VendorElements length = %d, want 1", len(fbd.VendorElements))
+	}
+	ve := fbd.VendorElements[0]
+	if ve.AlternativeText.PlainText() != "PID controller" || ve.AddData.Find("http://example.com/pid") == nil ||
+		len(ve.InputVariables) != 1 || ve.InputVariables[0].FormalParameter != "PV" {
+		t.Errorf("Vendor element not preserved: %+v", ve)
+	}
+	if len(fbd.Unknown) != 1 || fbd.Unknown[0].ElementName() != "sketch" || fbd.Unknown[0].ObjectID() != 3 {
+		t.Fatalf("Unknown children = %+v, want the sketch element", fbd.Unknown)
+	}
+
+	data, err := xml.Marshal(&fbd)
+	if err != nil {
+		t.Fatalf("Failed to marshal FBD body: %v", err)
+	}
+	var reparsed v201.BodyFBD
+	if err := xml.Unmarshal(data, &reparsed); err != nil {
+		t.Fatalf("Failed to unmarshal marshaled FBD body: %v\n%s", err, data)
+	}
+	var names []string
+	for _, obj := range reparsed.Objects() {
+		names = append(names, obj.ElementName())
+	}
+	if got := strings.Join(names, ","); got != "inVariable,vendorElement,sketch" {
+		t.Errorf("Objects after round trip = %s\n%s", got, data)
+	}
+	if !strings.Contains(string(data), `xmlns:v="urn:vendor"`) || !strings.Contains(string(data), `v:layer="2"`) || len(reparsed.VendorElements) != 1 ||
+		reparsed.VendorElements[0].AlternativeText.PlainText() != "PID controller" {
+		t.Errorf("Vendor element or unknown child lost on round trip:\n%s", data)
+	}
+
+	project := v201.Project{
+		Types: &v201.ProjectTypes{POUs: []v201.ProjectTypesPOU{{
+			Name: "Main", POUType: "program", Bodies: []v201.Body{{FBD: &fbd}},
+		}}},
+	}
+	downgraded, warnings := v201.Downgrade(&project)
+	if got := downgraded.Types.POUs[0].Body.FBD; got == nil || len(got.InVariables) != 1 {
+		t.Errorf("Known objects not downgraded: %+v", got)
+	}
+	const bodyPath = "project/types/pous/pou[@name='Main']/body[1]/FBD"
+	for _, want := range []v201.Warning{
+		{Path: bodyPath + "/vendorElement[@localId='2']", Message: "vendorElement dropped, V1.0B does not define it"},
+		{Path: bodyPath + "/sketch[@localId='3']", Message: "unknown element sketch dropped"},
+	} {
+		found := false
+		for _, w := range warnings {
+			if w == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("Missing warning %s\ngot: %v", want, warnings)
+		}
+	}
+}
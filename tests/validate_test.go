@@ -0,0 +1,302 @@
+package tests
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/suifei/plcopen-go/v2/v201"
+	"github.com/suifei/plcopen-go/v2/validate"
+)
+
+// validProjectXML is a minimal document that is valid against TC6_XML_V10_B.xsd
+const validProjectXML = `<?xml version="1.0" encoding="utf-8"?>
+<project xmlns="http://www.plcopen.org/xml/tc6.xsd" xmlns:xhtml="http://www.w3.org/1999/xhtml">
+  <fileHeader companyName="Test" productName="Test" productVersion="1.0" creationDateTime="2025-01-01T00:00:00"/>
+  <contentHeader name="Minimal">
+    <coordinateInfo><fbd><scaling x="1" y="1"/></fbd><ld><scaling x="1" y="1"/></ld><sfc><scaling x="1" y="1"/></sfc></coordinateInfo>
+  </contentHeader>
+  <types>
+    <dataTypes/>
+    <pous>
+      <pou name="Main" pouType="program">
+        <body>
+          <FBD>
+            <block localId="1" typeName="ADD" width="40" height="60">
+              <position x="0" y="0"/>
+              <inputVariables/><inOutVariables/><outputVariables/>
+            </block>
+          </FBD>
+        </body>
+      </pou>
+    </pous>
+  </types>
+  <instances><configurations/></instances>
+</project>
+`
+
+// TestValidateProject checks that generated projects pass the pure Go validator
+func TestValidateProject(t *testing.T) {
+	project := createComprehensiveProject()
+	if err := validate.Project(project); err != nil {
+		t.Fatalf("Project: %v", err)
+	}
+
+	var saved bytes.Buffer
+	if err := project.Save(&saved); err != nil {
+		t.Fatal(err)
+	}
+	if err := validate.Reader(&saved); err != nil {
+		t.Errorf("Reader: %v", err)
+	}
+	if err := validate.Bytes([]byte(validProjectXML)); err != nil {
+		t.Errorf("Bytes: %v", err)
+	}
+	agreeWithXmllint(t, validProjectXML, true)
+}
+
+// TestValidateErrors checks locations and messages of schema violations
+func TestValidateErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []validate.Error
+	}{
+		{
+			name: "missing attribute",
+			old:  `productVersion="1.0" `,
+			new:  ``,
+			want: []validate.Error{{Line: 3, Column: 3, Path: "/project/fileHeader/@productVersion", Message: `missing required attribute "productVersion"`}},
+		},
+		{
+			name: "invalid attribute value",
+			old:  `localId="1"`,
+			new:  `localId="-1"`,
+			want: []validate.Error{{Line: 13, Column: 13, Path: "/project/types/pous/pou[1]/body/FBD/block[1]/@localId", Message: "invalid value: -1 is less than the minimum 0"}},
+		},
+		{
+			name: "enumeration",
+			old:  `pouType="program"`,
+			new:  `pouType="task"`,
+			want: []validate.Error{{Line: 10, Column: 7, Path: "/project/types/pous/pou[1]/@pouType", Message: `invalid value: "task" is not one of "function", "functionBlock", "program"`}},
+		},
+		{
+			name: "unknown attribute",
+			old:  `typeName="ADD"`,
+			new:  `typeName="ADD" negated="true"`,
+			want: []validate.Error{{Line: 13, Column: 13, Path: "/project/types/pous/pou[1]/body/FBD/block[1]/@negated", Message: `attribute "negated" is not allowed`}},
+		},
+		{
+			name: "unexpected element",
+			old:  `<inputVariables/>`,
+			new:  `<inputVariables/><addData/>`,
+			want: []validate.Error{
+				{Line: 15, Column: 32, Path: "/project/types/pous/pou[1]/body/FBD/block[1]/addData", Message: `unexpected element "addData", expected "inOutVariables"`},
+			},
+		},
+		{
+			name: "missing element",
+			old:  `<inputVariables/><inOutVariables/><outputVariables/>`,
+			new:  `<inputVariables/><inOutVariables/>`,
+			want: []validate.Error{{Line: 16, Column: 13, Path: "/project/types/pous/pou[1]/body/FBD/block[1]", Message: `missing child element, expected "outputVariables"`}},
+		},
+		{
+			name: "text in element content",
+			old:  `<dataTypes/>`,
+			new:  `<dataTypes> x </dataTypes>`,
+			want: []validate.Error{{Line: 8, Column: 17, Path: "/project/types/dataTypes", Message: "text content is not allowed"}},
+		},
+		{
+			name: "simple content",
+			old:  `<contentHeader name="Minimal">`,
+			new:  `<contentHeader name="Minimal"><Comment>a<b/></Comment>`,
+			want: []validate.Error{{Line: 4, Column: 43, Path: "/project/contentHeader/Comment/b", Message: `unexpected element "b", element has simple content`}},
+		},
+		{
+			name: "syntax error",
+			old:  `</types>`,
+			new:  `</type>`,
+			want: []validate.Error{{Line: 21, Column: 10, Path: "/project/types", Message: "element <types> closed by </type>"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := strings.Replace(validProjectXML, tt.old, tt.new, 1)
+			if doc == validProjectXML {
+				t.Fatalf("Replacement %q not found", tt.old)
+			}
+			err := validate.Bytes([]byte(doc))
+			var errs validate.Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Bytes = %v, want validate.Errors", err)
+			}
+			if len(errs) != len(tt.want) {
+				t.Fatalf("Got %d errors, want %d: %v", len(errs), len(tt.want), errs)
+			}
+			for i, want := range tt.want {
+				if *errs[i] != want {
+					t.Errorf("Error %d = %+v, want %+v", i, *errs[i], want)
+				}
+			}
+			agreeWithXmllint(t, doc, false)
+		})
+	}
+}
+
+// TestValidateEncodings checks documents with byte order mark and non UTF-8 charset
+func TestValidateEncodings(t *testing.T) {
+	bom := append([]byte("\xEF\xBB\xBF"), validProjectXML...)
+	if err := validate.Bytes(bom); err != nil {
+		t.Errorf("UTF-8 BOM: %v", err)
+	}
+	gbk := strings.Replace(validProjectXML, `encoding="utf-8"`, `encoding="GBK"`, 1)
+	gbk = strings.Replace(gbk, `companyName="Test"`, "companyName=\"\xD6\xD0\xCE\xC4\"", 1)
+	if err := validate.Bytes([]byte(gbk)); err != nil {
+		t.Errorf("GBK: %v", err)
+	}
+}
+
+// TestValidateV201 checks 2.01 documents against the embedded 2.01 schema
+func TestValidateV201(t *testing.T) {
+	if err := validate.TC6V201().Validate(strings.NewReader(v201Project)); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+	upgraded, _ := v201.Upgrade(createComprehensiveProject())
+	if err := validate.V201Project(upgraded); err != nil {
+		t.Errorf("V201Project: %v", err)
+	}
+	// A V1.0B document is not a 2.01 document
+	err := validate.TC6V201().Validate(strings.NewReader(validProjectXML))
+	if err == nil || !strings.Contains(err.Error(), "no declaration for root element") {
+		t.Errorf("Validate V1.0B document = %v", err)
+	}
+}
+
+// TestEmbeddedSchemas checks that the schemas embedded by validate are the
+// files in docs/ and match the checksums recorded in docs/SCHEMAS.md
+func TestEmbeddedSchemas(t *testing.T) {
+	table, err := os.ReadFile(filepath.Join("..", "docs", "SCHEMAS.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"TC6_XML_V10_B.xsd", "TC6_XML_V201.xsd"} {
+		embedded, err := os.ReadFile(filepath.Join("..", "validate", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		docs, err := os.ReadFile(filepath.Join("..", "docs", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(embedded, docs) {
+			t.Errorf("validate/%s differs from docs/%s", name, name)
+		}
+		m := regexp.MustCompile("(?m)^\\| `" + regexp.QuoteMeta(name) + "` \\|.*`([0-9a-f]{64})` \\|$").FindSubmatch(table)
+		if m == nil {
+			t.Errorf("docs/SCHEMAS.md has no checksum for %s", name)
+			continue
+		}
+		if sum := fmt.Sprintf("%x", sha256.Sum256(docs)); sum != string(m[1]) {
+			t.Errorf("docs/%s has SHA-256 %s, docs/SCHEMAS.md records %s", name, sum, m[1])
+		}
+	}
+}
+
+// TestCompile checks compiling schemas besides the embedded one
+func TestCompile(t *testing.T) {
+	schema := `<xsd:schema xmlns:xsd="http://www.w3.org/2001/XMLSchema" targetNamespace="urn:t" xmlns:t="urn:t" elementFormDefault="qualified">
+  <xsd:element name="root">
+    <xsd:complexType>
+      <xsd:sequence>
+        <xsd:element name="item" type="t:code" maxOccurs="3"/>
+      </xsd:sequence>
+    </xsd:complexType>
+  </xsd:element>
+  <xsd:simpleType name="code">
+    <xsd:restriction base="xsd:string"><xsd:pattern value="[A-Z]{2}\d"/></xsd:restriction>
+  </xsd:simpleType>
+</xsd:schema>`
+	s, err := validate.Compile(strings.NewReader(schema))
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if err := s.Validate(strings.NewReader(`<root xmlns="urn:t"><item>AB1</item><item>CD2</item></root>`)); err != nil {
+		t.Errorf("Validate valid document: %v", err)
+	}
+	err = s.Validate(strings.NewReader(`<root xmlns="urn:t"><item>AB1</item><item>x</item><item>AB1</item><item>AB1</item></root>`))
+	if err == nil || !strings.Contains(err.Error(), `/root/item[2]: invalid value: "x" does not match the pattern [A-Z]{2}\d`) {
+		t.Errorf("Validate invalid document = %v", err)
+	}
+	if errs, ok := err.(validate.Errors); !ok || len(errs) != 2 || errs[1].Path != "/root/item[4]" {
+		t.Errorf("Validate invalid document = %v, want pattern and occurrence errors", err)
+	}
+
+	unsupported := strings.Replace(schema, `<xsd:restriction base="xsd:string">`, `<xsd:list itemType="xsd:string">`, 1)
+	unsupported = strings.Replace(unsupported, `</xsd:restriction>`, `</xsd:list>`, 1)
+	if _, err := validate.Compile(strings.NewReader(unsupported)); err == nil || !strings.Contains(err.Error(), "xsd:list is not supported") {
+		t.Errorf("Compile with xsd:list = %v, want an unsupported error", err)
+	}
+}
+
+var (
+	schemasMu sync.Mutex
+	schemas   = map[string]*validate.Schema{}
+)
+
+// validateWithGo validates xmlFile against xsdFile with the validate package
+func validateWithGo(t *testing.T, xmlFile, xsdFile string) {
+	t.Helper()
+	schemasMu.Lock()
+	s := schemas[xsdFile]
+	if s == nil {
+		f, err := os.Open(xsdFile)
+		if err != nil {
+			schemasMu.Unlock()
+			t.Logf("XSD file not available, skipping validation: %v", err)
+			return
+		}
+		s, err = validate.Compile(f)
+		f.Close()
+		if err != nil {
+			schemasMu.Unlock()
+			t.Fatalf("Failed to compile %s: %v", xsdFile, err)
+		}
+		schemas[xsdFile] = s
+	}
+	schemasMu.Unlock()
+
+	f, err := os.Open(xmlFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := s.Validate(f); err != nil {
+		t.Errorf("XML validation failed: %v", err)
+	}
+}
+
+// agreeWithXmllint checks that xmllint, if installed, agrees on the validity of doc
+func agreeWithXmllint(t *testing.T, doc string, valid bool) {
+	t.Helper()
+	if _, err := exec.LookPath("xmllint"); err != nil {
+		return
+	}
+	xmlFile := filepath.Join(t.TempDir(), "doc.xml")
+	if err := os.WriteFile(xmlFile, []byte(doc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	output, err := exec.Command("xmllint", "--schema", filepath.Join("..", "docs", "TC6_XML_V10_B.xsd"), "--noout", xmlFile).CombinedOutput()
+	if (err == nil) != valid {
+		t.Errorf("xmllint disagrees, valid = %v: %s", err == nil, output)
+	}
+}
@@ -11,8 +11,8 @@ import (
 	"testing"
 	"time"
 
-	"github.com/suifei/plcopen-go"
-	"github.com/suifei/plcopen-go/utils"
+	"github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/utils"
 )
 
 // TestXMLSchemaValidation validates generated XML against PLCopen XSD schema
@@ -162,7 +162,7 @@ func createTestProject() *plcopen.Project {
 					}, Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>counter := counter + 1;<xhtml:br/>status := counter &gt; 10;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>counter := counter + 1;<xhtml:br/>status := counter &gt; 10;</xhtml:p>"),
 						},
 					},
 				},
@@ -182,7 +182,7 @@ func createTestProject() *plcopen.Project {
 					}, Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>TestFunction := input1 &gt; 0;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>TestFunction := input1 &gt; 0;</xhtml:p>"),
 						},
 					},
 				},
@@ -346,7 +346,7 @@ func createComprehensiveProject() *plcopen.Project {
 					}, Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>counter := counter + 1;<xhtml:br/>outputStatus := counter &gt; 10;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>counter := counter + 1;<xhtml:br/>outputStatus := counter &gt; 10;</xhtml:p>"),
 						},
 					},
 				},
@@ -366,7 +366,7 @@ func createComprehensiveProject() *plcopen.Project {
 					}, Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>TestFunction := param1 &gt; 0;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>TestFunction := param1 &gt; 0;</xhtml:p>"),
 						},
 					},
 				},
@@ -429,7 +429,7 @@ func createComprehensiveProject() *plcopen.Project {
 					}, Body: &plcopen.Body{
 						ST: &plcopen.BodyST{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>done := enable;</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>done := enable;</xhtml:p>"),
 						},
 					},
 				},
@@ -556,6 +556,9 @@ func createComprehensiveProject() *plcopen.Project {
 
 // validateWithXmllint attempts to validate XML using xmllint command-line tool
 func validateWithXmllint(t *testing.T, xmlFile, xsdFile string) bool {
+	// The validate package does not depend on external tools
+	validateWithGo(t, xmlFile, xsdFile)
+
 	// Try xmllint validation (available on most Unix systems and some Windows installations)
 	cmd := exec.Command("xmllint", "--schema", xsdFile, "--noout", xmlFile)
 	output, err := cmd.CombinedOutput()
@@ -707,7 +710,7 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 				{
 					Name:          "BOOL_TYPE",
 					BaseType:      &plcopen.DataType{BOOL: &struct{}{}},
-					Documentation: []byte("<documentation>Boolean type</documentation>"),
+					Documentation: plcopen.NewFormattedText("Boolean type"),
 				},
 				{
 					Name:     "BYTE_TYPE",
@@ -856,13 +859,13 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 								{
 									Name:          "y",
 									Type:          &plcopen.DataType{REAL: &struct{}{}},
-									Documentation: []byte("<documentation>Y coordinate</documentation>"),
+									Documentation: plcopen.NewFormattedText("Y coordinate"),
 								},
 							},
-							Documentation: []byte("<documentation>Structure documentation</documentation>"),
+							Documentation: plcopen.NewFormattedText("Structure documentation"),
 						},
 					},
-					Documentation: []byte("<documentation>MyStruct type documentation</documentation>"),
+					Documentation: plcopen.NewFormattedText("MyStruct type documentation"),
 				},
 			},
 			POUs: []plcopen.ProjectTypesPOU{
@@ -887,7 +890,7 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 											Value: "0",
 										},
 									},
-									Documentation: []byte("<documentation>Data array</documentation>"),
+									Documentation: plcopen.NewFormattedText("Data array"),
 								},
 							},
 						},
@@ -991,7 +994,7 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 								{
 									LocalID:  5,
 									Position: &plcopen.Position{X: 75, Y: 75},
-									Content:  "This is a comment",
+									Content:  plcopen.NewFormattedText("This is a comment"),
 								},
 							},
 							Connectors: []plcopen.BodyFBDConnector{
@@ -1043,10 +1046,10 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 							Body: &plcopen.Body{
 								ST: &plcopen.BodyST{
 									XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-									Xhtml:      "<xhtml:p>done := TRUE;</xhtml:p>",
+									Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>done := TRUE;</xhtml:p>"),
 								},
 							},
-							Documentation: []byte("<documentation>Action documentation</documentation>"),
+							Documentation: plcopen.NewFormattedText("Action documentation"),
 						},
 					},
 					Transitions: []plcopen.ProjectTypesPOUTransition{
@@ -1055,13 +1058,13 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 							Body: &plcopen.Body{
 								ST: &plcopen.BodyST{
 									XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-									Xhtml:      "<xhtml:p>enable</xhtml:p>",
+									Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>enable</xhtml:p>"),
 								},
 							},
-							Documentation: []byte("<documentation>Transition documentation</documentation>"),
+							Documentation: plcopen.NewFormattedText("Transition documentation"),
 						},
 					},
-					Documentation: []byte("<documentation>Program documentation</documentation>"),
+					Documentation: plcopen.NewFormattedText("Program documentation"),
 				},
 				// Add a POU with SFC body
 				{
@@ -1102,7 +1105,7 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 											Body: &plcopen.Body{
 												ST: &plcopen.BodyST{
 													XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-													Xhtml:      "<xhtml:p>counter > 0</xhtml:p>",
+													Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>counter > 0</xhtml:p>"),
 												},
 											},
 										},
@@ -1129,7 +1132,7 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 					Body: &plcopen.Body{
 						IL: &plcopen.BodyIL{
 							XMLNSXhtml: "http://www.w3.org/1999/xhtml",
-							Xhtml:      "<xhtml:p>LD 10<xhtml:br/>ADD 5<xhtml:br/>ST result</xhtml:p>",
+							Xhtml:      plcopen.MustParseFormattedText("<xhtml:p>LD 10<xhtml:br/>ADD 5<xhtml:br/>ST result</xhtml:p>"),
 						},
 					},
 				},
@@ -1149,7 +1152,7 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 								},
 							},
 						},
-						Documentation: []byte("<documentation>Global variable documentation</documentation>"),
+						Documentation: plcopen.NewFormattedText("Global variable documentation"),
 					},
 					Resources: []plcopen.ProjectInstancesConfigurationResource{
 						{
@@ -1180,13 +1183,13 @@ func TestComplexXMLSchemaValidation(t *testing.T) {
 								{
 									Name:          "MainInstance",
 									TypeName:      "SFCProgram",
-									Documentation: []byte("<documentation>Instance documentation</documentation>"),
+									Documentation: plcopen.NewFormattedText("Instance documentation"),
 								},
 							},
-							Documentation: []byte("<documentation>Resource documentation</documentation>"),
+							Documentation: plcopen.NewFormattedText("Resource documentation"),
 						},
 					},
-					Documentation: []byte("<documentation>Configuration documentation</documentation>"),
+					Documentation: plcopen.NewFormattedText("Configuration documentation"),
 				},
 			},
 		},
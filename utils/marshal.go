@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"os"
@@ -8,13 +9,26 @@ import (
 
 // JSON 核心函数
 // 序列化 any → JSON（无缩进）
+// 不转义 <、>、&，程序代码中的比较运算符保持可读
 func ToJSON(v any) ([]byte, error) {
-	return json.Marshal(v)
+	return encodeJSON(v, "")
 }
 
 // 序列化 any → JSON（带缩进）
 func ToJSONIndent(v any) ([]byte, error) {
-	return json.MarshalIndent(v, "", "  ")
+	return encodeJSON(v, "  ")
+}
+
+// encodeJSON 使用不转义 HTML 字符的 Encoder 序列化，去掉末尾换行
+func encodeJSON(v any, indent string) ([]byte, error) {
+	var buf bytes.Buffer
+	e := json.NewEncoder(&buf)
+	e.SetEscapeHTML(false)
+	e.SetIndent("", indent)
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
 }
 
 // 反序列化 JSON → 结构体（泛型）
@@ -0,0 +1,809 @@
+package v201
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// GraphicalObject is implemented by every object that can appear in a FBD, LD
+// or SFC body, see plcopen.GraphicalObject
+type GraphicalObject = plcopen.GraphicalObject
+
+// objectKey identifies an object within a body for order tracking
+type objectKey struct {
+	name    string
+	localID uint64
+}
+
+// objectContainer is implemented by the graphical bodies
+type objectContainer interface {
+	groupedObjects() []GraphicalObject
+	newObject(name string) GraphicalObject
+	newUnknown() *UnknownObject
+}
+
+// decodeObjects decodes the children of start into c and returns the document
+// order of the decoded objects. Elements the body does not allow are kept as
+// UnknownObject.
+func decodeObjects(d *xml.Decoder, start xml.StartElement, c objectContainer) ([]objectKey, error) {
+	var order []objectKey
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			obj := c.newObject(t.Name.Local)
+			if obj == nil {
+				obj = c.newUnknown()
+			}
+			if err := d.DecodeElement(obj, &t); err != nil {
+				return nil, err
+			}
+			order = append(order, objectKey{name: obj.ElementName(), localID: obj.ObjectID()})
+		case xml.EndElement:
+			if t.Name == start.Name {
+				return order, nil
+			}
+		}
+	}
+}
+
+// encodeObjects writes objs as children of start
+func encodeObjects(e *xml.Encoder, start xml.StartElement, objs []GraphicalObject) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if err := e.EncodeElement(obj, xml.StartElement{Name: xml.Name{Local: obj.ElementName()}}); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// orderObjects sorts objs by the recorded document order. Objects that were
+// not part of the decoded document keep their grouped order after the others.
+func orderObjects(objs []GraphicalObject, order []objectKey) []GraphicalObject {
+	if len(order) == 0 {
+		return objs
+	}
+	positions := make(map[objectKey][]int, len(order))
+	for i, key := range order {
+		positions[key] = append(positions[key], i)
+	}
+	rank := make([]int, len(objs))
+	for i, obj := range objs {
+		key := objectKey{name: obj.ElementName(), localID: obj.ObjectID()}
+		if queue := positions[key]; len(queue) > 0 {
+			rank[i] = queue[0]
+			positions[key] = queue[1:]
+		} else {
+			rank[i] = len(order) + i
+		}
+	}
+	indices := make([]int, len(objs))
+	for i := range indices {
+		indices[i] = i
+	}
+	sort.SliceStable(indices, func(a, b int) bool { return rank[indices[a]] < rank[indices[b]] })
+	sorted := make([]GraphicalObject, len(objs))
+	for i, idx := range indices {
+		sorted[i] = objs[idx]
+	}
+	return sorted
+}
+
+// setObjects copies objs into c in the given order
+func setObjects(c objectContainer, objs []GraphicalObject) ([]objectKey, error) {
+	order := make([]objectKey, 0, len(objs))
+	for _, obj := range objs {
+		var dst GraphicalObject
+		if _, ok := obj.(*UnknownObject); ok {
+			dst = c.newUnknown()
+		} else {
+			dst = c.newObject(obj.ElementName())
+		}
+		if dst == nil || reflect.TypeOf(dst) != reflect.TypeOf(obj) {
+			return nil, fmt.Errorf("plcopen: %s (%T) is not allowed in this body", obj.ElementName(), obj)
+		}
+		reflect.ValueOf(dst).Elem().Set(reflect.ValueOf(obj).Elem())
+		order = append(order, objectKey{name: obj.ElementName(), localID: obj.ObjectID()})
+	}
+	return order, nil
+}
+
+// Objects returns pointers to the objects of the FBD body in document order.
+// Objects added after decoding are returned after the decoded ones.
+func (b *BodyFBD) Objects() []GraphicalObject {
+	return orderObjects(b.groupedObjects(), b.order)
+}
+
+// SetObjects replaces the content of the FBD body with objs, keeping their order
+// when the body is marshaled
+func (b *BodyFBD) SetObjects(objs []GraphicalObject) error {
+	var fresh BodyFBD
+	order, err := setObjects(&fresh, objs)
+	if err != nil {
+		return err
+	}
+	*b = fresh
+	b.order = order
+	return nil
+}
+
+// UnmarshalXML decodes the FBD body and records the document order of its objects
+func (b *BodyFBD) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var fresh BodyFBD
+	order, err := decodeObjects(d, start, &fresh)
+	if err != nil {
+		return err
+	}
+	*b = fresh
+	b.order = order
+	return nil
+}
+
+// MarshalXML encodes the FBD body, preserving the document order of decoded objects
+func (b BodyFBD) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeObjects(e, start, b.Objects())
+}
+
+func (b *BodyFBD) groupedObjects() []GraphicalObject {
+	var objs []GraphicalObject
+	for i := range b.Comments {
+		objs = append(objs, &b.Comments[i])
+	}
+	for i := range b.Errors {
+		objs = append(objs, &b.Errors[i])
+	}
+	for i := range b.Connectors {
+		objs = append(objs, &b.Connectors[i])
+	}
+	for i := range b.Continuations {
+		objs = append(objs, &b.Continuations[i])
+	}
+	for i := range b.ActionBlocks {
+		objs = append(objs, &b.ActionBlocks[i])
+	}
+	for i := range b.VendorElements {
+		objs = append(objs, &b.VendorElements[i])
+	}
+	for i := range b.Blocks {
+		objs = append(objs, &b.Blocks[i])
+	}
+	for i := range b.InVariables {
+		objs = append(objs, &b.InVariables[i])
+	}
+	for i := range b.OutVariables {
+		objs = append(objs, &b.OutVariables[i])
+	}
+	for i := range b.InOutVariables {
+		objs = append(objs, &b.InOutVariables[i])
+	}
+	for i := range b.Labels {
+		objs = append(objs, &b.Labels[i])
+	}
+	for i := range b.Jumps {
+		objs = append(objs, &b.Jumps[i])
+	}
+	for i := range b.Returns {
+		objs = append(objs, &b.Returns[i])
+	}
+	for i := range b.Unknown {
+		objs = append(objs, &b.Unknown[i])
+	}
+	return objs
+}
+
+func (b *BodyFBD) newObject(name string) GraphicalObject {
+	switch name {
+	case "comment":
+		b.Comments = append(b.Comments, BodyFBDComment{})
+		return &b.Comments[len(b.Comments)-1]
+	case "error":
+		b.Errors = append(b.Errors, BodyFBDError{})
+		return &b.Errors[len(b.Errors)-1]
+	case "connector":
+		b.Connectors = append(b.Connectors, BodyFBDConnector{})
+		return &b.Connectors[len(b.Connectors)-1]
+	case "continuation":
+		b.Continuations = append(b.Continuations, BodyFBDContinuation{})
+		return &b.Continuations[len(b.Continuations)-1]
+	case "actionBlock":
+		b.ActionBlocks = append(b.ActionBlocks, BodyFBDActionBlock{})
+		return &b.ActionBlocks[len(b.ActionBlocks)-1]
+	case "vendorElement":
+		b.VendorElements = append(b.VendorElements, BodyFBDVendorElement{})
+		return &b.VendorElements[len(b.VendorElements)-1]
+	case "block":
+		b.Blocks = append(b.Blocks, BodyFBDBlock{})
+		return &b.Blocks[len(b.Blocks)-1]
+	case "inVariable":
+		b.InVariables = append(b.InVariables, BodyFBDInVariable{})
+		return &b.InVariables[len(b.InVariables)-1]
+	case "outVariable":
+		b.OutVariables = append(b.OutVariables, BodyFBDOutVariable{})
+		return &b.OutVariables[len(b.OutVariables)-1]
+	case "inOutVariable":
+		b.InOutVariables = append(b.InOutVariables, BodyFBDInOutVariable{})
+		return &b.InOutVariables[len(b.InOutVariables)-1]
+	case "label":
+		b.Labels = append(b.Labels, BodyFBDLabel{})
+		return &b.Labels[len(b.Labels)-1]
+	case "jump":
+		b.Jumps = append(b.Jumps, BodyFBDJump{})
+		return &b.Jumps[len(b.Jumps)-1]
+	case "return":
+		b.Returns = append(b.Returns, BodyFBDReturn{})
+		return &b.Returns[len(b.Returns)-1]
+	}
+	return nil
+}
+
+func (b *BodyFBD) newUnknown() *UnknownObject {
+	b.Unknown = append(b.Unknown, UnknownObject{})
+	return &b.Unknown[len(b.Unknown)-1]
+}
+
+// Objects returns pointers to the objects of the LD body in document order.
+// Objects added after decoding are returned after the decoded ones.
+func (b *BodyLD) Objects() []GraphicalObject {
+	return orderObjects(b.groupedObjects(), b.order)
+}
+
+// SetObjects replaces the content of the LD body with objs, keeping their order
+// when the body is marshaled
+func (b *BodyLD) SetObjects(objs []GraphicalObject) error {
+	var fresh BodyLD
+	order, err := setObjects(&fresh, objs)
+	if err != nil {
+		return err
+	}
+	*b = fresh
+	b.order = order
+	return nil
+}
+
+// UnmarshalXML decodes the LD body and records the document order of its objects
+func (b *BodyLD) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var fresh BodyLD
+	order, err := decodeObjects(d, start, &fresh)
+	if err != nil {
+		return err
+	}
+	*b = fresh
+	b.order = order
+	return nil
+}
+
+// MarshalXML encodes the LD body, preserving the document order of decoded objects
+func (b BodyLD) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeObjects(e, start, b.Objects())
+}
+
+func (b *BodyLD) groupedObjects() []GraphicalObject {
+	var objs []GraphicalObject
+	for i := range b.Comments {
+		objs = append(objs, &b.Comments[i])
+	}
+	for i := range b.Errors {
+		objs = append(objs, &b.Errors[i])
+	}
+	for i := range b.Connectors {
+		objs = append(objs, &b.Connectors[i])
+	}
+	for i := range b.Continuations {
+		objs = append(objs, &b.Continuations[i])
+	}
+	for i := range b.ActionBlocks {
+		objs = append(objs, &b.ActionBlocks[i])
+	}
+	for i := range b.VendorElements {
+		objs = append(objs, &b.VendorElements[i])
+	}
+	for i := range b.Blocks {
+		objs = append(objs, &b.Blocks[i])
+	}
+	for i := range b.InVariables {
+		objs = append(objs, &b.InVariables[i])
+	}
+	for i := range b.OutVariables {
+		objs = append(objs, &b.OutVariables[i])
+	}
+	for i := range b.InOutVariables {
+		objs = append(objs, &b.InOutVariables[i])
+	}
+	for i := range b.Labels {
+		objs = append(objs, &b.Labels[i])
+	}
+	for i := range b.Jumps {
+		objs = append(objs, &b.Jumps[i])
+	}
+	for i := range b.Returns {
+		objs = append(objs, &b.Returns[i])
+	}
+	for i := range b.LeftPowerRails {
+		objs = append(objs, &b.LeftPowerRails[i])
+	}
+	for i := range b.RightPowerRails {
+		objs = append(objs, &b.RightPowerRails[i])
+	}
+	for i := range b.Coils {
+		objs = append(objs, &b.Coils[i])
+	}
+	for i := range b.Contacts {
+		objs = append(objs, &b.Contacts[i])
+	}
+	for i := range b.Unknown {
+		objs = append(objs, &b.Unknown[i])
+	}
+	return objs
+}
+
+func (b *BodyLD) newObject(name string) GraphicalObject {
+	switch name {
+	case "comment":
+		b.Comments = append(b.Comments, BodyFBDComment{})
+		return &b.Comments[len(b.Comments)-1]
+	case "error":
+		b.Errors = append(b.Errors, BodyFBDError{})
+		return &b.Errors[len(b.Errors)-1]
+	case "connector":
+		b.Connectors = append(b.Connectors, BodyFBDConnector{})
+		return &b.Connectors[len(b.Connectors)-1]
+	case "continuation":
+		b.Continuations = append(b.Continuations, BodyFBDContinuation{})
+		return &b.Continuations[len(b.Continuations)-1]
+	case "actionBlock":
+		b.ActionBlocks = append(b.ActionBlocks, BodyFBDActionBlock{})
+		return &b.ActionBlocks[len(b.ActionBlocks)-1]
+	case "vendorElement":
+		b.VendorElements = append(b.VendorElements, BodyFBDVendorElement{})
+		return &b.VendorElements[len(b.VendorElements)-1]
+	case "block":
+		b.Blocks = append(b.Blocks, BodyFBDBlock{})
+		return &b.Blocks[len(b.Blocks)-1]
+	case "inVariable":
+		b.InVariables = append(b.InVariables, BodyFBDInVariable{})
+		return &b.InVariables[len(b.InVariables)-1]
+	case "outVariable":
+		b.OutVariables = append(b.OutVariables, BodyFBDOutVariable{})
+		return &b.OutVariables[len(b.OutVariables)-1]
+	case "inOutVariable":
+		b.InOutVariables = append(b.InOutVariables, BodyFBDInOutVariable{})
+		return &b.InOutVariables[len(b.InOutVariables)-1]
+	case "label":
+		b.Labels = append(b.Labels, BodyFBDLabel{})
+		return &b.Labels[len(b.Labels)-1]
+	case "jump":
+		b.Jumps = append(b.Jumps, BodyFBDJump{})
+		return &b.Jumps[len(b.Jumps)-1]
+	case "return":
+		b.Returns = append(b.Returns, BodyFBDReturn{})
+		return &b.Returns[len(b.Returns)-1]
+	case "leftPowerRail":
+		b.LeftPowerRails = append(b.LeftPowerRails, BodyLDLeftPowerRail{})
+		return &b.LeftPowerRails[len(b.LeftPowerRails)-1]
+	case "rightPowerRail":
+		b.RightPowerRails = append(b.RightPowerRails, BodyLDRightPowerRail{})
+		return &b.RightPowerRails[len(b.RightPowerRails)-1]
+	case "coil":
+		b.Coils = append(b.Coils, BodyLDCoil{})
+		return &b.Coils[len(b.Coils)-1]
+	case "contact":
+		b.Contacts = append(b.Contacts, BodyLDContact{})
+		return &b.Contacts[len(b.Contacts)-1]
+	}
+	return nil
+}
+
+func (b *BodyLD) newUnknown() *UnknownObject {
+	b.Unknown = append(b.Unknown, UnknownObject{})
+	return &b.Unknown[len(b.Unknown)-1]
+}
+
+// Objects returns pointers to the objects of the SFC body in document order.
+// Objects added after decoding are returned after the decoded ones.
+func (b *BodySFC) Objects() []GraphicalObject {
+	return orderObjects(b.groupedObjects(), b.order)
+}
+
+// SetObjects replaces the content of the SFC body with objs, keeping their order
+// when the body is marshaled
+func (b *BodySFC) SetObjects(objs []GraphicalObject) error {
+	var fresh BodySFC
+	order, err := setObjects(&fresh, objs)
+	if err != nil {
+		return err
+	}
+	*b = fresh
+	b.order = order
+	return nil
+}
+
+// UnmarshalXML decodes the SFC body and records the document order of its objects
+func (b *BodySFC) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var fresh BodySFC
+	order, err := decodeObjects(d, start, &fresh)
+	if err != nil {
+		return err
+	}
+	*b = fresh
+	b.order = order
+	return nil
+}
+
+// MarshalXML encodes the SFC body, preserving the document order of decoded objects
+func (b BodySFC) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return encodeObjects(e, start, b.Objects())
+}
+
+func (b *BodySFC) groupedObjects() []GraphicalObject {
+	var objs []GraphicalObject
+	for i := range b.Comments {
+		objs = append(objs, &b.Comments[i])
+	}
+	for i := range b.Errors {
+		objs = append(objs, &b.Errors[i])
+	}
+	for i := range b.Connectors {
+		objs = append(objs, &b.Connectors[i])
+	}
+	for i := range b.Continuations {
+		objs = append(objs, &b.Continuations[i])
+	}
+	for i := range b.ActionBlocks {
+		objs = append(objs, &b.ActionBlocks[i])
+	}
+	for i := range b.VendorElements {
+		objs = append(objs, &b.VendorElements[i])
+	}
+	for i := range b.Blocks {
+		objs = append(objs, &b.Blocks[i])
+	}
+	for i := range b.InVariables {
+		objs = append(objs, &b.InVariables[i])
+	}
+	for i := range b.OutVariables {
+		objs = append(objs, &b.OutVariables[i])
+	}
+	for i := range b.InOutVariables {
+		objs = append(objs, &b.InOutVariables[i])
+	}
+	for i := range b.Labels {
+		objs = append(objs, &b.Labels[i])
+	}
+	for i := range b.Jumps {
+		objs = append(objs, &b.Jumps[i])
+	}
+	for i := range b.Returns {
+		objs = append(objs, &b.Returns[i])
+	}
+	for i := range b.LeftPowerRails {
+		objs = append(objs, &b.LeftPowerRails[i])
+	}
+	for i := range b.RightPowerRails {
+		objs = append(objs, &b.RightPowerRails[i])
+	}
+	for i := range b.Coils {
+		objs = append(objs, &b.Coils[i])
+	}
+	for i := range b.Contacts {
+		objs = append(objs, &b.Contacts[i])
+	}
+	for i := range b.Steps {
+		objs = append(objs, &b.Steps[i])
+	}
+	for i := range b.MacroSteps {
+		objs = append(objs, &b.MacroSteps[i])
+	}
+	for i := range b.JumpSteps {
+		objs = append(objs, &b.JumpSteps[i])
+	}
+	for i := range b.Transitions {
+		objs = append(objs, &b.Transitions[i])
+	}
+	for i := range b.SelectionDivergences {
+		objs = append(objs, &b.SelectionDivergences[i])
+	}
+	for i := range b.SelectionConvergences {
+		objs = append(objs, &b.SelectionConvergences[i])
+	}
+	for i := range b.SimultaneousDivergences {
+		objs = append(objs, &b.SimultaneousDivergences[i])
+	}
+	for i := range b.SimultaneousConvergences {
+		objs = append(objs, &b.SimultaneousConvergences[i])
+	}
+	for i := range b.Unknown {
+		objs = append(objs, &b.Unknown[i])
+	}
+	return objs
+}
+
+func (b *BodySFC) newObject(name string) GraphicalObject {
+	switch name {
+	case "comment":
+		b.Comments = append(b.Comments, BodyFBDComment{})
+		return &b.Comments[len(b.Comments)-1]
+	case "error":
+		b.Errors = append(b.Errors, BodyFBDError{})
+		return &b.Errors[len(b.Errors)-1]
+	case "connector":
+		b.Connectors = append(b.Connectors, BodyFBDConnector{})
+		return &b.Connectors[len(b.Connectors)-1]
+	case "continuation":
+		b.Continuations = append(b.Continuations, BodyFBDContinuation{})
+		return &b.Continuations[len(b.Continuations)-1]
+	case "actionBlock":
+		b.ActionBlocks = append(b.ActionBlocks, BodyFBDActionBlock{})
+		return &b.ActionBlocks[len(b.ActionBlocks)-1]
+	case "vendorElement":
+		b.VendorElements = append(b.VendorElements, BodyFBDVendorElement{})
+		return &b.VendorElements[len(b.VendorElements)-1]
+	case "block":
+		b.Blocks = append(b.Blocks, BodyFBDBlock{})
+		return &b.Blocks[len(b.Blocks)-1]
+	case "inVariable":
+		b.InVariables = append(b.InVariables, BodyFBDInVariable{})
+		return &b.InVariables[len(b.InVariables)-1]
+	case "outVariable":
+		b.OutVariables = append(b.OutVariables, BodyFBDOutVariable{})
+		return &b.OutVariables[len(b.OutVariables)-1]
+	case "inOutVariable":
+		b.InOutVariables = append(b.InOutVariables, BodyFBDInOutVariable{})
+		return &b.InOutVariables[len(b.InOutVariables)-1]
+	case "label":
+		b.Labels = append(b.Labels, BodyFBDLabel{})
+		return &b.Labels[len(b.Labels)-1]
+	case "jump":
+		b.Jumps = append(b.Jumps, BodyFBDJump{})
+		return &b.Jumps[len(b.Jumps)-1]
+	case "return":
+		b.Returns = append(b.Returns, BodyFBDReturn{})
+		return &b.Returns[len(b.Returns)-1]
+	case "leftPowerRail":
+		b.LeftPowerRails = append(b.LeftPowerRails, BodyLDLeftPowerRail{})
+		return &b.LeftPowerRails[len(b.LeftPowerRails)-1]
+	case "rightPowerRail":
+		b.RightPowerRails = append(b.RightPowerRails, BodyLDRightPowerRail{})
+		return &b.RightPowerRails[len(b.RightPowerRails)-1]
+	case "coil":
+		b.Coils = append(b.Coils, BodyLDCoil{})
+		return &b.Coils[len(b.Coils)-1]
+	case "contact":
+		b.Contacts = append(b.Contacts, BodyLDContact{})
+		return &b.Contacts[len(b.Contacts)-1]
+	case "step":
+		b.Steps = append(b.Steps, BodySFCStep{})
+		return &b.Steps[len(b.Steps)-1]
+	case "macroStep":
+		b.MacroSteps = append(b.MacroSteps, BodySFCMacroStep{})
+		return &b.MacroSteps[len(b.MacroSteps)-1]
+	case "jumpStep":
+		b.JumpSteps = append(b.JumpSteps, BodySFCJumpStep{})
+		return &b.JumpSteps[len(b.JumpSteps)-1]
+	case "transition":
+		b.Transitions = append(b.Transitions, BodySFCTransition{})
+		return &b.Transitions[len(b.Transitions)-1]
+	case "selectionDivergence":
+		b.SelectionDivergences = append(b.SelectionDivergences, BodySFCSelectionDivergence{})
+		return &b.SelectionDivergences[len(b.SelectionDivergences)-1]
+	case "selectionConvergence":
+		b.SelectionConvergences = append(b.SelectionConvergences, BodySFCSelectionConvergence{})
+		return &b.SelectionConvergences[len(b.SelectionConvergences)-1]
+	case "simultaneousDivergence":
+		b.SimultaneousDivergences = append(b.SimultaneousDivergences, BodySFCSimultaneousDivergence{})
+		return &b.SimultaneousDivergences[len(b.SimultaneousDivergences)-1]
+	case "simultaneousConvergence":
+		b.SimultaneousConvergences = append(b.SimultaneousConvergences, BodySFCSimultaneousConvergence{})
+		return &b.SimultaneousConvergences[len(b.SimultaneousConvergences)-1]
+	}
+	return nil
+}
+
+func (b *BodySFC) newUnknown() *UnknownObject {
+	b.Unknown = append(b.Unknown, UnknownObject{})
+	return &b.Unknown[len(b.Unknown)-1]
+}
+
+// ElementName returns "comment"
+func (o *BodyFBDComment) ElementName() string { return "comment" }
+
+// ObjectID returns the localId of the comment
+func (o *BodyFBDComment) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "error"
+func (o *BodyFBDError) ElementName() string { return "error" }
+
+// ObjectID returns the localId of the error
+func (o *BodyFBDError) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "connector"
+func (o *BodyFBDConnector) ElementName() string { return "connector" }
+
+// ObjectID returns the localId of the connector
+func (o *BodyFBDConnector) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "continuation"
+func (o *BodyFBDContinuation) ElementName() string { return "continuation" }
+
+// ObjectID returns the localId of the continuation
+func (o *BodyFBDContinuation) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "actionBlock"
+func (o *BodyFBDActionBlock) ElementName() string { return "actionBlock" }
+
+// ObjectID returns the localId of the action block
+func (o *BodyFBDActionBlock) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "vendorElement"
+func (o *BodyFBDVendorElement) ElementName() string { return "vendorElement" }
+
+// ObjectID returns the localId of the vendor element
+func (o *BodyFBDVendorElement) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns the local name of the element
+func (o *UnknownObject) ElementName() string { return o.XMLName.Local }
+
+// ObjectID returns the localId attribute of the element, or 0 if it has none
+func (o *UnknownObject) ObjectID() uint64 {
+	for _, attr := range o.Attrs {
+		if attr.Name.Space == "" && attr.Name.Local == "localId" {
+			id, _ := strconv.ParseUint(attr.Value, 10, 64)
+			return id
+		}
+	}
+	return 0
+}
+
+// MarshalXML writes the element with its attributes and raw content
+func (o UnknownObject) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{Name: o.XMLName}
+	if start.Name.Space == Namespace {
+		// Inherited from the body
+		start.Name.Space = ""
+	}
+	// Keep the prefixes declared on the element, which the raw content may use
+	prefixes := map[string]string{}
+	for _, attr := range o.Attrs {
+		if attr.Name.Space == "xmlns" {
+			prefixes[attr.Value] = attr.Name.Local
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "xmlns:" + attr.Name.Local}, Value: attr.Value})
+		}
+	}
+	for _, attr := range o.Attrs {
+		switch {
+		case attr.Name.Space == "xmlns", attr.Name.Space == "" && attr.Name.Local == "xmlns":
+			// The default namespace is declared by the encoder
+		case prefixes[attr.Name.Space] != "":
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: prefixes[attr.Name.Space] + ":" + attr.Name.Local}, Value: attr.Value})
+		default:
+			start.Attr = append(start.Attr, attr)
+		}
+	}
+	return e.EncodeElement(struct {
+		InnerXML string `xml:",innerxml"`
+	}{o.InnerXML}, start)
+}
+
+// ElementName returns "block"
+func (o *BodyFBDBlock) ElementName() string { return "block" }
+
+// ObjectID returns the localId of the block
+func (o *BodyFBDBlock) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "inVariable"
+func (o *BodyFBDInVariable) ElementName() string { return "inVariable" }
+
+// ObjectID returns the localId of the variable
+func (o *BodyFBDInVariable) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "outVariable"
+func (o *BodyFBDOutVariable) ElementName() string { return "outVariable" }
+
+// ObjectID returns the localId of the variable
+func (o *BodyFBDOutVariable) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "inOutVariable"
+func (o *BodyFBDInOutVariable) ElementName() string { return "inOutVariable" }
+
+// ObjectID returns the localId of the variable
+func (o *BodyFBDInOutVariable) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "label"
+func (o *BodyFBDLabel) ElementName() string { return "label" }
+
+// ObjectID returns the localId of the label
+func (o *BodyFBDLabel) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "jump"
+func (o *BodyFBDJump) ElementName() string { return "jump" }
+
+// ObjectID returns the localId of the jump
+func (o *BodyFBDJump) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "return"
+func (o *BodyFBDReturn) ElementName() string { return "return" }
+
+// ObjectID returns the localId of the return
+func (o *BodyFBDReturn) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "leftPowerRail"
+func (o *BodyLDLeftPowerRail) ElementName() string { return "leftPowerRail" }
+
+// ObjectID returns the localId of the power rail
+func (o *BodyLDLeftPowerRail) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "rightPowerRail"
+func (o *BodyLDRightPowerRail) ElementName() string { return "rightPowerRail" }
+
+// ObjectID returns the localId of the power rail
+func (o *BodyLDRightPowerRail) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "coil"
+func (o *BodyLDCoil) ElementName() string { return "coil" }
+
+// ObjectID returns the localId of the coil
+func (o *BodyLDCoil) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "contact"
+func (o *BodyLDContact) ElementName() string { return "contact" }
+
+// ObjectID returns the localId of the contact
+func (o *BodyLDContact) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "step"
+func (o *BodySFCStep) ElementName() string { return "step" }
+
+// ObjectID returns the localId of the step
+func (o *BodySFCStep) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "macroStep"
+func (o *BodySFCMacroStep) ElementName() string { return "macroStep" }
+
+// ObjectID returns the localId of the macro step
+func (o *BodySFCMacroStep) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "jumpStep"
+func (o *BodySFCJumpStep) ElementName() string { return "jumpStep" }
+
+// ObjectID returns the localId of the jump step
+func (o *BodySFCJumpStep) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "transition"
+func (o *BodySFCTransition) ElementName() string { return "transition" }
+
+// ObjectID returns the localId of the transition
+func (o *BodySFCTransition) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "selectionDivergence"
+func (o *BodySFCSelectionDivergence) ElementName() string { return "selectionDivergence" }
+
+// ObjectID returns the localId of the divergence
+func (o *BodySFCSelectionDivergence) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "selectionConvergence"
+func (o *BodySFCSelectionConvergence) ElementName() string { return "selectionConvergence" }
+
+// ObjectID returns the localId of the convergence
+func (o *BodySFCSelectionConvergence) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "simultaneousDivergence"
+func (o *BodySFCSimultaneousDivergence) ElementName() string { return "simultaneousDivergence" }
+
+// ObjectID returns the localId of the divergence
+func (o *BodySFCSimultaneousDivergence) ObjectID() uint64 { return o.LocalID }
+
+// ElementName returns "simultaneousConvergence"
+func (o *BodySFCSimultaneousConvergence) ElementName() string { return "simultaneousConvergence" }
+
+// ObjectID returns the localId of the convergence
+func (o *BodySFCSimultaneousConvergence) ObjectID() uint64 { return o.LocalID }
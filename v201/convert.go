@@ -0,0 +1,76 @@
+package v201
+
+import (
+	"fmt"
+)
+
+// Warning reports a part of a document that could not be represented exactly
+// in the target version of a conversion
+type Warning struct {
+	// Path locates the element in the source document, e.g.
+	// "project/types/pous/pou[@name='Main']/body/FBD/block[@localId='3']"
+	Path    string
+	Message string
+}
+
+// String returns the warning as "path: message"
+func (w Warning) String() string {
+	return w.Path + ": " + w.Message
+}
+
+// converter collects the warnings of a conversion
+type converter struct {
+	warnings []Warning
+}
+
+func (c *converter) warn(path, format string, args ...interface{}) {
+	c.warnings = append(c.warnings, Warning{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// named returns the path of the child elem of path with the given name attribute
+func named(path, elem, name string) string {
+	return fmt.Sprintf("%s/%s[@name='%s']", path, elem, name)
+}
+
+// indexed returns the path of the i-th (zero based) child elem of path
+func indexed(path, elem string, i int) string {
+	return fmt.Sprintf("%s/%s[%d]", path, elem, i+1)
+}
+
+// local returns the path of the graphical object obj within the body at path
+func local(path string, obj GraphicalObject) string {
+	return fmt.Sprintf("%s/%s[@localId='%d']", path, obj.ElementName(), obj.ObjectID())
+}
+
+// objectSetter is implemented by the graphical bodies of both versions
+type objectSetter interface {
+	SetObjects(objs []GraphicalObject) error
+}
+
+// setObjects stores objs in body, reporting objects the body does not allow
+func (c *converter) setObjects(path string, body objectSetter, objs []GraphicalObject) {
+	if err := body.SetObjects(objs); err != nil {
+		// Whether the body allows an object does not depend on the others,
+		// so each object is tried on its own and the accepted ones are kept
+		var kept []GraphicalObject
+		for _, obj := range objs {
+			if body.SetObjects([]GraphicalObject{obj}) != nil {
+				c.warn(local(path, obj), "%s is not allowed in this body and was dropped", obj.ElementName())
+				continue
+			}
+			kept = append(kept, obj)
+		}
+		body.SetObjects(kept)
+	}
+}
+
+func deref(f *float64) float64 {
+	if f == nil {
+		return 0
+	}
+	return *f
+}
+
+func stringPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,147 @@
+package v201
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// Namespace200 is the XML namespace of TC6 XML 2.0 documents. Version 2.01
+// only fixed errors of 2.0, so such documents are decoded into Project too.
+const Namespace200 = "http://www.plcopen.org/xml/tc6_0200"
+
+// Namespace10 is the XML namespace of TC6 XML V1.0B documents
+const Namespace10 = plcopen.Namespace
+
+// Version identifies the TC6 XML version of a document
+type Version string
+
+const (
+	Version10  Version = "1.0"
+	Version200 Version = "2.0"
+	Version201 Version = "2.01"
+)
+
+// Document is a decoded TC6 XML document of any supported version. Exactly
+// one of V10 and V201 is set, depending on Version.
+type Document struct {
+	Version Version
+	V10     *plcopen.Project
+	V201    *Project
+}
+
+func init() {
+	plcopen.RegisterNamespace(Namespace, load)
+	plcopen.RegisterNamespace(Namespace200, load)
+}
+
+// load decodes a 2.0 or 2.01 project for plcopen.Load and downgrades it
+func load(d *xml.Decoder, start xml.StartElement, warn func(path, message string)) (*plcopen.Project, error) {
+	project, err := decodeProject(d, start)
+	if err != nil {
+		return nil, err
+	}
+	downgraded, warnings := Downgrade(project)
+	for _, w := range warnings {
+		warn(w.Path, w.Message)
+	}
+	return downgraded, nil
+}
+
+// decodeProject decodes the 2.0 or 2.01 project starting at start. The
+// elements of 2.0 documents are moved into the 2.01 namespace.
+func decodeProject(d *xml.Decoder, start xml.StartElement) (*Project, error) {
+	if start.Name.Space == Namespace200 {
+		// The new decoder has to read the start of the root element itself
+		// to match its end
+		start = (&namespaceRewriter{from: Namespace200, to: Namespace}).rewrite(start)
+		d = xml.NewTokenDecoder(&namespaceRewriter{d: d, from: Namespace200, to: Namespace, first: start})
+		if _, err := d.Token(); err != nil {
+			return nil, err
+		}
+	}
+	var project Project
+	if err := d.DecodeElement(&project, &start); err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// Decode reads a TC6 XML document from r, detects its version from the
+// namespace of the root element and decodes it into the matching model.
+// The input is prepared as plcopen.Load does (see plcopen.OpenXML): byte
+// order marks, UTF-16 and declared character encodings are handled,
+// plcopen.WithCharset and plcopen.WithLimits apply, and documents without
+// a namespace are V1.0B documents.
+func Decode(r io.Reader, opts ...plcopen.Option) (*Document, error) {
+	d, start, err := plcopen.OpenXML(r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	switch start.Name.Space {
+	case Namespace10:
+		var project plcopen.Project
+		if err := d.DecodeElement(&project, &start); err != nil {
+			return nil, err
+		}
+		return &Document{Version: Version10, V10: &project}, nil
+	case Namespace, Namespace200:
+		project, err := decodeProject(d, start)
+		if err != nil {
+			return nil, err
+		}
+		version := Version201
+		if start.Name.Space == Namespace200 {
+			version = Version200
+		}
+		return &Document{Version: version, V201: project}, nil
+	}
+	return nil, fmt.Errorf("plcopen: unsupported document namespace %q", start.Name.Space)
+}
+
+// namespaceRewriter moves the elements of namespace from into namespace to
+type namespaceRewriter struct {
+	d        *xml.Decoder
+	from, to string
+	// first is returned before the tokens of d if set
+	first xml.Token
+}
+
+// Token returns the next token of the underlying decoder with the namespace rewritten
+func (r *namespaceRewriter) Token() (xml.Token, error) {
+	if tok := r.first; tok != nil {
+		r.first = nil
+		return tok, nil
+	}
+	tok, err := r.d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case xml.StartElement:
+		return r.rewrite(t), nil
+	case xml.EndElement:
+		if t.Name.Space == r.from {
+			t.Name.Space = r.to
+		}
+		return t, nil
+	}
+	return tok, nil
+}
+
+// rewrite returns a copy of start moved into namespace to, with namespace
+// declarations of from changed as well
+func (r *namespaceRewriter) rewrite(start xml.StartElement) xml.StartElement {
+	start = start.Copy()
+	if start.Name.Space == r.from {
+		start.Name.Space = r.to
+	}
+	for i, attr := range start.Attr {
+		if attr.Value == r.from && (attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns") {
+			start.Attr[i].Value = r.to
+		}
+	}
+	return start
+}
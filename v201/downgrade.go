@@ -0,0 +1,761 @@
+package v201
+
+import (
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// Downgrade converts a TC6 XML 2.01 project to TC6 XML V1.0B. Elements and
+// attributes that V1.0B cannot hold, such as globalId, additional bodies or
+// addData outside of graphical objects, are dropped and reported as
+// warnings. Leaf values are shared between p and the result as in Upgrade.
+func Downgrade(p *Project) (*plcopen.Project, []Warning) {
+	if p == nil {
+		return nil, nil
+	}
+	c := &converter{}
+	out := &plcopen.Project{
+		AddData: p.AddData,
+	}
+	if p.Documentation != nil {
+		c.warn("project", "project documentation dropped")
+	}
+	if h := p.FileHeader; h != nil {
+		out.FileHeader = &plcopen.ProjectFileHeader{
+			CompanyName:        h.CompanyName,
+			CompanyURL:         h.CompanyURL,
+			ProductName:        h.ProductName,
+			ProductVersion:     h.ProductVersion,
+			ProductRelease:     h.ProductRelease,
+			CreationDateTime:   h.CreationDateTime,
+			ContentDescription: h.ContentDescription,
+		}
+	}
+	if h := p.ContentHeader; h != nil {
+		out.ContentHeader = &plcopen.ProjectContentHeader{
+			Name:                 h.Name,
+			Version:              h.Version,
+			ModificationDateTime: h.ModificationDateTime,
+			Organization:         h.Organization,
+			Author:               h.Author,
+			Language:             h.Language,
+			Comment:              h.Comment,
+		}
+		if h.AddDataInfo != nil {
+			c.warn("project/contentHeader", "addDataInfo dropped")
+		}
+		c.dropAddData("project/contentHeader", h.AddData)
+		if ci := h.CoordinateInfo; ci != nil {
+			info := &plcopen.ProjectContentHeaderCoordinateInfo{}
+			if ci.PageSize != nil {
+				info.PageSize = &plcopen.ProjectContentHeaderCoordinateInfoPageSize{X: ci.PageSize.X, Y: ci.PageSize.Y}
+			}
+			if ci.FBD != nil {
+				info.FBD = &plcopen.ProjectContentHeaderCoordinateInfoFBD{}
+				if s := ci.FBD.Scaling; s != nil {
+					info.FBD.Scaling = &plcopen.ProjectContentHeaderCoordinateInfoFBDScaling{X: s.X, Y: s.Y}
+				}
+			}
+			if ci.LD != nil {
+				info.LD = &plcopen.ProjectContentHeaderCoordinateInfoLD{}
+				if s := ci.LD.Scaling; s != nil {
+					info.LD.Scaling = &plcopen.ProjectContentHeaderCoordinateInfoLDScaling{X: s.X, Y: s.Y}
+				}
+			}
+			if ci.SFC != nil {
+				info.SFC = &plcopen.ProjectContentHeaderCoordinateInfoSFC{}
+				if s := ci.SFC.Scaling; s != nil {
+					info.SFC.Scaling = &plcopen.ProjectContentHeaderCoordinateInfoSFCScaling{X: s.X, Y: s.Y}
+				}
+			}
+			out.ContentHeader.CoordinateInfo = info
+		}
+	}
+	if t := p.Types; t != nil {
+		out.Types = &plcopen.ProjectTypes{}
+		for _, dt := range t.DataTypes {
+			path := named("project/types/dataTypes", "dataType", dt.Name)
+			c.dropGlobalID(path, dt.GlobalID)
+			c.dropAddData(path, dt.AddData)
+			out.Types.DataTypes = append(out.Types.DataTypes, plcopen.ProjectTypesDataType{
+				Name:          dt.Name,
+				BaseType:      c.downDataType(path+"/baseType", dt.BaseType),
+				InitialValue:  dt.InitialValue,
+				Documentation: dt.Documentation,
+			})
+		}
+		for _, pou := range t.POUs {
+			out.Types.POUs = append(out.Types.POUs, c.downPOU(named("project/types/pous", "pou", pou.Name), pou))
+		}
+	}
+	if inst := p.Instances; inst != nil {
+		out.Instances = &plcopen.ProjectInstances{}
+		for _, cfg := range inst.Configurations {
+			out.Instances.Configurations = append(out.Instances.Configurations, c.downConfiguration(cfg))
+		}
+	}
+	return out, c.warnings
+}
+
+func (c *converter) dropGlobalID(path string, id *string) {
+	if id != nil {
+		c.warn(path, "globalId %q dropped", *id)
+	}
+}
+
+func (c *converter) dropAddData(path string, a *AddData) {
+	if a != nil && len(a.Data) > 0 {
+		c.warn(path, "addData dropped")
+	}
+}
+
+func (c *converter) downPOU(path string, pou ProjectTypesPOU) plcopen.ProjectTypesPOU {
+	c.dropGlobalID(path, pou.GlobalID)
+	out := plcopen.ProjectTypesPOU{
+		Name:          pou.Name,
+		POUType:       pou.POUType,
+		AddData:       pou.AddData,
+		Documentation: pou.Documentation,
+	}
+	if i := pou.Interface; i != nil {
+		ipath := path + "/interface"
+		c.dropAddData(ipath, i.AddData)
+		out.Interface = &plcopen.ProjectTypesPOUInterface{
+			ReturnType:    c.downDataType(ipath+"/returnType", i.ReturnType),
+			Documentation: i.Documentation,
+		}
+		for _, s := range []struct {
+			dst  **plcopen.VarList
+			src  []VarList
+			elem string
+		}{
+			{&out.Interface.LocalVars, i.LocalVars, "localVars"},
+			{&out.Interface.TempVars, i.TempVars, "tempVars"},
+			{&out.Interface.InputVars, i.InputVars, "inputVars"},
+			{&out.Interface.OutputVars, i.OutputVars, "outputVars"},
+			{&out.Interface.InOutVars, i.InOutVars, "inOutVars"},
+			{&out.Interface.ExternalVars, i.ExternalVars, "externalVars"},
+			{&out.Interface.GlobalVars, i.GlobalVars, "globalVars"},
+		} {
+			*s.dst = c.downVarLists(ipath, s.elem, s.src)
+		}
+		if len(i.AccessVars) > 0 {
+			out.Interface.AccessVars = c.downAccessVars(ipath, i.AccessVars)
+		}
+	}
+	for _, a := range pou.Actions {
+		apath := named(path+"/actions", "action", a.Name)
+		c.dropGlobalID(apath, a.GlobalID)
+		c.dropAddData(apath, a.AddData)
+		out.Actions = append(out.Actions, plcopen.ProjectTypesPOUAction{
+			Name:          a.Name,
+			Body:          c.downBody(apath+"/body", a.Body),
+			Documentation: a.Documentation,
+		})
+	}
+	for _, tr := range pou.Transitions {
+		tpath := named(path+"/transitions", "transition", tr.Name)
+		c.dropGlobalID(tpath, tr.GlobalID)
+		c.dropAddData(tpath, tr.AddData)
+		out.Transitions = append(out.Transitions, plcopen.ProjectTypesPOUTransition{
+			Name:          tr.Name,
+			Body:          c.downBody(tpath+"/body", tr.Body),
+			Documentation: tr.Documentation,
+		})
+	}
+	for i := range pou.Bodies {
+		bpath := indexed(path, "body", i)
+		if i > 0 {
+			c.warn(bpath, "V1.0B allows a single body per POU, body dropped")
+			continue
+		}
+		out.Body = c.downBody(bpath, &pou.Bodies[i])
+	}
+	return out
+}
+
+// downVarLists converts the variable sections elem of parent. V1.0B allows a
+// single section per kind, so sections with the same attributes are merged
+// and the others dropped.
+func (c *converter) downVarLists(parent, elem string, lists []VarList) *plcopen.VarList {
+	if len(lists) == 0 {
+		return nil
+	}
+	out := c.downVarList(indexed(parent, elem, 0), lists[0])
+	for i := 1; i < len(lists); i++ {
+		v := lists[i]
+		lpath := indexed(parent, elem, i)
+		if v.Name != lists[0].Name || !equalQualifiers(&v, &lists[0]) {
+			c.warn(lpath, "V1.0B allows a single variable section per kind, section with other attributes dropped")
+			continue
+		}
+		if v.Documentation != nil {
+			c.warn(lpath, "documentation of merged variable section dropped")
+		}
+		out.Variables = append(out.Variables, c.downVarList(lpath, v).Variables...)
+	}
+	return out
+}
+
+func equalQualifiers(a, b *VarList) bool {
+	flag := func(p *bool) bool { return p != nil && *p }
+	return flag(a.Constant) == flag(b.Constant) && flag(a.Retain) == flag(b.Retain) &&
+		flag(a.NonRetain) == flag(b.NonRetain) && flag(a.Persistent) == flag(b.Persistent) &&
+		flag(a.NonPersistent) == flag(b.NonPersistent)
+}
+
+func (c *converter) downVarList(path string, v VarList) *plcopen.VarList {
+	c.dropAddData(path, v.AddData)
+	out := &plcopen.VarList{
+		Documentation: v.Documentation,
+		Name:          v.Name,
+		Constant:      v.Constant,
+		Retain:        v.Retain,
+		NonRetain:     v.NonRetain,
+		Persistent:    v.Persistent,
+		NonPersistent: v.NonPersistent,
+	}
+	for _, variable := range v.Variables {
+		vpath := named(path, "variable", variable.Name)
+		c.dropGlobalID(vpath, variable.GlobalID)
+		c.dropAddData(vpath, variable.AddData)
+		out.Variables = append(out.Variables, plcopen.VarListVariable{
+			Name:          variable.Name,
+			Address:       variable.Address,
+			Type:          c.downDataType(vpath+"/type", variable.Type),
+			InitialValue:  variable.InitialValue,
+			Documentation: variable.Documentation,
+		})
+	}
+	return out
+}
+
+// downAccessVars converts the access paths of parent into the variable list
+// used for VAR_ACCESS in V1.0B, the inverse of upAccessVars
+func (c *converter) downAccessVars(parent string, lists []VarListAccess) *plcopen.VarList {
+	out := &plcopen.VarList{Documentation: lists[0].Documentation}
+	for i, list := range lists {
+		lpath := indexed(parent, "accessVars", i)
+		if i > 0 && list.Documentation != nil {
+			c.warn(lpath, "documentation of merged access section dropped")
+		}
+		c.dropAddData(lpath, list.AddData)
+		for _, v := range list.AccessVariables {
+			vpath := lpath + "/accessVariable[@alias='" + v.Alias + "']"
+			c.dropAddData(vpath, v.AddData)
+			if v.Direction != nil {
+				c.warn(vpath, "direction %q dropped", *v.Direction)
+			}
+			out.Variables = append(out.Variables, plcopen.VarListVariable{
+				Name:          v.Alias,
+				Address:       v.InstancePathAndName,
+				Type:          c.downDataType(vpath+"/type", v.Type),
+				Documentation: v.Documentation,
+			})
+		}
+	}
+	return out
+}
+
+func (c *converter) downDataType(path string, dt *DataType) *plcopen.DataType {
+	if dt == nil {
+		return nil
+	}
+	out := &plcopen.DataType{
+		BOOL: dt.BOOL, BYTE: dt.BYTE, WORD: dt.WORD, DWORD: dt.DWORD, LWORD: dt.LWORD,
+		SINT: dt.SINT, INT: dt.INT, DINT: dt.DINT, LINT: dt.LINT,
+		USINT: dt.USINT, UINT: dt.UINT, UDINT: dt.UDINT, ULINT: dt.ULINT,
+		REAL: dt.REAL, LREAL: dt.LREAL,
+		TIME: dt.TIME, DATE: dt.DATE, DT: dt.DT, TOD: dt.TOD,
+	}
+	if dt.String != nil {
+		out.String = &plcopen.DataTypeString{Length: dt.String.Length}
+	}
+	if dt.WString != nil {
+		out.WString = &plcopen.DataTypeWString{Length: dt.WString.Length}
+	}
+	if a := dt.Array; a != nil {
+		out.Array = &plcopen.DataTypeArray{Dimensions: a.Dimensions, BaseType: c.downDataType(path+"/array/baseType", a.BaseType)}
+	}
+	if d := dt.Derived; d != nil {
+		c.dropAddData(path+"/derived", d.AddData)
+		out.Derived = &plcopen.DataTypeDerived{Name: d.Name}
+	}
+	if e := dt.Enum; e != nil {
+		out.Enum = &plcopen.DataTypeEnum{
+			Values:   &plcopen.DataTypeEnumValues{},
+			BaseType: c.downDataType(path+"/enum/baseType", e.BaseType),
+		}
+		for _, v := range e.Values {
+			out.Enum.Values.Values = append(out.Enum.Values.Values, plcopen.DataTypeEnumValuesValue{Name: v.Name, Value: v.Value})
+		}
+	}
+	if s := dt.Struct; s != nil {
+		spath := path + "/struct"
+		c.dropAddData(spath, s.AddData)
+		out.Struct = &plcopen.VarListPlain{Documentation: s.Documentation}
+		for _, v := range s.Variables {
+			vpath := named(spath, "variable", v.Name)
+			c.dropGlobalID(vpath, v.GlobalID)
+			c.dropAddData(vpath, v.AddData)
+			out.Struct.Variables = append(out.Struct.Variables, plcopen.VarListPlainVariable{
+				Name:          v.Name,
+				Address:       v.Address,
+				Type:          c.downDataType(vpath+"/type", v.Type),
+				InitialValue:  v.InitialValue,
+				Documentation: v.Documentation,
+			})
+		}
+	}
+	if s := dt.SubrangeSigned; s != nil {
+		out.SubrangeSigned = &plcopen.DataTypeSubrangeSigned{Range: s.Range, BaseType: c.downDataType(path+"/subrangeSigned/baseType", s.BaseType)}
+	}
+	if s := dt.SubrangeUnsigned; s != nil {
+		out.SubrangeUnsigned = &plcopen.DataTypeSubrangeUnsigned{Range: s.Range, BaseType: c.downDataType(path+"/subrangeUnsigned/baseType", s.BaseType)}
+	}
+	if p := dt.Pointer; p != nil {
+		out.Pointer = &plcopen.DataTypePointer{BaseType: c.downDataType(path+"/pointer/baseType", p.BaseType)}
+	}
+	return out
+}
+
+func (c *converter) downConfiguration(cfg ProjectInstancesConfiguration) plcopen.ProjectInstancesConfiguration {
+	path := named("project/instances/configurations", "configuration", cfg.Name)
+	c.dropGlobalID(path, cfg.GlobalID)
+	c.dropAddData(path, cfg.AddData)
+	if cfg.AccessVars != nil {
+		c.warn(path+"/accessVars", "configuration access paths dropped")
+	}
+	if cfg.ConfigVars != nil {
+		c.warn(path+"/configVars", "configuration variables dropped")
+	}
+	out := plcopen.ProjectInstancesConfiguration{
+		Name:          cfg.Name,
+		GlobalVars:    c.downVarLists(path, "globalVars", cfg.GlobalVars),
+		Documentation: cfg.Documentation,
+	}
+	for _, res := range cfg.Resources {
+		rpath := named(path, "resource", res.Name)
+		c.dropGlobalID(rpath, res.GlobalID)
+		c.dropAddData(rpath, res.AddData)
+		r := plcopen.ProjectInstancesConfigurationResource{
+			Name:          res.Name,
+			GlobalVars:    c.downVarLists(rpath, "globalVars", res.GlobalVars),
+			POUInstances:  c.downPOUInstances(rpath, res.POUInstances),
+			Documentation: res.Documentation,
+		}
+		for _, task := range res.Tasks {
+			tpath := named(rpath, "task", task.Name)
+			c.dropGlobalID(tpath, task.GlobalID)
+			c.dropAddData(tpath, task.AddData)
+			if task.Documentation != nil {
+				c.warn(tpath, "task documentation dropped")
+			}
+			r.Tasks = append(r.Tasks, plcopen.ProjectInstancesConfigurationResourceTask{
+				Name:         task.Name,
+				Priority:     task.Priority,
+				Interval:     task.Interval,
+				Single:       task.Single,
+				POUInstances: c.downPOUInstances(tpath, task.POUInstances),
+			})
+		}
+		out.Resources = append(out.Resources, r)
+	}
+	return out
+}
+
+func (c *converter) downPOUInstances(path string, instances []POUInstance) []plcopen.POUInstance {
+	var out []plcopen.POUInstance
+	for _, inst := range instances {
+		ipath := named(path, "pouInstance", inst.Name)
+		c.dropGlobalID(ipath, inst.GlobalID)
+		c.dropAddData(ipath, inst.AddData)
+		out = append(out, plcopen.POUInstance{Name: inst.Name, TypeName: inst.TypeName, Documentation: inst.Documentation})
+	}
+	return out
+}
+
+func (c *converter) downBody(path string, b *Body) *plcopen.Body {
+	if b == nil {
+		return nil
+	}
+	c.dropGlobalID(path, b.GlobalID)
+	if b.WorksheetName != nil {
+		c.warn(path, "worksheet name %q dropped", *b.WorksheetName)
+	}
+	if b.Documentation != nil {
+		c.warn(path, "body documentation dropped")
+	}
+	out := &plcopen.Body{AddData: b.AddData}
+	if b.IL != nil {
+		out.IL = &plcopen.BodyIL{Xhtml: b.IL}
+	}
+	if b.ST != nil {
+		out.ST = &plcopen.BodyST{Xhtml: b.ST}
+	}
+	if b.FBD != nil {
+		out.FBD = &plcopen.BodyFBD{}
+		c.setObjects(path+"/FBD", out.FBD, c.downObjects(path+"/FBD", b.FBD.Objects()))
+	}
+	if b.LD != nil {
+		out.LD = &plcopen.BodyLD{}
+		c.setObjects(path+"/LD", out.LD, c.downObjects(path+"/LD", b.LD.Objects()))
+	}
+	if b.SFC != nil {
+		out.SFC = &plcopen.BodySFC{}
+		c.setObjects(path+"/SFC", out.SFC, c.downObjects(path+"/SFC", b.SFC.Objects()))
+	}
+	return out
+}
+
+func (c *converter) downObjects(path string, objs []GraphicalObject) []GraphicalObject {
+	var out []GraphicalObject
+	for _, obj := range objs {
+		opath := local(path, obj)
+		switch o := obj.(type) {
+		case *BodyFBDComment:
+			c.dropGlobalID(opath, o.GlobalID)
+			out = append(out, &plcopen.BodyFBDComment{
+				Position: o.Position, Content: o.Content, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: nonZero(o.Height), Width: nonZero(o.Width),
+			})
+		case *BodyFBDError:
+			c.dropGlobalID(opath, o.GlobalID)
+			out = append(out, &plcopen.BodyFBDError{
+				Position: o.Position, Content: o.Content, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: nonZero(o.Height), Width: nonZero(o.Width),
+			})
+		case *BodyFBDConnector:
+			c.dropGlobalID(opath, o.GlobalID)
+			out = append(out, &plcopen.BodyFBDConnector{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				Name: o.Name, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *BodyFBDContinuation:
+			c.dropGlobalID(opath, o.GlobalID)
+			out = append(out, &plcopen.BodyFBDContinuation{
+				Position: o.Position, ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut), AddData: o.AddData, Documentation: o.Documentation,
+				Name: o.Name, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *BodyFBDActionBlock:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			ab := &plcopen.BodyFBDActionBlock{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Negated: o.Negated,
+			}
+			for i, a := range o.Actions {
+				apath := indexed(opath, "action", i)
+				c.dropGlobalID(apath, a.GlobalID)
+				c.dropAddData(apath, a.AddData)
+				c.dropExecutionOrder(apath, a.ExecutionOrderID)
+				if a.RelPosition != nil || a.ConnectionPointOut != nil || a.Width != nil || a.Height != nil {
+					c.warn(apath, "graphical layout of action dropped")
+				}
+				action := plcopen.BodyFBDActionBlockAction{
+					Documentation: a.Documentation, Qualifier: a.Qualifier, Duration: a.Duration, Indicator: a.Indicator,
+				}
+				if a.Reference != nil {
+					action.Reference = &plcopen.BodyFBDActionBlockActionReference{Name: a.Reference.Name}
+				}
+				if a.Inline != nil {
+					action.Inline = &plcopen.BodyFBDActionBlockActionInline{Body: c.downBody(apath+"/inline", a.Inline)}
+				}
+				ab.Actions = append(ab.Actions, action)
+			}
+			out = append(out, ab)
+		case *BodyFBDBlock:
+			c.dropGlobalID(opath, o.GlobalID)
+			block := &plcopen.BodyFBDBlock{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID,
+				Width: o.Width, Height: o.Height, TypeName: o.TypeName, InstanceName: o.InstanceName, ExecutionOrderID: o.ExecutionOrderID,
+			}
+			for _, v := range o.InputVariables {
+				c.downBlockVariable(opath+"/inputVariables", v)
+				block.InputVariables = append(block.InputVariables, plcopen.BodyFBDBlockVariable{
+					FormalParameter: v.FormalParameter, ConnectionPointIn: c.downPointIn(opath, v.ConnectionPointIn),
+				})
+			}
+			for _, v := range o.InOutVariables {
+				c.downBlockVariable(opath+"/inOutVariables", v)
+				block.InOutVariables = append(block.InOutVariables, plcopen.BodyFBDBlockVariable2{
+					FormalParameter: v.FormalParameter, ConnectionPointIn: c.downPointIn(opath, v.ConnectionPointIn), ConnectionPointOut: c.downPointOut(opath, v.ConnectionPointOut),
+				})
+			}
+			for _, v := range o.OutputVariables {
+				c.downBlockVariable(opath+"/outputVariables", v)
+				block.OutputVariables = append(block.OutputVariables, plcopen.BodyFBDBlockVariable1{
+					FormalParameter: v.FormalParameter, ConnectionPointOut: c.downPointOut(opath, v.ConnectionPointOut),
+				})
+			}
+			out = append(out, block)
+		case *BodyFBDInVariable:
+			c.dropGlobalID(opath, o.GlobalID)
+			if o.Negated != nil || o.Storage != nil {
+				c.warn(opath, "negated and storage modifiers of in variable dropped")
+			}
+			out = append(out, &plcopen.BodyFBDInVariable{
+				Position: o.Position, ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut), Expression: o.Expression, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, EdgeModifier: o.Edge, ExecutionOrderID: o.ExecutionOrderID,
+			})
+		case *BodyFBDOutVariable:
+			c.dropGlobalID(opath, o.GlobalID)
+			if o.Negated != nil {
+				c.warn(opath, "negated modifier of out variable dropped")
+			}
+			out = append(out, &plcopen.BodyFBDOutVariable{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), Expression: o.Expression, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, EdgeModifier: o.Edge, StorageModifier: o.Storage, ExecutionOrderID: o.ExecutionOrderID,
+			})
+		case *BodyFBDInOutVariable:
+			c.dropGlobalID(opath, o.GlobalID)
+			if o.NegatedIn != nil || o.NegatedOut != nil || o.StorageIn != nil || o.EdgeOut != nil {
+				c.warn(opath, "only edgeIn and storageOut modifiers of in-out variable are kept")
+			}
+			out = append(out, &plcopen.BodyFBDInOutVariable{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut),
+				Expression: o.Expression, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, EdgeModifier: o.EdgeIn, StorageModifier: o.StorageOut, ExecutionOrderID: o.ExecutionOrderID,
+			})
+		case *BodyFBDLabel:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			out = append(out, &plcopen.BodyFBDLabel{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, Label: o.Label, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *BodyFBDJump:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			if o.ConnectionPointIn != nil {
+				c.warn(opath, "connectionPointIn of jump dropped")
+			}
+			out = append(out, &plcopen.BodyFBDJump{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, Label: o.Label, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *BodyFBDReturn:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			if o.ConnectionPointIn != nil {
+				c.warn(opath, "connectionPointIn of return dropped")
+			}
+			out = append(out, &plcopen.BodyFBDReturn{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *BodyLDContact:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			if o.Storage != nil {
+				c.warn(opath, "storage modifier of contact dropped")
+			}
+			out = append(out, &plcopen.BodyLDContact{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut),
+				Variable: o.Variable, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, EdgeModifier: o.Edge, Negated: o.Negated,
+			})
+		case *BodyLDCoil:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			out = append(out, &plcopen.BodyLDCoil{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut),
+				Variable: o.Variable, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, EdgeModifier: o.Edge, StorageModifier: o.Storage, Negated: o.Negated,
+			})
+		case *BodyLDLeftPowerRail:
+			c.dropGlobalID(opath, o.GlobalID)
+			rail := &plcopen.BodyLDLeftPowerRail{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			}
+			if len(o.ConnectionPointOuts) > 0 {
+				rail.ConnectionPointOut = c.downPointOut(opath, &o.ConnectionPointOuts[0])
+			}
+			if len(o.ConnectionPointOuts) > 1 {
+				c.warn(opath, "only the first of %d connection points of the power rail is kept", len(o.ConnectionPointOuts))
+			}
+			out = append(out, rail)
+		case *BodyLDRightPowerRail:
+			c.dropGlobalID(opath, o.GlobalID)
+			rail := &plcopen.BodyLDRightPowerRail{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			}
+			if len(o.ConnectionPointIns) > 0 {
+				rail.ConnectionPointIn = c.downPointIn(opath, &o.ConnectionPointIns[0])
+			}
+			if len(o.ConnectionPointIns) > 1 {
+				c.warn(opath, "only the first of %d connection points of the power rail is kept", len(o.ConnectionPointIns))
+			}
+			out = append(out, rail)
+		case *BodySFCStep:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			if o.Negated != nil {
+				c.warn(opath, "negated attribute of step dropped")
+			}
+			step := &plcopen.BodySFCStep{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation,
+				Name: o.Name, LocalID: o.LocalID, Height: o.Height, Width: o.Width, InitialStep: o.InitialStep,
+			}
+			if p := c.downPointIn(opath, o.ConnectionPointIn); p != nil {
+				step.ConnectionPointIn = &plcopen.BodySFCStepConnectionPointIn{RelPosition: p.RelPosition, Connections: p.Connections, Expression: p.Expression}
+			}
+			if p := c.downPointOut(opath, o.ConnectionPointOut); p != nil {
+				step.ConnectionPointOut = &plcopen.BodySFCStepConnectionPointOut{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: p.FormalParameter}
+			}
+			if p := c.downPointOut(opath, o.ConnectionPointOutAction); p != nil {
+				step.ConnectionPointOutAction = &plcopen.BodySFCStepConnectionPointOutAction{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: p.FormalParameter}
+			}
+			out = append(out, step)
+		case *BodySFCMacroStep:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			out = append(out, &plcopen.BodySFCMacroStep{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut),
+				Body: c.downBody(opath+"/body", o.Body), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Name: o.Name,
+			})
+		case *BodySFCJumpStep:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			out = append(out, &plcopen.BodySFCJumpStep{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, TargetName: o.TargetName,
+			})
+		case *BodySFCTransition:
+			c.dropGlobalID(opath, o.GlobalID)
+			c.dropExecutionOrder(opath, o.ExecutionOrderID)
+			tr := &plcopen.BodySFCTransition{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut),
+				AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Priority: o.Priority,
+			}
+			if cond := o.Condition; cond != nil {
+				cpath := opath + "/condition"
+				tr.Condition = &plcopen.BodySFCTransitionCondition{}
+				if cond.Reference != nil {
+					tr.Condition.Reference = &plcopen.BodySFCTransitionConditionReference{Name: cond.Reference.Name}
+				}
+				if cond.Inline != nil {
+					tr.Condition.Inline = &plcopen.BodySFCTransitionConditionInline{
+						Body: c.downBody(cpath+"/inline", &cond.Inline.Body),
+						Name: cond.Inline.Name,
+					}
+				}
+				if cond.ConnectionPointIn != nil {
+					c.warn(cpath, "graphical transition condition dropped")
+				}
+				if cond.Negated != nil {
+					c.warn(cpath, "negated attribute of condition dropped")
+				}
+			}
+			out = append(out, tr)
+		case *BodySFCSelectionDivergence:
+			c.dropGlobalID(opath, o.GlobalID)
+			div := &plcopen.BodySFCSelectionDivergence{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			}
+			for i := range o.ConnectionPointOuts {
+				p := c.downPointOut(opath, &o.ConnectionPointOuts[i])
+				div.ConnectionPointOut = append(div.ConnectionPointOut, plcopen.BodySFCSelectionDivergenceConnectionPointOut{
+					RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: derefString(p.FormalParameter),
+				})
+			}
+			out = append(out, div)
+		case *BodySFCSelectionConvergence:
+			c.dropGlobalID(opath, o.GlobalID)
+			out = append(out, &plcopen.BodySFCSelectionConvergence{
+				Position: o.Position, ConnectionPointIn: c.downPointIns(opath, o.ConnectionPointIns), ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut),
+				AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *BodySFCSimultaneousDivergence:
+			c.dropGlobalID(opath, o.GlobalID)
+			div := &plcopen.BodySFCSimultaneousDivergence{
+				Position: o.Position, ConnectionPointIn: c.downPointIn(opath, o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Name: o.Name,
+			}
+			for i := range o.ConnectionPointOuts {
+				p := c.downPointOut(opath, &o.ConnectionPointOuts[i])
+				div.ConnectionPointOut = append(div.ConnectionPointOut, plcopen.BodySFCSimultaneousDivergenceConnectionPointOut{
+					RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: derefString(p.FormalParameter),
+				})
+			}
+			out = append(out, div)
+		case *BodySFCSimultaneousConvergence:
+			c.dropGlobalID(opath, o.GlobalID)
+			out = append(out, &plcopen.BodySFCSimultaneousConvergence{
+				Position: o.Position, ConnectionPointIn: c.downPointIns(opath, o.ConnectionPointIns), ConnectionPointOut: c.downPointOut(opath, o.ConnectionPointOut),
+				AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *BodyFBDVendorElement:
+			c.warn(opath, "vendorElement dropped, V1.0B does not define it")
+		case *UnknownObject:
+			c.warn(opath, "unknown element %s dropped", o.XMLName.Local)
+		default:
+			c.warn(opath, "unsupported object %T dropped", obj)
+		}
+	}
+	return out
+}
+
+func (c *converter) dropExecutionOrder(path string, id *uint64) {
+	if id != nil {
+		c.warn(path, "executionOrderId %d dropped", *id)
+	}
+}
+
+func (c *converter) downBlockVariable(path string, v BodyFBDBlockVariable) {
+	vpath := path + "/variable[@formalParameter='" + v.FormalParameter + "']"
+	c.dropAddData(vpath, v.AddData)
+	if v.Documentation != nil || v.Negated != nil || v.Edge != nil || v.Storage != nil || v.Hidden != nil {
+		c.warn(vpath, "documentation and modifiers of block variable dropped")
+	}
+}
+
+func (c *converter) downConnections(path string, conns []Connection) []plcopen.Connection {
+	var out []plcopen.Connection
+	for _, conn := range conns {
+		c.dropGlobalID(path, conn.GlobalID)
+		c.dropAddData(path, conn.AddData)
+		out = append(out, plcopen.Connection{Positions: conn.Positions, RefLocalID: conn.RefLocalID, FormalParameter: conn.FormalParameter})
+	}
+	return out
+}
+
+func (c *converter) downPointIn(path string, p *ConnectionPointIn) *plcopen.ConnectionPointIn {
+	if p == nil {
+		return nil
+	}
+	c.dropGlobalID(path, p.GlobalID)
+	c.dropAddData(path, p.AddData)
+	return &plcopen.ConnectionPointIn{RelPosition: p.RelPosition, Connections: c.downConnections(path, p.Connections), Expression: p.Expression}
+}
+
+func (c *converter) downPointIns(path string, ps []ConnectionPointIn) []plcopen.ConnectionPointIn {
+	var out []plcopen.ConnectionPointIn
+	for i := range ps {
+		out = append(out, *c.downPointIn(path, &ps[i]))
+	}
+	return out
+}
+
+func (c *converter) downPointOut(path string, p *ConnectionPointOut) *plcopen.ConnectionPointOut {
+	if p == nil {
+		return nil
+	}
+	c.dropGlobalID(path, p.GlobalID)
+	c.dropAddData(path, p.AddData)
+	return &plcopen.ConnectionPointOut{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: p.FormalParameter}
+}
+
+// nonZero returns nil for a zero size, which V1.0B treats as unset
+func nonZero(f float64) *float64 {
+	if f == 0 {
+		return nil
+	}
+	return &f
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,969 @@
+// Package v201 provides Go structs for PLCopen TC6 XML version 2.01 documents
+// (namespace http://www.plcopen.org/xml/tc6_0201), as written by current tools
+// such as CODESYS V3, TwinCAT 3 and Beremiz.
+//
+// The types mirror the ones of package plcopen for TC6 XML V1.0B. Leaf types
+// that did not change between the versions (formatted text, addData, values,
+// positions and the modifier enumerations) are shared with package plcopen.
+package v201
+
+import (
+	"encoding/xml"
+	"time"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// Namespace is the XML namespace of TC6 XML 2.01 documents
+const Namespace = "http://www.plcopen.org/xml/tc6_0201"
+
+// Shared leaf types of package plcopen
+type (
+	FormattedText       = plcopen.FormattedText
+	AddData             = plcopen.AddData
+	Value               = plcopen.Value
+	Position            = plcopen.Position
+	RangeSigned         = plcopen.RangeSigned
+	RangeUnsigned       = plcopen.RangeUnsigned
+	POUType             = plcopen.POUType
+	EdgeModifierType    = plcopen.EdgeModifierType
+	StorageModifierType = plcopen.StorageModifierType
+)
+
+// Project represents the root element of a TC6 XML 2.01 document
+type Project struct {
+	XMLName       xml.Name              `xml:"http://www.plcopen.org/xml/tc6_0201 project" json:"-"`
+	FileHeader    *ProjectFileHeader    `xml:"fileHeader" json:"fileHeader,omitempty"`
+	ContentHeader *ProjectContentHeader `xml:"contentHeader" json:"contentHeader,omitempty"`
+	Types         *ProjectTypes         `xml:"types" json:"types,omitempty"`
+	Instances     *ProjectInstances     `xml:"instances" json:"instances,omitempty"`
+	AddData       *AddData              `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText        `xml:"documentation,omitempty" json:"documentation,omitempty"`
+}
+
+// ProjectFileHeader contains file metadata
+type ProjectFileHeader struct {
+	CompanyName        string    `xml:"companyName,attr" json:"companyName"`
+	CompanyURL         string    `xml:"companyURL,attr,omitempty" json:"companyURL,omitempty"`
+	ProductName        string    `xml:"productName,attr" json:"productName"`
+	ProductVersion     string    `xml:"productVersion,attr" json:"productVersion"`
+	ProductRelease     string    `xml:"productRelease,attr,omitempty" json:"productRelease,omitempty"`
+	CreationDateTime   time.Time `xml:"creationDateTime,attr" json:"creationDateTime"`
+	ContentDescription string    `xml:"contentDescription,attr,omitempty" json:"contentDescription,omitempty"`
+}
+
+// ProjectContentHeader contains project content metadata
+type ProjectContentHeader struct {
+	Comment              string                              `xml:"Comment,omitempty" json:"comment,omitempty"`
+	CoordinateInfo       *ProjectContentHeaderCoordinateInfo `xml:"coordinateInfo" json:"coordinateInfo,omitempty"`
+	AddDataInfo          *AddDataInfo                        `xml:"addDataInfo,omitempty" json:"addDataInfo,omitempty"`
+	AddData              *AddData                            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Name                 string                              `xml:"name,attr" json:"name"`
+	Version              string                              `xml:"version,attr,omitempty" json:"version,omitempty"`
+	ModificationDateTime *time.Time                          `xml:"modificationDateTime,attr,omitempty" json:"modificationDateTime,omitempty"`
+	Organization         string                              `xml:"organization,attr,omitempty" json:"organization,omitempty"`
+	Author               string                              `xml:"author,attr,omitempty" json:"author,omitempty"`
+	Language             string                              `xml:"language,attr,omitempty" json:"language,omitempty"`
+}
+
+// ProjectContentHeaderCoordinateInfo contains coordinate information
+type ProjectContentHeaderCoordinateInfo struct {
+	PageSize *ProjectContentHeaderCoordinateInfoPageSize `xml:"pageSize,omitempty" json:"pageSize,omitempty"`
+	FBD      *ProjectContentHeaderCoordinateInfoScaled   `xml:"fbd" json:"fbd,omitempty"`
+	LD       *ProjectContentHeaderCoordinateInfoScaled   `xml:"ld" json:"ld,omitempty"`
+	SFC      *ProjectContentHeaderCoordinateInfoScaled   `xml:"sfc" json:"sfc,omitempty"`
+}
+
+// ProjectContentHeaderCoordinateInfoPageSize represents page size
+type ProjectContentHeaderCoordinateInfoPageSize struct {
+	X float64 `xml:"x,attr" json:"x"`
+	Y float64 `xml:"y,attr" json:"y"`
+}
+
+// ProjectContentHeaderCoordinateInfoScaled represents the coordinate info of
+// one graphical language
+type ProjectContentHeaderCoordinateInfoScaled struct {
+	Scaling *ProjectContentHeaderCoordinateInfoScaling `xml:"scaling" json:"scaling,omitempty"`
+}
+
+// ProjectContentHeaderCoordinateInfoScaling represents the scaling of a graphical language
+type ProjectContentHeaderCoordinateInfoScaling struct {
+	X float64 `xml:"x,attr" json:"x"`
+	Y float64 `xml:"y,attr" json:"y"`
+}
+
+// AddDataInfo lists the kinds of additional data used in the document
+type AddDataInfo struct {
+	Info []AddDataInfoInfo `xml:"info,omitempty" json:"info,omitempty"`
+}
+
+// AddDataInfoInfo describes one kind of additional data
+type AddDataInfoInfo struct {
+	Description *string `xml:"description,omitempty" json:"description,omitempty"`
+	Name        string  `xml:"name,attr" json:"name"`
+	Version     *string `xml:"version,attr,omitempty" json:"version,omitempty"`
+	Vendor      string  `xml:"vendor,attr" json:"vendor"`
+}
+
+// ProjectTypes contains type definitions
+type ProjectTypes struct {
+	DataTypes []ProjectTypesDataType `xml:"dataTypes>dataType,omitempty" json:"dataTypes,omitempty"`
+	POUs      []ProjectTypesPOU      `xml:"pous>pou,omitempty" json:"pous,omitempty"`
+}
+
+// ProjectTypesDataType represents a data type definition
+type ProjectTypesDataType struct {
+	BaseType      *DataType      `xml:"baseType" json:"baseType,omitempty"`
+	InitialValue  *Value         `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ProjectTypesPOU represents a Program Organization Unit. A POU may have
+// several bodies, one per worksheet.
+type ProjectTypesPOU struct {
+	Interface     *ProjectTypesPOUInterface   `xml:"interface,omitempty" json:"interface,omitempty"`
+	Actions       []ProjectTypesPOUAction     `xml:"actions>action,omitempty" json:"actions,omitempty"`
+	Transitions   []ProjectTypesPOUTransition `xml:"transitions>transition,omitempty" json:"transitions,omitempty"`
+	Bodies        []Body                      `xml:"body,omitempty" json:"bodies,omitempty"`
+	AddData       *AddData                    `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText              `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string                      `xml:"name,attr" json:"name"`
+	POUType       POUType                     `xml:"pouType,attr" json:"pouType"`
+	GlobalID      *string                     `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ProjectTypesPOUInterface represents the interface of a POU. Every kind of
+// variable section may occur several times, e.g. a plain and a RETAIN section.
+type ProjectTypesPOUInterface struct {
+	ReturnType    *DataType       `xml:"returnType,omitempty" json:"returnType,omitempty"`
+	LocalVars     []VarList       `xml:"localVars,omitempty" json:"localVars,omitempty"`
+	TempVars      []VarList       `xml:"tempVars,omitempty" json:"tempVars,omitempty"`
+	InputVars     []VarList       `xml:"inputVars,omitempty" json:"inputVars,omitempty"`
+	OutputVars    []VarList       `xml:"outputVars,omitempty" json:"outputVars,omitempty"`
+	InOutVars     []VarList       `xml:"inOutVars,omitempty" json:"inOutVars,omitempty"`
+	ExternalVars  []VarList       `xml:"externalVars,omitempty" json:"externalVars,omitempty"`
+	GlobalVars    []VarList       `xml:"globalVars,omitempty" json:"globalVars,omitempty"`
+	AccessVars    []VarListAccess `xml:"accessVars,omitempty" json:"accessVars,omitempty"`
+	AddData       *AddData        `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText  `xml:"documentation,omitempty" json:"documentation,omitempty"`
+}
+
+// ProjectTypesPOUAction represents an action within a POU
+type ProjectTypesPOUAction struct {
+	Body          *Body          `xml:"body" json:"body,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ProjectTypesPOUTransition represents a transition within a POU
+type ProjectTypesPOUTransition struct {
+	Body          *Body          `xml:"body" json:"body,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ProjectInstances contains configuration and resource instances
+type ProjectInstances struct {
+	Configurations []ProjectInstancesConfiguration `xml:"configurations>configuration,omitempty" json:"configurations,omitempty"`
+}
+
+// ProjectInstancesConfiguration represents a configuration
+type ProjectInstancesConfiguration struct {
+	Resources     []ProjectInstancesConfigurationResource `xml:"resource,omitempty" json:"resources,omitempty"`
+	GlobalVars    []VarList                               `xml:"globalVars,omitempty" json:"globalVars,omitempty"`
+	AccessVars    *VarListAccess                          `xml:"accessVars,omitempty" json:"accessVars,omitempty"`
+	ConfigVars    *VarListConfig                          `xml:"configVars,omitempty" json:"configVars,omitempty"`
+	AddData       *AddData                                `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText                          `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string                                  `xml:"name,attr" json:"name"`
+	GlobalID      *string                                 `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ProjectInstancesConfigurationResource represents a resource within a configuration
+type ProjectInstancesConfigurationResource struct {
+	Tasks         []ProjectInstancesConfigurationResourceTask `xml:"task,omitempty" json:"tasks,omitempty"`
+	GlobalVars    []VarList                                   `xml:"globalVars,omitempty" json:"globalVars,omitempty"`
+	POUInstances  []POUInstance                               `xml:"pouInstance,omitempty" json:"pouInstances,omitempty"`
+	AddData       *AddData                                    `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText                              `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string                                      `xml:"name,attr" json:"name"`
+	GlobalID      *string                                     `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ProjectInstancesConfigurationResourceTask represents a task within a resource
+type ProjectInstancesConfigurationResourceTask struct {
+	POUInstances  []POUInstance  `xml:"pouInstance,omitempty" json:"pouInstances,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	Single        *string        `xml:"single,attr,omitempty" json:"single,omitempty"`
+	Interval      *string        `xml:"interval,attr,omitempty" json:"interval,omitempty"`
+	Priority      uint64         `xml:"priority,attr" json:"priority"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// POUInstance represents an instance of a POU. TC6 XML 2.01 names the type
+// attribute typeName instead of type.
+type POUInstance struct {
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	TypeName      string         `xml:"typeName,attr" json:"typeName"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// VarList represents a list of variables sharing the same memory attributes
+// (CONSTANT, RETAIN, NON_RETAIN, PERSISTENT, NON_PERSISTENT)
+type VarList struct {
+	Variables     []Variable     `xml:"variable,omitempty" json:"variables,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr,omitempty" json:"name,omitempty"`
+	Constant      *bool          `xml:"constant,attr,omitempty" json:"constant,omitempty"`
+	Retain        *bool          `xml:"retain,attr,omitempty" json:"retain,omitempty"`
+	NonRetain     *bool          `xml:"nonretain,attr,omitempty" json:"nonRetain,omitempty"`
+	Persistent    *bool          `xml:"persistent,attr,omitempty" json:"persistent,omitempty"`
+	NonPersistent *bool          `xml:"nonpersistent,attr,omitempty" json:"nonPersistent,omitempty"`
+}
+
+// VarListPlain represents a plain variable list, used for structure members
+type VarListPlain struct {
+	Variables     []Variable     `xml:"variable,omitempty" json:"variables,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+}
+
+// Variable represents a variable of a variable list
+type Variable struct {
+	Type          *DataType      `xml:"type" json:"type,omitempty"`
+	InitialValue  *Value         `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name          string         `xml:"name,attr" json:"name"`
+	Address       string         `xml:"address,attr,omitempty" json:"address,omitempty"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// VarListAccess represents a list of access paths (VAR_ACCESS)
+type VarListAccess struct {
+	AccessVariables []AccessVariable `xml:"accessVariable,omitempty" json:"accessVariables,omitempty"`
+	AddData         *AddData         `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation   *FormattedText   `xml:"documentation,omitempty" json:"documentation,omitempty"`
+}
+
+// AccessVariable represents an access path to a variable
+type AccessVariable struct {
+	Type                *DataType        `xml:"type" json:"type,omitempty"`
+	AddData             *AddData         `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation       *FormattedText   `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Alias               string           `xml:"alias,attr" json:"alias"`
+	InstancePathAndName string           `xml:"instancePathAndName,attr" json:"instancePathAndName"`
+	Direction           *AccessDirection `xml:"direction,attr,omitempty" json:"direction,omitempty"`
+}
+
+// AccessDirection represents the direction of an access path
+type AccessDirection string
+
+const (
+	AccessDirectionReadWrite AccessDirection = "readWrite"
+	AccessDirectionReadOnly  AccessDirection = "readOnly"
+)
+
+// VarListConfig represents a list of configuration variables (VAR_CONFIG)
+type VarListConfig struct {
+	ConfigVariables []ConfigVariable `xml:"configVariable,omitempty" json:"configVariables,omitempty"`
+	AddData         *AddData         `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation   *FormattedText   `xml:"documentation,omitempty" json:"documentation,omitempty"`
+}
+
+// ConfigVariable represents the configuration of an instance variable
+type ConfigVariable struct {
+	Type                *DataType      `xml:"type" json:"type,omitempty"`
+	InitialValue        *Value         `xml:"initialValue,omitempty" json:"initialValue,omitempty"`
+	AddData             *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation       *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	InstancePathAndName string         `xml:"instancePathAndName,attr" json:"instancePathAndName"`
+	Address             string         `xml:"address,attr,omitempty" json:"address,omitempty"`
+}
+
+// DataType represents a data type with choice-style content
+type DataType struct {
+	// Elementary types (as empty elements)
+	BOOL  *struct{} `xml:"BOOL,omitempty" json:"bool,omitempty"`
+	BYTE  *struct{} `xml:"BYTE,omitempty" json:"byte,omitempty"`
+	WORD  *struct{} `xml:"WORD,omitempty" json:"word,omitempty"`
+	DWORD *struct{} `xml:"DWORD,omitempty" json:"dword,omitempty"`
+	LWORD *struct{} `xml:"LWORD,omitempty" json:"lword,omitempty"`
+	SINT  *struct{} `xml:"SINT,omitempty" json:"sint,omitempty"`
+	INT   *struct{} `xml:"INT,omitempty" json:"int,omitempty"`
+	DINT  *struct{} `xml:"DINT,omitempty" json:"dint,omitempty"`
+	LINT  *struct{} `xml:"LINT,omitempty" json:"lint,omitempty"`
+	USINT *struct{} `xml:"USINT,omitempty" json:"usint,omitempty"`
+	UINT  *struct{} `xml:"UINT,omitempty" json:"uint,omitempty"`
+	UDINT *struct{} `xml:"UDINT,omitempty" json:"udint,omitempty"`
+	ULINT *struct{} `xml:"ULINT,omitempty" json:"ulint,omitempty"`
+	REAL  *struct{} `xml:"REAL,omitempty" json:"real,omitempty"`
+	LREAL *struct{} `xml:"LREAL,omitempty" json:"lreal,omitempty"`
+	TIME  *struct{} `xml:"TIME,omitempty" json:"time,omitempty"`
+	DATE  *struct{} `xml:"DATE,omitempty" json:"date,omitempty"`
+	DT    *struct{} `xml:"DT,omitempty" json:"dt,omitempty"`
+	TOD   *struct{} `xml:"TOD,omitempty" json:"tod,omitempty"`
+
+	String  *DataTypeString `xml:"string,omitempty" json:"string,omitempty"`
+	WString *DataTypeString `xml:"wstring,omitempty" json:"wstring,omitempty"`
+
+	// Derived types
+	Array            *DataTypeArray            `xml:"array,omitempty" json:"array,omitempty"`
+	Derived          *DataTypeDerived          `xml:"derived,omitempty" json:"derived,omitempty"`
+	Enum             *DataTypeEnum             `xml:"enum,omitempty" json:"enum,omitempty"`
+	Struct           *VarListPlain             `xml:"struct,omitempty" json:"struct,omitempty"`
+	SubrangeSigned   *DataTypeSubrangeSigned   `xml:"subrangeSigned,omitempty" json:"subrangeSigned,omitempty"`
+	SubrangeUnsigned *DataTypeSubrangeUnsigned `xml:"subrangeUnsigned,omitempty" json:"subrangeUnsigned,omitempty"`
+	Pointer          *DataTypePointer          `xml:"pointer,omitempty" json:"pointer,omitempty"`
+}
+
+// DataTypeString represents a string or wide string type with optional length
+type DataTypeString struct {
+	Length *uint64 `xml:"length,attr,omitempty" json:"length,omitempty"`
+}
+
+// DataTypeArray represents an array data type
+type DataTypeArray struct {
+	Dimensions []RangeSigned `xml:"dimension" json:"dimensions,omitempty"`
+	BaseType   *DataType     `xml:"baseType" json:"baseType,omitempty"`
+}
+
+// DataTypeDerived represents a reference to a user defined type
+type DataTypeDerived struct {
+	AddData *AddData `xml:"addData,omitempty" json:"addData,omitempty"`
+	Name    string   `xml:"name,attr" json:"name"`
+}
+
+// DataTypeEnum represents an enumerated data type
+type DataTypeEnum struct {
+	Values   []DataTypeEnumValue `xml:"values>value" json:"values,omitempty"`
+	BaseType *DataType           `xml:"baseType,omitempty" json:"baseType,omitempty"`
+}
+
+// DataTypeEnumValue represents an enumerated value
+type DataTypeEnumValue struct {
+	Name  string  `xml:"name,attr" json:"name"`
+	Value *string `xml:"value,attr,omitempty" json:"value,omitempty"`
+}
+
+// DataTypeSubrangeSigned represents a signed subrange data type
+type DataTypeSubrangeSigned struct {
+	Range    *RangeSigned `xml:"range" json:"range,omitempty"`
+	BaseType *DataType    `xml:"baseType" json:"baseType,omitempty"`
+}
+
+// DataTypeSubrangeUnsigned represents an unsigned subrange data type
+type DataTypeSubrangeUnsigned struct {
+	Range    *RangeUnsigned `xml:"range" json:"range,omitempty"`
+	BaseType *DataType      `xml:"baseType" json:"baseType,omitempty"`
+}
+
+// DataTypePointer represents a pointer data type
+type DataTypePointer struct {
+	BaseType *DataType `xml:"baseType" json:"baseType,omitempty"`
+}
+
+// Body represents a POU, action or transition body. WorksheetName names the
+// worksheet when a POU is split over several bodies.
+type Body struct {
+	IL            *FormattedText `xml:"IL,omitempty" json:"il,omitempty"`
+	ST            *FormattedText `xml:"ST,omitempty" json:"st,omitempty"`
+	FBD           *BodyFBD       `xml:"FBD,omitempty" json:"fbd,omitempty"`
+	LD            *BodyLD        `xml:"LD,omitempty" json:"ld,omitempty"`
+	SFC           *BodySFC       `xml:"SFC,omitempty" json:"sfc,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	WorksheetName *string        `xml:"WorksheetName,attr,omitempty" json:"worksheetName,omitempty"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBD represents a Function Block Diagram body.
+// Objects are grouped by element kind; use Objects for document order.
+type BodyFBD struct {
+	// Common objects
+	Comments       []BodyFBDComment       `xml:"comment,omitempty" json:"comments,omitempty"`
+	Errors         []BodyFBDError         `xml:"error,omitempty" json:"errors,omitempty"`
+	Connectors     []BodyFBDConnector     `xml:"connector,omitempty" json:"connectors,omitempty"`
+	Continuations  []BodyFBDContinuation  `xml:"continuation,omitempty" json:"continuations,omitempty"`
+	ActionBlocks   []BodyFBDActionBlock   `xml:"actionBlock,omitempty" json:"actionBlocks,omitempty"`
+	VendorElements []BodyFBDVendorElement `xml:"vendorElement,omitempty" json:"vendorElements,omitempty"`
+
+	// FBD objects
+	Blocks         []BodyFBDBlock         `xml:"block,omitempty" json:"blocks,omitempty"`
+	InVariables    []BodyFBDInVariable    `xml:"inVariable,omitempty" json:"inVariables,omitempty"`
+	OutVariables   []BodyFBDOutVariable   `xml:"outVariable,omitempty" json:"outVariables,omitempty"`
+	InOutVariables []BodyFBDInOutVariable `xml:"inOutVariable,omitempty" json:"inOutVariables,omitempty"`
+	Labels         []BodyFBDLabel         `xml:"label,omitempty" json:"labels,omitempty"`
+	Jumps          []BodyFBDJump          `xml:"jump,omitempty" json:"jumps,omitempty"`
+	Returns        []BodyFBDReturn        `xml:"return,omitempty" json:"returns,omitempty"`
+
+	// Children the schema does not define, kept as they are
+	Unknown []UnknownObject `xml:"-" json:"unknown,omitempty"`
+
+	order []objectKey // document order of decoded objects, see Objects
+}
+
+// BodyLD represents a Ladder Diagram body, which may also hold FBD objects.
+// Objects are grouped by element kind; use Objects for document order.
+type BodyLD struct {
+	// Common objects
+	Comments       []BodyFBDComment       `xml:"comment,omitempty" json:"comments,omitempty"`
+	Errors         []BodyFBDError         `xml:"error,omitempty" json:"errors,omitempty"`
+	Connectors     []BodyFBDConnector     `xml:"connector,omitempty" json:"connectors,omitempty"`
+	Continuations  []BodyFBDContinuation  `xml:"continuation,omitempty" json:"continuations,omitempty"`
+	ActionBlocks   []BodyFBDActionBlock   `xml:"actionBlock,omitempty" json:"actionBlocks,omitempty"`
+	VendorElements []BodyFBDVendorElement `xml:"vendorElement,omitempty" json:"vendorElements,omitempty"`
+
+	// FBD objects
+	Blocks         []BodyFBDBlock         `xml:"block,omitempty" json:"blocks,omitempty"`
+	InVariables    []BodyFBDInVariable    `xml:"inVariable,omitempty" json:"inVariables,omitempty"`
+	OutVariables   []BodyFBDOutVariable   `xml:"outVariable,omitempty" json:"outVariables,omitempty"`
+	InOutVariables []BodyFBDInOutVariable `xml:"inOutVariable,omitempty" json:"inOutVariables,omitempty"`
+	Labels         []BodyFBDLabel         `xml:"label,omitempty" json:"labels,omitempty"`
+	Jumps          []BodyFBDJump          `xml:"jump,omitempty" json:"jumps,omitempty"`
+	Returns        []BodyFBDReturn        `xml:"return,omitempty" json:"returns,omitempty"`
+
+	// LD objects
+	LeftPowerRails  []BodyLDLeftPowerRail  `xml:"leftPowerRail,omitempty" json:"leftPowerRails,omitempty"`
+	RightPowerRails []BodyLDRightPowerRail `xml:"rightPowerRail,omitempty" json:"rightPowerRails,omitempty"`
+	Coils           []BodyLDCoil           `xml:"coil,omitempty" json:"coils,omitempty"`
+	Contacts        []BodyLDContact        `xml:"contact,omitempty" json:"contacts,omitempty"`
+
+	// Children the schema does not define, kept as they are
+	Unknown []UnknownObject `xml:"-" json:"unknown,omitempty"`
+
+	order []objectKey // document order of decoded objects, see Objects
+}
+
+// BodySFC represents a Sequential Function Chart body, which may also hold
+// FBD and LD objects. Objects are grouped by element kind; use Objects for
+// document order.
+type BodySFC struct {
+	// Common objects
+	Comments       []BodyFBDComment       `xml:"comment,omitempty" json:"comments,omitempty"`
+	Errors         []BodyFBDError         `xml:"error,omitempty" json:"errors,omitempty"`
+	Connectors     []BodyFBDConnector     `xml:"connector,omitempty" json:"connectors,omitempty"`
+	Continuations  []BodyFBDContinuation  `xml:"continuation,omitempty" json:"continuations,omitempty"`
+	ActionBlocks   []BodyFBDActionBlock   `xml:"actionBlock,omitempty" json:"actionBlocks,omitempty"`
+	VendorElements []BodyFBDVendorElement `xml:"vendorElement,omitempty" json:"vendorElements,omitempty"`
+
+	// FBD objects
+	Blocks         []BodyFBDBlock         `xml:"block,omitempty" json:"blocks,omitempty"`
+	InVariables    []BodyFBDInVariable    `xml:"inVariable,omitempty" json:"inVariables,omitempty"`
+	OutVariables   []BodyFBDOutVariable   `xml:"outVariable,omitempty" json:"outVariables,omitempty"`
+	InOutVariables []BodyFBDInOutVariable `xml:"inOutVariable,omitempty" json:"inOutVariables,omitempty"`
+	Labels         []BodyFBDLabel         `xml:"label,omitempty" json:"labels,omitempty"`
+	Jumps          []BodyFBDJump          `xml:"jump,omitempty" json:"jumps,omitempty"`
+	Returns        []BodyFBDReturn        `xml:"return,omitempty" json:"returns,omitempty"`
+
+	// LD objects
+	LeftPowerRails  []BodyLDLeftPowerRail  `xml:"leftPowerRail,omitempty" json:"leftPowerRails,omitempty"`
+	RightPowerRails []BodyLDRightPowerRail `xml:"rightPowerRail,omitempty" json:"rightPowerRails,omitempty"`
+	Coils           []BodyLDCoil           `xml:"coil,omitempty" json:"coils,omitempty"`
+	Contacts        []BodyLDContact        `xml:"contact,omitempty" json:"contacts,omitempty"`
+
+	// SFC objects
+	Steps                    []BodySFCStep                    `xml:"step,omitempty" json:"steps,omitempty"`
+	MacroSteps               []BodySFCMacroStep               `xml:"macroStep,omitempty" json:"macroSteps,omitempty"`
+	JumpSteps                []BodySFCJumpStep                `xml:"jumpStep,omitempty" json:"jumpSteps,omitempty"`
+	Transitions              []BodySFCTransition              `xml:"transition,omitempty" json:"transitions,omitempty"`
+	SelectionDivergences     []BodySFCSelectionDivergence     `xml:"selectionDivergence,omitempty" json:"selectionDivergences,omitempty"`
+	SelectionConvergences    []BodySFCSelectionConvergence    `xml:"selectionConvergence,omitempty" json:"selectionConvergences,omitempty"`
+	SimultaneousDivergences  []BodySFCSimultaneousDivergence  `xml:"simultaneousDivergence,omitempty" json:"simultaneousDivergences,omitempty"`
+	SimultaneousConvergences []BodySFCSimultaneousConvergence `xml:"simultaneousConvergence,omitempty" json:"simultaneousConvergences,omitempty"`
+
+	// Children the schema does not define, kept as they are
+	Unknown []UnknownObject `xml:"-" json:"unknown,omitempty"`
+
+	order []objectKey // document order of decoded objects, see Objects
+}
+
+// Connection represents a connection. Positions is the routing path of the
+// wire, from the input pin of the consumer to the output pin of the producer.
+type Connection struct {
+	Positions       []Position `xml:"position,omitempty" json:"positions,omitempty"`
+	AddData         *AddData   `xml:"addData,omitempty" json:"addData,omitempty"`
+	RefLocalID      uint64     `xml:"refLocalId,attr" json:"refLocalId"`
+	FormalParameter *string    `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
+	GlobalID        *string    `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ConnectionPointIn represents an input connection point. RelPosition is
+// relative to the anchor position of the owning object.
+type ConnectionPointIn struct {
+	RelPosition *Position    `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
+	Connections []Connection `xml:"connection,omitempty" json:"connections,omitempty"`
+	Expression  *string      `xml:"expression,omitempty" json:"expression,omitempty"`
+	AddData     *AddData     `xml:"addData,omitempty" json:"addData,omitempty"`
+	GlobalID    *string      `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// ConnectionPointOut represents an output connection point. FormalParameter
+// is only used where the schema extends the type, e.g. on power rails, steps
+// and divergences.
+type ConnectionPointOut struct {
+	RelPosition     *Position `xml:"relPosition,omitempty" json:"relPosition,omitempty"`
+	Expression      *string   `xml:"expression,omitempty" json:"expression,omitempty"`
+	AddData         *AddData  `xml:"addData,omitempty" json:"addData,omitempty"`
+	GlobalID        *string   `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+	FormalParameter *string   `xml:"formalParameter,attr,omitempty" json:"formalParameter,omitempty"`
+}
+
+// BodyFBDComment represents a comment in a graphical body
+type BodyFBDComment struct {
+	Position      *Position      `xml:"position" json:"position,omitempty"`
+	Content       *FormattedText `xml:"content" json:"content,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID       uint64         `xml:"localId,attr" json:"localId"`
+	Height        float64        `xml:"height,attr" json:"height"`
+	Width         float64        `xml:"width,attr" json:"width"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDError represents an error object, used by tools to mark a part of
+// the diagram that could not be processed
+type BodyFBDError struct {
+	Position      *Position      `xml:"position" json:"position,omitempty"`
+	Content       *FormattedText `xml:"content" json:"content,omitempty"`
+	AddData       *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID       uint64         `xml:"localId,attr" json:"localId"`
+	Height        float64        `xml:"height,attr" json:"height"`
+	Width         float64        `xml:"width,attr" json:"width"`
+	GlobalID      *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDConnector represents a connector, the sink side of a named
+// connection that is continued elsewhere by a BodyFBDContinuation
+type BodyFBDConnector struct {
+	Position          *Position          `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	AddData           *AddData           `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name              string             `xml:"name,attr" json:"name"`
+	LocalID           uint64             `xml:"localId,attr" json:"localId"`
+	Height            *float64           `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64           `xml:"width,attr,omitempty" json:"width,omitempty"`
+	GlobalID          *string            `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDContinuation represents a continuation, the source side of a named
+// connection started by a BodyFBDConnector
+type BodyFBDContinuation struct {
+	Position           *Position           `xml:"position" json:"position,omitempty"`
+	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	Name               string              `xml:"name,attr" json:"name"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+	GlobalID           *string             `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDActionBlock represents an action block
+type BodyFBDActionBlock struct {
+	Position          *Position                  `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn         `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	Actions           []BodyFBDActionBlockAction `xml:"action,omitempty" json:"actions,omitempty"`
+	AddData           *AddData                   `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText             `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64                     `xml:"localId,attr" json:"localId"`
+	Height            *float64                   `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64                   `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Negated           *bool                      `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+	ExecutionOrderID  *uint64                    `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID          *string                    `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDVendorElement represents an application specific graphical object.
+// Tools that do not know it show AlternativeText; the data of the tool that
+// wrote it is kept in AddData.
+type BodyFBDVendorElement struct {
+	Position        *Position              `xml:"position" json:"position,omitempty"`
+	AlternativeText *FormattedText         `xml:"alternativeText" json:"alternativeText,omitempty"`
+	InputVariables  []BodyFBDBlockVariable `xml:"inputVariables>variable,omitempty" json:"inputVariables,omitempty"`
+	InOutVariables  []BodyFBDBlockVariable `xml:"inOutVariables>variable,omitempty" json:"inOutVariables,omitempty"`
+	OutputVariables []BodyFBDBlockVariable `xml:"outputVariables>variable,omitempty" json:"outputVariables,omitempty"`
+	AddData         *AddData               `xml:"addData" json:"addData,omitempty"`
+	Documentation   *FormattedText         `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID         uint64                 `xml:"localId,attr" json:"localId"`
+	Width           *float64               `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Height          *float64               `xml:"height,attr,omitempty" json:"height,omitempty"`
+	GlobalID        *string                `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// UnknownObject is a child element of a graphical body that the schema does
+// not define, e.g. one written by a tool for a later schema version. It is
+// kept as raw XML and written back unchanged; Downgrade reports it.
+type UnknownObject struct {
+	XMLName  xml.Name   `json:"name"`
+	Attrs    []xml.Attr `xml:",any,attr" json:"attrs,omitempty"`
+	InnerXML string     `xml:",innerxml" json:"innerXml,omitempty"`
+}
+
+// BodyFBDActionBlockAction represents an action of an action block, either a
+// reference to a POU action or an inline body
+type BodyFBDActionBlockAction struct {
+	RelPosition        *Position                                  `xml:"relPosition" json:"relPosition,omitempty"`
+	Reference          *BodyFBDActionBlockActionReference         `xml:"reference,omitempty" json:"reference,omitempty"`
+	Inline             *Body                                      `xml:"inline,omitempty" json:"inline,omitempty"`
+	ConnectionPointOut *ConnectionPointOut                        `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData                                   `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText                             `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64                                     `xml:"localId,attr" json:"localId"`
+	Qualifier          *plcopen.BodyFBDActionBlockActionQualifier `xml:"qualifier,attr,omitempty" json:"qualifier,omitempty"`
+	Width              *float64                                   `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Height             *float64                                   `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Duration           *string                                    `xml:"duration,attr,omitempty" json:"duration,omitempty"`
+	Indicator          *string                                    `xml:"indicator,attr,omitempty" json:"indicator,omitempty"`
+	ExecutionOrderID   *uint64                                    `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID           *string                                    `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDActionBlockActionReference represents an action reference
+type BodyFBDActionBlockActionReference struct {
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// BodyFBDBlock represents a function or function block call
+type BodyFBDBlock struct {
+	Position         *Position              `xml:"position" json:"position,omitempty"`
+	InputVariables   []BodyFBDBlockVariable `xml:"inputVariables>variable" json:"inputVariables,omitempty"`
+	InOutVariables   []BodyFBDBlockVariable `xml:"inOutVariables>variable" json:"inOutVariables,omitempty"`
+	OutputVariables  []BodyFBDBlockVariable `xml:"outputVariables>variable" json:"outputVariables,omitempty"`
+	AddData          *AddData               `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation    *FormattedText         `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID          uint64                 `xml:"localId,attr" json:"localId"`
+	Width            *float64               `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Height           *float64               `xml:"height,attr,omitempty" json:"height,omitempty"`
+	TypeName         string                 `xml:"typeName,attr" json:"typeName"`
+	InstanceName     *string                `xml:"instanceName,attr,omitempty" json:"instanceName,omitempty"`
+	ExecutionOrderID *uint64                `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID         *string                `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDBlockVariable represents a formal parameter of a block. Input
+// variables use ConnectionPointIn, output variables ConnectionPointOut and
+// in-out variables both.
+type BodyFBDBlockVariable struct {
+	ConnectionPointIn  *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut  `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	FormalParameter    string               `xml:"formalParameter,attr" json:"formalParameter"`
+	Negated            *bool                `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+	Edge               *EdgeModifierType    `xml:"edge,attr,omitempty" json:"edge,omitempty"`
+	Storage            *StorageModifierType `xml:"storage,attr,omitempty" json:"storage,omitempty"`
+	Hidden             *bool                `xml:"hidden,attr,omitempty" json:"hidden,omitempty"`
+}
+
+// BodyFBDInVariable represents a variable, literal or expression used as r-value
+type BodyFBDInVariable struct {
+	Position           *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointOut *ConnectionPointOut  `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Expression         string               `xml:"expression" json:"expression"`
+	AddData            *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64               `xml:"localId,attr" json:"localId"`
+	Height             *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	ExecutionOrderID   *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	Negated            *bool                `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+	Edge               *EdgeModifierType    `xml:"edge,attr,omitempty" json:"edge,omitempty"`
+	Storage            *StorageModifierType `xml:"storage,attr,omitempty" json:"storage,omitempty"`
+	GlobalID           *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDOutVariable represents a variable or expression used as l-value
+type BodyFBDOutVariable struct {
+	Position          *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	Expression        string               `xml:"expression" json:"expression"`
+	AddData           *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64               `xml:"localId,attr" json:"localId"`
+	Height            *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	ExecutionOrderID  *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	Negated           *bool                `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+	Edge              *EdgeModifierType    `xml:"edge,attr,omitempty" json:"edge,omitempty"`
+	Storage           *StorageModifierType `xml:"storage,attr,omitempty" json:"storage,omitempty"`
+	GlobalID          *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDInOutVariable represents a variable used as l-value and r-value
+type BodyFBDInOutVariable struct {
+	Position           *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut  `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Expression         string               `xml:"expression" json:"expression"`
+	AddData            *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64               `xml:"localId,attr" json:"localId"`
+	Height             *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	ExecutionOrderID   *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	NegatedIn          *bool                `xml:"negatedIn,attr,omitempty" json:"negatedIn,omitempty"`
+	EdgeIn             *EdgeModifierType    `xml:"edgeIn,attr,omitempty" json:"edgeIn,omitempty"`
+	StorageIn          *StorageModifierType `xml:"storageIn,attr,omitempty" json:"storageIn,omitempty"`
+	NegatedOut         *bool                `xml:"negatedOut,attr,omitempty" json:"negatedOut,omitempty"`
+	EdgeOut            *EdgeModifierType    `xml:"edgeOut,attr,omitempty" json:"edgeOut,omitempty"`
+	StorageOut         *StorageModifierType `xml:"storageOut,attr,omitempty" json:"storageOut,omitempty"`
+	GlobalID           *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDLabel represents a jump target
+type BodyFBDLabel struct {
+	Position         *Position      `xml:"position" json:"position,omitempty"`
+	AddData          *AddData       `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation    *FormattedText `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID          uint64         `xml:"localId,attr" json:"localId"`
+	Height           *float64       `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width            *float64       `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Label            string         `xml:"label,attr" json:"label"`
+	ExecutionOrderID *uint64        `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID         *string        `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDJump represents a jump to a label
+type BodyFBDJump struct {
+	Position          *Position          `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	AddData           *AddData           `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64             `xml:"localId,attr" json:"localId"`
+	Height            *float64           `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64           `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Label             string             `xml:"label,attr" json:"label"`
+	ExecutionOrderID  *uint64            `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID          *string            `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyFBDReturn represents a conditional return
+type BodyFBDReturn struct {
+	Position          *Position          `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	AddData           *AddData           `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64             `xml:"localId,attr" json:"localId"`
+	Height            *float64           `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64           `xml:"width,attr,omitempty" json:"width,omitempty"`
+	ExecutionOrderID  *uint64            `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID          *string            `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyLDLeftPowerRail represents a left power rail. Each connection point
+// carries a formalParameter.
+type BodyLDLeftPowerRail struct {
+	Position            *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointOuts []ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOuts,omitempty"`
+	AddData             *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation       *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID             uint64               `xml:"localId,attr" json:"localId"`
+	Height              *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width               *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	GlobalID            *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyLDRightPowerRail represents a right power rail
+type BodyLDRightPowerRail struct {
+	Position           *Position           `xml:"position" json:"position,omitempty"`
+	ConnectionPointIns []ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIns,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+	GlobalID           *string             `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyLDCoil represents a coil
+type BodyLDCoil struct {
+	Position           *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut  `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Variable           string               `xml:"variable" json:"variable"`
+	AddData            *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64               `xml:"localId,attr" json:"localId"`
+	Height             *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	ExecutionOrderID   *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	Negated            *bool                `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+	Edge               *EdgeModifierType    `xml:"edge,attr,omitempty" json:"edge,omitempty"`
+	Storage            *StorageModifierType `xml:"storage,attr,omitempty" json:"storage,omitempty"`
+	GlobalID           *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodyLDContact represents a contact
+type BodyLDContact struct {
+	Position           *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut  `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Variable           string               `xml:"variable" json:"variable"`
+	AddData            *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64               `xml:"localId,attr" json:"localId"`
+	Height             *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	ExecutionOrderID   *uint64              `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	Negated            *bool                `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+	Edge               *EdgeModifierType    `xml:"edge,attr,omitempty" json:"edge,omitempty"`
+	Storage            *StorageModifierType `xml:"storage,attr,omitempty" json:"storage,omitempty"`
+	GlobalID           *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCStep represents a step. The output connection points carry a
+// formalParameter.
+type BodySFCStep struct {
+	Position                 *Position           `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn        *ConnectionPointIn  `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut       *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	ConnectionPointOutAction *ConnectionPointOut `xml:"connectionPointOutAction,omitempty" json:"connectionPointOutAction,omitempty"`
+	AddData                  *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation            *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID                  uint64              `xml:"localId,attr" json:"localId"`
+	Height                   *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width                    *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Name                     string              `xml:"name,attr" json:"name"`
+	InitialStep              *bool               `xml:"initialStep,attr,omitempty" json:"initialStep,omitempty"`
+	Negated                  *bool               `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+	ExecutionOrderID         *uint64             `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID                 *string             `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCMacroStep represents a macro step whose body holds a nested sequence
+type BodySFCMacroStep struct {
+	Position           *Position           `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn  `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Body               *Body               `xml:"body,omitempty" json:"body,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Name               *string             `xml:"name,attr,omitempty" json:"name,omitempty"`
+	ExecutionOrderID   *uint64             `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID           *string             `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCJumpStep represents a jump to the step named TargetName
+type BodySFCJumpStep struct {
+	Position          *Position          `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn *ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	AddData           *AddData           `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation     *FormattedText     `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID           uint64             `xml:"localId,attr" json:"localId"`
+	Height            *float64           `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width             *float64           `xml:"width,attr,omitempty" json:"width,omitempty"`
+	TargetName        string             `xml:"targetName,attr" json:"targetName"`
+	ExecutionOrderID  *uint64            `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID          *string            `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCTransition represents a transition
+type BodySFCTransition struct {
+	Position           *Position                   `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn  *ConnectionPointIn          `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOut *ConnectionPointOut         `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	Condition          *BodySFCTransitionCondition `xml:"condition,omitempty" json:"condition,omitempty"`
+	AddData            *AddData                    `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText              `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64                      `xml:"localId,attr" json:"localId"`
+	Height             *float64                    `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64                    `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Priority           *uint64                     `xml:"priority,attr,omitempty" json:"priority,omitempty"`
+	ExecutionOrderID   *uint64                     `xml:"executionOrderId,attr,omitempty" json:"executionOrderId,omitempty"`
+	GlobalID           *string                     `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCTransitionCondition represents a transition condition: a reference
+// to a POU transition, a connection to a graphical condition or an inline body
+type BodySFCTransitionCondition struct {
+	Reference         *BodySFCTransitionConditionReference `xml:"reference,omitempty" json:"reference,omitempty"`
+	ConnectionPointIn *ConnectionPointIn                   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	Inline            *BodySFCTransitionConditionInline    `xml:"inline,omitempty" json:"inline,omitempty"`
+	Negated           *bool                                `xml:"negated,attr,omitempty" json:"negated,omitempty"`
+}
+
+// BodySFCTransitionConditionReference represents a transition condition reference
+type BodySFCTransitionConditionReference struct {
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// BodySFCTransitionConditionInline represents an inline transition condition
+type BodySFCTransitionConditionInline struct {
+	Body
+	Name string `xml:"name,attr" json:"name"`
+}
+
+// BodySFCSelectionDivergence represents a selection divergence (alternative
+// branch start). Each output connection point carries a formalParameter.
+type BodySFCSelectionDivergence struct {
+	Position            *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn   *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOuts []ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOuts,omitempty"`
+	AddData             *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation       *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID             uint64               `xml:"localId,attr" json:"localId"`
+	Height              *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width               *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	GlobalID            *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCSelectionConvergence represents a selection convergence (alternative branch end)
+type BodySFCSelectionConvergence struct {
+	Position           *Position           `xml:"position" json:"position,omitempty"`
+	ConnectionPointIns []ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIns,omitempty"`
+	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+	GlobalID           *string             `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCSimultaneousDivergence represents a simultaneous divergence
+// (parallel branch start). Each output connection point carries a
+// formalParameter.
+type BodySFCSimultaneousDivergence struct {
+	Position            *Position            `xml:"position" json:"position,omitempty"`
+	ConnectionPointIn   *ConnectionPointIn   `xml:"connectionPointIn,omitempty" json:"connectionPointIn,omitempty"`
+	ConnectionPointOuts []ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOuts,omitempty"`
+	AddData             *AddData             `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation       *FormattedText       `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID             uint64               `xml:"localId,attr" json:"localId"`
+	Height              *float64             `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width               *float64             `xml:"width,attr,omitempty" json:"width,omitempty"`
+	Name                *string              `xml:"name,attr,omitempty" json:"name,omitempty"`
+	GlobalID            *string              `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
+
+// BodySFCSimultaneousConvergence represents a simultaneous convergence (parallel branch end)
+type BodySFCSimultaneousConvergence struct {
+	Position           *Position           `xml:"position" json:"position,omitempty"`
+	ConnectionPointIns []ConnectionPointIn `xml:"connectionPointIn,omitempty" json:"connectionPointIns,omitempty"`
+	ConnectionPointOut *ConnectionPointOut `xml:"connectionPointOut,omitempty" json:"connectionPointOut,omitempty"`
+	AddData            *AddData            `xml:"addData,omitempty" json:"addData,omitempty"`
+	Documentation      *FormattedText      `xml:"documentation,omitempty" json:"documentation,omitempty"`
+	LocalID            uint64              `xml:"localId,attr" json:"localId"`
+	Height             *float64            `xml:"height,attr,omitempty" json:"height,omitempty"`
+	Width              *float64            `xml:"width,attr,omitempty" json:"width,omitempty"`
+	GlobalID           *string             `xml:"globalId,attr,omitempty" json:"globalId,omitempty"`
+}
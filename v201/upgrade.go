@@ -0,0 +1,558 @@
+package v201
+
+import (
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+// Upgrade converts a TC6 XML V1.0B project to TC6 XML 2.01. Elements that
+// have no 2.01 equivalent are dropped and reported as warnings. Leaf values
+// such as positions, formatted text, addData and initial values are shared
+// between p and the result.
+func Upgrade(p *plcopen.Project) (*Project, []Warning) {
+	if p == nil {
+		return nil, nil
+	}
+	c := &converter{}
+	out := &Project{
+		AddData: p.AddData,
+	}
+	if h := p.FileHeader; h != nil {
+		out.FileHeader = &ProjectFileHeader{
+			CompanyName:        h.CompanyName,
+			CompanyURL:         h.CompanyURL,
+			ProductName:        h.ProductName,
+			ProductVersion:     h.ProductVersion,
+			ProductRelease:     h.ProductRelease,
+			CreationDateTime:   h.CreationDateTime,
+			ContentDescription: h.ContentDescription,
+		}
+	}
+	if h := p.ContentHeader; h != nil {
+		out.ContentHeader = &ProjectContentHeader{
+			Comment:              h.Comment,
+			Name:                 h.Name,
+			Version:              h.Version,
+			ModificationDateTime: h.ModificationDateTime,
+			Organization:         h.Organization,
+			Author:               h.Author,
+			Language:             h.Language,
+		}
+		if ci := h.CoordinateInfo; ci != nil {
+			info := &ProjectContentHeaderCoordinateInfo{}
+			if ci.PageSize != nil {
+				info.PageSize = &ProjectContentHeaderCoordinateInfoPageSize{X: ci.PageSize.X, Y: ci.PageSize.Y}
+			}
+			if ci.FBD != nil {
+				info.FBD = &ProjectContentHeaderCoordinateInfoScaled{}
+				if s := ci.FBD.Scaling; s != nil {
+					info.FBD.Scaling = &ProjectContentHeaderCoordinateInfoScaling{X: s.X, Y: s.Y}
+				}
+			}
+			if ci.LD != nil {
+				info.LD = &ProjectContentHeaderCoordinateInfoScaled{}
+				if s := ci.LD.Scaling; s != nil {
+					info.LD.Scaling = &ProjectContentHeaderCoordinateInfoScaling{X: s.X, Y: s.Y}
+				}
+			}
+			if ci.SFC != nil {
+				info.SFC = &ProjectContentHeaderCoordinateInfoScaled{}
+				if s := ci.SFC.Scaling; s != nil {
+					info.SFC.Scaling = &ProjectContentHeaderCoordinateInfoScaling{X: s.X, Y: s.Y}
+				}
+			}
+			out.ContentHeader.CoordinateInfo = info
+		}
+	}
+	if t := p.Types; t != nil {
+		out.Types = &ProjectTypes{}
+		for _, dt := range t.DataTypes {
+			path := named("project/types/dataTypes", "dataType", dt.Name)
+			out.Types.DataTypes = append(out.Types.DataTypes, ProjectTypesDataType{
+				BaseType:      c.upDataType(path+"/baseType", dt.BaseType),
+				InitialValue:  dt.InitialValue,
+				Documentation: dt.Documentation,
+				Name:          dt.Name,
+			})
+		}
+		for _, pou := range t.POUs {
+			out.Types.POUs = append(out.Types.POUs, c.upPOU(named("project/types/pous", "pou", pou.Name), pou))
+		}
+	}
+	if inst := p.Instances; inst != nil {
+		out.Instances = &ProjectInstances{}
+		for _, cfg := range inst.Configurations {
+			out.Instances.Configurations = append(out.Instances.Configurations, c.upConfiguration(cfg))
+		}
+	}
+	return out, c.warnings
+}
+
+func (c *converter) upPOU(path string, pou plcopen.ProjectTypesPOU) ProjectTypesPOU {
+	out := ProjectTypesPOU{
+		AddData:       pou.AddData,
+		Documentation: pou.Documentation,
+		Name:          pou.Name,
+		POUType:       pou.POUType,
+	}
+	if i := pou.Interface; i != nil {
+		out.Interface = &ProjectTypesPOUInterface{
+			ReturnType:    c.upDataType(path+"/interface/returnType", i.ReturnType),
+			Documentation: i.Documentation,
+		}
+		for _, s := range []struct {
+			dst  *[]VarList
+			src  *plcopen.VarList
+			elem string
+		}{
+			{&out.Interface.LocalVars, i.LocalVars, "localVars"},
+			{&out.Interface.TempVars, i.TempVars, "tempVars"},
+			{&out.Interface.InputVars, i.InputVars, "inputVars"},
+			{&out.Interface.OutputVars, i.OutputVars, "outputVars"},
+			{&out.Interface.InOutVars, i.InOutVars, "inOutVars"},
+			{&out.Interface.ExternalVars, i.ExternalVars, "externalVars"},
+			{&out.Interface.GlobalVars, i.GlobalVars, "globalVars"},
+		} {
+			if s.src != nil {
+				*s.dst = []VarList{c.upVarList(path+"/interface/"+s.elem, s.src)}
+			}
+		}
+		if i.AccessVars != nil {
+			out.Interface.AccessVars = []VarListAccess{c.upAccessVars(path+"/interface/accessVars", i.AccessVars)}
+		}
+	}
+	for _, a := range pou.Actions {
+		apath := named(path+"/actions", "action", a.Name)
+		out.Actions = append(out.Actions, ProjectTypesPOUAction{
+			Body:          c.upBody(apath+"/body", a.Body),
+			Documentation: a.Documentation,
+			Name:          a.Name,
+		})
+	}
+	for _, tr := range pou.Transitions {
+		tpath := named(path+"/transitions", "transition", tr.Name)
+		out.Transitions = append(out.Transitions, ProjectTypesPOUTransition{
+			Body:          c.upBody(tpath+"/body", tr.Body),
+			Documentation: tr.Documentation,
+			Name:          tr.Name,
+		})
+	}
+	if pou.Body != nil {
+		out.Bodies = []Body{*c.upBody(path+"/body", pou.Body)}
+	}
+	return out
+}
+
+func (c *converter) upVarList(path string, v *plcopen.VarList) VarList {
+	out := VarList{
+		Documentation: v.Documentation,
+		Name:          v.Name,
+		Constant:      v.Constant,
+		Retain:        v.Retain,
+		NonRetain:     v.NonRetain,
+		Persistent:    v.Persistent,
+		NonPersistent: v.NonPersistent,
+	}
+	for _, variable := range v.Variables {
+		out.Variables = append(out.Variables, Variable{
+			Type:          c.upDataType(named(path, "variable", variable.Name)+"/type", variable.Type),
+			InitialValue:  variable.InitialValue,
+			Documentation: variable.Documentation,
+			Name:          variable.Name,
+			Address:       variable.Address,
+		})
+	}
+	return out
+}
+
+// upAccessVars converts the variable list used for VAR_ACCESS in V1.0B into
+// access paths. The variable name becomes the alias and the address the
+// instance path.
+func (c *converter) upAccessVars(path string, v *plcopen.VarList) VarListAccess {
+	out := VarListAccess{Documentation: v.Documentation}
+	for _, variable := range v.Variables {
+		vpath := named(path, "variable", variable.Name)
+		instancePath := variable.Address
+		if instancePath == "" {
+			instancePath = variable.Name
+			c.warn(vpath, "access variable has no address, using its name as instance path")
+		}
+		if variable.InitialValue != nil {
+			c.warn(vpath, "initial value of access variable dropped")
+		}
+		out.AccessVariables = append(out.AccessVariables, AccessVariable{
+			Type:                c.upDataType(vpath+"/type", variable.Type),
+			Documentation:       variable.Documentation,
+			Alias:               variable.Name,
+			InstancePathAndName: instancePath,
+		})
+	}
+	return out
+}
+
+func (c *converter) upDataType(path string, dt *plcopen.DataType) *DataType {
+	if dt == nil {
+		return nil
+	}
+	out := &DataType{
+		BOOL: dt.BOOL, BYTE: dt.BYTE, WORD: dt.WORD, DWORD: dt.DWORD, LWORD: dt.LWORD,
+		SINT: dt.SINT, INT: dt.INT, DINT: dt.DINT, LINT: dt.LINT,
+		USINT: dt.USINT, UINT: dt.UINT, UDINT: dt.UDINT, ULINT: dt.ULINT,
+		REAL: dt.REAL, LREAL: dt.LREAL,
+		TIME: dt.TIME, DATE: dt.DATE, DT: dt.DT, TOD: dt.TOD,
+	}
+	if dt.String != nil {
+		out.String = &DataTypeString{Length: dt.String.Length}
+	}
+	if dt.WString != nil {
+		out.WString = &DataTypeString{Length: dt.WString.Length}
+	}
+	if a := dt.Array; a != nil {
+		out.Array = &DataTypeArray{Dimensions: a.Dimensions, BaseType: c.upDataType(path+"/array/baseType", a.BaseType)}
+	}
+	if d := dt.Derived; d != nil {
+		out.Derived = &DataTypeDerived{Name: d.Name}
+	}
+	if e := dt.Enum; e != nil {
+		out.Enum = &DataTypeEnum{BaseType: c.upDataType(path+"/enum/baseType", e.BaseType)}
+		if e.Values != nil {
+			for _, v := range e.Values.Values {
+				if v.Documentation != nil {
+					c.warn(named(path+"/enum/values", "value", v.Name), "documentation of enumeration value dropped")
+				}
+				out.Enum.Values = append(out.Enum.Values, DataTypeEnumValue{Name: v.Name, Value: v.Value})
+			}
+		}
+	}
+	if s := dt.Struct; s != nil {
+		out.Struct = &VarListPlain{Documentation: s.Documentation}
+		for _, v := range s.Variables {
+			out.Struct.Variables = append(out.Struct.Variables, Variable{
+				Type:          c.upDataType(named(path+"/struct", "variable", v.Name)+"/type", v.Type),
+				InitialValue:  v.InitialValue,
+				Documentation: v.Documentation,
+				Name:          v.Name,
+				Address:       v.Address,
+			})
+		}
+	}
+	if s := dt.SubrangeSigned; s != nil {
+		out.SubrangeSigned = &DataTypeSubrangeSigned{Range: s.Range, BaseType: c.upDataType(path+"/subrangeSigned/baseType", s.BaseType)}
+	}
+	if s := dt.SubrangeUnsigned; s != nil {
+		out.SubrangeUnsigned = &DataTypeSubrangeUnsigned{Range: s.Range, BaseType: c.upDataType(path+"/subrangeUnsigned/baseType", s.BaseType)}
+	}
+	if p := dt.Pointer; p != nil {
+		out.Pointer = &DataTypePointer{BaseType: c.upDataType(path+"/pointer/baseType", p.BaseType)}
+	}
+	return out
+}
+
+func (c *converter) upConfiguration(cfg plcopen.ProjectInstancesConfiguration) ProjectInstancesConfiguration {
+	path := named("project/instances/configurations", "configuration", cfg.Name)
+	out := ProjectInstancesConfiguration{
+		Documentation: cfg.Documentation,
+		Name:          cfg.Name,
+	}
+	if cfg.GlobalVars != nil {
+		out.GlobalVars = []VarList{c.upVarList(path+"/globalVars", cfg.GlobalVars)}
+	}
+	for _, res := range cfg.Resources {
+		rpath := named(path, "resource", res.Name)
+		r := ProjectInstancesConfigurationResource{
+			POUInstances:  upPOUInstances(res.POUInstances),
+			Documentation: res.Documentation,
+			Name:          res.Name,
+		}
+		if res.GlobalVars != nil {
+			r.GlobalVars = []VarList{c.upVarList(rpath+"/globalVars", res.GlobalVars)}
+		}
+		for _, task := range res.Tasks {
+			r.Tasks = append(r.Tasks, ProjectInstancesConfigurationResourceTask{
+				POUInstances: upPOUInstances(task.POUInstances),
+				Name:         task.Name,
+				Single:       task.Single,
+				Interval:     task.Interval,
+				Priority:     task.Priority,
+			})
+		}
+		out.Resources = append(out.Resources, r)
+	}
+	return out
+}
+
+func upPOUInstances(instances []plcopen.POUInstance) []POUInstance {
+	var out []POUInstance
+	for _, inst := range instances {
+		out = append(out, POUInstance{Documentation: inst.Documentation, Name: inst.Name, TypeName: inst.TypeName})
+	}
+	return out
+}
+
+func (c *converter) upBody(path string, b *plcopen.Body) *Body {
+	if b == nil {
+		return nil
+	}
+	out := &Body{AddData: b.AddData}
+	if b.IL != nil {
+		out.IL = b.IL.Xhtml
+	}
+	if b.ST != nil {
+		out.ST = b.ST.Xhtml
+	}
+	if b.FBD != nil {
+		out.FBD = &BodyFBD{}
+		c.setObjects(path+"/FBD", out.FBD, c.upObjects(path+"/FBD", b.FBD.Objects()))
+	}
+	if b.LD != nil {
+		out.LD = &BodyLD{}
+		c.setObjects(path+"/LD", out.LD, c.upObjects(path+"/LD", b.LD.Objects()))
+	}
+	if b.SFC != nil {
+		out.SFC = &BodySFC{}
+		c.setObjects(path+"/SFC", out.SFC, c.upObjects(path+"/SFC", b.SFC.Objects()))
+	}
+	return out
+}
+
+func (c *converter) upObjects(path string, objs []GraphicalObject) []GraphicalObject {
+	var out []GraphicalObject
+	for _, obj := range objs {
+		opath := local(path, obj)
+		switch o := obj.(type) {
+		case *plcopen.BodyFBDComment:
+			content := o.Content
+			if content == nil {
+				content = plcopen.NewFormattedText("")
+			}
+			out = append(out, &BodyFBDComment{
+				Position: o.Position, Content: content, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: deref(o.Height), Width: deref(o.Width),
+			})
+		case *plcopen.BodyFBDError:
+			content := o.Content
+			if content == nil {
+				content = plcopen.NewFormattedText("")
+			}
+			out = append(out, &BodyFBDError{
+				Position: o.Position, Content: content, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: deref(o.Height), Width: deref(o.Width),
+			})
+		case *plcopen.BodyFBDConnector:
+			out = append(out, &BodyFBDConnector{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				Name: o.Name, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *plcopen.BodyFBDContinuation:
+			out = append(out, &BodyFBDContinuation{
+				Position: o.Position, ConnectionPointOut: upPointOut(o.ConnectionPointOut), AddData: o.AddData, Documentation: o.Documentation,
+				Name: o.Name, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *plcopen.BodyFBDActionBlock:
+			if o.ConnectionPointOut != nil {
+				c.warn(opath, "connectionPointOut of action block dropped")
+			}
+			ab := &BodyFBDActionBlock{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Negated: o.Negated,
+			}
+			for i, a := range o.Actions {
+				action := BodyFBDActionBlockAction{
+					Documentation: a.Documentation, Qualifier: a.Qualifier, Duration: a.Duration, Indicator: a.Indicator,
+				}
+				if a.Reference != nil {
+					action.Reference = &BodyFBDActionBlockActionReference{Name: a.Reference.Name}
+				}
+				if a.Inline != nil {
+					if a.Inline.Name != "" {
+						c.warn(indexed(opath, "action", i)+"/inline", "name of inline action dropped")
+					}
+					action.Inline = c.upBody(indexed(opath, "action", i)+"/inline", a.Inline.Body)
+					if action.Inline == nil {
+						action.Inline = &Body{}
+					}
+				}
+				ab.Actions = append(ab.Actions, action)
+			}
+			out = append(out, ab)
+		case *plcopen.BodyFBDBlock:
+			block := &BodyFBDBlock{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID,
+				Width: o.Width, Height: o.Height, TypeName: o.TypeName, InstanceName: o.InstanceName, ExecutionOrderID: o.ExecutionOrderID,
+			}
+			for _, v := range o.InputVariables {
+				block.InputVariables = append(block.InputVariables, BodyFBDBlockVariable{FormalParameter: v.FormalParameter, ConnectionPointIn: upPointIn(v.ConnectionPointIn)})
+			}
+			for _, v := range o.InOutVariables {
+				block.InOutVariables = append(block.InOutVariables, BodyFBDBlockVariable{FormalParameter: v.FormalParameter, ConnectionPointIn: upPointIn(v.ConnectionPointIn), ConnectionPointOut: upPointOut(v.ConnectionPointOut)})
+			}
+			for _, v := range o.OutputVariables {
+				block.OutputVariables = append(block.OutputVariables, BodyFBDBlockVariable{FormalParameter: v.FormalParameter, ConnectionPointOut: upPointOut(v.ConnectionPointOut)})
+			}
+			out = append(out, block)
+		case *plcopen.BodyFBDInVariable:
+			out = append(out, &BodyFBDInVariable{
+				Position: o.Position, ConnectionPointOut: upPointOut(o.ConnectionPointOut), Expression: o.Expression, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, ExecutionOrderID: o.ExecutionOrderID, Edge: o.EdgeModifier,
+			})
+		case *plcopen.BodyFBDOutVariable:
+			out = append(out, &BodyFBDOutVariable{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), Expression: o.Expression, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, ExecutionOrderID: o.ExecutionOrderID, Edge: o.EdgeModifier, Storage: o.StorageModifier,
+			})
+		case *plcopen.BodyFBDInOutVariable:
+			// The edge is detected on reading and the storage applies on writing
+			out = append(out, &BodyFBDInOutVariable{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), ConnectionPointOut: upPointOut(o.ConnectionPointOut),
+				Expression: o.Expression, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, ExecutionOrderID: o.ExecutionOrderID, EdgeIn: o.EdgeModifier, StorageOut: o.StorageModifier,
+			})
+		case *plcopen.BodyFBDLabel:
+			out = append(out, &BodyFBDLabel{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width, Label: o.Label,
+			})
+		case *plcopen.BodyFBDJump:
+			out = append(out, &BodyFBDJump{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width, Label: o.Label,
+			})
+		case *plcopen.BodyFBDReturn:
+			out = append(out, &BodyFBDReturn{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *plcopen.BodyLDContact:
+			out = append(out, &BodyLDContact{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), ConnectionPointOut: upPointOut(o.ConnectionPointOut),
+				Variable: o.Variable, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Negated: o.Negated, Edge: o.EdgeModifier,
+			})
+		case *plcopen.BodyLDCoil:
+			out = append(out, &BodyLDCoil{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), ConnectionPointOut: upPointOut(o.ConnectionPointOut),
+				Variable: o.Variable, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Negated: o.Negated, Edge: o.EdgeModifier, Storage: o.StorageModifier,
+			})
+		case *plcopen.BodyLDLeftPowerRail:
+			rail := &BodyLDLeftPowerRail{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			}
+			if o.ConnectionPointOut != nil {
+				rail.ConnectionPointOuts = []ConnectionPointOut{*upPointOut(o.ConnectionPointOut)}
+			}
+			out = append(out, rail)
+		case *plcopen.BodyLDRightPowerRail:
+			rail := &BodyLDRightPowerRail{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			}
+			if o.ConnectionPointIn != nil {
+				rail.ConnectionPointIns = []ConnectionPointIn{*upPointIn(o.ConnectionPointIn)}
+			}
+			out = append(out, rail)
+		case *plcopen.BodySFCStep:
+			step := &BodySFCStep{
+				Position: o.Position, AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Name: o.Name, InitialStep: o.InitialStep,
+			}
+			if p := o.ConnectionPointIn; p != nil {
+				step.ConnectionPointIn = &ConnectionPointIn{RelPosition: p.RelPosition, Connections: upConnections(p.Connections), Expression: p.Expression}
+			}
+			if p := o.ConnectionPointOut; p != nil {
+				step.ConnectionPointOut = &ConnectionPointOut{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: p.FormalParameter}
+			}
+			if p := o.ConnectionPointOutAction; p != nil {
+				step.ConnectionPointOutAction = &ConnectionPointOut{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: p.FormalParameter}
+			}
+			out = append(out, step)
+		case *plcopen.BodySFCMacroStep:
+			out = append(out, &BodySFCMacroStep{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), ConnectionPointOut: upPointOut(o.ConnectionPointOut),
+				Body: c.upBody(opath+"/body", o.Body), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Name: o.Name,
+			})
+		case *plcopen.BodySFCJumpStep:
+			out = append(out, &BodySFCJumpStep{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, TargetName: o.TargetName,
+			})
+		case *plcopen.BodySFCTransition:
+			tr := &BodySFCTransition{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), ConnectionPointOut: upPointOut(o.ConnectionPointOut),
+				AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Priority: o.Priority,
+			}
+			if cond := o.Condition; cond != nil {
+				tr.Condition = &BodySFCTransitionCondition{}
+				if cond.Reference != nil {
+					tr.Condition.Reference = &BodySFCTransitionConditionReference{Name: cond.Reference.Name}
+				}
+				if cond.Inline != nil {
+					inline := &BodySFCTransitionConditionInline{Name: cond.Inline.Name}
+					if body := c.upBody(opath+"/condition/inline", cond.Inline.Body); body != nil {
+						inline.Body = *body
+					}
+					tr.Condition.Inline = inline
+				}
+			}
+			out = append(out, tr)
+		case *plcopen.BodySFCSelectionDivergence:
+			div := &BodySFCSelectionDivergence{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			}
+			for _, p := range o.ConnectionPointOut {
+				div.ConnectionPointOuts = append(div.ConnectionPointOuts, ConnectionPointOut{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: stringPtr(p.FormalParameter)})
+			}
+			out = append(out, div)
+		case *plcopen.BodySFCSelectionConvergence:
+			out = append(out, &BodySFCSelectionConvergence{
+				Position: o.Position, ConnectionPointIns: upPointIns(o.ConnectionPointIn), ConnectionPointOut: upPointOut(o.ConnectionPointOut),
+				AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		case *plcopen.BodySFCSimultaneousDivergence:
+			div := &BodySFCSimultaneousDivergence{
+				Position: o.Position, ConnectionPointIn: upPointIn(o.ConnectionPointIn), AddData: o.AddData, Documentation: o.Documentation,
+				LocalID: o.LocalID, Height: o.Height, Width: o.Width, Name: o.Name,
+			}
+			for _, p := range o.ConnectionPointOut {
+				div.ConnectionPointOuts = append(div.ConnectionPointOuts, ConnectionPointOut{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: stringPtr(p.FormalParameter)})
+			}
+			out = append(out, div)
+		case *plcopen.BodySFCSimultaneousConvergence:
+			out = append(out, &BodySFCSimultaneousConvergence{
+				Position: o.Position, ConnectionPointIns: upPointIns(o.ConnectionPointIn), ConnectionPointOut: upPointOut(o.ConnectionPointOut),
+				AddData: o.AddData, Documentation: o.Documentation, LocalID: o.LocalID, Height: o.Height, Width: o.Width,
+			})
+		default:
+			c.warn(opath, "unsupported object %T dropped", obj)
+		}
+	}
+	return out
+}
+
+func upConnections(conns []plcopen.Connection) []Connection {
+	var out []Connection
+	for _, conn := range conns {
+		out = append(out, Connection{Positions: conn.Positions, RefLocalID: conn.RefLocalID, FormalParameter: conn.FormalParameter})
+	}
+	return out
+}
+
+func upPointIn(p *plcopen.ConnectionPointIn) *ConnectionPointIn {
+	if p == nil {
+		return nil
+	}
+	return &ConnectionPointIn{RelPosition: p.RelPosition, Connections: upConnections(p.Connections), Expression: p.Expression}
+}
+
+func upPointIns(ps []plcopen.ConnectionPointIn) []ConnectionPointIn {
+	var out []ConnectionPointIn
+	for i := range ps {
+		out = append(out, *upPointIn(&ps[i]))
+	}
+	return out
+}
+
+func upPointOut(p *plcopen.ConnectionPointOut) *ConnectionPointOut {
+	if p == nil {
+		return nil
+	}
+	return &ConnectionPointOut{RelPosition: p.RelPosition, Expression: p.Expression, FormalParameter: p.FormalParameter}
+}
@@ -0,0 +1,193 @@
+package validate
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// automaton is a nondeterministic finite automaton over child elements,
+// built from a content model with Thompson's construction
+type automaton struct {
+	states []state
+	start  int
+	accept int
+}
+
+type state struct {
+	edges []edge
+	eps   []int
+}
+
+// edge consumes one child element matching an element or wildcard particle
+type edge struct {
+	p  *particle
+	to int
+	// repeat is set if the particle may occur more than once, its elements
+	// get a position in paths then
+	repeat bool
+}
+
+func newAutomaton(p *particle) *automaton {
+	a := &automaton{}
+	if p == nil {
+		a.start = a.newState()
+		a.accept = a.start
+		return a
+	}
+	a.start, a.accept = a.occurrences(p, false)
+	return a
+}
+
+func (a *automaton) newState() int {
+	a.states = append(a.states, state{})
+	return len(a.states) - 1
+}
+
+func (a *automaton) epsilon(from, to int) {
+	a.states[from].eps = append(a.states[from].eps, to)
+}
+
+// occurrences builds the fragment for p repeated minOccurs to maxOccurs times
+func (a *automaton) occurrences(p *particle, repeat bool) (in, out int) {
+	repeat = repeat || p.max != 1
+	in = a.newState()
+	out = in
+	for i := 0; i < p.min; i++ {
+		s, e := a.single(p, repeat)
+		a.epsilon(out, s)
+		out = e
+	}
+	switch {
+	case p.max < 0:
+		s, e := a.single(p, repeat)
+		loop := a.newState()
+		a.epsilon(out, loop)
+		a.epsilon(loop, s)
+		a.epsilon(e, loop)
+		out = loop
+	default:
+		for i := p.min; i < p.max; i++ {
+			s, e := a.single(p, repeat)
+			end := a.newState()
+			a.epsilon(out, s)
+			a.epsilon(out, end)
+			a.epsilon(e, end)
+			out = end
+		}
+	}
+	return in, out
+}
+
+// single builds the fragment for one occurrence of p
+func (a *automaton) single(p *particle, repeat bool) (in, out int) {
+	in, out = a.newState(), a.newState()
+	switch p.kind {
+	case particleElement, particleAny:
+		a.states[in].edges = append(a.states[in].edges, edge{p: p, to: out, repeat: repeat})
+	case particleSequence:
+		cur := in
+		for _, child := range p.children {
+			s, e := a.occurrences(child, repeat)
+			a.epsilon(cur, s)
+			cur = e
+		}
+		a.epsilon(cur, out)
+	case particleChoice:
+		for _, child := range p.children {
+			s, e := a.occurrences(child, repeat)
+			a.epsilon(in, s)
+			a.epsilon(e, out)
+		}
+	}
+	return in, out
+}
+
+// initial returns the states reachable before any child element
+func (a *automaton) initial() []int {
+	return a.closure([]int{a.start})
+}
+
+// closure adds the states reachable through epsilon moves to states
+func (a *automaton) closure(states []int) []int {
+	seen := make(map[int]bool, len(states))
+	var out []int
+	var visit func(int)
+	visit = func(s int) {
+		if seen[s] {
+			return
+		}
+		seen[s] = true
+		out = append(out, s)
+		for _, next := range a.states[s].eps {
+			visit(next)
+		}
+	}
+	for _, s := range states {
+		visit(s)
+	}
+	return out
+}
+
+// step consumes the child element name in states. It returns the edge that
+// matched, or nil if the element is not allowed here.
+func (a *automaton) step(states []int, name xml.Name) ([]int, *edge) {
+	var next []int
+	var matched *edge
+	for _, s := range states {
+		for i := range a.states[s].edges {
+			e := &a.states[s].edges[i]
+			if !e.matches(name) {
+				continue
+			}
+			if matched == nil {
+				matched = e
+			}
+			next = append(next, e.to)
+		}
+	}
+	if matched == nil {
+		return states, nil
+	}
+	return a.closure(next), matched
+}
+
+func (e *edge) matches(name xml.Name) bool {
+	if e.p.kind == particleAny {
+		return e.p.any.matches(name.Space)
+	}
+	return e.p.elem.name == name
+}
+
+// accepts reports whether the content may end in states
+func (a *automaton) accepts(states []int) bool {
+	for _, s := range states {
+		if s == a.accept {
+			return true
+		}
+	}
+	return false
+}
+
+// expected lists the elements allowed next in states
+func (a *automaton) expected(states []int) string {
+	var names []string
+	seen := map[string]bool{}
+	for _, s := range states {
+		for _, e := range a.states[s].edges {
+			name := e.p.String()
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+func (p *particle) String() string {
+	if p.kind == particleAny {
+		return p.any.String()
+	}
+	return strconv.Quote(p.elem.name.Local)
+}
@@ -0,0 +1,675 @@
+package validate
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+)
+
+const (
+	xsdNS = "http://www.w3.org/2001/XMLSchema"
+	xsiNS = "http://www.w3.org/2001/XMLSchema-instance"
+)
+
+// Schema is a compiled XML schema. It is safe for concurrent use.
+type Schema struct {
+	target   string
+	elements map[xml.Name]*element
+}
+
+// element is an element declaration. Without complex and simple type the
+// element has xsd:anyType and its content is not checked.
+type element struct {
+	name    xml.Name
+	complex *complexType
+	simple  *simpleType
+}
+
+// complexType is a complex type definition
+type complexType struct {
+	name    string
+	base    *complexType
+	extends bool
+	content *particle
+	attrs   []*attribute
+	anyAttr bool
+	mixed   bool
+
+	// Set when the schema is complete
+	attributes map[string]*attribute
+	model      *automaton
+	// empty is set if the type allows neither elements nor text, not even whitespace
+	empty bool
+}
+
+// attribute is an attribute declaration
+type attribute struct {
+	name       string
+	typ        *simpleType
+	required   bool
+	prohibited bool
+	fixed      *string
+}
+
+type particleKind int
+
+const (
+	particleElement particleKind = iota
+	particleAny
+	particleSequence
+	particleChoice
+)
+
+// particle is a node of a content model
+type particle struct {
+	kind     particleKind
+	elem     *element
+	any      *wildcard
+	children []*particle
+	min, max int // max < 0 is unbounded
+}
+
+// wildcard is an xsd:any
+type wildcard struct {
+	namespaces []string // "##any", "##other", "##local", "##targetNamespace" or URIs
+	target     string
+	process    string // "strict", "lax" or "skip"
+}
+
+func (w *wildcard) matches(space string) bool {
+	for _, ns := range w.namespaces {
+		switch ns {
+		case "##any":
+			return true
+		case "##other":
+			if space != w.target && space != "" {
+				return true
+			}
+		case "##local":
+			if space == "" {
+				return true
+			}
+		case "##targetNamespace":
+			if space == w.target {
+				return true
+			}
+		default:
+			if space == ns {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (w *wildcard) String() string {
+	return "any element in " + strings.Join(w.namespaces, " ")
+}
+
+// Compile reads an XML schema from r. Only the parts of XSD 1.0 needed by
+// the TC6 XML schemas are supported: element, attribute, complexType with
+// complexContent, simpleType restrictions, sequence, choice, group and any.
+// Other constructs are reported as errors.
+func Compile(r io.Reader) (*Schema, error) {
+	root, err := parseSchema(r)
+	if err != nil {
+		return nil, err
+	}
+	if root.name != (xml.Name{Space: xsdNS, Local: "schema"}) {
+		return nil, fmt.Errorf("plcopen: root element is %q, want xsd:schema", root.name.Local)
+	}
+	c := &compiler{
+		target:    root.attr("targetNamespace"),
+		qualified: root.attr("elementFormDefault") == "qualified",
+		defs:      map[string]map[string]*xsdNode{},
+		elements:  map[string]*element{},
+		complex:   map[string]*complexType{},
+		simple:    map[string]*simpleType{},
+		groups:    map[string]*particle{},
+		attrs:     map[string]*attribute{},
+	}
+	for _, n := range root.children {
+		switch n.name.Local {
+		case "element", "complexType", "simpleType", "group", "attribute":
+			if c.defs[n.name.Local] == nil {
+				c.defs[n.name.Local] = map[string]*xsdNode{}
+			}
+			c.defs[n.name.Local][n.attr("name")] = n
+		case "annotation":
+		default:
+			return nil, n.unsupported()
+		}
+	}
+
+	s := &Schema{target: c.target, elements: map[xml.Name]*element{}}
+	for name := range c.defs["element"] {
+		e, err := c.globalElement(name)
+		if err != nil {
+			return nil, err
+		}
+		s.elements[e.name] = e
+	}
+	for name := range c.defs["complexType"] {
+		if _, err := c.complexType(name); err != nil {
+			return nil, err
+		}
+	}
+	for _, ct := range c.types {
+		ct.finish()
+	}
+	return s, nil
+}
+
+// xsdNode is an element of a schema document
+type xsdNode struct {
+	name     xml.Name
+	attrs    []xml.Attr
+	parent   *xsdNode
+	children []*xsdNode
+	line     int
+}
+
+func parseSchema(r io.Reader) (*xsdNode, error) {
+	d := xml.NewDecoder(r)
+	d.CharsetReader = plcopen.CharsetReader
+	var root, cur *xsdNode
+	for {
+		line, _ := d.InputPos()
+		tok, err := d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xsdNode{name: t.Name, attrs: t.Attr, parent: cur, line: line}
+			if cur == nil {
+				root = n
+			} else if t.Name.Space == xsdNS && t.Name.Local != "annotation" {
+				cur.children = append(cur.children, n)
+			}
+			cur = n
+		case xml.EndElement:
+			cur = cur.parent
+		}
+	}
+	if root == nil {
+		return nil, fmt.Errorf("plcopen: schema has no root element")
+	}
+	return root, nil
+}
+
+func (n *xsdNode) attr(name string) string {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func (n *xsdNode) has(name string) bool {
+	for _, a := range n.attrs {
+		if a.Name.Space == "" && a.Name.Local == name {
+			return true
+		}
+	}
+	return false
+}
+
+// qname resolves the prefixed name in attribute attr against the namespace
+// declarations in scope
+func (n *xsdNode) qname(attr string) (xml.Name, error) {
+	value := n.attr(attr)
+	prefix, local, ok := strings.Cut(value, ":")
+	if !ok {
+		prefix, local = "", value
+	}
+	for s := n; s != nil; s = s.parent {
+		for _, a := range s.attrs {
+			if (prefix == "" && a.Name.Space == "" && a.Name.Local == "xmlns") ||
+				(prefix != "" && a.Name.Space == "xmlns" && a.Name.Local == prefix) {
+				return xml.Name{Space: a.Value, Local: local}, nil
+			}
+		}
+	}
+	if prefix == "" {
+		return xml.Name{Local: local}, nil
+	}
+	return xml.Name{}, n.errorf("undeclared namespace prefix %q in %s", prefix, attr)
+}
+
+func (n *xsdNode) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("plcopen: schema line %d: %s", n.line, fmt.Sprintf(format, args...))
+}
+
+func (n *xsdNode) unsupported() error {
+	return n.errorf("xsd:%s is not supported", n.name.Local)
+}
+
+// occurs returns the minOccurs and maxOccurs of n
+func (n *xsdNode) occurs() (min, max int, err error) {
+	min, max = 1, 1
+	if v := n.attr("minOccurs"); v != "" {
+		if min, err = strconv.Atoi(v); err != nil {
+			return 0, 0, n.errorf("invalid minOccurs %q", v)
+		}
+	}
+	if v := n.attr("maxOccurs"); v == "unbounded" {
+		max = -1
+	} else if v != "" {
+		if max, err = strconv.Atoi(v); err != nil {
+			return 0, 0, n.errorf("invalid maxOccurs %q", v)
+		}
+	}
+	return min, max, nil
+}
+
+// compiler turns schema documents into declarations. Global definitions are
+// compiled on first use, so they may be referenced before they are defined.
+type compiler struct {
+	target    string
+	qualified bool
+	defs      map[string]map[string]*xsdNode
+
+	elements map[string]*element
+	complex  map[string]*complexType
+	simple   map[string]*simpleType
+	groups   map[string]*particle
+	attrs    map[string]*attribute
+	types    []*complexType
+}
+
+// lookup returns the global definition of kind for the name in attribute attr of n
+func (c *compiler) lookup(n *xsdNode, attr, kind string) (string, error) {
+	name, err := n.qname(attr)
+	if err != nil {
+		return "", err
+	}
+	if name.Space != c.target || c.defs[kind][name.Local] == nil {
+		return "", n.errorf("unknown %s %q", kind, n.attr(attr))
+	}
+	return name.Local, nil
+}
+
+func (c *compiler) globalElement(name string) (*element, error) {
+	if e := c.elements[name]; e != nil {
+		return e, nil
+	}
+	e := &element{name: xml.Name{Space: c.target, Local: name}}
+	c.elements[name] = e
+	return e, c.elementType(c.defs["element"][name], e)
+}
+
+// localElement compiles an element declaration inside a content model
+func (c *compiler) localElement(n *xsdNode) (*element, error) {
+	if n.has("ref") {
+		name, err := c.lookup(n, "ref", "element")
+		if err != nil {
+			return nil, err
+		}
+		return c.globalElement(name)
+	}
+	e := &element{name: xml.Name{Local: n.attr("name")}}
+	if form := n.attr("form"); form == "qualified" || (form == "" && c.qualified) {
+		e.name.Space = c.target
+	}
+	return e, c.elementType(n, e)
+}
+
+func (c *compiler) elementType(n *xsdNode, e *element) error {
+	var err error
+	if n.has("type") {
+		e.complex, e.simple, err = c.typeRef(n, "type")
+		return err
+	}
+	for _, child := range n.children {
+		switch child.name.Local {
+		case "complexType":
+			e.complex, err = c.complexDef(child, "")
+		case "simpleType":
+			e.simple, err = c.simpleDef(child, "")
+		case "unique", "key", "keyref":
+			err = child.unsupported()
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// typeRef resolves the type named in attribute attr of n
+func (c *compiler) typeRef(n *xsdNode, attr string) (*complexType, *simpleType, error) {
+	name, err := n.qname(attr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if name.Space == xsdNS {
+		if name.Local == "anyType" {
+			return nil, nil, nil
+		}
+		if st := builtins[name.Local]; st != nil {
+			return nil, st, nil
+		}
+		return nil, nil, n.errorf("unsupported built-in type %q", n.attr(attr))
+	}
+	if name.Space == c.target {
+		if c.defs["complexType"][name.Local] != nil {
+			ct, err := c.complexType(name.Local)
+			return ct, nil, err
+		}
+		if c.defs["simpleType"][name.Local] != nil {
+			st, err := c.simpleType(name.Local)
+			return nil, st, err
+		}
+	}
+	return nil, nil, n.errorf("unknown type %q", n.attr(attr))
+}
+
+func (c *compiler) complexType(name string) (*complexType, error) {
+	if ct := c.complex[name]; ct != nil {
+		return ct, nil
+	}
+	return c.complexDef(c.defs["complexType"][name], name)
+}
+
+func (c *compiler) complexDef(n *xsdNode, name string) (*complexType, error) {
+	ct := &complexType{name: name, mixed: n.attr("mixed") == "true" || n.attr("mixed") == "1"}
+	if name != "" {
+		c.complex[name] = ct
+	}
+	c.types = append(c.types, ct)
+	return ct, c.complexContent(n, ct)
+}
+
+// complexContent compiles the content model and attributes below n into ct
+func (c *compiler) complexContent(n *xsdNode, ct *complexType) error {
+	for _, child := range n.children {
+		switch child.name.Local {
+		case "sequence", "choice", "group", "all":
+			p, err := c.particle(child)
+			if err != nil {
+				return err
+			}
+			ct.content = p
+		case "attribute":
+			a, err := c.attribute(child)
+			if err != nil {
+				return err
+			}
+			ct.attrs = append(ct.attrs, a)
+		case "anyAttribute":
+			ct.anyAttr = true
+		case "complexContent":
+			if child.attr("mixed") == "true" || child.attr("mixed") == "1" {
+				ct.mixed = true
+			}
+			for _, derivation := range child.children {
+				if derivation.name.Local != "extension" && derivation.name.Local != "restriction" {
+					return derivation.unsupported()
+				}
+				base, _, err := c.typeRef(derivation, "base")
+				if err != nil {
+					return err
+				}
+				if base == nil {
+					return derivation.errorf("base %q is not a complex type", derivation.attr("base"))
+				}
+				ct.base, ct.extends = base, derivation.name.Local == "extension"
+				if err := c.complexContent(derivation, ct); err != nil {
+					return err
+				}
+			}
+		default:
+			return child.unsupported()
+		}
+	}
+	return nil
+}
+
+func (c *compiler) particle(n *xsdNode) (*particle, error) {
+	min, max, err := n.occurs()
+	if err != nil {
+		return nil, err
+	}
+	p := &particle{min: min, max: max}
+	switch n.name.Local {
+	case "element":
+		p.kind = particleElement
+		p.elem, err = c.localElement(n)
+		return p, err
+	case "any":
+		p.kind = particleAny
+		p.any = &wildcard{namespaces: strings.Fields(n.attr("namespace")), target: c.target, process: n.attr("processContents")}
+		if len(p.any.namespaces) == 0 {
+			p.any.namespaces = []string{"##any"}
+		}
+		if p.any.process == "" {
+			p.any.process = "strict"
+		}
+		return p, nil
+	case "sequence", "choice":
+		p.kind = particleSequence
+		if n.name.Local == "choice" {
+			p.kind = particleChoice
+		}
+		for _, child := range n.children {
+			cp, err := c.particle(child)
+			if err != nil {
+				return nil, err
+			}
+			p.children = append(p.children, cp)
+		}
+		return p, nil
+	case "group":
+		name, err := c.lookup(n, "ref", "group")
+		if err != nil {
+			return nil, err
+		}
+		g, err := c.group(name)
+		if err != nil {
+			return nil, err
+		}
+		p.kind = particleSequence
+		p.children = []*particle{g}
+		return p, nil
+	}
+	return nil, n.unsupported()
+}
+
+func (c *compiler) group(name string) (*particle, error) {
+	if g := c.groups[name]; g != nil {
+		return g, nil
+	}
+	def := c.defs["group"][name]
+	if len(def.children) != 1 {
+		return nil, def.errorf("group %q must contain one model group", name)
+	}
+	// Register a placeholder first, groups may refer to themselves through elements
+	g := &particle{}
+	c.groups[name] = g
+	p, err := c.particle(def.children[0])
+	if err != nil {
+		return nil, err
+	}
+	*g = *p
+	return g, nil
+}
+
+func (c *compiler) attribute(n *xsdNode) (*attribute, error) {
+	if n.has("ref") {
+		name, err := c.lookup(n, "ref", "attribute")
+		if err != nil {
+			return nil, err
+		}
+		global := c.attrs[name]
+		if global == nil {
+			if global, err = c.attribute(c.defs["attribute"][name]); err != nil {
+				return nil, err
+			}
+			c.attrs[name] = global
+		}
+		a := *global
+		a.required, a.prohibited = n.attr("use") == "required", n.attr("use") == "prohibited"
+		return &a, nil
+	}
+	a := &attribute{
+		name:       n.attr("name"),
+		typ:        builtins["anySimpleType"],
+		required:   n.attr("use") == "required",
+		prohibited: n.attr("use") == "prohibited",
+	}
+	if n.has("fixed") {
+		fixed := n.attr("fixed")
+		a.fixed = &fixed
+	}
+	if n.has("type") {
+		_, st, err := c.typeRef(n, "type")
+		if err != nil {
+			return nil, err
+		}
+		if st == nil {
+			return nil, n.errorf("attribute type %q is not a simple type", n.attr("type"))
+		}
+		a.typ = st
+	}
+	for _, child := range n.children {
+		if child.name.Local != "simpleType" {
+			return nil, child.unsupported()
+		}
+		st, err := c.simpleDef(child, "")
+		if err != nil {
+			return nil, err
+		}
+		a.typ = st
+	}
+	return a, nil
+}
+
+func (c *compiler) simpleType(name string) (*simpleType, error) {
+	if st := c.simple[name]; st != nil {
+		return st, nil
+	}
+	return c.simpleDef(c.defs["simpleType"][name], name)
+}
+
+func (c *compiler) simpleDef(n *xsdNode, name string) (*simpleType, error) {
+	if len(n.children) != 1 || n.children[0].name.Local != "restriction" {
+		if len(n.children) == 1 {
+			return nil, n.children[0].unsupported()
+		}
+		return nil, n.errorf("simpleType must contain one restriction")
+	}
+	r := n.children[0]
+	st := &simpleType{name: name, length: -1, minLength: -1, maxLength: -1}
+	if name != "" {
+		c.simple[name] = st
+	}
+	var err error
+	if r.has("base") {
+		var ct *complexType
+		if ct, st.base, err = c.typeRef(r, "base"); err != nil {
+			return nil, err
+		}
+		if st.base == nil || ct != nil {
+			return nil, r.errorf("base %q is not a simple type", r.attr("base"))
+		}
+	}
+	for _, facet := range r.children {
+		value := facet.attr("value")
+		switch facet.name.Local {
+		case "simpleType":
+			if st.base, err = c.simpleDef(facet, ""); err != nil {
+				return nil, err
+			}
+		case "enumeration":
+			st.enum = append(st.enum, value)
+		case "minInclusive", "maxInclusive", "minExclusive", "maxExclusive":
+			v, ok := parseDecimal(strings.TrimSpace(value))
+			if !ok {
+				return nil, facet.errorf("invalid %s %q", facet.name.Local, value)
+			}
+			b := &bound{value: v, text: value, inclusive: strings.HasSuffix(facet.name.Local, "Inclusive")}
+			if strings.HasPrefix(facet.name.Local, "min") {
+				st.min = b
+			} else {
+				st.max = b
+			}
+		case "pattern":
+			re, err := regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return nil, facet.errorf("unsupported pattern %q: %v", value, err)
+			}
+			st.patterns = append(st.patterns, re)
+		case "length", "minLength", "maxLength":
+			l, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, facet.errorf("invalid %s %q", facet.name.Local, value)
+			}
+			switch facet.name.Local {
+			case "length":
+				st.length = l
+			case "minLength":
+				st.minLength = l
+			default:
+				st.maxLength = l
+			}
+		case "whiteSpace":
+			st.collapse = value == "collapse"
+		default:
+			return nil, facet.unsupported()
+		}
+	}
+	if st.base == nil {
+		return nil, r.errorf("restriction has no base type")
+	}
+	return st, nil
+}
+
+// finish merges inherited attributes and builds the content automaton
+func (ct *complexType) finish() {
+	if ct.attributes != nil {
+		return
+	}
+	ct.attributes = map[string]*attribute{}
+	if ct.base != nil {
+		ct.base.finish()
+		for name, a := range ct.base.attributes {
+			ct.attributes[name] = a
+		}
+		ct.anyAttr = ct.anyAttr || ct.base.anyAttr
+	}
+	for _, a := range ct.attrs {
+		if a.prohibited {
+			delete(ct.attributes, a.name)
+			continue
+		}
+		ct.attributes[a.name] = a
+	}
+	content := ct.effectiveContent()
+	ct.model = newAutomaton(content)
+	ct.empty = content == nil && !ct.mixed
+}
+
+// effectiveContent returns the content model including inherited content
+func (ct *complexType) effectiveContent() *particle {
+	if ct.base == nil || !ct.extends {
+		return ct.content
+	}
+	base := ct.base.effectiveContent()
+	switch {
+	case base == nil:
+		return ct.content
+	case ct.content == nil:
+		return base
+	}
+	return &particle{kind: particleSequence, min: 1, max: 1, children: []*particle{base, ct.content}}
+}
@@ -0,0 +1,224 @@
+package validate
+
+import (
+	"fmt"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// simpleType is a built-in type or a restriction of another simple type
+type simpleType struct {
+	name string
+	base *simpleType
+	// lexical checks the lexical space of built-in primitive types
+	lexical  func(string) bool
+	collapse bool
+
+	enum      []string
+	min, max  *bound
+	patterns  []*regexp.Regexp
+	length    int
+	minLength int
+	maxLength int
+}
+
+// builtins are the XSD built-in types supported by the validator
+var builtins = map[string]*simpleType{}
+
+func init() {
+	add := func(name string, base *simpleType, lexical func(string) bool, collapse bool) *simpleType {
+		st := &simpleType{name: name, base: base, lexical: lexical, collapse: collapse, length: -1, minLength: -1, maxLength: -1}
+		builtins[name] = st
+		return st
+	}
+	match := func(expr string) func(string) bool {
+		return regexp.MustCompile("^(?:" + expr + ")$").MatchString
+	}
+	anything := func(string) bool { return true }
+
+	anySimple := add("anySimpleType", nil, anything, false)
+	str := add("string", anySimple, anything, false)
+	add("normalizedString", str, anything, false)
+	token := add("token", str, anything, true)
+	add("language", token, match(`[a-zA-Z]{1,8}(-[a-zA-Z0-9]{1,8})*`), true)
+	add("NMTOKEN", token, match(`[\pL\pN._:\-]+`), true)
+	name := add("Name", token, match(`[\pL_:][\pL\pN._:\-]*`), true)
+	ncname := add("NCName", name, match(`[\pL_][\pL\pN._\-]*`), true)
+	add("ID", ncname, anything, true)
+	add("IDREF", ncname, anything, true)
+	add("anyURI", anySimple, anything, true)
+	add("QName", anySimple, match(`([\pL_][\pL\pN._\-]*:)?[\pL_][\pL\pN._\-]*`), true)
+	add("boolean", anySimple, match(`true|false|1|0`), true)
+	add("float", anySimple, isFloat, true)
+	add("double", anySimple, isFloat, true)
+	add("duration", anySimple, match(`-?P(\d+Y)?(\d+M)?(\d+D)?(T(\d+H)?(\d+M)?(\d+(\.\d+)?S)?)?`), true)
+	const (
+		date = `-?\d{4,}-(0[1-9]|1[0-2])-(0[1-9]|[12]\d|3[01])`
+		time = `([01]\d|2[0-3]):[0-5]\d:[0-5]\d(\.\d+)?|24:00:00(\.0+)?`
+		zone = `(Z|[+-]((0\d|1[0-3]):[0-5]\d|14:00))?`
+	)
+	add("dateTime", anySimple, match(date+`T(`+time+`)`+zone), true)
+	add("date", anySimple, match(date+zone), true)
+	add("time", anySimple, match(`(`+time+`)`+zone), true)
+
+	decimal := add("decimal", anySimple, func(s string) bool { _, ok := parseDecimal(s); return ok }, true)
+	integer := add("integer", decimal, match(`[+-]?\d+`), true)
+	ranged := func(name string, base *simpleType, min, max string) *simpleType {
+		st := add(name, base, nil, true)
+		if min != "" {
+			v, _ := parseDecimal(min)
+			st.min = &bound{value: v, text: min, inclusive: true}
+		}
+		if max != "" {
+			v, _ := parseDecimal(max)
+			st.max = &bound{value: v, text: max, inclusive: true}
+		}
+		return st
+	}
+	long := ranged("long", integer, "-9223372036854775808", "9223372036854775807")
+	i := ranged("int", long, "-2147483648", "2147483647")
+	short := ranged("short", i, "-32768", "32767")
+	ranged("byte", short, "-128", "127")
+	nonNegative := ranged("nonNegativeInteger", integer, "0", "")
+	ranged("positiveInteger", nonNegative, "1", "")
+	nonPositive := ranged("nonPositiveInteger", integer, "", "0")
+	ranged("negativeInteger", nonPositive, "", "-1")
+	unsignedLong := ranged("unsignedLong", nonNegative, "", "18446744073709551615")
+	unsignedInt := ranged("unsignedInt", unsignedLong, "", "4294967295")
+	unsignedShort := ranged("unsignedShort", unsignedInt, "", "65535")
+	ranged("unsignedByte", unsignedShort, "", "255")
+}
+
+// bound is a minimum or maximum facet
+type bound struct {
+	value     *big.Rat
+	text      string
+	inclusive bool
+}
+
+var decimalRe = regexp.MustCompile(`^[+-]?(\d+(\.\d*)?|\.\d+)$`)
+
+// parseDecimal parses an xsd:decimal
+func parseDecimal(s string) (*big.Rat, bool) {
+	if !decimalRe.MatchString(s) {
+		return nil, false
+	}
+	s = strings.TrimPrefix(s, "+")
+	s = strings.TrimSuffix(s, ".")
+	if strings.HasPrefix(s, "-.") {
+		s = "-0" + s[1:]
+	} else if strings.HasPrefix(s, ".") {
+		s = "0" + s
+	}
+	return new(big.Rat).SetString(s)
+}
+
+func isFloat(s string) bool {
+	switch s {
+	case "INF", "-INF", "NaN":
+		return true
+	case "+INF", "Infinity", "-Infinity", "inf", "nan":
+		return false
+	}
+	_, err := strconv.ParseFloat(s, 64)
+	return err == nil || err.(*strconv.NumError).Err == strconv.ErrRange
+}
+
+// primitive returns the name of the built-in type st is derived from
+func (st *simpleType) primitive() string {
+	for t := st; t != nil; t = t.base {
+		if t.name != "" && builtins[t.name] == t {
+			return t.name
+		}
+	}
+	return "anySimpleType"
+}
+
+// whitespace normalizes value the way st requires
+func (st *simpleType) whitespace(value string) string {
+	for t := st; t != nil; t = t.base {
+		if t.collapse {
+			return strings.Join(strings.Fields(value), " ")
+		}
+	}
+	return value
+}
+
+// check validates value against st and all its base types
+func (st *simpleType) check(value string) error {
+	value = st.whitespace(value)
+	for t := st; t != nil; t = t.base {
+		if err := t.facets(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (st *simpleType) facets(value string) error {
+	if st.lexical != nil && !st.lexical(value) {
+		return fmt.Errorf("%q is not a valid %s", value, st.name)
+	}
+	if len(st.enum) > 0 {
+		found := false
+		for _, e := range st.enum {
+			if e == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%q is not one of %s", value, quoteList(st.enum))
+		}
+	}
+	for _, re := range st.patterns {
+		if !re.MatchString(value) {
+			return fmt.Errorf("%q does not match the pattern %s", value, strings.TrimSuffix(strings.TrimPrefix(re.String(), "^(?:"), ")$"))
+		}
+	}
+	if st.length >= 0 || st.minLength >= 0 || st.maxLength >= 0 {
+		n := utf8.RuneCountInString(value)
+		switch {
+		case st.length >= 0 && n != st.length:
+			return fmt.Errorf("%q has length %d, want %d", value, n, st.length)
+		case st.minLength >= 0 && n < st.minLength:
+			return fmt.Errorf("%q is shorter than %d", value, st.minLength)
+		case st.maxLength >= 0 && n > st.maxLength:
+			return fmt.Errorf("%q is longer than %d", value, st.maxLength)
+		}
+	}
+	if st.min != nil || st.max != nil {
+		v, ok := parseDecimal(value)
+		if !ok {
+			return fmt.Errorf("%q is not a valid %s", value, st.primitive())
+		}
+		if b := st.min; b != nil {
+			if c := v.Cmp(b.value); c < 0 || (c == 0 && !b.inclusive) {
+				if !b.inclusive {
+					return fmt.Errorf("%s is not greater than %s", value, b.text)
+				}
+				return fmt.Errorf("%s is less than the minimum %s", value, b.text)
+			}
+		}
+		if b := st.max; b != nil {
+			if c := v.Cmp(b.value); c > 0 || (c == 0 && !b.inclusive) {
+				if !b.inclusive {
+					return fmt.Errorf("%s is not less than %s", value, b.text)
+				}
+				return fmt.Errorf("%s is greater than the maximum %s", value, b.text)
+			}
+		}
+	}
+	return nil
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = strconv.Quote(v)
+	}
+	return strings.Join(quoted, ", ")
+}
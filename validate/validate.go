@@ -0,0 +1,399 @@
+// Package validate checks PLCopen XML documents against an XML schema
+// without external tools. The TC6 XML V1.0B and 2.01 schemas are embedded;
+// other schemas can be compiled with Compile.
+package validate
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	plcopen "github.com/suifei/plcopen-go/v2"
+	"github.com/suifei/plcopen-go/v2/v201"
+)
+
+var (
+	//go:embed TC6_XML_V10_B.xsd
+	tc6V10B []byte
+	//go:embed TC6_XML_V201.xsd
+	tc6V201 []byte
+)
+
+var (
+	tc6V10BSchema = embedded(tc6V10B)
+	tc6V201Schema = embedded(tc6V201)
+)
+
+// TC6V10B returns the compiled TC6 XML V1.0B schema
+func TC6V10B() *Schema {
+	return tc6V10BSchema()
+}
+
+// TC6V201 returns the compiled TC6 XML 2.01 schema
+func TC6V201() *Schema {
+	return tc6V201Schema()
+}
+
+// embedded returns a function compiling the embedded schema data on first use
+func embedded(data []byte) func() *Schema {
+	var (
+		once   sync.Once
+		schema *Schema
+	)
+	return func() *Schema {
+		once.Do(func() {
+			s, err := Compile(bytes.NewReader(data))
+			if err != nil {
+				panic(err)
+			}
+			schema = s
+		})
+		return schema
+	}
+}
+
+// Reader validates the document read from r against the TC6 XML V1.0B schema
+func Reader(r io.Reader) error {
+	return TC6V10B().Validate(r)
+}
+
+// Bytes validates data against the TC6 XML V1.0B schema
+func Bytes(data []byte) error {
+	return TC6V10B().Validate(bytes.NewReader(data))
+}
+
+// Project validates the XML form of p against the TC6 XML V1.0B schema.
+// Lines and columns refer to the output of xml.MarshalIndent(p, "", "  ").
+func Project(p *plcopen.Project) error {
+	return TC6V10B().ValidateProject(p)
+}
+
+// V201Project validates the XML form of p against the TC6 XML 2.01 schema
+func V201Project(p *v201.Project) error {
+	data, err := xml.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return TC6V201().Validate(bytes.NewReader(data))
+}
+
+// Error is a schema violation or syntax error in a document
+type Error struct {
+	Line   int
+	Column int
+	// Path is an XPath-like location of the element or attribute, e.g.
+	// "/project/types/pous/pou[2]/body/FBD/block[1]/@localId". Elements that
+	// may occur more than once get their position among equally named siblings.
+	Path    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%d:%d: %s: %s", e.Line, e.Column, e.Path, e.Message)
+}
+
+// Errors are all errors found in a document, in document order
+type Errors []*Error
+
+func (e Errors) Error() string {
+	switch len(e) {
+	case 0:
+		return "no errors"
+	case 1:
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", e[0], len(e)-1)
+}
+
+// Validate checks the document read from r against s. It returns nil if
+// the document is valid and Errors otherwise. Reading stops at the first
+// syntax error; schema violations are all collected.
+func (s *Schema) Validate(r io.Reader) error {
+	br := bufio.NewReader(r)
+	if bom, _ := br.Peek(3); bytes.Equal(bom, []byte("\xEF\xBB\xBF")) {
+		br.Discard(3)
+	}
+	v := &validator{schema: s, d: xml.NewDecoder(br)}
+	v.d.CharsetReader = plcopen.CharsetReader
+	v.run()
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// ValidateProject checks the XML form of p against s
+func (s *Schema) ValidateProject(p *plcopen.Project) error {
+	data, err := xml.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return s.Validate(bytes.NewReader(data))
+}
+
+// frame is an open element during validation
+type frame struct {
+	path string
+	line int
+	col  int
+	// decl is nil for elements whose content is not validated
+	decl   *element
+	states []int
+	counts map[string]int
+	text   strings.Builder
+	// textSeen is set once non-whitespace text was reported
+	textSeen bool
+}
+
+type validator struct {
+	schema *Schema
+	d      *xml.Decoder
+	stack  []*frame
+	errs   Errors
+}
+
+func (v *validator) errorf(line, col int, path, format string, args ...interface{}) {
+	v.errs = append(v.errs, &Error{Line: line, Column: col, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) top() *frame {
+	if len(v.stack) == 0 {
+		return nil
+	}
+	return v.stack[len(v.stack)-1]
+}
+
+func (v *validator) run() {
+	root := false
+	for {
+		line, col := v.d.InputPos()
+		tok, err := v.d.Token()
+		if err == io.EOF {
+			if !root {
+				v.errorf(line, col, "", "document has no root element")
+			}
+			return
+		}
+		if err != nil {
+			v.syntaxError(err)
+			return
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			root = true
+			v.start(t, line, col)
+		case xml.EndElement:
+			v.end(line, col)
+		case xml.CharData:
+			v.charData(t, line, col)
+		}
+	}
+}
+
+func (v *validator) syntaxError(err error) {
+	line, col := v.d.InputPos()
+	var syntax *xml.SyntaxError
+	msg := err.Error()
+	if errors.As(err, &syntax) {
+		line, msg = syntax.Line, syntax.Msg
+	}
+	path := ""
+	if f := v.top(); f != nil {
+		path = f.path
+	}
+	v.errorf(line, col, path, "%s", msg)
+}
+
+func (v *validator) start(t xml.StartElement, line, col int) {
+	parent := v.top()
+	f := &frame{line: line, col: col, counts: map[string]int{}}
+	v.stack = append(v.stack, f)
+
+	if parent == nil {
+		f.path = "/" + t.Name.Local
+		f.decl = v.schema.elements[t.Name]
+		if f.decl == nil {
+			v.errorf(line, col, f.path, "no declaration for root element %s", v.qualified(t.Name))
+			return
+		}
+	} else {
+		parent.counts[t.Name.Local]++
+		n := parent.counts[t.Name.Local]
+		decl, repeat, ok := v.child(parent, t, line, col)
+		f.path = parent.path + "/" + t.Name.Local
+		if repeat || n > 1 {
+			f.path += "[" + strconv.Itoa(n) + "]"
+		}
+		if !ok {
+			v.errorf(line, col, f.path, "unexpected element %s%s", v.qualified(t.Name), v.expected(parent))
+			return
+		}
+		f.decl = decl
+	}
+	if f.decl != nil {
+		v.attributes(f, t)
+		if f.decl.complex != nil {
+			f.states = f.decl.complex.model.initial()
+		}
+	}
+}
+
+// child matches the child element t against the content model of parent. It
+// returns the declaration to validate t with, nil if t is not validated.
+func (v *validator) child(parent *frame, t xml.StartElement, line, col int) (decl *element, repeat, ok bool) {
+	if parent.decl == nil {
+		return nil, false, true
+	}
+	if parent.decl.complex == nil {
+		if parent.decl.simple != nil {
+			return nil, false, false
+		}
+		// xsd:anyType allows anything
+		return nil, false, true
+	}
+	states, e := parent.decl.complex.model.step(parent.states, t.Name)
+	if e == nil {
+		return nil, false, false
+	}
+	parent.states = states
+	if e.p.kind == particleElement {
+		return e.p.elem, e.repeat, true
+	}
+	if e.p.any.process == "skip" {
+		return nil, e.repeat, true
+	}
+	decl = v.schema.elements[t.Name]
+	if decl == nil && e.p.any.process == "strict" {
+		v.errorf(line, col, parent.path, "no declaration for element %s", v.qualified(t.Name))
+	}
+	return decl, e.repeat, true
+}
+
+// expected describes what the content model of f allows next
+func (v *validator) expected(f *frame) string {
+	if f.decl == nil || f.decl.complex == nil {
+		if f.decl != nil && f.decl.simple != nil {
+			return ", element has simple content"
+		}
+		return ""
+	}
+	if names := f.decl.complex.model.expected(f.states); names != "" {
+		return ", expected " + names
+	}
+	return ", no more child elements expected"
+}
+
+func (v *validator) attributes(f *frame, t xml.StartElement) {
+	if f.decl.complex == nil && f.decl.simple == nil {
+		// xsd:anyType allows any attributes
+		return
+	}
+	seen := map[string]bool{}
+	var declared map[string]*attribute
+	anyAttr := false
+	if f.decl.complex != nil {
+		declared, anyAttr = f.decl.complex.attributes, f.decl.complex.anyAttr
+	}
+	for _, attr := range t.Attr {
+		if attr.Name.Space == "xmlns" || (attr.Name.Space == "" && attr.Name.Local == "xmlns") || attr.Name.Space == xsiNS {
+			continue
+		}
+		path := f.path + "/@" + attr.Name.Local
+		a := declared[attr.Name.Local]
+		if attr.Name.Space != "" || a == nil {
+			if !anyAttr {
+				v.errorf(f.line, f.col, path, "attribute %s is not allowed", v.qualified(attr.Name))
+			}
+			continue
+		}
+		seen[a.name] = true
+		if err := a.typ.check(attr.Value); err != nil {
+			v.errorf(f.line, f.col, path, "invalid value: %v", err)
+		} else if a.fixed != nil && a.typ.whitespace(attr.Value) != *a.fixed {
+			v.errorf(f.line, f.col, path, "value must be %q", *a.fixed)
+		}
+	}
+	var missing []string
+	for name, a := range declared {
+		if a.required && !seen[name] {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		for _, name := range missing {
+			v.errorf(f.line, f.col, f.path+"/@"+name, "missing required attribute %q", name)
+		}
+	}
+}
+
+func (v *validator) charData(t xml.CharData, line, col int) {
+	f := v.top()
+	if f == nil || f.decl == nil {
+		return
+	}
+	if f.decl.simple != nil {
+		f.text.Write(t)
+		return
+	}
+	if f.decl.complex == nil || f.decl.complex.mixed || f.textSeen {
+		return
+	}
+	if f.decl.complex.empty && len(t) > 0 {
+		f.textSeen = true
+		v.errorf(line, col, f.path, "element must be empty")
+		return
+	}
+	// Point at the first non-whitespace character
+	for _, b := range t {
+		switch b {
+		case ' ', '\t', '\r':
+			col++
+			continue
+		case '\n':
+			line, col = line+1, 1
+			continue
+		}
+		f.textSeen = true
+		v.errorf(line, col, f.path, "text content is not allowed")
+		return
+	}
+}
+
+func (v *validator) end(line, col int) {
+	f := v.top()
+	v.stack = v.stack[:len(v.stack)-1]
+	if f.decl == nil {
+		return
+	}
+	switch {
+	case f.decl.simple != nil:
+		if err := f.decl.simple.check(f.text.String()); err != nil {
+			v.errorf(f.line, f.col, f.path, "invalid value: %v", err)
+		}
+	case f.decl.complex != nil:
+		if !f.decl.complex.model.accepts(f.states) {
+			v.errorf(line, col, f.path, "missing child element, expected %s", f.decl.complex.model.expected(f.states))
+		}
+	}
+}
+
+// qualified formats name for messages, names in the target namespace are
+// shown without it
+func (v *validator) qualified(name xml.Name) string {
+	if name.Space == "" || name.Space == v.schema.target {
+		return strconv.Quote(name.Local)
+	}
+	return strconv.Quote("{" + name.Space + "}" + name.Local)
+}
@@ -0,0 +1,456 @@
+package plcopen
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// UnmarshalXML decodes the simpleValue, arrayValue or structValue child of start.
+// It is also used for the nested values of arrays and structs, which carry
+// their content in the same element as their own attributes.
+func (v *Value) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*v = Value{}
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "simpleValue":
+				v.SimpleValue = &ValueSimpleValue{}
+				err = d.DecodeElement(v.SimpleValue, &t)
+			case "arrayValue":
+				v.ArrayValue = &ValueArrayValue{}
+				err = d.DecodeElement(v.ArrayValue, &t)
+			case "structValue":
+				v.StructValue = &ValueStructValue{}
+				err = d.DecodeElement(v.StructValue, &t)
+			default:
+				err = d.Skip()
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// MarshalXML encodes the value as start wrapping its simpleValue, arrayValue
+// or structValue child
+func (v Value) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	var err error
+	switch {
+	case v.SimpleValue != nil:
+		err = e.EncodeElement(v.SimpleValue, xml.StartElement{Name: xml.Name{Local: "simpleValue"}})
+	case v.ArrayValue != nil:
+		err = e.EncodeElement(v.ArrayValue, xml.StartElement{Name: xml.Name{Local: "arrayValue"}})
+	case v.StructValue != nil:
+		err = e.EncodeElement(v.StructValue, xml.StartElement{Name: xml.Name{Local: "structValue"}})
+	}
+	if err != nil {
+		return err
+	}
+	return e.EncodeToken(start.End())
+}
+
+// isEmpty reports whether the value holds none of its choices
+func (v *Value) isEmpty() bool {
+	return v.SimpleValue == nil && v.ArrayValue == nil && v.StructValue == nil
+}
+
+// UnmarshalXML decodes the repetitionValue attribute and the nested value
+func (v *ValueArrayValueValue) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*v = ValueArrayValueValue{}
+	for _, attr := range start.Attr {
+		// "repetition" was written by earlier versions of this package
+		if attr.Name.Local == "repetitionValue" || attr.Name.Local == "repetition" {
+			n, err := strconv.ParseUint(strings.TrimSpace(attr.Value), 10, 64)
+			if err != nil {
+				return err
+			}
+			v.RepeatCount = &n
+		}
+	}
+	var value Value
+	if err := value.UnmarshalXML(d, start); err != nil {
+		return err
+	}
+	if !value.isEmpty() {
+		v.Value = &value
+	}
+	return nil
+}
+
+// MarshalXML encodes the repetitionValue attribute and the nested value
+func (v ValueArrayValueValue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if v.RepeatCount != nil {
+		start.Attr = append(start.Attr, xml.Attr{
+			Name:  xml.Name{Local: "repetitionValue"},
+			Value: strconv.FormatUint(*v.RepeatCount, 10),
+		})
+	}
+	var value Value
+	if v.Value != nil {
+		value = *v.Value
+	}
+	return value.MarshalXML(e, start)
+}
+
+// UnmarshalXML decodes the member attribute and the nested value
+func (v *ValueStructValueValue) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	*v = ValueStructValueValue{}
+	for _, attr := range start.Attr {
+		if attr.Name.Local == "member" {
+			v.Member = attr.Value
+		}
+	}
+	var value Value
+	if err := value.UnmarshalXML(d, start); err != nil {
+		return err
+	}
+	if !value.isEmpty() {
+		v.Value = &value
+	}
+	return nil
+}
+
+// MarshalXML encodes the member attribute and the nested value
+func (v ValueStructValueValue) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: "member"}, Value: v.Member})
+	var value Value
+	if v.Value != nil {
+		value = *v.Value
+	}
+	return value.MarshalXML(e, start)
+}
+
+// MarshalJSON writes the value as natural JSON: simple values that are JSON
+// numbers as numbers, TRUE and FALSE as booleans and others as strings,
+// arrays as arrays and structs as objects with their members in order.
+// Array elements with a repetition count are written as
+// {"$repeat":3,"$value":0}, and structs whose member names could be taken
+// for markers or the form of earlier versions as {"$struct":{...}}. An
+// empty value is written as null.
+func (v Value) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := v.writeJSON(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (v *Value) writeJSON(buf *bytes.Buffer) error {
+	switch {
+	case v == nil || v.isEmpty():
+		buf.WriteString("null")
+	case v.SimpleValue != nil:
+		s := v.SimpleValue.Value
+		switch {
+		case isJSONNumber(s):
+			buf.WriteString(s)
+		case s == "TRUE":
+			buf.WriteString("true")
+		case s == "FALSE":
+			buf.WriteString("false")
+		default:
+			text, err := marshalJSON(s)
+			if err != nil {
+				return err
+			}
+			buf.Write(text)
+		}
+	case v.ArrayValue != nil:
+		buf.WriteByte('[')
+		for i, elem := range v.ArrayValue.Values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if elem.RepeatCount != nil {
+				fmt.Fprintf(buf, `{"$repeat":%d,"$value":`, *elem.RepeatCount)
+			}
+			if err := elem.Value.writeJSON(buf); err != nil {
+				return err
+			}
+			if elem.RepeatCount != nil {
+				buf.WriteByte('}')
+			}
+		}
+		buf.WriteByte(']')
+	case v.StructValue != nil:
+		marker := needsStructMarker(v.StructValue)
+		if marker {
+			buf.WriteString(`{"$struct":`)
+		}
+		buf.WriteByte('{')
+		for i, member := range v.StructValue.Values {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, err := marshalJSON(member.Member)
+			if err != nil {
+				return err
+			}
+			buf.Write(name)
+			buf.WriteByte(':')
+			if err := member.Value.writeJSON(buf); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		if marker {
+			buf.WriteByte('}')
+		}
+	}
+	return nil
+}
+
+// isJSONNumber reports whether s can be written as JSON number unchanged
+func isJSONNumber(s string) bool {
+	if s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) || s[len(s)-1] < '0' || s[len(s)-1] > '9' {
+		return false
+	}
+	return json.Valid([]byte(s))
+}
+
+// UnmarshalJSON accepts the form written by MarshalJSON and the object
+// {"simpleValue":{"value":"1"}} written by earlier versions. The shape of a
+// value follows from its JSON type; objects with members starting with $
+// are markers, {"$struct":{...}} for structs and {"$repeat":3,"$value":0}
+// for array elements. An object with a single simpleValue, arrayValue or
+// structValue member holding an object is taken as the earlier form, which
+// MarshalJSON never writes for a struct.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	*v = Value{}
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var members map[string]json.RawMessage
+		if err := json.Unmarshal(data, &members); err != nil {
+			return err
+		}
+		for name, content := range members {
+			if len(members) == 1 && isLegacyValueMember(name) && bytes.HasPrefix(bytes.TrimSpace(content), []byte("{")) {
+				// Value without its methods, decoded field by field
+				type valueFields Value
+				return json.Unmarshal(data, (*valueFields)(v))
+			}
+		}
+	}
+
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	value, err := decodeValueJSON(d)
+	if err != nil {
+		return err
+	}
+	if value != nil {
+		*v = *value
+	}
+	return nil
+}
+
+// isLegacyValueMember reports whether name is a member of the object form
+// of earlier versions
+func isLegacyValueMember(name string) bool {
+	return strings.EqualFold(name, "simpleValue") || strings.EqualFold(name, "arrayValue") || strings.EqualFold(name, "structValue")
+}
+
+// needsStructMarker reports whether the members of st would be read as a
+// marker or as the form of earlier versions unless written in $struct
+func needsStructMarker(st *ValueStructValue) bool {
+	if len(st.Values) == 1 && isLegacyValueMember(st.Values[0].Member) {
+		return true
+	}
+	for _, member := range st.Values {
+		if strings.HasPrefix(member.Member, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeValueJSON decodes the next value of d, nil for null
+func decodeValueJSON(d *json.Decoder) (*Value, error) {
+	item, err := decodeItemJSON(d)
+	if err != nil {
+		return nil, err
+	}
+	if item.RepeatCount != nil {
+		return nil, fmt.Errorf("plcopen: $repeat outside of an array value")
+	}
+	return item.Value, nil
+}
+
+// decodeItemJSON decodes the next value of d, which has a repetition count
+// if it is written as {"$repeat":n,"$value":v}
+func decodeItemJSON(d *json.Decoder) (ValueArrayValueValue, error) {
+	tok, err := d.Token()
+	if err != nil {
+		return ValueArrayValueValue{}, err
+	}
+	simple := func(s string) (ValueArrayValueValue, error) {
+		return ValueArrayValueValue{Value: &Value{SimpleValue: &ValueSimpleValue{Value: s}}}, nil
+	}
+	switch t := tok.(type) {
+	case nil:
+		return ValueArrayValueValue{}, nil
+	case json.Number:
+		return simple(string(t))
+	case string:
+		return simple(t)
+	case bool:
+		if t {
+			return simple("TRUE")
+		}
+		return simple("FALSE")
+	case json.Delim:
+		if t == '[' {
+			array := &ValueArrayValue{}
+			for d.More() {
+				item, err := decodeItemJSON(d)
+				if err != nil {
+					return ValueArrayValueValue{}, err
+				}
+				array.Values = append(array.Values, item)
+			}
+			_, err := d.Token()
+			return ValueArrayValueValue{Value: &Value{ArrayValue: array}}, err
+		}
+		st, err := decodeMembersJSON(d, false)
+		if err != nil {
+			return ValueArrayValueValue{}, err
+		}
+		return marked(st)
+	}
+	return ValueArrayValueValue{}, fmt.Errorf("plcopen: unexpected JSON token %v in value", tok)
+}
+
+// decodeMembersJSON decodes the members of an object up to its end, in
+// order. Unless the names are literal, the content of a $struct marker is
+// decoded as members with literal names.
+func decodeMembersJSON(d *json.Decoder, literal bool) (*ValueStructValue, error) {
+	st := &ValueStructValue{}
+	for d.More() {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		name := tok.(string)
+		var member *Value
+		if !literal && name == "$struct" {
+			if tok, err := d.Token(); err != nil || tok != json.Delim('{') {
+				return nil, fmt.Errorf("plcopen: $struct of value is not an object")
+			}
+			inner, err := decodeMembersJSON(d, true)
+			if err != nil {
+				return nil, err
+			}
+			member = &Value{StructValue: inner}
+		} else if member, err = decodeValueJSON(d); err != nil {
+			return nil, err
+		}
+		st.Values = append(st.Values, ValueStructValueValue{Member: name, Value: member})
+	}
+	_, err := d.Token()
+	return st, err
+}
+
+// marked returns the value of the object with the members of st, which is
+// a struct unless its members are markers
+func marked(st *ValueStructValue) (ValueArrayValueValue, error) {
+	var item ValueArrayValueValue
+	markers := 0
+	for _, member := range st.Values {
+		switch member.Member {
+		case "$struct":
+			item.Value = member.Value
+		case "$repeat":
+			if member.Value == nil || member.Value.SimpleValue == nil {
+				return item, fmt.Errorf("plcopen: $repeat of array element is not a number")
+			}
+			n, err := strconv.ParseUint(member.Value.SimpleValue.Value, 10, 64)
+			if err != nil {
+				return item, fmt.Errorf("plcopen: $repeat of array element: %w", err)
+			}
+			item.RepeatCount = &n
+		case "$value":
+			item.Value = member.Value
+		default:
+			if strings.HasPrefix(member.Member, "$") {
+				return item, fmt.Errorf("plcopen: unknown marker %q in value", member.Member)
+			}
+			continue
+		}
+		markers++
+	}
+	switch {
+	case markers == 0:
+		return ValueArrayValueValue{Value: &Value{StructValue: st}}, nil
+	case markers != len(st.Values):
+		return item, fmt.Errorf("plcopen: struct members mixed with markers in value, write the struct as {\"$struct\":{...}}")
+	case len(st.Values) == 1 && st.Values[0].Member == "$struct":
+		return item, nil
+	case len(st.Values) == 2 && item.RepeatCount != nil && st.Values[0].Member != st.Values[1].Member:
+		return item, nil
+	}
+	return item, fmt.Errorf("plcopen: invalid markers in value, want {\"$struct\":{...}} or {\"$repeat\":n,\"$value\":v}")
+}
+
+// String renders the value in IEC 61131-3 literal syntax, e.g.
+// "[1, 2, 3(0)]" for arrays and "(a := 1, b := 2)" for structs
+func (v *Value) String() string {
+	if v == nil {
+		return ""
+	}
+	var sb strings.Builder
+	v.writeIEC(&sb)
+	return sb.String()
+}
+
+func (v *Value) writeIEC(sb *strings.Builder) {
+	switch {
+	case v.SimpleValue != nil:
+		sb.WriteString(v.SimpleValue.Value)
+	case v.ArrayValue != nil:
+		sb.WriteByte('[')
+		for i, elem := range v.ArrayValue.Values {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			if elem.RepeatCount != nil && *elem.RepeatCount != 1 {
+				sb.WriteString(strconv.FormatUint(*elem.RepeatCount, 10))
+				sb.WriteByte('(')
+				if elem.Value != nil {
+					elem.Value.writeIEC(sb)
+				}
+				sb.WriteByte(')')
+			} else if elem.Value != nil {
+				elem.Value.writeIEC(sb)
+			}
+		}
+		sb.WriteByte(']')
+	case v.StructValue != nil:
+		sb.WriteByte('(')
+		for i, member := range v.StructValue.Values {
+			if i > 0 {
+				sb.WriteString(", ")
+			}
+			sb.WriteString(member.Member)
+			sb.WriteString(" := ")
+			if member.Value != nil {
+				member.Value.writeIEC(sb)
+			}
+		}
+		sb.WriteByte(')')
+	}
+}
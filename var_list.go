@@ -0,0 +1,68 @@
+package plcopen
+
+// VarListQualifier represents a memory qualifier of a variable list,
+// spelled as the IEC 61131-3 keyword that follows VAR
+type VarListQualifier string
+
+const (
+	VarListQualifierConstant      VarListQualifier = "CONSTANT"
+	VarListQualifierRetain        VarListQualifier = "RETAIN"
+	VarListQualifierNonRetain     VarListQualifier = "NON_RETAIN"
+	VarListQualifierPersistent    VarListQualifier = "PERSISTENT"
+	VarListQualifierNonPersistent VarListQualifier = "NON_PERSISTENT"
+)
+
+// Qualifiers returns the qualifiers set on the variable list, in the order
+// CONSTANT, RETAIN, NON_RETAIN, PERSISTENT, NON_PERSISTENT
+func (v *VarList) Qualifiers() []VarListQualifier {
+	if v == nil {
+		return nil
+	}
+	var qualifiers []VarListQualifier
+	for _, q := range []struct {
+		flag      *bool
+		qualifier VarListQualifier
+	}{
+		{v.Constant, VarListQualifierConstant},
+		{v.Retain, VarListQualifierRetain},
+		{v.NonRetain, VarListQualifierNonRetain},
+		{v.Persistent, VarListQualifierPersistent},
+		{v.NonPersistent, VarListQualifierNonPersistent},
+	} {
+		if q.flag != nil && *q.flag {
+			qualifiers = append(qualifiers, q.qualifier)
+		}
+	}
+	return qualifiers
+}
+
+// HasQualifier reports whether q is set on the variable list
+func (v *VarList) HasQualifier(q VarListQualifier) bool {
+	for _, set := range v.Qualifiers() {
+		if set == q {
+			return true
+		}
+	}
+	return false
+}
+
+// SetQualifiers replaces the qualifiers of the variable list with qs.
+// Qualifiers that are not set are cleared rather than written as false.
+func (v *VarList) SetQualifiers(qs ...VarListQualifier) {
+	v.Constant, v.Retain, v.NonRetain, v.Persistent, v.NonPersistent = nil, nil, nil, nil, nil
+	for _, q := range qs {
+		set := true
+		switch q {
+		case VarListQualifierConstant:
+			v.Constant = &set
+		case VarListQualifierRetain:
+			v.Retain = &set
+		case VarListQualifierNonRetain:
+			v.NonRetain = &set
+		case VarListQualifierPersistent:
+			v.Persistent = &set
+		case VarListQualifierNonPersistent:
+			v.NonPersistent = &set
+		}
+	}
+}